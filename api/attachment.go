@@ -0,0 +1,366 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/bolusarz/task-manager/blob"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultAttachmentMaxSize     = 10 << 20
+	defaultAttachmentURLDuration = 15 * time.Minute
+	// multipartOverhead leaves room for the boundaries and part headers
+	// around the file itself.
+	multipartOverhead   = 64 << 10
+	maxAttachmentName   = 255
+	attachmentFormField = "file"
+)
+
+// defaultAttachmentTypes is used when ATTACHMENT_ALLOWED_TYPES is not set.
+var defaultAttachmentTypes = []string{
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+	"image/webp",
+	"application/pdf",
+	"text/plain",
+	"application/zip",
+}
+
+var (
+	errAttachmentNotFound    = errors.New("attachment not found")
+	errMissingAttachment     = errors.New("the request must be multipart/form-data with a file field")
+	errEmptyAttachment       = errors.New("file must not be empty")
+	errAttachmentTooLarge    = errors.New("file is too large")
+	errAttachmentTypeBlocked = errors.New("this type of file is not allowed")
+	errInvalidDownloadToken  = errors.New("download link is invalid or has expired")
+)
+
+type attachmentResponse struct {
+	db.TaskAttachment
+	DownloadURL string    `json:"downloadUrl"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+func (s *Server) ListTaskAttachments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	task, ok := s.loadVisibleTask(w, r)
+	if !ok {
+		return
+	}
+
+	attachments, err := s.store.ListTaskAttachments(ctx, task.ID)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	response := make([]attachmentResponse, len(attachments))
+	for i, attachment := range attachments {
+		response[i], err = s.newAttachmentResponse(attachment)
+		if err != nil {
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+	}
+
+	render.Render(w, r, SuccessfulResponse(response))
+}
+
+// UploadTaskAttachment stores the file sent in the "file" field of a
+// multipart request. The content type is detected from the file itself;
+// whatever the client claims is ignored.
+func (s *Server) UploadTaskAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	task, ok := s.loadVisibleTask(w, r)
+	if !ok {
+		return
+	}
+
+	maxSize := s.config.AttachmentMaxSize
+	if maxSize <= 0 {
+		maxSize = defaultAttachmentMaxSize
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+multipartOverhead)
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(errMissingAttachment))
+		return
+	}
+
+	var part io.Reader
+	var fileName string
+	for {
+		p, err := reader.NextPart()
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				render.Render(w, r, ErrInvalidRequestWithCode(errAttachmentTooLarge, http.StatusRequestEntityTooLarge))
+				return
+			}
+			render.Render(w, r, ErrInvalidRequest(errMissingAttachment))
+			return
+		}
+
+		if p.FormName() == attachmentFormField && p.FileName() != "" {
+			part = p
+			fileName = attachmentFileName(p.FileName())
+			break
+		}
+	}
+
+	// The file is spooled to disk so its size is known before it is handed
+	// to the blob store.
+	tmp, err := os.CreateTemp("", "attachment-*")
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, io.LimitReader(part, maxSize+1))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			render.Render(w, r, ErrInvalidRequestWithCode(errAttachmentTooLarge, http.StatusRequestEntityTooLarge))
+			return
+		}
+		render.Render(w, r, ErrInvalidRequest(errMissingAttachment))
+		return
+	}
+
+	if size > maxSize {
+		render.Render(w, r, ErrInvalidRequestWithCode(errAttachmentTooLarge, http.StatusRequestEntityTooLarge))
+		return
+	}
+	if size == 0 {
+		render.Render(w, r, ErrInvalidRequest(errEmptyAttachment))
+		return
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	contentType, err := mimetype.DetectReader(tmp)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	if !s.attachmentTypeAllowed(contentType) {
+		render.Render(w, r, ErrInvalidRequestWithCode(errAttachmentTypeBlocked, http.StatusUnsupportedMediaType))
+		return
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	key := fmt.Sprintf("tasks/%d/%s", task.ID, uuid.New())
+	if err := s.blobs.Put(ctx, key, tmp, size, contentType.String()); err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	principal := principalFromContext(ctx)
+
+	attachment, err := s.store.CreateTaskAttachment(ctx, db.CreateTaskAttachmentParams{
+		TaskID:      task.ID,
+		UploadedBy:  &principal.User.ID,
+		FileName:    fileName,
+		ContentType: contentType.String(),
+		SizeBytes:   size,
+		StorageKey:  key,
+	})
+	if err != nil {
+		s.deleteBlobs(context.WithoutCancel(ctx), key)
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	response, err := s.newAttachmentResponse(attachment)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponseWithCode(response, http.StatusCreated))
+}
+
+func (s *Server) DeleteTaskAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	attachmentID, err := parseIDParam(r, "attachmentId")
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	task, ok := s.loadVisibleTask(w, r)
+	if !ok {
+		return
+	}
+
+	attachment, err := s.store.GetTaskAttachment(ctx, attachmentID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			render.Render(w, r, ErrNotFound(errAttachmentNotFound))
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	if attachment.TaskID != task.ID {
+		render.Render(w, r, ErrNotFound(errAttachmentNotFound))
+		return
+	}
+
+	if err := s.store.DeleteTaskAttachment(ctx, attachment.ID); err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	s.deleteBlobs(ctx, attachment.StorageKey)
+
+	render.Render(w, r, SuccessfulResponse(nil))
+}
+
+// DownloadAttachment serves an attachment to whoever holds a signed link from
+// newAttachmentResponse. The link is the only credential, so it works for
+// plain <a> and <img> tags.
+func (s *Server) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	_, err = s.tokenMaker.ValidateResourceToken(r.URL.Query().Get("token"), attachmentResource(id))
+	if err != nil {
+		render.Render(w, r, ErrForbidden(errInvalidDownloadToken))
+		return
+	}
+
+	attachment, err := s.store.GetTaskAttachment(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			render.Render(w, r, ErrNotFound(errAttachmentNotFound))
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	body, err := s.blobs.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, blob.ErrNotFound) {
+			render.Render(w, r, ErrNotFound(errAttachmentNotFound))
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.SizeBytes, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, no-store")
+
+	if _, err := io.Copy(w, body); err != nil {
+		log.Printf("unable to send attachment %d: %v", attachment.ID, err)
+	}
+}
+
+// newAttachmentResponse adds a signed, expiring download link to attachment.
+func (s *Server) newAttachmentResponse(attachment db.TaskAttachment) (attachmentResponse, error) {
+	duration := durationOrDefault(s.config.AttachmentURLDuration, defaultAttachmentURLDuration)
+
+	token, payload, err := s.tokenMaker.CreateResourceToken(attachmentResource(attachment.ID), duration)
+	if err != nil {
+		return attachmentResponse{}, err
+	}
+
+	return attachmentResponse{
+		TaskAttachment: attachment,
+		DownloadURL:    fmt.Sprintf("/api/v1/attachments/%d/download?token=%s", attachment.ID, url.QueryEscape(token)),
+		ExpiresAt:      payload.ExpiresAt,
+	}, nil
+}
+
+// attachmentTypeAllowed checks the detected type, and the types it is a more
+// specific form of, against the configured allow list. Allowing text/plain
+// therefore also allows text/csv.
+func (s *Server) attachmentTypeAllowed(contentType *mimetype.MIME) bool {
+	allowed := s.config.AttachmentAllowedTypes
+	if len(allowed) == 0 {
+		allowed = defaultAttachmentTypes
+	}
+
+	for m := contentType; m != nil; m = m.Parent() {
+		for _, t := range allowed {
+			if m.Is(t) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// deleteBlobs removes stored files whose database rows are already gone.
+// Failures only leave unreachable files behind, so they are logged rather
+// than reported to the caller.
+func (s *Server) deleteBlobs(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			log.Printf("unable to delete blob %s: %v", key, err)
+		}
+	}
+}
+
+func attachmentResource(id int32) string {
+	return fmt.Sprintf("attachment:%d", id)
+}
+
+// attachmentFileName keeps the base name of the client supplied file name,
+// cleaned up and cut to a length the database accepts.
+func attachmentFileName(name string) string {
+	name = path.Base(util.SanitizeInput(name))
+	if name == "." || name == "/" {
+		name = "file"
+	}
+
+	if runes := []rune(name); len(runes) > maxAttachmentName {
+		name = string(runes[:maxAttachmentName])
+	}
+
+	return name
+}
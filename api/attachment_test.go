@@ -0,0 +1,311 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// pngHeader is enough of a PNG file for content sniffing.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00")
+
+func newAttachmentRequest(t *testing.T, url, fileName string, content []byte) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	require.NoError(t, writer.WriteField("note", "ignored"))
+
+	part, err := writer.CreateFormFile(attachmentFormField, fileName)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	request, err := http.NewRequest(http.MethodPost, url, &body)
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return request
+}
+
+func TestUploadTaskAttachmentApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	task := randomTask()
+	task.CreatedBy = &user.ID
+
+	testCases := []struct {
+		name          string
+		fileName      string
+		content       []byte
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			fileName: "../../shot.txt",
+			content:  pngHeader,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().
+					CreateTaskAttachment(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateTaskAttachmentParams) (db.TaskAttachment, error) {
+						require.Equal(t, task.ID, arg.TaskID)
+						require.Equal(t, user.ID, *arg.UploadedBy)
+						require.Equal(t, "shot.txt", arg.FileName)
+						require.Equal(t, "image/png", arg.ContentType)
+						require.Equal(t, int64(len(pngHeader)), arg.SizeBytes)
+						require.True(t, strings.HasPrefix(arg.StorageKey, fmt.Sprintf("tasks/%d/", task.ID)))
+						return db.TaskAttachment{
+							ID:          7,
+							TaskID:      arg.TaskID,
+							UploadedBy:  arg.UploadedBy,
+							FileName:    arg.FileName,
+							ContentType: arg.ContentType,
+							SizeBytes:   arg.SizeBytes,
+							StorageKey:  arg.StorageKey,
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), "tasks/")
+
+				var response struct {
+					Data attachmentResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.True(t, strings.HasPrefix(response.Data.DownloadURL, "/api/v1/attachments/7/download?token="))
+				require.WithinDuration(t, time.Now().Add(defaultAttachmentURLDuration), response.Data.ExpiresAt, time.Minute)
+			},
+		},
+		{
+			name:     "TooLarge",
+			fileName: "big.txt",
+			content:  bytes.Repeat([]byte("a"), 2048),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().CreateTaskAttachment(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+			},
+		},
+		{
+			name:     "BlockedType",
+			fileName: "report.pdf",
+			content:  []byte("MZ\x90\x00\x03\x00\x00\x00\x04\x00\x00\x00\xff\xff\x00\x00"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().CreateTaskAttachment(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+			},
+		},
+		{
+			name:     "Empty",
+			fileName: "empty.txt",
+			content:  nil,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().CreateTaskAttachment(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errEmptyAttachment.Error())
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimEditTask)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.config.AttachmentMaxSize = 1024
+			recorder := httptest.NewRecorder()
+
+			request := newAttachmentRequest(t, fmt.Sprintf("/api/v1/tasks/%d/attachments", task.ID), tt.fileName, tt.content)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDownloadAttachmentApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	task := randomTask()
+	task.CreatedBy = &user.ID
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	stubAuthenticatedUser(store, user, ClaimEditTask)
+
+	var stored db.TaskAttachment
+	store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).AnyTimes().Return(task, nil)
+	store.EXPECT().
+		CreateTaskAttachment(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateTaskAttachmentParams) (db.TaskAttachment, error) {
+			stored = db.TaskAttachment{
+				ID:          9,
+				TaskID:      arg.TaskID,
+				FileName:    arg.FileName,
+				ContentType: arg.ContentType,
+				SizeBytes:   arg.SizeBytes,
+				StorageKey:  arg.StorageKey,
+			}
+			return stored, nil
+		})
+	store.EXPECT().
+		GetTaskAttachment(gomock.Any(), gomock.Eq(int32(9))).
+		AnyTimes().
+		DoAndReturn(func(context.Context, int32) (db.TaskAttachment, error) {
+			return stored, nil
+		})
+	store.EXPECT().ListTaskAttachments(gomock.Any(), gomock.Eq(task.ID)).AnyTimes().DoAndReturn(
+		func(context.Context, int32) ([]db.TaskAttachment, error) {
+			return []db.TaskAttachment{stored}, nil
+		})
+
+	server := newTestServer(t, store)
+	content := []byte("meeting notes\nline two\n")
+
+	recorder := httptest.NewRecorder()
+	request := newAttachmentRequest(t, fmt.Sprintf("/api/v1/tasks/%d/attachments", task.ID), `notes "v2".txt`, content)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	require.Equal(t, "text/plain; charset=utf-8", stored.ContentType)
+
+	recorder = httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/tasks/%d/attachments", task.ID), nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Data []attachmentResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response.Data, 1)
+	downloadURL := response.Data[0].DownloadURL
+
+	// The signed link works without an Authorization header.
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, downloadURL, nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, stored.ContentType, recorder.Header().Get("Content-Type"))
+	require.Equal(t, "nosniff", recorder.Header().Get("X-Content-Type-Options"))
+	require.Equal(t, `attachment; filename="notes \"v2\".txt"`, recorder.Header().Get("Content-Disposition"))
+
+	body, err := io.ReadAll(recorder.Body)
+	require.NoError(t, err)
+	require.Equal(t, content, body)
+
+	// A token for one attachment does not open another.
+	token, _, err := server.tokenMaker.CreateResourceToken(attachmentResource(10), time.Minute)
+	require.NoError(t, err)
+
+	for _, url := range []string{
+		"/api/v1/attachments/9/download",
+		"/api/v1/attachments/9/download?token=" + token,
+		strings.Replace(downloadURL, "/9/", "/10/", 1),
+	} {
+		recorder = httptest.NewRecorder()
+		request, err = http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusForbidden, recorder.Code, url)
+	}
+}
+
+func TestDeleteTaskAttachmentApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	task := randomTask()
+	task.CreatedBy = &user.ID
+
+	attachment := db.TaskAttachment{ID: 3, TaskID: task.ID, StorageKey: fmt.Sprintf("tasks/%d/file", task.ID)}
+	otherAttachment := db.TaskAttachment{ID: 4, TaskID: task.ID + 1, StorageKey: "tasks/0/file"}
+
+	testCases := []struct {
+		name          string
+		attachment    db.TaskAttachment
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "OK",
+			attachment: attachment,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetTaskAttachment(gomock.Any(), gomock.Eq(attachment.ID)).Times(1).Return(attachment, nil)
+				store.EXPECT().DeleteTaskAttachment(gomock.Any(), gomock.Eq(attachment.ID)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "NotFound: Other Task",
+			attachment: otherAttachment,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetTaskAttachment(gomock.Any(), gomock.Eq(otherAttachment.ID)).Times(1).Return(otherAttachment, nil)
+				store.EXPECT().DeleteTaskAttachment(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimEditTask)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/tasks/%d/attachments/%d", task.ID, tt.attachment.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
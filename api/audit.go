@@ -0,0 +1,100 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/go-chi/render"
+)
+
+var errInvalidAuditAction = errors.New("action must be one of [create update delete]")
+
+// auditCursor is the state behind the opaque cursor of the audit log.
+type auditCursor struct {
+	BeforeID int64 `json:"beforeId"`
+}
+
+// ListAuditEvents returns the audit log, newest first. It can be narrowed
+// down by actorId, action, entityType (a table name such as tasks),
+// entityId and a since/until time range.
+func (s *Server) ListAuditEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	arg, err := parseListAuditEventsQuery(r)
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	// One extra event tells whether there is another page.
+	limit := arg.Limit
+	arg.Limit = limit + 1
+
+	events, err := s.store.ListAuditEvents(ctx, arg)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	var nextCursor *string
+	if len(events) > int(limit) {
+		events = events[:limit]
+
+		cursor, err := encodeCursor(auditCursor{BeforeID: events[len(events)-1].ID})
+		if err != nil {
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+		nextCursor = &cursor
+	}
+
+	render.Render(w, r, SuccessfulPaginatedResponse(events, nextCursor))
+}
+
+func parseListAuditEventsQuery(r *http.Request) (db.ListAuditEventsParams, error) {
+	var (
+		arg db.ListAuditEventsParams
+		err error
+	)
+
+	query := r.URL.Query()
+
+	if arg.Limit, err = parseIntQuery(r, "limit", 50, 1, 100); err != nil {
+		return arg, err
+	}
+	if arg.ActorID, err = parseOptionalIDQuery(r, "actorId"); err != nil {
+		return arg, err
+	}
+	if arg.Since, err = parseTimeQuery(r, "since"); err != nil {
+		return arg, err
+	}
+	if arg.Until, err = parseTimeQuery(r, "until"); err != nil {
+		return arg, err
+	}
+
+	if action := query.Get("action"); action != "" {
+		switch action {
+		case "create", "update", "delete":
+			arg.Action = &action
+		default:
+			return arg, errInvalidAuditAction
+		}
+	}
+	if entityType := query.Get("entityType"); entityType != "" {
+		arg.EntityType = &entityType
+	}
+	if entityID := query.Get("entityId"); entityID != "" {
+		arg.EntityID = &entityID
+	}
+
+	if raw := query.Get("cursor"); raw != "" {
+		var cursor auditCursor
+		if err := decodeCursor(raw, &cursor); err != nil {
+			return arg, err
+		}
+		arg.BeforeID = &cursor.BeforeID
+	}
+
+	return arg, nil
+}
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func randomAuditEvent(id int64, actorID int32) db.AuditEvent {
+	return db.AuditEvent{
+		ID:         id,
+		ActorID:    &actorID,
+		Action:     "update",
+		EntityType: "tasks",
+		EntityID:   "42",
+		Before:     json.RawMessage(`{"title": "Old"}`),
+		After:      json.RawMessage(`{"title": "New"}`),
+		RequestID:  "host/abc-000001",
+		ClientIp:   "10.0.0.1",
+		CreatedAt:  time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+func TestListAuditEventsApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	events := []db.AuditEvent{
+		randomAuditEvent(30, user.ID),
+		randomAuditEvent(20, user.ID),
+		randomAuditEvent(10, user.ID),
+	}
+
+	cursor, err := encodeCursor(auditCursor{BeforeID: 30})
+	require.NoError(t, err)
+
+	type listResponse struct {
+		Data       []db.AuditEvent `json:"data"`
+		NextCursor *string         `json:"nextCursor"`
+	}
+
+	testCases := []struct {
+		name          string
+		claims        []string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK: Next Page",
+			claims: []string{ClaimManageRoles},
+			query:  "?limit=2",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAuditEvents(gomock.Any(), gomock.Eq(db.ListAuditEventsParams{Limit: 3})).
+					Times(1).
+					Return(events, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response listResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response.Data, 2)
+				require.JSONEq(t, `{"title": "New"}`, string(response.Data[0].After))
+				require.NotNil(t, response.NextCursor)
+
+				var next auditCursor
+				require.NoError(t, decodeCursor(*response.NextCursor, &next))
+				require.Equal(t, events[1].ID, next.BeforeID)
+			},
+		},
+		{
+			name:   "OK: Filters",
+			claims: []string{ClaimManageRoles},
+			query:  "?actorId=5&action=delete&entityType=tasks&entityId=42&since=2025-01-01T00:00:00Z&cursor=" + cursor,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAuditEvents(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ListAuditEventsParams) ([]db.AuditEvent, error) {
+						require.Equal(t, int32(5), *arg.ActorID)
+						require.Equal(t, "delete", *arg.Action)
+						require.Equal(t, "tasks", *arg.EntityType)
+						require.Equal(t, "42", *arg.EntityID)
+						require.True(t, arg.Since.Equal(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)))
+						require.Nil(t, arg.Until)
+						require.Equal(t, int64(30), *arg.BeforeID)
+						require.Equal(t, int32(51), arg.Limit)
+						return events[1:], nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response listResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response.Data, 2)
+				require.Nil(t, response.NextCursor)
+			},
+		},
+		{
+			name:   "Forbidden",
+			claims: []string{ClaimManageClaims},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAuditEvents(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "BadRequest: Invalid Action",
+			claims: []string{ClaimManageRoles},
+			query:  "?action=read",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAuditEvents(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errInvalidAuditAction.Error())
+			},
+		},
+		{
+			name:   "BadRequest: Invalid Cursor",
+			claims: []string{ClaimManageRoles},
+			query:  "?cursor=not-a-cursor",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAuditEvents(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Internal Server Error",
+			claims: []string{ClaimManageRoles},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAuditEvents(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, tt.claims...)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/v1/audit"+tt.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+// TestAuditContext checks that writes made while serving a request are
+// attributed to its caller, request ID and client IP.
+func TestAuditContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := randomAuthenticatedUser(t)
+	task := randomTask()
+	task.CreatedBy = &user.ID
+
+	store := mockdb.NewMockStore(ctrl)
+	stubAuthenticatedUser(store, user, ClaimDeleteTask)
+	store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+	store.EXPECT().ListTaskAttachments(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return([]db.TaskAttachment{}, nil)
+	store.EXPECT().
+		DeleteTask(gomock.Any(), gomock.Eq(task.ID)).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, _ int32) error {
+			audit := db.AuditContextFrom(ctx)
+			require.Equal(t, user.ID, audit.ActorID)
+			require.NotEmpty(t, audit.RequestID)
+			require.Equal(t, "203.0.113.7", audit.ClientIP)
+			return nil
+		})
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/tasks/%d", task.ID), nil)
+	require.NoError(t, err)
+	request.Header.Set("X-Forwarded-For", "203.0.113.7")
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+const (
+	ClaimCreateTask        = "can_create_task"
+	ClaimEditTask          = "can_edit_task"
+	ClaimDeleteTask        = "can_delete_task"
+	ClaimAssignTask        = "can_assign_task"
+	ClaimCreateTeam        = "can_create_team"
+	ClaimEditTeam          = "can_edit_team"
+	ClaimDeleteTeam        = "can_delete_team"
+	ClaimManageTeamMembers = "can_manage_team_members"
+	ClaimManageRoles       = "can_manage_roles"
+	ClaimManageClaims      = "can_manage_claims"
+	ClaimViewAllRecords    = "can_view_all_records"
+)
+
+var errPermissionDenied = errors.New("you do not have permission to perform this action")
+
+// hasClaim reports whether the principal holds the given claim through any of
+// their roles. Claims are loaded from the store at most once per request.
+func (s *Server) hasClaim(ctx context.Context, principal *Principal, claim string) (bool, error) {
+	principal.claimsOnce.Do(func() {
+		names, err := s.store.ListUserClaims(ctx, principal.User.ID)
+		if err != nil {
+			principal.claimsErr = err
+			return
+		}
+
+		principal.claims = make(map[string]bool, len(names))
+		for _, name := range names {
+			principal.claims[name] = true
+		}
+	})
+
+	if principal.claimsErr != nil {
+		return false, principal.claimsErr
+	}
+
+	return principal.claims[claim], nil
+}
+
+// RequireClaim only lets the request through if the authenticated principal
+// holds the given claim. It must be mounted after Authenticate.
+func (s *Server) RequireClaim(claim string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal := principalFromContext(r.Context())
+			if principal == nil {
+				unauthorized(w, r, errMissingAuthorization)
+				return
+			}
+
+			ok, err := s.hasClaim(r.Context(), principal, claim)
+			if err != nil {
+				render.Render(w, r, ErrInternalServer())
+				return
+			}
+
+			if !ok {
+				render.Render(w, r, ErrForbidden(errPermissionDenied))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	"github.com/go-chi/render"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRequireClaimMiddleware(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				// Both middlewares on the route share a single lookup.
+				store.EXPECT().
+					ListUserClaims(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return([]string{ClaimCreateTask, ClaimDeleteTask}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Forbidden",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListUserClaims(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return([]string{ClaimCreateTask}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Server Error",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListUserClaims(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+				Times(1).
+				Return(user, nil)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+
+			path := "/claims"
+			server.router.
+				With(server.Authenticate, server.RequireClaim(ClaimCreateTask), server.RequireClaim(ClaimDeleteTask)).
+				Get(path, func(w http.ResponseWriter, r *http.Request) {
+					render.Render(w, r, SuccessfulResponse(nil))
+				})
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, path, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestProtectedRoutesRequireClaims(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPost, "/api/v1/tasks"},
+		{http.MethodPatch, "/api/v1/tasks/1"},
+		{http.MethodDelete, "/api/v1/tasks/1"},
+		{http.MethodPost, "/api/v1/tasks/1/assignees"},
+		{http.MethodDelete, "/api/v1/tasks/1/assignees"},
+		{http.MethodPost, "/api/v1/tasks/1/transition"},
+		{http.MethodPost, "/api/v1/teams"},
+		{http.MethodPatch, "/api/v1/teams/1"},
+		{http.MethodDelete, "/api/v1/teams/1"},
+		{http.MethodPost, "/api/v1/teams/1/owner"},
+		{http.MethodPost, "/api/v1/teams/1/members"},
+		{http.MethodDelete, "/api/v1/teams/1/members/2"},
+		{http.MethodPost, "/api/v1/teams/1/workflow/statuses"},
+		{http.MethodPatch, "/api/v1/teams/1/workflow/statuses/2"},
+		{http.MethodDelete, "/api/v1/teams/1/workflow/statuses/2"},
+		{http.MethodPost, "/api/v1/teams/1/workflow/transitions"},
+		{http.MethodDelete, "/api/v1/teams/1/workflow/transitions"},
+		{http.MethodGet, "/api/v1/roles"},
+		{http.MethodPost, "/api/v1/roles/1/claims"},
+		{http.MethodGet, "/api/v1/claims"},
+		{http.MethodPost, "/api/v1/users/1/roles"},
+	}
+
+	for _, route := range routes {
+		t.Run(route.method+" "+route.path, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(route.method, route.path, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusForbidden, recorder.Code)
+		})
+	}
+}
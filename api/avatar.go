@@ -0,0 +1,263 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/bolusarz/task-manager/blob"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultAvatarMaxSize = 5 << 20
+	// maxAvatarPixels keeps a small file that decodes into a huge image from
+	// using up memory.
+	maxAvatarPixels   = 25_000_000
+	avatarJPEGQuality = 85
+	avatarURLPrefix   = "/api/v1/avatars/"
+)
+
+// avatarSizes are the edge lengths of the square thumbnails made for every
+// upload. The profile picture URL points at the first one.
+var avatarSizes = []int{512, 256, 128, 64}
+
+var (
+	errEmptyAvatar         = errors.New("image must not be empty")
+	errAvatarTooLarge      = errors.New("image is too large")
+	errAvatarTypeBlocked   = errors.New("only JPEG, PNG and GIF images are allowed")
+	errInvalidImage        = errors.New("image could not be read")
+	errAvatarTooManyPixels = errors.New("image dimensions are too large")
+	errAvatarNotFound      = errors.New("avatar not found")
+)
+
+type avatarResponse struct {
+	createAccountResponse
+	Thumbnails map[int]string `json:"thumbnails"`
+}
+
+// UploadAvatar replaces the caller's profile picture with the image sent as
+// the request body. The image is re-encoded from its pixels, which drops EXIF
+// and any other metadata, after being turned upright.
+func (s *Server) UploadAvatar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	maxSize := s.config.AvatarMaxSize
+	if maxSize <= 0 {
+		maxSize = defaultAvatarMaxSize
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			render.Render(w, r, ErrInvalidRequestWithCode(errAvatarTooLarge, http.StatusRequestEntityTooLarge))
+			return
+		}
+		render.Render(w, r, ErrInvalidRequest(errInvalidImage))
+		return
+	}
+
+	if len(data) == 0 {
+		render.Render(w, r, ErrInvalidRequest(errEmptyAvatar))
+		return
+	}
+
+	contentType := mimetype.Detect(data)
+	if !contentType.Is("image/jpeg") && !contentType.Is("image/png") && !contentType.Is("image/gif") {
+		render.Render(w, r, ErrInvalidRequestWithCode(errAvatarTypeBlocked, http.StatusUnsupportedMediaType))
+		return
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(errInvalidImage))
+		return
+	}
+
+	if config.Width*config.Height > maxAvatarPixels {
+		render.Render(w, r, ErrInvalidRequest(errAvatarTooManyPixels))
+		return
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(errInvalidImage))
+		return
+	}
+
+	// Photos stay JPEG; PNG and GIF may have transparency, so they become
+	// PNG.
+	ext := "png"
+	orientation := 1
+	if contentType.Is("image/jpeg") {
+		ext = "jpg"
+		orientation = util.ImageOrientation(data)
+	}
+
+	avatarID := uuid.New()
+	thumbnails := make(map[int]string, len(avatarSizes))
+	keys := make([]string, 0, len(avatarSizes))
+
+	for _, size := range avatarSizes {
+		thumbnail := util.Orient(util.SquareThumbnail(img, size), orientation)
+
+		var encoded bytes.Buffer
+		if ext == "jpg" {
+			err = jpeg.Encode(&encoded, thumbnail, &jpeg.Options{Quality: avatarJPEGQuality})
+		} else {
+			err = png.Encode(&encoded, thumbnail)
+		}
+		if err != nil {
+			s.deleteBlobs(ctx, keys...)
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+
+		file := avatarFileName(size, ext)
+		key := avatarKey(avatarID.String(), file)
+		if err := s.blobs.Put(ctx, key, &encoded, int64(encoded.Len()), avatarContentType(ext)); err != nil {
+			s.deleteBlobs(ctx, keys...)
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+
+		keys = append(keys, key)
+		thumbnails[size] = avatarURLPrefix + avatarID.String() + "/" + file
+	}
+
+	user := principalFromContext(ctx).User
+
+	updatedUser, err := s.store.UpdateUser(ctx, db.UpdateUserParams{
+		ID:                user.ID,
+		FirstName:         user.FirstName,
+		LastName:          user.LastName,
+		Email:             user.Email,
+		ProfilePictureUrl: thumbnails[avatarSizes[0]],
+		IsEmailVerified:   user.IsEmailVerified,
+		Timezone:          user.Timezone,
+	})
+	if err != nil {
+		s.deleteBlobs(context.WithoutCancel(ctx), keys...)
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	s.deleteAvatar(ctx, user.ProfilePictureUrl)
+
+	render.Render(w, r, SuccessfulResponse(avatarResponse{
+		createAccountResponse: newCreateAccountResponse(updatedUser),
+		Thumbnails:            thumbnails,
+	}))
+}
+
+// GetAvatar serves a profile picture thumbnail. Every upload gets a new ID,
+// so the files never change and can be cached for good.
+func (s *Server) GetAvatar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	avatarID, err := uuid.Parse(chi.URLParam(r, "avatarId"))
+	if err != nil {
+		render.Render(w, r, ErrNotFound(errAvatarNotFound))
+		return
+	}
+
+	file := chi.URLParam(r, "file")
+	ext, ok := avatarFileExt(file)
+	if !ok {
+		render.Render(w, r, ErrNotFound(errAvatarNotFound))
+		return
+	}
+
+	body, err := s.blobs.Get(ctx, avatarKey(avatarID.String(), file))
+	if err != nil {
+		if errors.Is(err, blob.ErrNotFound) {
+			render.Render(w, r, ErrNotFound(errAvatarNotFound))
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", avatarContentType(ext))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+
+	if _, err := io.Copy(w, body); err != nil {
+		log.Printf("unable to send avatar %s/%s: %v", avatarID, file, err)
+	}
+}
+
+// deleteAvatar removes the thumbnails behind a profile picture URL. URLs that
+// were not made by UploadAvatar are left alone.
+func (s *Server) deleteAvatar(ctx context.Context, profilePictureURL string) {
+	rest, ok := strings.CutPrefix(profilePictureURL, avatarURLPrefix)
+	if !ok {
+		return
+	}
+
+	id, file, ok := strings.Cut(rest, "/")
+	if !ok {
+		return
+	}
+
+	if _, err := uuid.Parse(id); err != nil {
+		return
+	}
+
+	ext, ok := avatarFileExt(file)
+	if !ok {
+		return
+	}
+
+	keys := make([]string, len(avatarSizes))
+	for i, size := range avatarSizes {
+		keys[i] = avatarKey(id, avatarFileName(size, ext))
+	}
+
+	s.deleteBlobs(ctx, keys...)
+}
+
+func avatarKey(id, file string) string {
+	return "avatars/" + id + "/" + file
+}
+
+func avatarFileName(size int, ext string) string {
+	return fmt.Sprintf("%d.%s", size, ext)
+}
+
+// avatarFileExt returns the extension of file if it names one of the
+// thumbnails UploadAvatar makes.
+func avatarFileExt(file string) (string, bool) {
+	for _, size := range avatarSizes {
+		for _, ext := range []string{"jpg", "png"} {
+			if file == avatarFileName(size, ext) {
+				return ext, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func avatarContentType(ext string) string {
+	if ext == "jpg" {
+		return "image/jpeg"
+	}
+	return "image/png"
+}
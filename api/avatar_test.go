@@ -0,0 +1,264 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func randomImage(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+// jpegWithMetadata encodes img as a JPEG with an EXIF segment holding marker.
+func jpegWithMetadata(t *testing.T, img image.Image, marker string) []byte {
+	var encoded bytes.Buffer
+	require.NoError(t, jpeg.Encode(&encoded, img, nil))
+
+	segment := []byte("Exif\x00\x00MM\x00\x2a\x00\x00\x00\x08\x00\x00" + marker)
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(segment)+2))
+
+	data := encoded.Bytes()
+	return append(append(append(append([]byte{}, data[:2]...), app1...), segment...), data[2:]...)
+}
+
+func TestUploadAvatarApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+
+	var pngImage bytes.Buffer
+	require.NoError(t, png.Encode(&pngImage, randomImage(300, 200)))
+
+	testCases := []struct {
+		name          string
+		body          []byte
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK: JPEG",
+			body: jpegWithMetadata(t, randomImage(200, 300), "secret-location"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateUserParams) (db.User, error) {
+						require.Equal(t, user.ID, arg.ID)
+						require.Equal(t, user.Email, arg.Email)
+						require.Equal(t, user.IsEmailVerified, arg.IsEmailVerified)
+						require.True(t, strings.HasPrefix(arg.ProfilePictureUrl, avatarURLPrefix))
+						require.True(t, strings.HasSuffix(arg.ProfilePictureUrl, "/512.jpg"))
+
+						updated := user
+						updated.ProfilePictureUrl = arg.ProfilePictureUrl
+						return updated, nil
+					})
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Data avatarResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response.Data.Thumbnails, len(avatarSizes))
+				require.Equal(t, response.Data.Thumbnails[512], response.Data.ProfilePicture)
+
+				for _, size := range avatarSizes {
+					recorder := httptest.NewRecorder()
+					request, err := http.NewRequest(http.MethodGet, response.Data.Thumbnails[size], nil)
+					require.NoError(t, err)
+
+					server.router.ServeHTTP(recorder, request)
+					require.Equal(t, http.StatusOK, recorder.Code)
+					require.Equal(t, "image/jpeg", recorder.Header().Get("Content-Type"))
+					require.NotContains(t, recorder.Body.String(), "secret-location")
+					require.NotContains(t, recorder.Body.String(), "Exif")
+
+					config, err := jpeg.DecodeConfig(recorder.Body)
+					require.NoError(t, err)
+					require.Equal(t, size, config.Width)
+					require.Equal(t, size, config.Height)
+				}
+			},
+		},
+		{
+			name: "OK: PNG",
+			body: pngImage.Bytes(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateUserParams) (db.User, error) {
+						require.True(t, strings.HasSuffix(arg.ProfilePictureUrl, "/512.png"))
+						return user, nil
+					})
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "UnsupportedType",
+			body: []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+			},
+		},
+		{
+			name: "TooLarge",
+			body: append(pngImage.Bytes(), make([]byte, 64<<10)...),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidImage",
+			body: pngImage.Bytes()[:64],
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errInvalidImage.Error())
+			},
+		},
+		{
+			name: "Empty",
+			body: nil,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errEmptyAvatar.Error())
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.config.AvatarMaxSize = 64 << 10
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPut, "/api/v1/users/me/avatar", bytes.NewReader(tt.body))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, server, recorder)
+		})
+	}
+}
+
+func TestUploadAvatarReplacesPrevious(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	oldID := "0b0e2f8c-59d5-4bb5-8d2c-3c2a1f4e6a10"
+	user.ProfilePictureUrl = avatarURLPrefix + oldID + "/512.png"
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	stubAuthenticatedUser(store, user)
+	store.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+
+	server := newTestServer(t, store)
+
+	for _, size := range avatarSizes {
+		file := avatarFileName(size, "png")
+		err := server.blobs.Put(t.Context(), avatarKey(oldID, file), strings.NewReader("old"), 3, "image/png")
+		require.NoError(t, err)
+	}
+
+	var body bytes.Buffer
+	require.NoError(t, png.Encode(&body, randomImage(64, 64)))
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPut, "/api/v1/users/me/avatar", &body)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	for _, size := range avatarSizes {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, avatarURLPrefix+oldID+"/"+avatarFileName(size, "png"), nil)
+		require.NoError(t, err)
+
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusNotFound, recorder.Code)
+	}
+}
+
+func TestGetAvatarApi(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+
+	id := "0b0e2f8c-59d5-4bb5-8d2c-3c2a1f4e6a10"
+	err := server.blobs.Put(t.Context(), avatarKey(id, "64.png"), strings.NewReader("png"), 3, "image/png")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		url  string
+		code int
+	}{
+		{url: avatarURLPrefix + id + "/64.png", code: http.StatusOK},
+		{url: avatarURLPrefix + id + "/64.jpg", code: http.StatusNotFound},
+		{url: avatarURLPrefix + id + "/65.png", code: http.StatusNotFound},
+		{url: avatarURLPrefix + "not-a-uuid/64.png", code: http.StatusNotFound},
+	}
+
+	for _, tt := range testCases {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, tt.url, nil)
+		require.NoError(t, err)
+
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, tt.code, recorder.Code, tt.url)
+
+		if tt.code == http.StatusOK {
+			require.Equal(t, "image/png", recorder.Header().Get("Content-Type"))
+			require.Contains(t, recorder.Header().Get("Cache-Control"), "immutable")
+		}
+	}
+}
@@ -0,0 +1,374 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/go-chi/render"
+)
+
+const maxCommentMentions = 20
+
+var (
+	errCommentNotFound     = errors.New("comment not found")
+	errEmptyComment        = errors.New("body must not be empty")
+	errNotCommentAuthor    = errors.New("only the author can edit this comment")
+	errCannotDeleteComment = errors.New("only the author can delete this comment")
+)
+
+// mentionPattern matches "@" followed by an email address, e.g.
+// "@jane@example.com". The leading group keeps plain email addresses and
+// words like "a@b" from counting as mentions.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w.@+-])@([\w.%+-]+@[\w-]+(?:\.[\w-]+)+)`)
+
+type commentMention struct {
+	UserID    int32  `json:"userId"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Email     string `json:"email"`
+}
+
+type taskCommentResponse struct {
+	db.TaskComment
+	Mentions []commentMention `json:"mentions"`
+}
+
+type taskCommentPayload struct {
+	Body string `json:"body" validate:"required,max=10000"`
+}
+
+func (s *Server) ListTaskComments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	task, ok := s.loadVisibleTask(w, r)
+	if !ok {
+		return
+	}
+
+	comments, err := s.store.ListTaskComments(ctx, task.ID)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	commentIDs := make([]int32, len(comments))
+	for i, comment := range comments {
+		commentIDs[i] = comment.ID
+	}
+
+	mentions, err := s.store.ListTaskCommentMentions(ctx, commentIDs)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	mentionsByComment := make(map[int32][]commentMention, len(comments))
+	for _, mention := range mentions {
+		mentionsByComment[mention.CommentID] = append(mentionsByComment[mention.CommentID], commentMention{
+			UserID:    mention.UserID,
+			FirstName: mention.FirstName,
+			LastName:  mention.LastName,
+			Email:     mention.Email,
+		})
+	}
+
+	response := make([]taskCommentResponse, len(comments))
+	for i, comment := range comments {
+		response[i] = taskCommentResponse{TaskComment: comment, Mentions: mentionsByComment[comment.ID]}
+		if response[i].Mentions == nil {
+			response[i].Mentions = []commentMention{}
+		}
+	}
+
+	render.Render(w, r, SuccessfulResponse(response))
+}
+
+// CreateTaskComment posts a markdown comment on a task. Users mentioned as
+// "@email" are recorded when they can see the task; other mentions are left
+// as plain text.
+func (s *Server) CreateTaskComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	body, ok := s.decodeTaskComment(w, r)
+	if !ok {
+		return
+	}
+
+	task, ok := s.loadVisibleTask(w, r)
+	if !ok {
+		return
+	}
+
+	bodyHTML, err := util.RenderMarkdown(body)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	mentions, err := s.resolveMentions(ctx, task, body)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	principal := principalFromContext(ctx)
+
+	comment, err := s.store.CreateTaskCommentWithMentions(ctx, db.CreateTaskCommentWithMentionsParams{
+		CreateTaskCommentParams: db.CreateTaskCommentParams{
+			TaskID:   task.ID,
+			AuthorID: &principal.User.ID,
+			Body:     body,
+			BodyHtml: bodyHTML,
+		},
+		MentionIDs: mentionIDs(mentions),
+	})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponseWithCode(taskCommentResponse{TaskComment: comment, Mentions: mentions}, http.StatusCreated))
+}
+
+// UpdateTaskComment replaces the body of a comment. The previous body is kept
+// in the comment's history.
+func (s *Server) UpdateTaskComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	body, ok := s.decodeTaskComment(w, r)
+	if !ok {
+		return
+	}
+
+	task, comment, ok := s.loadTaskComment(w, r)
+	if !ok {
+		return
+	}
+
+	principal := principalFromContext(ctx)
+	if comment.AuthorID == nil || *comment.AuthorID != principal.User.ID {
+		render.Render(w, r, ErrForbidden(errNotCommentAuthor))
+		return
+	}
+
+	bodyHTML, err := util.RenderMarkdown(body)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	mentions, err := s.resolveMentions(ctx, task, body)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	comment, err = s.store.EditTaskComment(ctx, db.EditTaskCommentParams{
+		ID:         comment.ID,
+		Body:       body,
+		BodyHtml:   bodyHTML,
+		EditedBy:   &principal.User.ID,
+		MentionIDs: mentionIDs(mentions),
+	})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(taskCommentResponse{TaskComment: comment, Mentions: mentions}))
+}
+
+// DeleteTaskComment removes a comment along with its history. Besides the
+// author, anyone allowed to delete tasks can remove comments.
+func (s *Server) DeleteTaskComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	_, comment, ok := s.loadTaskComment(w, r)
+	if !ok {
+		return
+	}
+
+	principal := principalFromContext(ctx)
+	if comment.AuthorID == nil || *comment.AuthorID != principal.User.ID {
+		canDelete, err := s.hasClaim(ctx, principal, ClaimDeleteTask)
+		if err != nil {
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+		if !canDelete {
+			render.Render(w, r, ErrForbidden(errCannotDeleteComment))
+			return
+		}
+	}
+
+	if err := s.store.DeleteTaskComment(ctx, comment.ID); err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(nil))
+}
+
+// ListTaskCommentRevisions returns the earlier bodies of a comment, most
+// recent edit first.
+func (s *Server) ListTaskCommentRevisions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	_, comment, ok := s.loadTaskComment(w, r)
+	if !ok {
+		return
+	}
+
+	revisions, err := s.store.ListTaskCommentRevisions(ctx, comment.ID)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(revisions))
+}
+
+// decodeTaskComment reads and sanitizes the comment body, writing the error
+// response itself when it cannot.
+func (s *Server) decodeTaskComment(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var payload taskCommentPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return "", false
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return "", false
+	}
+
+	body := util.SanitizeMultilineInput(payload.Body)
+	if body == "" {
+		render.Render(w, r, ErrInvalidRequest(errEmptyComment))
+		return "", false
+	}
+
+	return body, true
+}
+
+// loadTaskComment fetches the comment identified by the "commentId" URL
+// parameter on the task identified by "id".
+func (s *Server) loadTaskComment(w http.ResponseWriter, r *http.Request) (db.Task, db.TaskComment, bool) {
+	commentID, err := parseIDParam(r, "commentId")
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return db.Task{}, db.TaskComment{}, false
+	}
+
+	task, ok := s.loadVisibleTask(w, r)
+	if !ok {
+		return db.Task{}, db.TaskComment{}, false
+	}
+
+	comment, err := s.store.GetTaskComment(r.Context(), commentID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			render.Render(w, r, ErrNotFound(errCommentNotFound))
+			return db.Task{}, db.TaskComment{}, false
+		}
+		render.Render(w, r, ErrInternalServer())
+		return db.Task{}, db.TaskComment{}, false
+	}
+
+	if comment.TaskID != task.ID {
+		render.Render(w, r, ErrNotFound(errCommentNotFound))
+		return db.Task{}, db.TaskComment{}, false
+	}
+
+	return task, comment, true
+}
+
+// resolveMentions looks up the users mentioned in body, keeping only those
+// who can see the task: members of its team, or the creator of a personal
+// task.
+func (s *Server) resolveMentions(ctx context.Context, task db.Task, body string) ([]commentMention, error) {
+	emails := parseMentions(body)
+	if len(emails) == 0 {
+		return []commentMention{}, nil
+	}
+
+	users, err := s.store.ListUsersByEmails(ctx, emails)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make(map[int32]bool, len(users))
+	if task.TeamID != nil && len(users) > 0 {
+		userIDs := make([]int32, len(users))
+		for i, user := range users {
+			userIDs[i] = user.ID
+		}
+
+		members, err := s.store.FilterTeamMembers(ctx, db.FilterTeamMembersParams{
+			TeamID:  *task.TeamID,
+			UserIds: userIDs,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, userID := range members {
+			allowed[userID] = true
+		}
+	} else if task.CreatedBy != nil {
+		allowed[*task.CreatedBy] = true
+	}
+
+	mentions := []commentMention{}
+	for _, user := range users {
+		if allowed[user.ID] {
+			mentions = append(mentions, commentMention{
+				UserID:    user.ID,
+				FirstName: user.FirstName,
+				LastName:  user.LastName,
+				Email:     user.Email,
+			})
+		}
+	}
+
+	return mentions, nil
+}
+
+// parseMentions returns the lowercased, de-duplicated email addresses
+// mentioned in body, up to maxCommentMentions of them.
+func parseMentions(body string) []string {
+	var emails []string
+	seen := map[string]bool{}
+
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		email := strings.ToLower(match[1])
+		if seen[email] {
+			continue
+		}
+
+		seen[email] = true
+		emails = append(emails, email)
+		if len(emails) == maxCommentMentions {
+			break
+		}
+	}
+
+	return emails
+}
+
+func mentionIDs(mentions []commentMention) []int32 {
+	ids := make([]int32, len(mentions))
+	for i, mention := range mentions {
+		ids[i] = mention.UserID
+	}
+
+	return ids
+}
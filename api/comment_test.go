@@ -0,0 +1,379 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func randomTaskComment(taskID, authorID int32) db.TaskComment {
+	return db.TaskComment{
+		ID:        int32(util.RandomInt(1, 1000)),
+		TaskID:    taskID,
+		AuthorID:  &authorID,
+		Body:      util.RandomString(20),
+		BodyHtml:  "<p>" + util.RandomString(20) + "</p>",
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+func TestCreateTaskCommentApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	mentioned := randomAuthenticatedUser(t)
+	outsider := randomAuthenticatedUser(t)
+
+	personalTask := randomTask()
+	personalTask.CreatedBy = &user.ID
+
+	teamID := int32(util.RandomInt(1, 1000))
+	teamTask := randomTask()
+	teamTask.TeamID = &teamID
+
+	othersTask := randomTask()
+	othersTask.CreatedBy = &outsider.ID
+
+	testCases := []struct {
+		name          string
+		task          db.Task
+		body          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK: Team Task With Mentions",
+			task: teamTask,
+			body: fmt.Sprintf("**ping** @%s and @%s <script>alert(1)</script>", mentioned.Email, outsider.Email),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(teamTask.ID)).Times(1).Return(teamTask, nil)
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: teamID, UserID: user.ID})).
+					Times(1).
+					Return(db.TeamMember{TeamID: teamID, UserID: user.ID}, nil)
+				store.EXPECT().
+					ListUsersByEmails(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ListUsersByEmailsRow{
+						{ID: mentioned.ID, Email: mentioned.Email},
+						{ID: outsider.ID, Email: outsider.Email},
+					}, nil)
+				store.EXPECT().
+					FilterTeamMembers(gomock.Any(), gomock.Eq(db.FilterTeamMembersParams{TeamID: teamID, UserIds: []int32{mentioned.ID, outsider.ID}})).
+					Times(1).
+					Return([]int32{mentioned.ID}, nil)
+				store.EXPECT().
+					CreateTaskCommentWithMentions(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateTaskCommentWithMentionsParams) (db.TaskComment, error) {
+						require.Equal(t, teamTask.ID, arg.TaskID)
+						require.Equal(t, user.ID, *arg.AuthorID)
+						require.Contains(t, arg.BodyHtml, "<strong>ping</strong>")
+						require.NotContains(t, arg.BodyHtml, "<script")
+						require.Equal(t, []int32{mentioned.ID}, arg.MentionIDs)
+						return db.TaskComment{ID: 1, TaskID: arg.TaskID, AuthorID: arg.AuthorID, Body: arg.Body, BodyHtml: arg.BodyHtml}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var response struct {
+					Data taskCommentResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response.Data.Mentions, 1)
+				require.Equal(t, mentioned.ID, response.Data.Mentions[0].UserID)
+			},
+		},
+		{
+			name: "OK: Personal Task",
+			task: personalTask,
+			body: "  first line\r\nsecond line  ",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(personalTask.ID)).Times(1).Return(personalTask, nil)
+				store.EXPECT().ListUsersByEmails(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					CreateTaskCommentWithMentions(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateTaskCommentWithMentionsParams) (db.TaskComment, error) {
+						require.Equal(t, "first line\nsecond line", arg.Body)
+						require.Empty(t, arg.MentionIDs)
+						return db.TaskComment{ID: 1, TaskID: arg.TaskID, Body: arg.Body}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound: Task Not Visible",
+			task: othersTask,
+			body: "hello",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(othersTask.ID)).Times(1).Return(othersTask, nil)
+				store.EXPECT().ListTaskAssignees(gomock.Any(), gomock.Eq(othersTask.ID)).Times(1).Return([]db.ListTaskAssigneesRow{}, nil)
+				store.EXPECT().CreateTaskCommentWithMentions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errTaskNotFound.Error())
+			},
+		},
+		{
+			name: "BadRequest: Empty Body",
+			task: personalTask,
+			body: " \x00 ",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errEmptyComment.Error())
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(map[string]any{"body": tt.body})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/tasks/%d/comments", tt.task.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListTaskCommentsApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	task := randomTask()
+	task.CreatedBy = &user.ID
+
+	comments := []db.TaskComment{randomTaskComment(task.ID, user.ID), randomTaskComment(task.ID, user.ID)}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	stubAuthenticatedUser(store, user)
+	store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+	store.EXPECT().ListTaskComments(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(comments, nil)
+	store.EXPECT().
+		ListTaskCommentMentions(gomock.Any(), gomock.Eq([]int32{comments[0].ID, comments[1].ID})).
+		Times(1).
+		Return([]db.ListTaskCommentMentionsRow{{CommentID: comments[1].ID, UserID: user.ID, Email: user.Email}}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/tasks/%d/comments", task.ID), nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Data []taskCommentResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response.Data, 2)
+	require.Empty(t, response.Data[0].Mentions)
+	require.Len(t, response.Data[1].Mentions, 1)
+	require.Equal(t, user.ID, response.Data[1].Mentions[0].UserID)
+}
+
+func TestUpdateTaskCommentApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	other := randomAuthenticatedUser(t)
+	task := randomTask()
+	task.CreatedBy = &user.ID
+
+	ownComment := randomTaskComment(task.ID, user.ID)
+	othersComment := randomTaskComment(task.ID, other.ID)
+	misplacedComment := randomTaskComment(task.ID+1, user.ID)
+
+	testCases := []struct {
+		name          string
+		comment       db.TaskComment
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			comment: ownComment,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskComment(gomock.Any(), gomock.Eq(ownComment.ID)).Times(1).Return(ownComment, nil)
+				store.EXPECT().
+					EditTaskComment(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.EditTaskCommentParams) (db.TaskComment, error) {
+						require.Equal(t, ownComment.ID, arg.ID)
+						require.Equal(t, "edited _text_", arg.Body)
+						require.Contains(t, arg.BodyHtml, "<em>text</em>")
+						require.Equal(t, user.ID, *arg.EditedBy)
+						return db.TaskComment{ID: arg.ID, Body: arg.Body, BodyHtml: arg.BodyHtml}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:    "Forbidden: Not Author",
+			comment: othersComment,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskComment(gomock.Any(), gomock.Eq(othersComment.ID)).Times(1).Return(othersComment, nil)
+				store.EXPECT().EditTaskComment(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errNotCommentAuthor.Error())
+			},
+		},
+		{
+			name:    "NotFound: Comment On Another Task",
+			comment: misplacedComment,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskComment(gomock.Any(), gomock.Eq(misplacedComment.ID)).Times(1).Return(misplacedComment, nil)
+				store.EXPECT().EditTaskComment(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user)
+			store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).AnyTimes().Return(task, nil)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(map[string]any{"body": "edited _text_"})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/tasks/%d/comments/%d", task.ID, tt.comment.ID)
+			request, err := http.NewRequest(http.MethodPatch, url, bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteTaskCommentApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	other := randomAuthenticatedUser(t)
+	task := randomTask()
+	task.CreatedBy = &user.ID
+
+	ownComment := randomTaskComment(task.ID, user.ID)
+	othersComment := randomTaskComment(task.ID, other.ID)
+
+	testCases := []struct {
+		name          string
+		comment       db.TaskComment
+		claims        []string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK: Author",
+			comment: ownComment,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DeleteTaskComment(gomock.Any(), gomock.Eq(ownComment.ID)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:    "OK: Moderator",
+			comment: othersComment,
+			claims:  []string{ClaimDeleteTask},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DeleteTaskComment(gomock.Any(), gomock.Eq(othersComment.ID)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:    "Forbidden",
+			comment: othersComment,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DeleteTaskComment(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, tt.claims...)
+			store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+			store.EXPECT().GetTaskComment(gomock.Any(), gomock.Eq(tt.comment.ID)).Times(1).Return(tt.comment, nil)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/tasks/%d/comments/%d", task.ID, tt.comment.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestParseMentions(t *testing.T) {
+	body := "hi @Jane@Example.com, cc @bob@example.org and @jane@example.com. Mail me at me@example.com or @nobody"
+	require.Equal(t, []string{"jane@example.com", "bob@example.org"}, parseMentions(body))
+}
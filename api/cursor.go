@@ -0,0 +1,33 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var errInvalidCursor = errors.New("cursor is invalid")
+
+// encodeCursor turns pagination state into an opaque string for clients to
+// hand back unchanged.
+func encodeCursor(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(cursor string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return errInvalidCursor
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return errInvalidCursor
+	}
+
+	return nil
+}
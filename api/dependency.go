@@ -0,0 +1,357 @@
+package api
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/go-chi/render"
+)
+
+var (
+	errLinkedTaskNotFound = errors.New("linked task not found")
+	errLinkOtherScope     = errors.New("linked tasks must belong to the same team, or both be personal tasks of the same person")
+	errDependencyCycle    = errors.New("this link would make the task depend on itself")
+	errDependencyNotFound = errors.New("the task is not blocked by this task")
+	errTaskBlocked        = errors.New("task is blocked by open tasks")
+)
+
+type setTaskParentPayload struct {
+	// ParentID is nil to turn a subtask back into a top level task.
+	ParentID *int32 `json:"parentId" validate:"omitempty,min=1"`
+}
+
+type addTaskBlockerPayload struct {
+	TaskID int32 `json:"taskId" validate:"required,min=1"`
+}
+
+type taskDependencyNode struct {
+	db.Task
+	Done bool `json:"done"`
+}
+
+type subtaskLink struct {
+	ParentID int32 `json:"parentId"`
+	TaskID   int32 `json:"taskId"`
+}
+
+// taskDependencyGraph is everything a planning view needs around one task:
+// its subtasks at every depth, the tasks blocking it at every depth and the
+// tasks it blocks directly. Links are listed separately from the tasks so a
+// task reached along several paths appears once.
+type taskDependencyGraph struct {
+	RootID   int32                    `json:"rootId"`
+	Tasks    []taskDependencyNode     `json:"tasks"`
+	Subtasks []subtaskLink            `json:"subtasks"`
+	Blockers []db.ListBlockerGraphRow `json:"blockers"`
+}
+
+// SetTaskParent makes the task a subtask of another one, or a top level task
+// again when parentId is null.
+func (s *Server) SetTaskParent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload setTaskParentPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	task, ok := s.loadVisibleTask(w, r)
+	if !ok {
+		return
+	}
+
+	if payload.ParentID != nil {
+		if _, ok := s.loadLinkedTask(w, r, task, *payload.ParentID); !ok {
+			return
+		}
+	}
+
+	task, err := s.store.SetTaskParent(ctx, db.SetTaskParentParams{
+		ID:       task.ID,
+		ParentID: payload.ParentID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrDependencyCycle) {
+			render.Render(w, r, ErrInvalidRequestWithCode(errDependencyCycle, http.StatusConflict))
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(task))
+}
+
+// AddTaskBlocker records that the task in the payload blocks this one.
+func (s *Server) AddTaskBlocker(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload addTaskBlockerPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	task, ok := s.loadVisibleTask(w, r)
+	if !ok {
+		return
+	}
+
+	blocker, ok := s.loadLinkedTask(w, r, task, payload.TaskID)
+	if !ok {
+		return
+	}
+
+	err := s.store.AddTaskDependency(ctx, db.CreateTaskDependencyParams{
+		BlockerID: blocker.ID,
+		BlockedID: task.ID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrDependencyCycle) {
+			render.Render(w, r, ErrInvalidRequestWithCode(errDependencyCycle, http.StatusConflict))
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponseWithCode(blocker, http.StatusCreated))
+}
+
+func (s *Server) RemoveTaskBlocker(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	blockerID, err := parseIDParam(r, "blockerId")
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	task, ok := s.loadVisibleTask(w, r)
+	if !ok {
+		return
+	}
+
+	deleted, err := s.store.DeleteTaskDependency(ctx, db.DeleteTaskDependencyParams{
+		BlockerID: blockerID,
+		BlockedID: task.ID,
+	})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	if deleted == 0 {
+		render.Render(w, r, ErrNotFound(errDependencyNotFound))
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(nil))
+}
+
+// GetTaskDependencies returns the dependency graph around a task. Tasks the
+// caller cannot see are left out along with their links.
+func (s *Server) GetTaskDependencies(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	task, ok := s.loadVisibleTask(w, r)
+	if !ok {
+		return
+	}
+
+	graph, err := s.taskDependencyGraph(ctx, task)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(graph))
+}
+
+func (s *Server) taskDependencyGraph(ctx context.Context, root db.Task) (taskDependencyGraph, error) {
+	tasks := map[int32]db.Task{root.ID: root}
+
+	subtree, err := s.store.ListSubtaskTree(ctx, root.ID)
+	if err != nil {
+		return taskDependencyGraph{}, err
+	}
+	for _, task := range subtree {
+		tasks[task.ID] = task
+	}
+
+	blockerEdges, err := s.store.ListBlockerGraph(ctx, root.ID)
+	if err != nil {
+		return taskDependencyGraph{}, err
+	}
+
+	var missing []int32
+	for _, edge := range blockerEdges {
+		if _, ok := tasks[edge.BlockerID]; !ok && !slices.Contains(missing, edge.BlockerID) {
+			missing = append(missing, edge.BlockerID)
+		}
+	}
+
+	if len(missing) > 0 {
+		blockers, err := s.store.ListTasksByIDs(ctx, missing)
+		if err != nil {
+			return taskDependencyGraph{}, err
+		}
+		for _, task := range blockers {
+			tasks[task.ID] = task
+		}
+	}
+
+	blocked, err := s.store.ListBlockedTasks(ctx, root.ID)
+	if err != nil {
+		return taskDependencyGraph{}, err
+	}
+	for _, task := range blocked {
+		tasks[task.ID] = task
+		blockerEdges = append(blockerEdges, db.ListBlockerGraphRow{BlockerID: root.ID, BlockedID: task.ID})
+	}
+
+	statuses, err := s.store.ListTaskStatuses(ctx, root.TeamID)
+	if err != nil {
+		return taskDependencyGraph{}, err
+	}
+
+	done := make(map[int32]bool, len(statuses))
+	for _, status := range statuses {
+		done[status.ID] = status.Category == "done"
+	}
+
+	principal := principalFromContext(ctx)
+
+	graph := taskDependencyGraph{
+		RootID:   root.ID,
+		Tasks:    []taskDependencyNode{},
+		Subtasks: []subtaskLink{},
+		Blockers: []db.ListBlockerGraphRow{},
+	}
+
+	for id, task := range tasks {
+		// Links never cross teams, so only personal tasks can be hidden from
+		// someone who sees the root.
+		if id != root.ID && (root.TeamID == nil || !sameTeam(task.TeamID, root.TeamID)) {
+			canView, err := s.canViewTask(ctx, principal, task)
+			if err != nil {
+				return taskDependencyGraph{}, err
+			}
+			if !canView {
+				delete(tasks, id)
+				continue
+			}
+		}
+
+		graph.Tasks = append(graph.Tasks, taskDependencyNode{Task: task, Done: done[task.StatusID]})
+	}
+
+	slices.SortFunc(graph.Tasks, func(a, b taskDependencyNode) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	for _, task := range subtree {
+		if _, ok := tasks[task.ID]; ok && task.ParentID != nil {
+			if _, ok := tasks[*task.ParentID]; ok {
+				graph.Subtasks = append(graph.Subtasks, subtaskLink{ParentID: *task.ParentID, TaskID: task.ID})
+			}
+		}
+	}
+
+	for _, edge := range blockerEdges {
+		_, blockerVisible := tasks[edge.BlockerID]
+		_, blockedVisible := tasks[edge.BlockedID]
+		if blockerVisible && blockedVisible {
+			graph.Blockers = append(graph.Blockers, edge)
+		}
+	}
+
+	return graph, nil
+}
+
+// loadLinkedTask fetches the task that is about to become the parent or a
+// blocker of task. The caller has to be able to see it, and only tasks of the
+// same team, or personal tasks of the same creator, can be linked. Visibility
+// is checked first so the scope error cannot be used to probe hidden IDs.
+func (s *Server) loadLinkedTask(w http.ResponseWriter, r *http.Request, task db.Task, id int32) (db.Task, bool) {
+	ctx := r.Context()
+
+	linked, err := s.store.GetTask(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			render.Render(w, r, ErrNotFound(errLinkedTaskNotFound))
+			return db.Task{}, false
+		}
+		render.Render(w, r, ErrInternalServer())
+		return db.Task{}, false
+	}
+
+	canView, err := s.canViewTask(ctx, principalFromContext(ctx), linked)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return db.Task{}, false
+	}
+	if !canView {
+		render.Render(w, r, ErrNotFound(errLinkedTaskNotFound))
+		return db.Task{}, false
+	}
+
+	samePerson := task.CreatedBy != nil && linked.CreatedBy != nil && *task.CreatedBy == *linked.CreatedBy
+	if !sameTeam(task.TeamID, linked.TeamID) || (task.TeamID == nil && !samePerson) {
+		render.Render(w, r, ErrInvalidRequest(errLinkOtherScope))
+		return db.Task{}, false
+	}
+
+	return linked, true
+}
+
+// visibleTasks returns the tasks the principal can see, in order.
+func (s *Server) visibleTasks(ctx context.Context, principal *Principal, tasks []db.Task) ([]db.Task, error) {
+	visible := make([]db.Task, 0, len(tasks))
+	for _, task := range tasks {
+		canView, err := s.canViewTask(ctx, principal, task)
+		if err != nil {
+			return nil, err
+		}
+		if canView {
+			visible = append(visible, task)
+		}
+	}
+	return visible, nil
+}
+
+// openBlockersError describes the open tasks that keep a task from being
+// done. Callers pass only the blockers the caller can see; a personal task
+// can be blocked by tasks of its creator that its assignees cannot see.
+func openBlockersError(blockers []db.Task) error {
+	if len(blockers) == 0 {
+		return errTaskBlocked
+	}
+
+	ids := make([]string, len(blockers))
+	for i, blocker := range blockers {
+		ids[i] = fmt.Sprint(blocker.ID)
+	}
+	return fmt.Errorf("%w: %s", errTaskBlocked, strings.Join(ids, ", "))
+}
@@ -0,0 +1,422 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSetTaskParentApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	other := randomAuthenticatedUser(t)
+
+	task := randomTask()
+	task.CreatedBy = &user.ID
+
+	parent := randomTask()
+	parent.ID = task.ID + 1
+	parent.CreatedBy = &user.ID
+
+	othersTask := randomTask()
+	othersTask.ID = task.ID + 2
+	othersTask.CreatedBy = &other.ID
+
+	teamID := int32(util.RandomInt(1, 1000))
+	teamTask := randomTask()
+	teamTask.ID = task.ID + 3
+	teamTask.TeamID = &teamID
+
+	testCases := []struct {
+		name          string
+		payload       map[string]any
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			payload: map[string]any{"parentId": parent.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(parent.ID)).Times(1).Return(parent, nil)
+				store.EXPECT().
+					SetTaskParent(gomock.Any(), gomock.Eq(db.SetTaskParentParams{ID: task.ID, ParentID: &parent.ID})).
+					Times(1).
+					Return(db.Task{ID: task.ID, ParentID: &parent.ID}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Data db.Task `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, parent.ID, *response.Data.ParentID)
+			},
+		},
+		{
+			name:    "OK: Detach",
+			payload: map[string]any{"parentId": nil},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().
+					SetTaskParent(gomock.Any(), gomock.Eq(db.SetTaskParentParams{ID: task.ID})).
+					Times(1).
+					Return(task, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:    "Conflict: Cycle",
+			payload: map[string]any{"parentId": parent.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(parent.ID)).Times(1).Return(parent, nil)
+				store.EXPECT().SetTaskParent(gomock.Any(), gomock.Any()).Times(1).Return(db.Task{}, db.ErrDependencyCycle)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errDependencyCycle.Error())
+			},
+		},
+		{
+			name:    "BadRequest: Other Person",
+			payload: map[string]any{"parentId": othersTask.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(othersTask.ID)).Times(1).Return(othersTask, nil)
+				store.EXPECT().
+					ListTaskAssignees(gomock.Any(), gomock.Eq(othersTask.ID)).
+					Times(1).
+					Return([]db.ListTaskAssigneesRow{{UserID: user.ID}}, nil)
+				store.EXPECT().SetTaskParent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errLinkOtherScope.Error())
+			},
+		},
+		{
+			name:    "BadRequest: Other Team",
+			payload: map[string]any{"parentId": teamTask.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(teamTask.ID)).Times(1).Return(teamTask, nil)
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: teamID, UserID: user.ID})).
+					Times(1).
+					Return(db.TeamMember{TeamID: teamID, UserID: user.ID}, nil)
+				store.EXPECT().SetTaskParent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errLinkOtherScope.Error())
+			},
+		},
+		{
+			// A task the caller cannot see is reported missing, not out of
+			// scope, so IDs of hidden tasks cannot be probed.
+			name:    "NotFound: Hidden Person",
+			payload: map[string]any{"parentId": othersTask.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(othersTask.ID)).Times(1).Return(othersTask, nil)
+				store.EXPECT().ListTaskAssignees(gomock.Any(), gomock.Eq(othersTask.ID)).Times(1).Return([]db.ListTaskAssigneesRow{}, nil)
+				store.EXPECT().SetTaskParent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errLinkedTaskNotFound.Error())
+			},
+		},
+		{
+			name:    "NotFound: Hidden Team",
+			payload: map[string]any{"parentId": teamTask.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(teamTask.ID)).Times(1).Return(teamTask, nil)
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: teamID, UserID: user.ID})).
+					Times(1).
+					Return(db.TeamMember{}, db.ErrRecordNotFound)
+				store.EXPECT().SetTaskParent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errLinkedTaskNotFound.Error())
+			},
+		},
+		{
+			name:    "NotFound: Parent",
+			payload: map[string]any{"parentId": parent.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(parent.ID)).Times(1).Return(db.Task{}, db.ErrRecordNotFound)
+				store.EXPECT().SetTaskParent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errLinkedTaskNotFound.Error())
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimEditTask)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/tasks/%d/parent", task.ID)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAddTaskBlockerApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	teamID := int32(util.RandomInt(1, 1000))
+
+	task := randomTask()
+	task.TeamID = &teamID
+
+	blocker := randomTask()
+	blocker.ID = task.ID + 1
+	blocker.TeamID = &teamID
+
+	member := db.TeamMember{TeamID: teamID, UserID: user.ID}
+
+	testCases := []struct {
+		name          string
+		payload       map[string]any
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			payload: map[string]any{"taskId": blocker.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(blocker.ID)).Times(1).Return(blocker, nil)
+				store.EXPECT().GetTeamMember(gomock.Any(), gomock.Any()).Times(2).Return(member, nil)
+				store.EXPECT().
+					AddTaskDependency(gomock.Any(), gomock.Eq(db.CreateTaskDependencyParams{BlockerID: blocker.ID, BlockedID: task.ID})).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:    "Conflict: Cycle",
+			payload: map[string]any{"taskId": blocker.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(blocker.ID)).Times(1).Return(blocker, nil)
+				store.EXPECT().GetTeamMember(gomock.Any(), gomock.Any()).Times(2).Return(member, nil)
+				store.EXPECT().AddTaskDependency(gomock.Any(), gomock.Any()).Times(1).Return(db.ErrDependencyCycle)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errDependencyCycle.Error())
+			},
+		},
+		{
+			name:    "BadRequest: Missing Task",
+			payload: map[string]any{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().AddTaskDependency(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimEditTask)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/tasks/%d/blockers", task.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRemoveTaskBlockerApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	task := randomTask()
+	task.CreatedBy = &user.ID
+	blockerID := task.ID + 1
+
+	testCases := []struct {
+		name    string
+		deleted int64
+		code    int
+	}{
+		{name: "OK", deleted: 1, code: http.StatusOK},
+		{name: "NotFound", deleted: 0, code: http.StatusNotFound},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimEditTask)
+			store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+			store.EXPECT().
+				DeleteTaskDependency(gomock.Any(), gomock.Eq(db.DeleteTaskDependencyParams{BlockerID: blockerID, BlockedID: task.ID})).
+				Times(1).
+				Return(tt.deleted, nil)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/tasks/%d/blockers/%d", task.ID, blockerID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tt.code, recorder.Code)
+		})
+	}
+}
+
+func TestGetTaskDependenciesApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	other := randomAuthenticatedUser(t)
+
+	newTask := func(id int32, createdBy int32, parentID *int32, statusID int32) db.Task {
+		task := randomTask()
+		task.ID = id
+		task.CreatedBy = &createdBy
+		task.ParentID = parentID
+		task.StatusID = statusID
+		return task
+	}
+
+	const todo, done = int32(1), int32(3)
+
+	root := newTask(10, user.ID, nil, todo)
+	child := newTask(11, user.ID, &root.ID, done)
+	grandchild := newTask(12, user.ID, &child.ID, todo)
+	blocker := newTask(20, user.ID, nil, done)
+	upstream := newTask(21, user.ID, nil, todo)
+	hidden := newTask(22, other.ID, nil, todo)
+	blocked := newTask(30, user.ID, nil, todo)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	stubAuthenticatedUser(store, user)
+	store.EXPECT().GetTask(gomock.Any(), gomock.Eq(root.ID)).Times(1).Return(root, nil)
+	store.EXPECT().ListSubtaskTree(gomock.Any(), gomock.Eq(root.ID)).Times(1).Return([]db.Task{child, grandchild}, nil)
+	store.EXPECT().
+		ListBlockerGraph(gomock.Any(), gomock.Eq(root.ID)).
+		Times(1).
+		Return([]db.ListBlockerGraphRow{
+			{BlockerID: blocker.ID, BlockedID: root.ID},
+			{BlockerID: upstream.ID, BlockedID: blocker.ID},
+			{BlockerID: hidden.ID, BlockedID: blocker.ID},
+		}, nil)
+	store.EXPECT().
+		ListTasksByIDs(gomock.Any(), gomock.Eq([]int32{blocker.ID, upstream.ID, hidden.ID})).
+		Times(1).
+		Return([]db.Task{blocker, upstream, hidden}, nil)
+	store.EXPECT().ListBlockedTasks(gomock.Any(), gomock.Eq(root.ID)).Times(1).Return([]db.Task{blocked}, nil)
+	store.EXPECT().
+		ListTaskStatuses(gomock.Any(), gomock.Nil()).
+		Times(1).
+		Return([]db.TaskStatus{{ID: todo, Category: "todo"}, {ID: done, Category: "done"}}, nil)
+	store.EXPECT().ListTaskAssignees(gomock.Any(), gomock.Eq(hidden.ID)).Times(1).Return([]db.ListTaskAssigneesRow{}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/tasks/%d/dependencies", root.ID), nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Data struct {
+			RootID int32 `json:"rootId"`
+			Tasks  []struct {
+				ID   int32 `json:"id"`
+				Done bool  `json:"done"`
+			} `json:"tasks"`
+			Subtasks []subtaskLink            `json:"subtasks"`
+			Blockers []db.ListBlockerGraphRow `json:"blockers"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+
+	require.Equal(t, root.ID, response.Data.RootID)
+
+	ids := make([]int32, len(response.Data.Tasks))
+	for i, task := range response.Data.Tasks {
+		ids[i] = task.ID
+		require.Equal(t, task.ID == child.ID || task.ID == blocker.ID, task.Done, task.ID)
+	}
+	require.Equal(t, []int32{10, 11, 12, 20, 21, 30}, ids)
+
+	require.ElementsMatch(t, []subtaskLink{
+		{ParentID: root.ID, TaskID: child.ID},
+		{ParentID: child.ID, TaskID: grandchild.ID},
+	}, response.Data.Subtasks)
+
+	require.ElementsMatch(t, []db.ListBlockerGraphRow{
+		{BlockerID: blocker.ID, BlockedID: root.ID},
+		{BlockerID: upstream.ID, BlockedID: blocker.ID},
+		{BlockerID: root.ID, BlockedID: blocked.ID},
+	}, response.Data.Blockers)
+}
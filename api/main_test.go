@@ -1,18 +1,42 @@
 package api
 
 import (
+	"io"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/bolusarz/task-manager/blob"
 	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/mail"
+	"github.com/bolusarz/task-manager/recurrence"
+	"github.com/bolusarz/task-manager/stream"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/stretchr/testify/require"
 )
 
 func newTestServer(t *testing.T, store db.Store) *Server {
-	return NewServer(store)
+	config := util.Config{
+		TokenSymmetricKey:    util.RandomString(32),
+		AccessTokenDuration:  time.Minute,
+		RefreshTokenDuration: time.Hour,
+	}
+
+	mailer := mail.NewLogMailer(io.Discard, "")
+	recurrences := recurrence.NewGenerator(store, config.RecurrenceInterval)
+
+	server, err := NewServer(store, config, mailer, recurrences, stream.NewHub())
+	require.NoError(t, err)
+
+	server.blobs = blob.NewLocalStore(t.TempDir())
+
+	return server
 }
 
 func TestMain(m *testing.M) {
 	validate.RegisterValidation("strong", IsPasswordStrong)
+	validate.RegisterValidation("iana_timezone", IsTimezone)
+	validate.RegisterValidation("event_type", IsEventType)
 
 	exitCode := m.Run()
 
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/token"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+const (
+	authorizationHeaderKey  = "Authorization"
+	authorizationTypeBearer = "bearer"
+	accessTokenQueryKey     = "access_token"
+)
+
+type contextKey string
+
+const principalContextKey contextKey = "principal"
+
+var (
+	errMissingAuthorization = errors.New("authorization header is not provided")
+	errInvalidAuthorization = errors.New("invalid authorization header format")
+	errTokenRevoked         = errors.New("token has been revoked")
+)
+
+// Principal is the authenticated caller of a request. Its claims are
+// resolved lazily and cached for the lifetime of the request.
+type Principal struct {
+	User    db.User
+	Payload *token.Payload
+
+	claimsOnce sync.Once
+	claims     map[string]bool
+	claimsErr  error
+}
+
+// Authenticate validates the bearer token on the request and stores the
+// resulting Principal in the request context.
+func (s *Server) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		header := r.Header.Get(authorizationHeaderKey)
+		if header == "" {
+			unauthorized(w, r, errMissingAuthorization)
+			return
+		}
+
+		fields := strings.Fields(header)
+		if len(fields) != 2 || !strings.EqualFold(fields[0], authorizationTypeBearer) {
+			unauthorized(w, r, errInvalidAuthorization)
+			return
+		}
+
+		payload, err := s.tokenMaker.ValidateToken(fields[1], token.TokenTypeAccessToken)
+		if err != nil {
+			if errors.Is(err, token.ErrExpiredToken) {
+				unauthorized(w, r, token.ErrExpiredToken)
+				return
+			}
+			unauthorized(w, r, token.ErrInvalidToken)
+			return
+		}
+
+		user, err := s.tokenUser(ctx, payload)
+		if err != nil {
+			if errors.Is(err, token.ErrInvalidToken) || errors.Is(err, errTokenRevoked) {
+				unauthorized(w, r, err)
+				return
+			}
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+
+		principal := &Principal{
+			User:    user,
+			Payload: payload,
+		}
+
+		audit := db.AuditContextFrom(ctx)
+		audit.ActorID = user.ID
+
+		ctx = context.WithValue(ctx, principalContextKey, principal)
+		ctx = db.WithAuditContext(ctx, audit)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// tokenUser loads the user a valid access token was issued to, failing with
+// token.ErrInvalidToken when they are gone and errTokenRevoked when the token
+// no longer stands.
+func (s *Server) tokenUser(ctx context.Context, payload *token.Payload) (db.User, error) {
+	user, err := s.store.GetUserById(ctx, payload.UserID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return db.User{}, token.ErrInvalidToken
+		}
+		return db.User{}, err
+	}
+
+	// Tokens are bound to the address they were issued for, so changing it
+	// signs the user out everywhere.
+	if !strings.EqualFold(user.Email, payload.Email) {
+		return db.User{}, errTokenRevoked
+	}
+
+	// Tokens issued before the last password change belong to sessions that
+	// were revoked by it.
+	if user.PasswordChangedAt != nil && payload.IssuedAt.Before(*user.PasswordChangedAt) {
+		return db.User{}, errTokenRevoked
+	}
+
+	return user, nil
+}
+
+// AuditContext attributes the writes of a request to its request ID and
+// client IP. It must run after middleware.RequestID and middleware.RealIP;
+// Authenticate adds the caller.
+func (s *Server) AuditContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := db.WithAuditContext(r.Context(), db.AuditContext{
+			RequestID: middleware.GetReqID(r.Context()),
+			ClientIP:  clientIP(r),
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// StreamTokenFromQuery moves the access token of a stream request from the
+// access_token query parameter, since browsers cannot set headers there, to
+// the Authorization header where Authenticate looks for it. It must run
+// before middleware.Logger, so the token is out of the URL when it is logged.
+func (s *Server) StreamTokenFromQuery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isStreamRequest(r) || !r.URL.Query().Has(accessTokenQueryKey) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		r = r.Clone(r.Context())
+
+		query := r.URL.Query()
+		if accessToken := query.Get(accessTokenQueryKey); accessToken != "" && r.Header.Get(authorizationHeaderKey) == "" {
+			r.Header.Set(authorizationHeaderKey, authorizationTypeBearer+" "+accessToken)
+		}
+
+		query.Del(accessTokenQueryKey)
+		r.URL.RawQuery = query.Encode()
+		r.RequestURI = r.URL.RequestURI()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// principalFromContext returns the caller stored by Authenticate. It must only
+// be used by handlers mounted behind that middleware.
+func principalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalContextKey).(*Principal)
+	return principal
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request, err error) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	render.Render(w, r, ErrUnauthorized(err))
+}
@@ -0,0 +1,238 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/token"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/go-chi/render"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func addAuthorization(
+	t *testing.T,
+	request *http.Request,
+	tokenMaker token.TokenMaker,
+	authorizationType string,
+	user db.User,
+	duration time.Duration,
+) {
+	accessToken, payload, err := tokenMaker.CreateToken(user.ID, user.Email, token.TokenTypeAccessToken, duration)
+	require.NoError(t, err)
+	require.NotEmpty(t, payload)
+
+	request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationType, accessToken))
+}
+
+func randomAuthenticatedUser(t *testing.T) db.User {
+	user, _ := randomUser(t)
+	user.ID = int32(util.RandomInt(1, 1000))
+	return user
+}
+
+func stubAuthenticatedUser(store *mockdb.MockStore, user db.User, claims ...string) {
+	store.EXPECT().
+		GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+		AnyTimes().
+		Return(user, nil)
+	store.EXPECT().
+		ListUserClaims(gomock.Any(), gomock.Eq(user.ID)).
+		AnyTimes().
+		Return(claims, nil)
+}
+
+func TestAuthenticateMiddleware(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.TokenMaker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.TokenMaker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), user.Email)
+			},
+		},
+		{
+			name:      "Unauthorized: No Authorization",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.TokenMaker) {},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserById(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errMissingAuthorization.Error())
+			},
+		},
+		{
+			name: "Unauthorized: Unsupported Authorization Type",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.TokenMaker) {
+				addAuthorization(t, request, tokenMaker, "basic", user, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserById(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errInvalidAuthorization.Error())
+			},
+		},
+		{
+			name: "Unauthorized: Expired Token",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.TokenMaker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user, -time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserById(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), token.ErrExpiredToken.Error())
+			},
+		},
+		{
+			name: "Unauthorized: Invalid Token",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.TokenMaker) {
+				request.Header.Set(authorizationHeaderKey, "Bearer "+util.RandomString(40))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserById(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), token.ErrInvalidToken.Error())
+			},
+		},
+		{
+			name: "Unauthorized: Refresh Token",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.TokenMaker) {
+				refreshToken, _, err := tokenMaker.CreateToken(user.ID, user.Email, token.TokenTypeRefreshToken, time.Minute)
+				require.NoError(t, err)
+				request.Header.Set(authorizationHeaderKey, "Bearer "+refreshToken)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserById(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), token.ErrInvalidToken.Error())
+			},
+		},
+		{
+			name: "Unauthorized: Issued Before Password Change",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.TokenMaker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				changed := user
+				changedAt := time.Now().Add(time.Second)
+				changed.PasswordChangedAt = &changedAt
+
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(changed, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errTokenRevoked.Error())
+			},
+		},
+		{
+			name: "Unauthorized: Email Changed",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.TokenMaker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				changed := user
+				changed.Email = util.RandomEmail()
+
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(changed, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errTokenRevoked.Error())
+			},
+		},
+		{
+			name: "Unauthorized: User No Longer Exists",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.TokenMaker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(db.User{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Server Error",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.TokenMaker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+
+			authPath := "/auth"
+			server.router.With(server.Authenticate).Get(authPath, func(w http.ResponseWriter, r *http.Request) {
+				principal := principalFromContext(r.Context())
+				render.Render(w, r, SuccessfulResponse(principal.User.Email))
+			})
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, authPath, nil)
+			require.NoError(t, err)
+
+			tt.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/go-chi/render"
+)
+
+var errNotificationNotFound = errors.New("notification not found")
+
+// notificationCursor is the state behind the opaque cursor of the
+// notification list.
+type notificationCursor struct {
+	BeforeID int64 `json:"beforeId"`
+}
+
+// ListNotifications returns the current user's notifications, newest first.
+// With unread=true only those not yet read are listed.
+func (s *Server) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, err := parseIntQuery(r, "limit", 20, 1, 100)
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	unreadOnly, err := parseBoolQuery(r, "unread")
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	principal := principalFromContext(ctx)
+
+	arg := db.ListNotificationsParams{
+		UserID:     principal.User.ID,
+		UnreadOnly: unreadOnly,
+		Limit:      limit + 1,
+	}
+
+	if raw := r.URL.Query().Get("cursor"); raw != "" {
+		var cursor notificationCursor
+		if err := decodeCursor(raw, &cursor); err != nil {
+			render.Render(w, r, ErrInvalidRequest(err))
+			return
+		}
+		arg.BeforeID = &cursor.BeforeID
+	}
+
+	notifications, err := s.store.ListNotifications(ctx, arg)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	var nextCursor *string
+	if len(notifications) > int(limit) {
+		notifications = notifications[:limit]
+
+		cursor, err := encodeCursor(notificationCursor{BeforeID: notifications[len(notifications)-1].ID})
+		if err != nil {
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+		nextCursor = &cursor
+	}
+
+	render.Render(w, r, SuccessfulPaginatedResponse(notifications, nextCursor))
+}
+
+type unreadNotificationsResponse struct {
+	Count int64 `json:"count"`
+}
+
+func (s *Server) CountUnreadNotifications(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	count, err := s.store.CountUnreadNotifications(ctx, principalFromContext(ctx).User.ID)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(unreadNotificationsResponse{Count: count}))
+}
+
+func (s *Server) MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	s.markNotification(w, r, true)
+}
+
+func (s *Server) MarkNotificationUnread(w http.ResponseWriter, r *http.Request) {
+	s.markNotification(w, r, false)
+}
+
+type markedReadResponse struct {
+	Marked int64 `json:"marked"`
+}
+
+// MarkAllNotificationsRead marks every unread notification of the current
+// user as read and reports how many there were.
+func (s *Server) MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	marked, err := s.store.MarkAllNotificationsRead(ctx, principalFromContext(ctx).User.ID)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(markedReadResponse{Marked: marked}))
+}
+
+// notificationPreferences says which notifications a user gets and whether
+// they are emailed a daily digest of the unread ones.
+type notificationPreferences struct {
+	TaskAssigned       bool `json:"taskAssigned"`
+	Mentioned          bool `json:"mentioned"`
+	WatchedTaskChanged bool `json:"watchedTaskChanged"`
+	DailyDigest        bool `json:"dailyDigest"`
+}
+
+func (s *Server) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	preferences, ok := s.loadNotificationPreferences(w, r)
+	if !ok {
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(preferences))
+}
+
+type updateNotificationPreferencesPayload struct {
+	TaskAssigned       *bool `json:"taskAssigned"`
+	Mentioned          *bool `json:"mentioned"`
+	WatchedTaskChanged *bool `json:"watchedTaskChanged"`
+	DailyDigest        *bool `json:"dailyDigest"`
+}
+
+// UpdateNotificationPreferences changes the preferences given in the body,
+// leaving the others as they were.
+func (s *Server) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload updateNotificationPreferencesPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	preferences, ok := s.loadNotificationPreferences(w, r)
+	if !ok {
+		return
+	}
+
+	if payload.TaskAssigned != nil {
+		preferences.TaskAssigned = *payload.TaskAssigned
+	}
+	if payload.Mentioned != nil {
+		preferences.Mentioned = *payload.Mentioned
+	}
+	if payload.WatchedTaskChanged != nil {
+		preferences.WatchedTaskChanged = *payload.WatchedTaskChanged
+	}
+	if payload.DailyDigest != nil {
+		preferences.DailyDigest = *payload.DailyDigest
+	}
+
+	updated, err := s.store.UpsertNotificationPreferences(ctx, db.UpsertNotificationPreferencesParams{
+		UserID:             principalFromContext(ctx).User.ID,
+		TaskAssigned:       preferences.TaskAssigned,
+		Mentioned:          preferences.Mentioned,
+		WatchedTaskChanged: preferences.WatchedTaskChanged,
+		DailyDigest:        preferences.DailyDigest,
+	})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(toNotificationPreferences(updated)))
+}
+
+// loadNotificationPreferences fetches the current user's preferences. Users
+// who never set any get every notification and no digest.
+func (s *Server) loadNotificationPreferences(w http.ResponseWriter, r *http.Request) (notificationPreferences, bool) {
+	ctx := r.Context()
+
+	preferences, err := s.store.GetNotificationPreferences(ctx, principalFromContext(ctx).User.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return notificationPreferences{
+				TaskAssigned:       true,
+				Mentioned:          true,
+				WatchedTaskChanged: true,
+			}, true
+		}
+		render.Render(w, r, ErrInternalServer())
+		return notificationPreferences{}, false
+	}
+
+	return toNotificationPreferences(preferences), true
+}
+
+func toNotificationPreferences(preferences db.NotificationPreference) notificationPreferences {
+	return notificationPreferences{
+		TaskAssigned:       preferences.TaskAssigned,
+		Mentioned:          preferences.Mentioned,
+		WatchedTaskChanged: preferences.WatchedTaskChanged,
+		DailyDigest:        preferences.DailyDigest,
+	}
+}
+
+// markNotification marks the notification identified by the "id" URL
+// parameter as read or unread. Notifications of other users are not found.
+func (s *Server) markNotification(w http.ResponseWriter, r *http.Request, read bool) {
+	ctx := r.Context()
+
+	id, err := parseID64Param(r, "id")
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	userID := principalFromContext(ctx).User.ID
+
+	var notification db.Notification
+	if read {
+		notification, err = s.store.MarkNotificationRead(ctx, db.MarkNotificationReadParams{ID: id, UserID: userID})
+	} else {
+		notification, err = s.store.MarkNotificationUnread(ctx, db.MarkNotificationUnreadParams{ID: id, UserID: userID})
+	}
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			render.Render(w, r, ErrNotFound(errNotificationNotFound))
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(notification))
+}
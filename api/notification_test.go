@@ -0,0 +1,388 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestListNotificationsApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	notifications := []db.Notification{
+		{ID: 30, UserID: user.ID, Type: "task_assigned", TaskID: 4, TaskTitle: "Write report"},
+		{ID: 20, UserID: user.ID, Type: "mentioned", TaskID: 4, TaskTitle: "Write report"},
+		{ID: 10, UserID: user.ID, Type: "watched_task_changed", TaskID: 5, TaskTitle: "Fix login"},
+	}
+
+	cursor, err := encodeCursor(notificationCursor{BeforeID: 40})
+	require.NoError(t, err)
+
+	type listResponse struct {
+		Data       []db.Notification `json:"data"`
+		NextCursor *string           `json:"nextCursor"`
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK: Next Page",
+			query: "?limit=2",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListNotifications(gomock.Any(), gomock.Eq(db.ListNotificationsParams{UserID: user.ID, Limit: 3})).
+					Times(1).
+					Return(notifications, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response listResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response.Data, 2)
+				require.Equal(t, "Write report", response.Data[0].TaskTitle)
+				require.NotNil(t, response.NextCursor)
+
+				var next notificationCursor
+				require.NoError(t, decodeCursor(*response.NextCursor, &next))
+				require.Equal(t, int64(20), next.BeforeID)
+			},
+		},
+		{
+			name:  "OK: Unread After Cursor",
+			query: "?unread=true&cursor=" + cursor,
+			buildStubs: func(store *mockdb.MockStore) {
+				beforeID := int64(40)
+				store.EXPECT().
+					ListNotifications(gomock.Any(), gomock.Eq(db.ListNotificationsParams{
+						UserID:     user.ID,
+						UnreadOnly: true,
+						BeforeID:   &beforeID,
+						Limit:      21,
+					})).
+					Times(1).
+					Return(notifications, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response listResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response.Data, 3)
+				require.Nil(t, response.NextCursor)
+			},
+		},
+		{
+			name:  "BadRequest: Invalid Unread",
+			query: "?unread=maybe",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListNotifications(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "BadRequest: Invalid Cursor",
+			query: "?cursor=not-a-cursor",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListNotifications(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Server Error",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListNotifications(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/v1/notifications"+tt.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestMarkNotificationApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	readAt := time.Now().UTC().Truncate(time.Second)
+
+	testCases := []struct {
+		name          string
+		url           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Read",
+			url:  "/api/v1/notifications/7/read",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					MarkNotificationRead(gomock.Any(), gomock.Eq(db.MarkNotificationReadParams{ID: 7, UserID: user.ID})).
+					Times(1).
+					Return(db.Notification{ID: 7, UserID: user.ID, ReadAt: &readAt}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Data db.Notification `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, &readAt, response.Data.ReadAt)
+			},
+		},
+		{
+			name: "Unread",
+			url:  "/api/v1/notifications/7/unread",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					MarkNotificationUnread(gomock.Any(), gomock.Eq(db.MarkNotificationUnreadParams{ID: 7, UserID: user.ID})).
+					Times(1).
+					Return(db.Notification{ID: 7, UserID: user.ID}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"readAt":null`)
+			},
+		},
+		{
+			// Notifications of other users are not matched by the update.
+			name: "NotFound",
+			url:  "/api/v1/notifications/8/read",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					MarkNotificationRead(gomock.Any(), gomock.Eq(db.MarkNotificationReadParams{ID: 8, UserID: user.ID})).
+					Times(1).
+					Return(db.Notification{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errNotificationNotFound.Error())
+			},
+		},
+		{
+			name: "BadRequest: Invalid ID",
+			url:  "/api/v1/notifications/abc/read",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkNotificationRead(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Read All",
+			url:  "/api/v1/notifications/read",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkAllNotificationsRead(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(int64(5), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"marked":5`)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, tt.url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCountUnreadNotificationsApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	stubAuthenticatedUser(store, user)
+	store.EXPECT().CountUnreadNotifications(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(int64(3), nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/v1/notifications/unread-count", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `"count":3`)
+}
+
+func TestNotificationPreferencesApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	saved := db.NotificationPreference{UserID: user.ID, TaskAssigned: true, Mentioned: false, WatchedTaskChanged: true, DailyDigest: true}
+
+	testCases := []struct {
+		name          string
+		method        string
+		payload       map[string]any
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Get: Defaults",
+			method: http.MethodGet,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.NotificationPreference{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireNotificationPreferences(t, recorder, notificationPreferences{TaskAssigned: true, Mentioned: true, WatchedTaskChanged: true})
+			},
+		},
+		{
+			name:   "Get: Saved",
+			method: http.MethodGet,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(saved, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireNotificationPreferences(t, recorder, notificationPreferences{TaskAssigned: true, WatchedTaskChanged: true, DailyDigest: true})
+			},
+		},
+		{
+			name:    "Update: Keeps Others",
+			method:  http.MethodPatch,
+			payload: map[string]any{"mentioned": true, "dailyDigest": false},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(saved, nil)
+
+				updated := db.NotificationPreference{UserID: user.ID, TaskAssigned: true, Mentioned: true, WatchedTaskChanged: true}
+				store.EXPECT().
+					UpsertNotificationPreferences(gomock.Any(), gomock.Eq(db.UpsertNotificationPreferencesParams{
+						UserID:             user.ID,
+						TaskAssigned:       true,
+						Mentioned:          true,
+						WatchedTaskChanged: true,
+						DailyDigest:        false,
+					})).
+					Times(1).
+					Return(updated, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireNotificationPreferences(t, recorder, notificationPreferences{TaskAssigned: true, Mentioned: true, WatchedTaskChanged: true})
+			},
+		},
+		{
+			name:    "Update: From Defaults",
+			method:  http.MethodPatch,
+			payload: map[string]any{"watchedTaskChanged": false},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(db.NotificationPreference{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					UpsertNotificationPreferences(gomock.Any(), gomock.Eq(db.UpsertNotificationPreferencesParams{
+						UserID:       user.ID,
+						TaskAssigned: true,
+						Mentioned:    true,
+					})).
+					Times(1).
+					Return(db.NotificationPreference{UserID: user.ID, TaskAssigned: true, Mentioned: true}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:    "Update: Internal Server Error",
+			method:  http.MethodPatch,
+			payload: map[string]any{"dailyDigest": true},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Any()).Times(1).Return(db.NotificationPreference{}, sql.ErrConnDone)
+				store.EXPECT().UpsertNotificationPreferences(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			var body bytes.Buffer
+			if tt.payload != nil {
+				require.NoError(t, json.NewEncoder(&body).Encode(tt.payload))
+			}
+
+			request, err := http.NewRequest(tt.method, "/api/v1/notifications/preferences", &body)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func requireNotificationPreferences(t *testing.T, recorder *httptest.ResponseRecorder, expected notificationPreferences) {
+	var response struct {
+		Data notificationPreferences `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Equal(t, expected, response.Data)
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func parseIDParam(r *http.Request, key string) (int32, error) {
+	id, err := strconv.ParseInt(chi.URLParam(r, key), 10, 32)
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("%s must be a positive integer", key)
+	}
+
+	return int32(id), nil
+}
+
+// parseID64Param is parseIDParam for BIGSERIAL identifiers.
+func parseID64Param(r *http.Request, key string) (int64, error) {
+	id, err := strconv.ParseInt(chi.URLParam(r, key), 10, 64)
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("%s must be a positive integer", key)
+	}
+
+	return id, nil
+}
+
+func parseIntQuery(r *http.Request, key string, defaultValue, min, max int32) (int32, error) {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil || int32(value) < min || int32(value) > max {
+		return 0, fmt.Errorf("%s must be a number between %d and %d", key, min, max)
+	}
+
+	return int32(value), nil
+}
+
+// parseOptionalIDQuery returns nil when the query parameter is absent.
+func parseOptionalIDQuery(r *http.Request, key string) (*int32, error) {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return nil, nil
+	}
+
+	id, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil || id < 1 {
+		return nil, fmt.Errorf("%s must be a positive integer", key)
+	}
+
+	value := int32(id)
+	return &value, nil
+}
+
+// parseTimeQuery parses an RFC 3339 query parameter, returning nil when it
+// is absent.
+func parseTimeQuery(r *http.Request, key string) (*time.Time, error) {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC 3339 timestamp", key)
+	}
+
+	return &value, nil
+}
+
+func parseBoolQuery(r *http.Request, key string) (bool, error) {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return false, nil
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false", key)
+	}
+
+	return value, nil
+}
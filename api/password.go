@@ -0,0 +1,247 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/mail"
+	"github.com/bolusarz/task-manager/token"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+const (
+	tokenPurposePasswordReset = "password_reset"
+
+	defaultPasswordResetTokenDuration = time.Hour
+	passwordResetResendDelay          = time.Minute
+)
+
+var (
+	errInvalidResetToken    = errors.New("invalid or expired password reset token")
+	errIncorrectPassword    = errors.New("current password is incorrect")
+	errPasswordResetPending = errors.New("a password reset email was sent recently")
+)
+
+type forgotPasswordPayload struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ForgotPassword emails a password reset token to the account with the given
+// address. It responds the same way whether or not such an account exists so
+// it cannot be used to discover registered emails: the account is looked up
+// and emailed after responding, so the time taken gives nothing away either.
+func (s *Server) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var payload forgotPasswordPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	email := util.SanitizeInput(payload.Email)
+
+	s.runInBackground(r.Context(), func(ctx context.Context) {
+		user, err := s.store.GetUserByEmail(ctx, email)
+		if err != nil {
+			if !errors.Is(err, db.ErrRecordNotFound) {
+				log.Printf("unable to look up user for password reset: %v", err)
+			}
+			return
+		}
+
+		if err := s.sendPasswordResetEmail(ctx, user); err != nil && !errors.Is(err, errPasswordResetPending) {
+			log.Printf("unable to send password reset email to %s: %v", user.Email, err)
+		}
+	})
+
+	render.Render(w, r, SuccessfulResponseWithCode(nil, http.StatusAccepted))
+}
+
+type resetPasswordPayload struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,strong"`
+}
+
+// ResetPassword sets a new password using a token from ForgotPassword. Every
+// existing session of the user is revoked.
+func (s *Server) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload resetPasswordPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	userToken, ok := s.loadUserToken(w, r, payload.Token, tokenPurposePasswordReset, errInvalidResetToken)
+	if !ok {
+		return
+	}
+
+	hashedPassword, err := util.HashPassword(util.SanitizeInput(payload.Password))
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	changedAt := time.Now().UTC()
+
+	err = s.store.ConsumeUserToken(ctx, db.ConsumeUserTokenParams{
+		TokenID: userToken.ID,
+		Apply: func(q db.Querier) error {
+			return db.ChangePassword(ctx, q, db.UpdateUserPasswordParams{
+				ID:                userToken.UserID,
+				PasswordHash:      hashedPassword,
+				PasswordChangedAt: &changedAt,
+			})
+		},
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrUserTokenUsed) {
+			render.Render(w, r, ErrInvalidRequest(errInvalidResetToken))
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(nil))
+}
+
+type changePasswordPayload struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required,strong"`
+}
+
+// ChangePassword replaces the caller's password. All of their sessions are
+// revoked, including the current one, so a fresh token pair is returned.
+func (s *Server) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload changePasswordPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	principal := principalFromContext(ctx)
+
+	if err := util.ComparePassword(payload.CurrentPassword, principal.User.PasswordHash); err != nil {
+		render.Render(w, r, ErrInvalidRequest(errIncorrectPassword))
+		return
+	}
+
+	hashedPassword, err := util.HashPassword(util.SanitizeInput(payload.NewPassword))
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	changedAt := time.Now().UTC()
+
+	err = s.store.ChangeUserPassword(ctx, db.UpdateUserPasswordParams{
+		ID:                principal.User.ID,
+		PasswordHash:      hashedPassword,
+		PasswordChangedAt: &changedAt,
+	})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	accessToken, accessPayload, err := s.tokenMaker.CreateToken(principal.User.ID, principal.User.Email, token.TokenTypeAccessToken, s.config.AccessTokenDuration)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	refreshToken, refreshPayload, session, err := s.newSession(r, principal.User, uuid.New())
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	if _, err := s.store.CreateSession(ctx, session); err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	response := refreshTokenResponse{
+		Token:                 accessToken,
+		ExpiresAt:             accessPayload.ExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshPayload.ExpiresAt,
+	}
+
+	render.Render(w, r, SuccessfulResponse(response))
+}
+
+// sendPasswordResetEmail replaces any outstanding reset token for the user
+// with a new one and emails it to them. It returns errPasswordResetPending
+// without sending anything if a reset email went out very recently.
+func (s *Server) sendPasswordResetEmail(ctx context.Context, user db.User) error {
+	latest, err := s.store.GetLatestUserToken(ctx, db.GetLatestUserTokenParams{
+		UserID:  user.ID,
+		Purpose: tokenPurposePasswordReset,
+	})
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		return err
+	}
+
+	if err == nil && time.Since(latest.CreatedAt) < passwordResetResendDelay {
+		return errPasswordResetPending
+	}
+
+	err = s.store.InvalidateUserTokens(ctx, db.InvalidateUserTokensParams{
+		UserID:  user.ID,
+		Purpose: tokenPurposePasswordReset,
+	})
+	if err != nil {
+		return err
+	}
+
+	duration := durationOrDefault(s.config.PasswordResetTokenDuration, defaultPasswordResetTokenDuration)
+
+	resetToken, err := s.issueUserToken(ctx, user, tokenPurposePasswordReset, duration)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to choose a new password:\n\n%s\n\nThis link expires in %s. If you did not ask for a password reset you can ignore this email.\n",
+			user.FirstName,
+			s.appLink("/reset-password", resetToken),
+			duration,
+		),
+	})
+}
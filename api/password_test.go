@@ -0,0 +1,298 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/mail"
+	mockmail "github.com/bolusarz/task-manager/mail/mock"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestForgotPasswordApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	latestArg := db.GetLatestUserTokenParams{UserID: user.ID, Purpose: tokenPurposePasswordReset}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore, mailer *mockmail.MockMailer)
+	}{
+		{
+			name: "Existing User",
+			buildStubs: func(store *mockdb.MockStore, mailer *mockmail.MockMailer) {
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetLatestUserToken(gomock.Any(), gomock.Eq(latestArg)).Times(1).Return(db.UserToken{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					InvalidateUserTokens(gomock.Any(), gomock.Eq(db.InvalidateUserTokensParams{UserID: user.ID, Purpose: tokenPurposePasswordReset})).
+					Times(1).
+					Return(nil)
+				store.EXPECT().
+					CreateUserToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateUserTokenParams) (db.UserToken, error) {
+						require.Equal(t, tokenPurposePasswordReset, arg.Purpose)
+						return db.UserToken{}, nil
+					})
+				mailer.EXPECT().
+					Send(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, message mail.Message) error {
+						require.Equal(t, user.Email, message.To)
+						return nil
+					})
+			},
+		},
+		{
+			name: "Unknown User",
+			buildStubs: func(store *mockdb.MockStore, mailer *mockmail.MockMailer) {
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+				store.EXPECT().CreateUserToken(gomock.Any(), gomock.Any()).Times(0)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "Lookup Fails",
+			buildStubs: func(store *mockdb.MockStore, mailer *mockmail.MockMailer) {
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(db.User{}, sql.ErrConnDone)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "Recently Sent",
+			buildStubs: func(store *mockdb.MockStore, mailer *mockmail.MockMailer) {
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().GetLatestUserToken(gomock.Any(), gomock.Eq(latestArg)).Times(1).Return(db.UserToken{CreatedAt: time.Now()}, nil)
+				store.EXPECT().CreateUserToken(gomock.Any(), gomock.Any()).Times(0)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			mailer := mockmail.NewMockMailer(ctrl)
+			tt.buildStubs(store, mailer)
+
+			server := newTestServer(t, store)
+			server.mailer = mailer
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(map[string]any{"email": user.Email})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/password/forgot", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+
+			// Every case must look the same to the caller.
+			require.Equal(t, http.StatusAccepted, recorder.Code)
+
+			server.background.Wait()
+		})
+	}
+}
+
+func TestResetPasswordApi(t *testing.T) {
+	token, err := util.SecureToken(secureTokenBytes)
+	require.NoError(t, err)
+
+	password, err := util.RandomPassword(12)
+	require.NoError(t, err)
+
+	hashArg := db.GetUserTokenByHashParams{TokenHash: util.HashToken(token), Purpose: tokenPurposePasswordReset}
+	userToken := db.UserToken{
+		ID:        int32(util.RandomInt(1, 1000)),
+		UserID:    int32(util.RandomInt(1, 1000)),
+		Purpose:   tokenPurposePasswordReset,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	testCases := []struct {
+		name          string
+		payload       map[string]any
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			payload: map[string]any{"token": token, "password": password},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTokenByHash(gomock.Any(), gomock.Eq(hashArg)).Times(1).Return(userToken, nil)
+				store.EXPECT().
+					ConsumeUserToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ConsumeUserTokenParams) error {
+						require.Equal(t, userToken.ID, arg.TokenID)
+						return arg.Apply(store)
+					})
+				store.EXPECT().
+					UpdateUserPassword(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateUserPasswordParams) error {
+						require.Equal(t, userToken.UserID, arg.ID)
+						require.NoError(t, util.ComparePassword(password, arg.PasswordHash))
+						require.NotNil(t, arg.PasswordChangedAt)
+						return nil
+					})
+				store.EXPECT().RevokeUserSessions(gomock.Any(), gomock.Eq(userToken.UserID)).Times(1).Return(nil)
+				store.EXPECT().
+					CreateOutboxEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateOutboxEventParams) (db.OutboxEvent, error) {
+						require.Equal(t, string(db.EventSessionsRevoked), arg.EventType)
+						require.Equal(t, userToken.UserID, arg.AggregateID)
+						return db.OutboxEvent{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:    "BadRequest: Weak Password",
+			payload: map[string]any{"token": token, "password": util.RandomString(12)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTokenByHash(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "BadRequest: Invalid Token",
+			payload: map[string]any{"token": token, "password": password},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTokenByHash(gomock.Any(), gomock.Eq(hashArg)).Times(1).Return(db.UserToken{}, db.ErrRecordNotFound)
+				store.EXPECT().ConsumeUserToken(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errInvalidResetToken.Error())
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/password/reset", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestChangePasswordApi(t *testing.T) {
+	user, password := randomUser(t)
+	user.ID = int32(util.RandomInt(1, 1000))
+
+	newPassword, err := util.RandomPassword(12)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		payload       map[string]any
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			payload: map[string]any{"currentPassword": password, "newPassword": newPassword},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ChangeUserPassword(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateUserPasswordParams) error {
+						require.Equal(t, user.ID, arg.ID)
+						require.NoError(t, util.ComparePassword(newPassword, arg.PasswordHash))
+						return nil
+					})
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1).Return(db.Session{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Data refreshTokenResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.NotEmpty(t, response.Data.Token)
+				require.NotEmpty(t, response.Data.RefreshToken)
+			},
+		},
+		{
+			name:    "BadRequest: Wrong Current Password",
+			payload: map[string]any{"currentPassword": password + "x", "newPassword": newPassword},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ChangeUserPassword(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errIncorrectPassword.Error())
+			},
+		},
+		{
+			name:    "BadRequest: Weak Password",
+			payload: map[string]any{"currentPassword": password, "newPassword": util.RandomString(12)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ChangeUserPassword(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPut, "/api/v1/users/me/password", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/token"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+var (
+	errEmailTaken = errors.New("email already exists")
+	errLastAdmin  = errors.New("you are the only admin, grant the admin role to another user before deleting your account")
+)
+
+type updateProfilePayload struct {
+	FirstName      *string `json:"firstName" validate:"omitempty,alpha,min=3,max=50"`
+	LastName       *string `json:"lastName" validate:"omitempty,alpha,min=3,max=50"`
+	Email          *string `json:"email" validate:"omitempty,email,max=100"`
+	ProfilePicture *string `json:"profilePicture" validate:"omitempty,url,max=255"`
+	Timezone       *string `json:"timezone" validate:"omitempty,iana_timezone,max=64"`
+}
+
+type updateProfileResponse struct {
+	createAccountResponse
+	// Tokens is only set when the email changed. Existing tokens are bound to
+	// the old address and stop working, so the client must switch to these.
+	Tokens *refreshTokenResponse `json:"tokens,omitempty"`
+}
+
+func (s *Server) GetProfile(w http.ResponseWriter, r *http.Request) {
+	principal := principalFromContext(r.Context())
+
+	render.Render(w, r, SuccessfulResponse(newCreateAccountResponse(principal.User)))
+}
+
+// UpdateProfile changes the caller's details. A new email address has to be
+// verified again, and because tokens are bound to the address every session
+// is revoked and a fresh token pair is returned.
+func (s *Server) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload updateProfilePayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	user := principalFromContext(ctx).User
+
+	arg := db.UpdateUserProfileParams{
+		UpdateUserParams: db.UpdateUserParams{
+			ID:                user.ID,
+			FirstName:         user.FirstName,
+			LastName:          user.LastName,
+			Email:             user.Email,
+			ProfilePictureUrl: user.ProfilePictureUrl,
+			IsEmailVerified:   user.IsEmailVerified,
+			Timezone:          user.Timezone,
+		},
+	}
+
+	if payload.FirstName != nil {
+		arg.FirstName = util.SanitizeInput(*payload.FirstName)
+	}
+	if payload.LastName != nil {
+		arg.LastName = util.SanitizeInput(*payload.LastName)
+	}
+	if payload.ProfilePicture != nil {
+		arg.ProfilePictureUrl = util.SanitizeInput(*payload.ProfilePicture)
+	}
+	if payload.Timezone != nil {
+		arg.Timezone = *payload.Timezone
+	}
+	if payload.Email != nil {
+		email := util.SanitizeInput(*payload.Email)
+		if !strings.EqualFold(email, user.Email) {
+			arg.Email = email
+			arg.IsEmailVerified = false
+			arg.RevokeSessions = true
+			arg.InvalidateTokens = []string{tokenPurposeEmailVerification}
+		}
+	}
+
+	updatedUser, err := s.store.UpdateUserProfile(ctx, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			render.Render(w, r, ErrInvalidRequestWithCode(errEmailTaken, http.StatusConflict))
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	if updatedUser.ProfilePictureUrl != user.ProfilePictureUrl {
+		s.deleteAvatar(ctx, user.ProfilePictureUrl)
+	}
+
+	response := updateProfileResponse{
+		createAccountResponse: newCreateAccountResponse(updatedUser),
+	}
+
+	if arg.RevokeSessions {
+		accessToken, accessPayload, err := s.tokenMaker.CreateToken(updatedUser.ID, updatedUser.Email, token.TokenTypeAccessToken, s.config.AccessTokenDuration)
+		if err != nil {
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+
+		refreshToken, refreshPayload, session, err := s.newSession(r, updatedUser, uuid.New())
+		if err != nil {
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+
+		if _, err := s.store.CreateSession(ctx, session); err != nil {
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+
+		response.Tokens = &refreshTokenResponse{
+			Token:                 accessToken,
+			ExpiresAt:             accessPayload.ExpiresAt,
+			RefreshToken:          refreshToken,
+			RefreshTokenExpiresAt: refreshPayload.ExpiresAt,
+		}
+
+		if err := s.sendVerificationEmail(ctx, updatedUser); err != nil {
+			log.Printf("unable to send verification email to %s: %v", updatedUser.Email, err)
+		}
+	}
+
+	render.Render(w, r, SuccessfulResponse(response))
+}
+
+type deleteAccountPayload struct {
+	Password string `json:"password" validate:"required"`
+}
+
+// DeleteAccount removes the caller's account after confirming their password.
+// Tasks and teams they created are kept with their creator cleared.
+func (s *Server) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload deleteAccountPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	user := principalFromContext(ctx).User
+
+	if err := util.ComparePassword(payload.Password, user.PasswordHash); err != nil {
+		render.Render(w, r, ErrInvalidRequest(errIncorrectPassword))
+		return
+	}
+
+	roles, err := s.store.ListUserRoles(ctx, user.ID)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	for _, role := range roles {
+		if role.Name != adminRoleName {
+			continue
+		}
+
+		admins, err := s.store.CountUsersWithRole(ctx, adminRoleName)
+		if err != nil {
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+
+		if admins <= 1 {
+			render.Render(w, r, ErrInvalidRequestWithCode(errLastAdmin, http.StatusConflict))
+			return
+		}
+	}
+
+	if err := s.store.DeleteUser(ctx, user.ID); err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	s.deleteAvatar(ctx, user.ProfilePictureUrl)
+
+	render.Render(w, r, SuccessfulResponse(nil))
+}
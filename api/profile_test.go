@@ -0,0 +1,354 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetProfileApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	stubAuthenticatedUser(store, user)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/v1/users/me", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Data createAccountResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Equal(t, user.Email, response.Data.Email)
+	require.NotContains(t, recorder.Body.String(), user.PasswordHash)
+}
+
+func TestUpdateProfileApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	user.IsEmailVerified = true
+
+	newEmail := util.RandomEmail()
+
+	testCases := []struct {
+		name          string
+		payload       map[string]any
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK: Name",
+			payload: map[string]any{"firstName": "Bolu"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserProfile(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateUserProfileParams) (db.User, error) {
+						require.Equal(t, user.ID, arg.ID)
+						require.Equal(t, "Bolu", arg.FirstName)
+						require.Equal(t, user.LastName, arg.LastName)
+						require.Equal(t, user.Email, arg.Email)
+						require.True(t, arg.IsEmailVerified)
+						require.False(t, arg.RevokeSessions)
+
+						updated := user
+						updated.FirstName = arg.FirstName
+						return updated, nil
+					})
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateUserToken(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Data updateProfileResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, "Bolu", response.Data.FirstName)
+				require.Nil(t, response.Data.Tokens)
+			},
+		},
+		{
+			name:    "OK: Email",
+			payload: map[string]any{"email": newEmail},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserProfile(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateUserProfileParams) (db.User, error) {
+						require.Equal(t, newEmail, arg.Email)
+						require.False(t, arg.IsEmailVerified)
+						require.True(t, arg.RevokeSessions)
+						require.Equal(t, []string{tokenPurposeEmailVerification}, arg.InvalidateTokens)
+
+						updated := user
+						updated.Email = arg.Email
+						updated.IsEmailVerified = false
+						return updated, nil
+					})
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateSessionParams) (db.Session, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						return db.Session{}, nil
+					})
+				store.EXPECT().InvalidateUserTokens(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().
+					CreateUserToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateUserTokenParams) (db.UserToken, error) {
+						require.Equal(t, tokenPurposeEmailVerification, arg.Purpose)
+						require.Equal(t, newEmail, arg.Email)
+						return db.UserToken{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Data updateProfileResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, newEmail, response.Data.Email)
+				require.False(t, response.Data.IsEmailVerified)
+				require.NotNil(t, response.Data.Tokens)
+				require.NotEmpty(t, response.Data.Tokens.Token)
+				require.NotEmpty(t, response.Data.Tokens.RefreshToken)
+			},
+		},
+		{
+			name:    "OK: Timezone",
+			payload: map[string]any{"timezone": "Asia/Tokyo"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserProfile(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateUserProfileParams) (db.User, error) {
+						require.Equal(t, "Asia/Tokyo", arg.Timezone)
+						require.Equal(t, user.FirstName, arg.FirstName)
+						require.False(t, arg.RevokeSessions)
+
+						updated := user
+						updated.Timezone = arg.Timezone
+						return updated, nil
+					})
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Data updateProfileResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, "Asia/Tokyo", response.Data.Timezone)
+			},
+		},
+		{
+			name:    "BadRequest: Invalid Timezone",
+			payload: map[string]any{"timezone": "Mars/Olympus_Mons"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserProfile(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "Conflict: Email Taken",
+			payload: map[string]any{"email": newEmail},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserProfile(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, &pgconn.PgError{Code: db.UniqueViolation})
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errEmailTaken.Error())
+			},
+		},
+		{
+			name:    "BadRequest: Invalid Email",
+			payload: map[string]any{"email": "not-an-email"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserProfile(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "Internal Server Error",
+			payload: map[string]any{"lastName": "Sarz"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserProfile(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPatch, "/api/v1/users/me", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteAccountApi(t *testing.T) {
+	user, password := randomUser(t)
+	user.ID = int32(util.RandomInt(1, 1000))
+
+	adminRole := db.Role{ID: 1, Name: adminRoleName}
+	memberRole := db.Role{ID: 2, Name: memberRoleName}
+
+	testCases := []struct {
+		name          string
+		payload       map[string]any
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			payload: map[string]any{"password": password},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListUserRoles(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]db.Role{memberRole}, nil)
+				store.EXPECT().CountUsersWithRole(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().DeleteUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:    "OK: One Of Several Admins",
+			payload: map[string]any{"password": password},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListUserRoles(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]db.Role{adminRole, memberRole}, nil)
+				store.EXPECT().CountUsersWithRole(gomock.Any(), gomock.Eq(adminRoleName)).Times(1).Return(int64(2), nil)
+				store.EXPECT().DeleteUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:    "Conflict: Last Admin",
+			payload: map[string]any{"password": password},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListUserRoles(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]db.Role{adminRole}, nil)
+				store.EXPECT().CountUsersWithRole(gomock.Any(), gomock.Eq(adminRoleName)).Times(1).Return(int64(1), nil)
+				store.EXPECT().DeleteUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errLastAdmin.Error())
+			},
+		},
+		{
+			name:    "BadRequest: Wrong Password",
+			payload: map[string]any{"password": password + "x"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListUserRoles(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().DeleteUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errIncorrectPassword.Error())
+			},
+		},
+		{
+			name:    "BadRequest: Missing Password",
+			payload: map[string]any{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DeleteUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "Internal Server Error",
+			payload: map[string]any{"password": password},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListUserRoles(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return([]db.Role{memberRole}, nil)
+				store.EXPECT().DeleteUser(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodDelete, "/api/v1/users/me", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
@@ -33,6 +33,24 @@ func SuccessfulResponseWithCode(data any, code int) render.Renderer {
 	}
 }
 
+// PaginatedResponse is a SuccessResponse for one page of a list. NextCursor
+// is passed back to fetch the following page and is null on the last one.
+type PaginatedResponse struct {
+	SuccessResponse
+	NextCursor *string `json:"nextCursor"`
+}
+
+func SuccessfulPaginatedResponse(data any, nextCursor *string) render.Renderer {
+	return &PaginatedResponse{
+		SuccessResponse: SuccessResponse{
+			HttpResponseCode: http.StatusOK,
+			StatusText:       "Success",
+			Data:             data,
+		},
+		NextCursor: nextCursor,
+	}
+}
+
 type ErrResponse struct {
 	HttpResponseCode int    `json:"-"`
 	StatusText       string `json:"status"`
@@ -67,3 +85,35 @@ func ErrInternalServer() render.Renderer {
 		ErrorMessage:     "An error occured. Please try again later",
 	}
 }
+
+func ErrNotFound(err error) render.Renderer {
+	return &ErrResponse{
+		HttpResponseCode: http.StatusNotFound,
+		StatusText:       "Not found",
+		ErrorMessage:     err.Error(),
+	}
+}
+
+func ErrUnauthorized(err error) render.Renderer {
+	return &ErrResponse{
+		HttpResponseCode: http.StatusUnauthorized,
+		StatusText:       "Unauthorized",
+		ErrorMessage:     err.Error(),
+	}
+}
+
+func ErrForbidden(err error) render.Renderer {
+	return &ErrResponse{
+		HttpResponseCode: http.StatusForbidden,
+		StatusText:       "Forbidden",
+		ErrorMessage:     err.Error(),
+	}
+}
+
+func ErrTooManyRequests(err error) render.Renderer {
+	return &ErrResponse{
+		HttpResponseCode: http.StatusTooManyRequests,
+		StatusText:       "Too many requests",
+		ErrorMessage:     err.Error(),
+	}
+}
@@ -0,0 +1,459 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/go-chi/render"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	adminRoleName  = "admin"
+	memberRoleName = "member"
+)
+
+var (
+	errRoleNotFound       = errors.New("role not found")
+	errClaimNotFound      = errors.New("claim not found")
+	errRoleExists         = errors.New("a role with this name already exists")
+	errSystemRole         = errors.New("built-in roles cannot be renamed or deleted")
+	errAdminClaims        = errors.New("claims cannot be removed from the admin role")
+	errRevokeOwnAdminRole = errors.New("you cannot revoke your own admin role")
+	errUserOrRoleNotFound = errors.New("user or role not found")
+)
+
+func isSystemRole(name string) bool {
+	return name == adminRoleName || name == memberRoleName
+}
+
+// grantDefaultRoles gives every new user the member role.
+func (s *Server) grantDefaultRoles(ctx context.Context, q db.Querier, user db.User) error {
+	return q.AddUserRoleByName(ctx, db.AddUserRoleByNameParams{
+		UserID: user.ID,
+		Name:   memberRoleName,
+	})
+}
+
+// grantBootstrapAdmin makes the user an admin once they have verified an
+// email matching ADMIN_EMAIL, but only while no admin exists, so the setting
+// cannot be used to take over an already running system. Waiting for the
+// verification keeps whoever merely registers the address first from getting
+// the role.
+func (s *Server) grantBootstrapAdmin(ctx context.Context, q db.Querier, userID int32) error {
+	if s.config.AdminEmail == "" {
+		return nil
+	}
+
+	user, err := q.GetUserById(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(s.config.AdminEmail, user.Email) {
+		return nil
+	}
+
+	admins, err := q.CountUsersWithRole(ctx, adminRoleName)
+	if err != nil {
+		return err
+	}
+
+	if admins > 0 {
+		return nil
+	}
+
+	return q.AddUserRoleByName(ctx, db.AddUserRoleByNameParams{
+		UserID: user.ID,
+		Name:   adminRoleName,
+	})
+}
+
+type rolePayload struct {
+	Name        string `json:"name" validate:"required,min=2,max=50"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+type roleResponse struct {
+	ID          int32       `json:"id"`
+	Name        string      `json:"name"`
+	Description pgtype.Text `json:"description"`
+	Claims      []string    `json:"claims"`
+}
+
+func (s *Server) CreateRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload rolePayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	arg := db.CreateRoleParams{
+		Name:        util.SanitizeInput(payload.Name),
+		Description: newText(payload.Description),
+	}
+
+	role, err := s.store.CreateRole(ctx, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			render.Render(w, r, ErrInvalidRequestWithCode(errRoleExists, http.StatusConflict))
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponseWithCode(role, http.StatusCreated))
+}
+
+func (s *Server) ListRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := s.store.ListRoles(r.Context())
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(roles))
+}
+
+func (s *Server) GetRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	role, ok := s.loadRole(w, r)
+	if !ok {
+		return
+	}
+
+	claims, err := s.store.ListRoleClaims(ctx, role.ID)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	response := roleResponse{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		Claims:      make([]string, 0, len(claims)),
+	}
+	for _, claim := range claims {
+		response.Claims = append(response.Claims, claim.Name)
+	}
+
+	render.Render(w, r, SuccessfulResponse(response))
+}
+
+type updateRolePayload struct {
+	Name        *string `json:"name" validate:"omitempty,min=2,max=50"`
+	Description *string `json:"description" validate:"omitempty,max=500"`
+}
+
+func (s *Server) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload updateRolePayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	role, ok := s.loadRole(w, r)
+	if !ok {
+		return
+	}
+
+	arg := db.UpdateRoleParams{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+	}
+
+	if payload.Name != nil {
+		name := util.SanitizeInput(*payload.Name)
+		if name != role.Name && isSystemRole(role.Name) {
+			render.Render(w, r, ErrInvalidRequest(errSystemRole))
+			return
+		}
+		arg.Name = name
+	}
+	if payload.Description != nil {
+		arg.Description = newText(*payload.Description)
+	}
+
+	role, err := s.store.UpdateRole(ctx, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			render.Render(w, r, ErrInvalidRequestWithCode(errRoleExists, http.StatusConflict))
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(role))
+}
+
+func (s *Server) DeleteRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	role, ok := s.loadRole(w, r)
+	if !ok {
+		return
+	}
+
+	if isSystemRole(role.Name) {
+		render.Render(w, r, ErrInvalidRequest(errSystemRole))
+		return
+	}
+
+	if err := s.store.DeleteRole(ctx, role.ID); err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(nil))
+}
+
+func (s *Server) ListClaims(w http.ResponseWriter, r *http.Request) {
+	claims, err := s.store.ListClaims(r.Context())
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(claims))
+}
+
+func (s *Server) ListRoleClaims(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	role, ok := s.loadRole(w, r)
+	if !ok {
+		return
+	}
+
+	claims, err := s.store.ListRoleClaims(ctx, role.ID)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(claims))
+}
+
+type roleClaimPayload struct {
+	Claim string `json:"claim" validate:"required,max=100"`
+}
+
+func (s *Server) AddRoleClaim(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload roleClaimPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	role, ok := s.loadRole(w, r)
+	if !ok {
+		return
+	}
+
+	claim, err := s.store.GetClaimByName(ctx, payload.Claim)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			render.Render(w, r, ErrNotFound(errClaimNotFound))
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	err = s.store.AddRoleClaim(ctx, db.AddRoleClaimParams{
+		RoleID:  role.ID,
+		ClaimID: claim.ID,
+	})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(claim))
+}
+
+func (s *Server) RemoveRoleClaim(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	claimID, err := parseIDParam(r, "claimId")
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	role, ok := s.loadRole(w, r)
+	if !ok {
+		return
+	}
+
+	if role.Name == adminRoleName {
+		render.Render(w, r, ErrInvalidRequest(errAdminClaims))
+		return
+	}
+
+	err = s.store.RemoveRoleClaim(ctx, db.RemoveRoleClaimParams{
+		RoleID:  role.ID,
+		ClaimID: claimID,
+	})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(nil))
+}
+
+func (s *Server) ListUserRoles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := parseIDParam(r, "id")
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	roles, err := s.store.ListUserRoles(ctx, userID)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(roles))
+}
+
+type userRolePayload struct {
+	RoleID int32 `json:"roleId" validate:"required,min=1"`
+}
+
+func (s *Server) GrantUserRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := parseIDParam(r, "id")
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	var payload userRolePayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	err = s.store.AddUserRole(ctx, db.AddUserRoleParams{
+		UserID: userID,
+		RoleID: payload.RoleID,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			render.Render(w, r, ErrNotFound(errUserOrRoleNotFound))
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(nil))
+}
+
+func (s *Server) RevokeUserRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := parseIDParam(r, "id")
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	role, ok := s.loadRoleParam(w, r, "roleId")
+	if !ok {
+		return
+	}
+
+	principal := principalFromContext(ctx)
+	if principal.User.ID == userID && role.Name == adminRoleName {
+		render.Render(w, r, ErrInvalidRequest(errRevokeOwnAdminRole))
+		return
+	}
+
+	err = s.store.RemoveUserRole(ctx, db.RemoveUserRoleParams{
+		UserID: userID,
+		RoleID: role.ID,
+	})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(nil))
+}
+
+func (s *Server) loadRole(w http.ResponseWriter, r *http.Request) (db.Role, bool) {
+	return s.loadRoleParam(w, r, "id")
+}
+
+// loadRoleParam fetches the role identified by the given URL parameter and
+// writes the error response itself when it cannot.
+func (s *Server) loadRoleParam(w http.ResponseWriter, r *http.Request, key string) (db.Role, bool) {
+	id, err := parseIDParam(r, key)
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return db.Role{}, false
+	}
+
+	role, err := s.store.GetRole(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			render.Render(w, r, ErrNotFound(errRoleNotFound))
+			return db.Role{}, false
+		}
+		render.Render(w, r, ErrInternalServer())
+		return db.Role{}, false
+	}
+
+	return role, true
+}
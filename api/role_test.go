@@ -0,0 +1,453 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func randomRole() db.Role {
+	return db.Role{
+		ID:          int32(util.RandomInt(10, 1000)),
+		Name:        util.RandomString(8),
+		Description: pgtype.Text{String: util.RandomString(20), Valid: true},
+	}
+}
+
+func TestCreateRoleApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	role := randomRole()
+
+	testCases := []struct {
+		name          string
+		claims        []string
+		payload       map[string]any
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			claims: []string{ClaimManageRoles},
+			payload: map[string]any{
+				"name":        role.Name,
+				"description": role.Description.String,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateRoleParams{
+					Name:        role.Name,
+					Description: role.Description,
+				}
+				store.EXPECT().
+					CreateRole(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(role, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:   "Forbidden",
+			claims: []string{ClaimManageClaims},
+			payload: map[string]any{
+				"name": role.Name,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateRole(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "BadRequest: Missing Name",
+			claims: []string{ClaimManageRoles},
+			payload: map[string]any{
+				"description": role.Description.String,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateRole(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Conflict",
+			claims: []string{ClaimManageRoles},
+			payload: map[string]any{
+				"name": role.Name,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateRole(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Role{}, db.ErrUniqueViolation)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, tt.claims...)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/roles", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteRoleApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	role := randomRole()
+	systemRole := db.Role{ID: 1, Name: adminRoleName}
+
+	testCases := []struct {
+		name          string
+		roleID        int32
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			roleID: role.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRole(gomock.Any(), gomock.Eq(role.ID)).Times(1).Return(role, nil)
+				store.EXPECT().DeleteRole(gomock.Any(), gomock.Eq(role.ID)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "BadRequest: System Role",
+			roleID: systemRole.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRole(gomock.Any(), gomock.Eq(systemRole.ID)).Times(1).Return(systemRole, nil)
+				store.EXPECT().DeleteRole(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "NotFound",
+			roleID: role.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRole(gomock.Any(), gomock.Eq(role.ID)).Times(1).Return(db.Role{}, db.ErrRecordNotFound)
+				store.EXPECT().DeleteRole(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimManageRoles)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/roles/%d", tt.roleID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAddRoleClaimApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	role := randomRole()
+	claim := db.Claim{ID: 3, Name: ClaimDeleteTask}
+
+	testCases := []struct {
+		name          string
+		payload       map[string]any
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			payload: map[string]any{"claim": claim.Name},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRole(gomock.Any(), gomock.Eq(role.ID)).Times(1).Return(role, nil)
+				store.EXPECT().GetClaimByName(gomock.Any(), gomock.Eq(claim.Name)).Times(1).Return(claim, nil)
+				store.EXPECT().
+					AddRoleClaim(gomock.Any(), gomock.Eq(db.AddRoleClaimParams{RoleID: role.ID, ClaimID: claim.ID})).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:    "NotFound: Unknown Claim",
+			payload: map[string]any{"claim": "can_fly"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRole(gomock.Any(), gomock.Eq(role.ID)).Times(1).Return(role, nil)
+				store.EXPECT().GetClaimByName(gomock.Any(), gomock.Eq("can_fly")).Times(1).Return(db.Claim{}, db.ErrRecordNotFound)
+				store.EXPECT().AddRoleClaim(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimManageClaims)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/roles/%d/claims", role.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGrantUserRoleApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	role := randomRole()
+	targetID := user.ID + 1
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AddUserRole(gomock.Any(), gomock.Eq(db.AddUserRoleParams{UserID: targetID, RoleID: role.ID})).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AddUserRole(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(&pgconn.PgError{Code: db.ForeignKeyViolation})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Server Error",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AddUserRole(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimManageRoles)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(map[string]any{"roleId": role.ID})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/users/%d/roles", targetID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRevokeOwnAdminRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := randomAuthenticatedUser(t)
+	adminRole := db.Role{ID: 1, Name: adminRoleName}
+
+	store := mockdb.NewMockStore(ctrl)
+	stubAuthenticatedUser(store, user, ClaimManageRoles)
+	store.EXPECT().GetRole(gomock.Any(), gomock.Eq(adminRole.ID)).Times(1).Return(adminRole, nil)
+	store.EXPECT().RemoveUserRole(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	url := fmt.Sprintf("/api/v1/users/%d/roles/%d", user.ID, adminRole.ID)
+	request, err := http.NewRequest(http.MethodDelete, url, nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestGrantDefaultRoles(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		AddUserRoleByName(gomock.Any(), gomock.Eq(db.AddUserRoleByNameParams{UserID: user.ID, Name: memberRoleName})).
+		Times(1).
+		Return(nil)
+
+	server := newTestServer(t, store)
+	// Registering with the admin email is not enough to become an admin.
+	server.config.AdminEmail = user.Email
+
+	err := server.grantDefaultRoles(context.Background(), store, user)
+	require.NoError(t, err)
+}
+
+func TestGrantBootstrapAdmin(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+
+	adminArg := db.AddUserRoleByNameParams{UserID: user.ID, Name: adminRoleName}
+
+	testCases := []struct {
+		name       string
+		adminEmail string
+		buildStubs func(store *mockdb.MockStore)
+	}{
+		{
+			name:       "No Admin Email",
+			adminEmail: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserById(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().AddUserRoleByName(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:       "Other Email",
+			adminEmail: "admin@" + util.RandomString(8) + ".com",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserById(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().CountUsersWithRole(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().AddUserRoleByName(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:       "Bootstrap Admin",
+			adminEmail: strings.ToUpper(user.Email),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserById(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().CountUsersWithRole(gomock.Any(), gomock.Eq(adminRoleName)).Times(1).Return(int64(0), nil)
+				store.EXPECT().AddUserRoleByName(gomock.Any(), gomock.Eq(adminArg)).Times(1).Return(nil)
+			},
+		},
+		{
+			name:       "Admin Already Exists",
+			adminEmail: user.Email,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserById(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().CountUsersWithRole(gomock.Any(), gomock.Eq(adminRoleName)).Times(1).Return(int64(1), nil)
+				store.EXPECT().AddUserRoleByName(gomock.Any(), gomock.Eq(adminArg)).Times(0)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.config.AdminEmail = tt.adminEmail
+
+			err := server.grantBootstrapAdmin(context.Background(), store, user.ID)
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package api
+
+import (
+	"errors"
+	"html"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/go-chi/render"
+)
+
+const (
+	maxSearchQueryLength = 200
+	maxSearchTerms       = 10
+
+	searchTypeTasks    = "tasks"
+	searchTypeComments = "comments"
+	searchTypeTeams    = "teams"
+)
+
+var (
+	errEmptySearchQuery   = errors.New("q must contain at least one word")
+	errSearchQueryTooLong = errors.New("q must be at most 200 characters and 10 terms")
+	errInvalidSearchType  = errors.New("type must be one of [tasks comments teams]")
+)
+
+// searchWord matches the parts of a term that can become tsquery lexemes.
+// Everything else is dropped, so user input never reaches the tsquery
+// parser as syntax.
+var searchWord = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+type searchResponse struct {
+	Tasks    []db.SearchTasksRow        `json:"tasks"`
+	Comments []db.SearchTaskCommentsRow `json:"comments"`
+	Teams    []db.SearchTeamsRow        `json:"teams"`
+}
+
+// Search looks the q parameter up across the tasks, comments and teams the
+// caller can see, best matches first. Callers without ClaimViewAllRecords only
+// see their teams, the tasks of those teams and their own personal tasks,
+// along with the comments on those tasks.
+func (s *Server) Search(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	principal := principalFromContext(ctx)
+
+	query, err := parseSearchQuery(r.URL.Query().Get("q"))
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	searchType := r.URL.Query().Get("type")
+	switch searchType {
+	case "", searchTypeTasks, searchTypeComments, searchTypeTeams:
+	default:
+		render.Render(w, r, ErrInvalidRequest(errInvalidSearchType))
+		return
+	}
+
+	limit, err := parseIntQuery(r, "limit", 20, 1, 50)
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	viewAll, err := s.hasClaim(ctx, principal, ClaimViewAllRecords)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	response := searchResponse{
+		Tasks:    []db.SearchTasksRow{},
+		Comments: []db.SearchTaskCommentsRow{},
+		Teams:    []db.SearchTeamsRow{},
+	}
+
+	if searchType == "" || searchType == searchTypeTasks {
+		response.Tasks, err = s.store.SearchTasks(ctx, db.SearchTasksParams{
+			Query:   query,
+			ViewAll: viewAll,
+			UserID:  principal.User.ID,
+			Limit:   limit,
+		})
+		if err != nil {
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+
+		for i := range response.Tasks {
+			response.Tasks[i].Snippet = escapeSnippet(response.Tasks[i].Snippet)
+		}
+	}
+
+	if searchType == "" || searchType == searchTypeComments {
+		response.Comments, err = s.store.SearchTaskComments(ctx, db.SearchTaskCommentsParams{
+			Query:   query,
+			ViewAll: viewAll,
+			UserID:  principal.User.ID,
+			Limit:   limit,
+		})
+		if err != nil {
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+
+		for i := range response.Comments {
+			response.Comments[i].Snippet = escapeSnippet(response.Comments[i].Snippet)
+		}
+	}
+
+	if searchType == "" || searchType == searchTypeTeams {
+		response.Teams, err = s.store.SearchTeams(ctx, db.SearchTeamsParams{
+			Query:   query,
+			ViewAll: viewAll,
+			UserID:  principal.User.ID,
+			Limit:   limit,
+		})
+		if err != nil {
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+
+		for i := range response.Teams {
+			response.Teams[i].Snippet = escapeSnippet(response.Teams[i].Snippet)
+		}
+	}
+
+	render.Render(w, r, SuccessfulResponse(response))
+}
+
+// parseSearchQuery turns the user's search text into a to_tsquery
+// expression. Terms are ANDed together, "quoted text" becomes a phrase and a
+// trailing * makes a term match as a prefix.
+func parseSearchQuery(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if utf8.RuneCountInString(raw) > maxSearchQueryLength {
+		return "", errSearchQueryTooLong
+	}
+
+	var terms []string
+	for i, part := range strings.Split(raw, `"`) {
+		// Odd parts sit between a pair of quotes.
+		if i%2 == 1 {
+			if term := searchTerm(part); term != "" {
+				terms = append(terms, term)
+			}
+			continue
+		}
+
+		for _, field := range strings.Fields(part) {
+			if term := searchTerm(field); term != "" {
+				terms = append(terms, term)
+			}
+		}
+	}
+
+	if len(terms) == 0 {
+		return "", errEmptySearchQuery
+	}
+	if len(terms) > maxSearchTerms {
+		return "", errSearchQueryTooLong
+	}
+
+	return strings.Join(terms, " & "), nil
+}
+
+// searchTerm builds the tsquery for a single term. Words inside a term must
+// follow each other, so "foo-bar" only matches the two words side by side.
+func searchTerm(text string) string {
+	words := searchWord.FindAllString(text, -1)
+	if len(words) == 0 {
+		return ""
+	}
+
+	term := strings.Join(words, " <-> ")
+	if strings.HasSuffix(strings.TrimSpace(text), "*") {
+		term += ":*"
+	}
+
+	return term
+}
+
+// escapeSnippet makes a ts_headline snippet safe to render as HTML while
+// keeping the <mark> tags around the matched words.
+func escapeSnippet(snippet string) string {
+	snippet = html.EscapeString(snippet)
+	snippet = strings.ReplaceAll(snippet, "&lt;mark&gt;", "<mark>")
+	return strings.ReplaceAll(snippet, "&lt;/mark&gt;", "</mark>")
+}
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSearchApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	task := randomTask()
+	taskRow := db.SearchTasksRow{ID: task.ID, Title: task.Title, Rank: 0.6, Snippet: "<mark>deploy</mark> <script>"}
+	commentRow := db.SearchTaskCommentsRow{ID: 7, TaskID: task.ID, TaskTitle: task.Title, Rank: 0.5, Snippet: "<mark>deploy</mark> & ship"}
+	teamRow := db.SearchTeamsRow{ID: 3, Name: "Platform", Rank: 0.4, Snippet: "<mark>deploy</mark> team"}
+
+	testCases := []struct {
+		name          string
+		query         string
+		claims        []string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "q=deploy",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchTasks(gomock.Any(), gomock.Eq(db.SearchTasksParams{Query: "deploy", UserID: user.ID, Limit: 20})).
+					Times(1).
+					Return([]db.SearchTasksRow{taskRow}, nil)
+				store.EXPECT().
+					SearchTaskComments(gomock.Any(), gomock.Eq(db.SearchTaskCommentsParams{Query: "deploy", UserID: user.ID, Limit: 20})).
+					Times(1).
+					Return([]db.SearchTaskCommentsRow{commentRow}, nil)
+				store.EXPECT().
+					SearchTeams(gomock.Any(), gomock.Eq(db.SearchTeamsParams{Query: "deploy", UserID: user.ID, Limit: 20})).
+					Times(1).
+					Return([]db.SearchTeamsRow{teamRow}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Data searchResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response.Data.Tasks, 1)
+				require.Equal(t, "<mark>deploy</mark> &lt;script&gt;", response.Data.Tasks[0].Snippet)
+				require.Len(t, response.Data.Comments, 1)
+				require.Equal(t, "<mark>deploy</mark> &amp; ship", response.Data.Comments[0].Snippet)
+				require.Len(t, response.Data.Teams, 1)
+				require.Equal(t, teamRow.ID, response.Data.Teams[0].ID)
+			},
+		},
+		{
+			name:   "OK: View All Records",
+			query:  "q=deploy&type=tasks&limit=5",
+			claims: []string{ClaimViewAllRecords},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchTasks(gomock.Any(), gomock.Eq(db.SearchTasksParams{Query: "deploy", ViewAll: true, UserID: user.ID, Limit: 5})).
+					Times(1).
+					Return([]db.SearchTasksRow{}, nil)
+				store.EXPECT().SearchTeams(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"comments":[]`)
+				require.Contains(t, recorder.Body.String(), `"teams":[]`)
+			},
+		},
+		{
+			name:  "OK: Teams Only",
+			query: "q=" + url.QueryEscape(`"release notes" plat*`) + "&type=teams",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchTasks(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					SearchTeams(gomock.Any(), gomock.Eq(db.SearchTeamsParams{Query: "release <-> notes & plat:*", UserID: user.ID, Limit: 20})).
+					Times(1).
+					Return([]db.SearchTeamsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "BadRequest: Empty Query",
+			query: "q=" + url.QueryEscape(` "" & ! `),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchTasks(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errEmptySearchQuery.Error())
+			},
+		},
+		{
+			name:  "BadRequest: Invalid Type",
+			query: "q=deploy&type=users",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchTasks(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "Internal Server Error",
+			query: "q=deploy",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchTasks(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+				store.EXPECT().SearchTeams(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, tt.claims...)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/v1/search?"+tt.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestParseSearchQuery(t *testing.T) {
+	testCases := []struct {
+		raw      string
+		expected string
+		err      error
+	}{
+		{raw: "deploy", expected: "deploy"},
+		{raw: "deploy staging", expected: "deploy & staging"},
+		{raw: "deplo*", expected: "deplo:*"},
+		{raw: `"release notes"`, expected: "release <-> notes"},
+		{raw: `"release not*" q3`, expected: "release <-> not:* & q3"},
+		{raw: "foo-bar", expected: "foo <-> bar"},
+		{raw: "it's | !x & (y)", expected: "it <-> s & x & y"},
+		{raw: "   ", err: errEmptySearchQuery},
+		{raw: "a b c d e f g h i j k", err: errSearchQueryTooLong},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.raw, func(t *testing.T) {
+			query, err := parseSearchQuery(tt.raw)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, query)
+		})
+	}
+}
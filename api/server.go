@@ -1,12 +1,19 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 
+	"github.com/bolusarz/task-manager/blob"
 	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/mail"
+	"github.com/bolusarz/task-manager/recurrence"
+	"github.com/bolusarz/task-manager/stream"
 	"github.com/bolusarz/task-manager/token"
 	"github.com/bolusarz/task-manager/util"
 	"github.com/go-chi/chi/v5"
@@ -14,29 +21,54 @@ import (
 	"github.com/go-playground/validator/v10"
 )
 
+// shutdownTimeout is how long StartServer waits for requests in flight when
+// it is stopped. Streams never finish on their own, so whatever is still open
+// by then is cut off.
+const shutdownTimeout = 10 * time.Second
+
 type Server struct {
-	store      db.Store
-	validate   *validator.Validate
-	router     *chi.Mux
-	config     util.Config
-	tokenMaker token.TokenMaker
+	store       db.Store
+	validate    *validator.Validate
+	router      *chi.Mux
+	config      util.Config
+	tokenMaker  token.TokenMaker
+	mailer      mail.Mailer
+	blobs       blob.BlobStore
+	recurrences *recurrence.Generator
+	hub         *stream.Hub
+	// background tracks the work requests leave running after responding,
+	// which StartServer waits for before it returns.
+	background sync.WaitGroup
 }
 
-func NewServer(store db.Store, config util.Config) (*Server, error) {
+// NewServer builds the API server. The mailer and recurrence generator are
+// shared with the background workers that main runs alongside it.
+func NewServer(store db.Store, config util.Config, mailer mail.Mailer, recurrences *recurrence.Generator, hub *stream.Hub) (*Server, error) {
 	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
 
 	if err != nil {
 		return nil, err
 	}
 
+	blobs, err := blob.NewBlobStore(config)
+	if err != nil {
+		return nil, err
+	}
+
 	server := &Server{
-		store:      store,
-		validate:   validator.New(),
-		config:     config,
-		tokenMaker: tokenMaker,
+		store:       store,
+		validate:    validator.New(),
+		config:      config,
+		tokenMaker:  tokenMaker,
+		mailer:      mailer,
+		blobs:       blobs,
+		recurrences: recurrences,
+		hub:         hub,
 	}
 
 	server.validate.RegisterValidation("strong", IsPasswordStrong)
+	server.validate.RegisterValidation("iana_timezone", IsTimezone)
+	server.validate.RegisterValidation("event_type", IsEventType)
 
 	server.setupRoutes()
 
@@ -48,12 +80,168 @@ func (s *Server) setupRoutes() {
 
 	router.Use(middleware.RequestID)
 	router.Use(middleware.RealIP)
+	router.Use(s.AuditContext)
+	router.Use(s.StreamTokenFromQuery)
 	router.Use(middleware.Logger)
 	router.Use(middleware.Recoverer)
-	router.Use(middleware.Timeout(60 * time.Second))
+	// Streams stay open for as long as the client listens.
+	router.Use(middleware.Maybe(middleware.Timeout(60*time.Second), func(r *http.Request) bool {
+		return !isStreamRequest(r)
+	}))
 
 	router.Post("/api/v1/users", s.Register)
 	router.Post("/api/v1/login", s.Login)
+	router.Post("/api/v1/tokens/refresh", s.RefreshToken)
+	router.Post("/api/v1/logout", s.Logout)
+	router.Post("/api/v1/users/verify-email", s.VerifyEmail)
+	router.With(s.Authenticate).Post("/api/v1/users/verify-email/resend", s.ResendVerificationEmail)
+	router.Post("/api/v1/password/forgot", s.ForgotPassword)
+	router.Post("/api/v1/password/reset", s.ResetPassword)
+
+	router.Route("/api/v1/users/me", func(r chi.Router) {
+		r.Use(s.Authenticate)
+
+		r.Get("/", s.GetProfile)
+		r.Patch("/", s.UpdateProfile)
+		r.Delete("/", s.DeleteAccount)
+		r.Put("/password", s.ChangePassword)
+		r.Put("/avatar", s.UploadAvatar)
+	})
+
+	router.Get("/api/v1/avatars/{avatarId}/{file}", s.GetAvatar)
+
+	router.Route("/api/v1/tasks", func(r chi.Router) {
+		r.Use(s.Authenticate)
+
+		r.With(s.RequireClaim(ClaimCreateTask), s.RequireVerifiedEmail).Post("/", s.CreateTask)
+		r.Get("/", s.ListTasks)
+		r.Get("/{id}", s.GetTask)
+		r.With(s.RequireClaim(ClaimEditTask)).Patch("/{id}", s.UpdateTask)
+		r.With(s.RequireClaim(ClaimDeleteTask)).Delete("/{id}", s.DeleteTask)
+		r.With(s.RequireClaim(ClaimEditTask)).Post("/{id}/transition", s.TransitionTask)
+
+		r.Get("/{id}/assignees", s.ListTaskAssignees)
+		r.With(s.RequireClaim(ClaimAssignTask)).Post("/{id}/assignees", s.AddTaskAssignees)
+		r.With(s.RequireClaim(ClaimAssignTask)).Delete("/{id}/assignees", s.RemoveTaskAssignees)
+
+		r.Get("/{id}/comments", s.ListTaskComments)
+		r.With(s.RequireVerifiedEmail).Post("/{id}/comments", s.CreateTaskComment)
+		r.Patch("/{id}/comments/{commentId}", s.UpdateTaskComment)
+		r.Delete("/{id}/comments/{commentId}", s.DeleteTaskComment)
+		r.Get("/{id}/comments/{commentId}/revisions", s.ListTaskCommentRevisions)
+
+		r.Get("/{id}/dependencies", s.GetTaskDependencies)
+		r.With(s.RequireClaim(ClaimEditTask)).Put("/{id}/parent", s.SetTaskParent)
+		r.With(s.RequireClaim(ClaimEditTask)).Post("/{id}/blockers", s.AddTaskBlocker)
+		r.With(s.RequireClaim(ClaimEditTask)).Delete("/{id}/blockers/{blockerId}", s.RemoveTaskBlocker)
+
+		r.Get("/{id}/watchers", s.ListTaskWatchers)
+		r.Put("/{id}/watch", s.WatchTask)
+		r.Delete("/{id}/watch", s.UnwatchTask)
+
+		r.Get("/{id}/attachments", s.ListTaskAttachments)
+		r.With(s.RequireClaim(ClaimEditTask)).Post("/{id}/attachments", s.UploadTaskAttachment)
+		r.With(s.RequireClaim(ClaimEditTask)).Delete("/{id}/attachments/{attachmentId}", s.DeleteTaskAttachment)
+	})
+
+	router.Route("/api/v1/notifications", func(r chi.Router) {
+		r.Use(s.Authenticate)
+
+		r.Get("/", s.ListNotifications)
+		r.Get("/unread-count", s.CountUnreadNotifications)
+		r.Post("/read", s.MarkAllNotificationsRead)
+		r.Post("/{id}/read", s.MarkNotificationRead)
+		r.Post("/{id}/unread", s.MarkNotificationUnread)
+		r.Get("/preferences", s.GetNotificationPreferences)
+		r.Patch("/preferences", s.UpdateNotificationPreferences)
+	})
+
+	router.Route(streamPathPrefix, func(r chi.Router) {
+		r.Use(s.Authenticate)
+
+		r.Get("/", s.StreamEvents)
+		r.Get("/ws", s.StreamEventsWebSocket)
+	})
+
+	router.Route("/api/v1/task-templates", func(r chi.Router) {
+		r.Use(s.Authenticate)
+
+		r.With(s.RequireClaim(ClaimCreateTask), s.RequireVerifiedEmail).Post("/", s.CreateTaskTemplate)
+		r.Get("/", s.ListTaskTemplates)
+		r.Get("/{id}", s.GetTaskTemplate)
+		r.With(s.RequireClaim(ClaimDeleteTask)).Delete("/{id}", s.DeleteTaskTemplate)
+	})
+
+	router.With(s.Authenticate).Get("/api/v1/workflow", s.GetDefaultWorkflow)
+	router.With(s.Authenticate).Get("/api/v1/search", s.Search)
+	router.Get("/api/v1/attachments/{id}/download", s.DownloadAttachment)
+
+	router.Route("/api/v1/teams", func(r chi.Router) {
+		r.Use(s.Authenticate)
+
+		r.With(s.RequireClaim(ClaimCreateTeam)).Post("/", s.CreateTeam)
+		r.Get("/", s.ListMyTeams)
+		r.Get("/{id}", s.GetTeam)
+		r.With(s.RequireClaim(ClaimEditTeam)).Patch("/{id}", s.UpdateTeam)
+		r.With(s.RequireClaim(ClaimDeleteTeam)).Delete("/{id}", s.DeleteTeam)
+		r.With(s.RequireClaim(ClaimEditTeam)).Post("/{id}/owner", s.TransferTeamOwnership)
+
+		r.Get("/{id}/members", s.ListTeamMembers)
+		r.With(s.RequireClaim(ClaimManageTeamMembers)).Post("/{id}/members", s.AddTeamMember)
+		r.With(s.RequireClaim(ClaimManageTeamMembers)).Delete("/{id}/members/{userId}", s.RemoveTeamMember)
+
+		r.Get("/{id}/workflow", s.GetTeamWorkflow)
+		r.Group(func(r chi.Router) {
+			r.Use(s.RequireClaim(ClaimEditTeam))
+
+			r.Post("/{id}/workflow/statuses", s.CreateTaskStatus)
+			r.Patch("/{id}/workflow/statuses/{statusId}", s.UpdateTaskStatus)
+			r.Delete("/{id}/workflow/statuses/{statusId}", s.DeleteTaskStatus)
+			r.Post("/{id}/workflow/transitions", s.AddTaskStatusTransition)
+			r.Delete("/{id}/workflow/transitions", s.RemoveTaskStatusTransition)
+
+			r.Post("/{id}/webhooks", s.CreateWebhook)
+			r.Get("/{id}/webhooks", s.ListWebhooks)
+			r.Get("/{id}/webhooks/{webhookId}", s.GetWebhook)
+			r.Patch("/{id}/webhooks/{webhookId}", s.UpdateWebhook)
+			r.Delete("/{id}/webhooks/{webhookId}", s.DeleteWebhook)
+			r.Get("/{id}/webhooks/{webhookId}/deliveries", s.ListWebhookDeliveries)
+			r.Post("/{id}/webhooks/{webhookId}/deliveries/{deliveryId}/redeliver", s.RedeliverWebhookDelivery)
+		})
+	})
+
+	router.Route("/api/v1/roles", func(r chi.Router) {
+		r.Use(s.Authenticate)
+
+		r.Group(func(r chi.Router) {
+			r.Use(s.RequireClaim(ClaimManageRoles))
+
+			r.Post("/", s.CreateRole)
+			r.Get("/", s.ListRoles)
+			r.Get("/{id}", s.GetRole)
+			r.Patch("/{id}", s.UpdateRole)
+			r.Delete("/{id}", s.DeleteRole)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(s.RequireClaim(ClaimManageClaims))
+
+			r.Get("/{id}/claims", s.ListRoleClaims)
+			r.Post("/{id}/claims", s.AddRoleClaim)
+			r.Delete("/{id}/claims/{claimId}", s.RemoveRoleClaim)
+		})
+	})
+
+	router.With(s.Authenticate, s.RequireClaim(ClaimManageClaims)).Get("/api/v1/claims", s.ListClaims)
+	router.With(s.Authenticate, s.RequireClaim(ClaimManageRoles)).Get("/api/v1/audit", s.ListAuditEvents)
+
+	router.Route("/api/v1/users/{id}/roles", func(r chi.Router) {
+		r.Use(s.Authenticate, s.RequireClaim(ClaimManageRoles))
+
+		r.Get("/", s.ListUserRoles)
+		r.Post("/", s.GrantUserRole)
+		r.Delete("/{roleId}", s.RevokeUserRole)
+	})
 
 	router.Get("/api/v1/health", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, "I am good")
@@ -62,10 +250,44 @@ func (s *Server) setupRoutes() {
 	s.router = router
 }
 
-func (s *Server) StartServer(addr string) {
+// runInBackground runs fn without holding up the response to the request of
+// ctx. fn gets a context that keeps the request's values but not its
+// cancellation.
+func (s *Server) runInBackground(ctx context.Context, fn func(ctx context.Context)) {
+	ctx = context.WithoutCancel(ctx)
+
+	s.background.Add(1)
+	go func() {
+		defer s.background.Done()
+		fn(ctx)
+	}()
+}
+
+// StartServer serves requests on addr until ctx is done. It then stops
+// accepting connections, lets the requests in flight finish and waits for the
+// work they left running in the background before returning.
+func (s *Server) StartServer(ctx context.Context, addr string) {
+	httpServer := &http.Server{Addr: addr, Handler: s.router}
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			httpServer.Close()
+		}
+	}()
+
 	fmt.Println("Server listening at ", addr)
-	err := http.ListenAndServe(addr, s.router)
-	if err != nil {
+	err := httpServer.ListenAndServe()
+	if !errors.Is(err, http.ErrServerClosed) {
 		log.Fatal(fmt.Errorf("unable to start server: %v", err))
 	}
+
+	<-stopped
+	s.background.Wait()
 }
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/token"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+)
+
+const maxUserAgentLength = 255
+
+var (
+	errSessionRevoked     = errors.New("session has been revoked")
+	errRefreshTokenReused = errors.New("refresh token has already been used")
+)
+
+type refreshTokenPayload struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+type refreshTokenResponse struct {
+	Token                 string    `json:"token"`
+	ExpiresAt             time.Time `json:"expiresAt"`
+	RefreshToken          string    `json:"refreshToken"`
+	RefreshTokenExpiresAt time.Time `json:"refreshTokenExpiresAt"`
+}
+
+// RefreshToken exchanges a refresh token for a new access token and a new
+// refresh token. Each refresh token can be used once; presenting one that has
+// already been rotated revokes every session descended from the same login.
+func (s *Server) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	session, payload, ok := s.loadSession(w, r)
+	if !ok {
+		return
+	}
+
+	if session.IsRevoked {
+		unauthorized(w, r, errSessionRevoked)
+		return
+	}
+
+	if session.RotatedAt != nil {
+		s.revokeReusedSession(w, r, session)
+		return
+	}
+
+	user, err := s.store.GetUserById(ctx, payload.UserID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			unauthorized(w, r, token.ErrInvalidToken)
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	if user.ID != session.UserID {
+		unauthorized(w, r, token.ErrInvalidToken)
+		return
+	}
+
+	refreshToken, refreshPayload, next, err := s.newSession(r, user, session.FamilyID)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	_, err = s.store.ReplaceSession(ctx, db.ReplaceSessionParams{
+		ID:   session.ID,
+		Next: next,
+	})
+	if err != nil {
+		// Another request rotated or revoked the session after it was read
+		// above, which means the same refresh token was used twice.
+		if errors.Is(err, db.ErrSessionRotated) {
+			s.revokeReusedSession(w, r, session)
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	accessToken, accessPayload, err := s.tokenMaker.CreateToken(user.ID, user.Email, token.TokenTypeAccessToken, s.config.AccessTokenDuration)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	response := refreshTokenResponse{
+		Token:                 accessToken,
+		ExpiresAt:             accessPayload.ExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshPayload.ExpiresAt,
+	}
+
+	render.Render(w, r, SuccessfulResponse(response))
+}
+
+// Logout revokes the session the refresh token belongs to, along with every
+// other session issued from the same login.
+func (s *Server) Logout(w http.ResponseWriter, r *http.Request) {
+	session, _, ok := s.loadSession(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.store.RevokeSessionFamily(r.Context(), session.FamilyID); err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(nil))
+}
+
+// loadSession decodes the refresh token in the request body and fetches the
+// session it was issued for, writing the error response itself when it cannot.
+func (s *Server) loadSession(w http.ResponseWriter, r *http.Request) (db.Session, *token.Payload, bool) {
+	var payload refreshTokenPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return db.Session{}, nil, false
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return db.Session{}, nil, false
+	}
+
+	tokenPayload, err := s.tokenMaker.ValidateToken(payload.RefreshToken, token.TokenTypeRefreshToken)
+	if err != nil {
+		if errors.Is(err, token.ErrExpiredToken) {
+			unauthorized(w, r, token.ErrExpiredToken)
+			return db.Session{}, nil, false
+		}
+		unauthorized(w, r, token.ErrInvalidToken)
+		return db.Session{}, nil, false
+	}
+
+	session, err := s.store.GetSession(r.Context(), tokenPayload.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			unauthorized(w, r, token.ErrInvalidToken)
+			return db.Session{}, nil, false
+		}
+		render.Render(w, r, ErrInternalServer())
+		return db.Session{}, nil, false
+	}
+
+	return session, tokenPayload, true
+}
+
+// revokeReusedSession handles a refresh token that was presented after it had
+// already been rotated. The token may have been stolen, so the whole family is
+// revoked and the legitimate client has to log in again.
+func (s *Server) revokeReusedSession(w http.ResponseWriter, r *http.Request, session db.Session) {
+	if err := s.store.RevokeSessionFamily(r.Context(), session.FamilyID); err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	unauthorized(w, r, errRefreshTokenReused)
+}
+
+// newSession issues a refresh token for the user and returns the session row
+// to record for it in the given family.
+func (s *Server) newSession(r *http.Request, user db.User, familyID uuid.UUID) (string, *token.Payload, db.CreateSessionParams, error) {
+	refreshToken, payload, err := s.tokenMaker.CreateToken(user.ID, user.Email, token.TokenTypeRefreshToken, s.config.RefreshTokenDuration)
+	if err != nil {
+		return "", nil, db.CreateSessionParams{}, err
+	}
+
+	userAgent := r.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	session := db.CreateSessionParams{
+		ID:        payload.ID,
+		FamilyID:  familyID,
+		UserID:    user.ID,
+		UserAgent: userAgent,
+		ClientIp:  clientIP(r),
+		ExpiresAt: payload.ExpiresAt,
+	}
+
+	return refreshToken, payload, session, nil
+}
+
+// clientIP returns the caller's address without the port. RemoteAddr has
+// already been rewritten by the RealIP middleware when the request came
+// through a proxy.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,296 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/token"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLoginApi(t *testing.T) {
+	user, password := randomUser(t)
+	user.ID = 1
+
+	testCases := []struct {
+		name          string
+		payload       map[string]any
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			payload: map[string]any{
+				"email":    user.Email,
+				"password": password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.CreateSessionParams) (db.Session, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.NotEqual(t, uuid.Nil, arg.FamilyID)
+						return db.Session{ID: arg.ID, FamilyID: arg.FamilyID, UserID: arg.UserID}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Data loginResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.NotEmpty(t, response.Data.Token)
+				require.NotEmpty(t, response.Data.RefreshToken)
+				require.True(t, response.Data.RefreshTokenExpiresAt.After(response.Data.ExpiresAt))
+			},
+		},
+		{
+			name: "BadRequest: Wrong Password",
+			payload: map[string]any{
+				"email":    user.Email,
+				"password": password + "x",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Server Error: Session",
+			payload: map[string]any{
+				"email":    user.Email,
+				"password": password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Session{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/login", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRefreshTokenApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	familyID := uuid.New()
+	rotatedAt := time.Now()
+
+	testCases := []struct {
+		name          string
+		tokenType     token.TokenType
+		session       func(payload *token.Payload) db.Session
+		buildStubs    func(store *mockdb.MockStore, session db.Session)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			tokenType: token.TokenTypeRefreshToken,
+			session: func(payload *token.Payload) db.Session {
+				return db.Session{ID: payload.ID, FamilyID: familyID, UserID: user.ID, ExpiresAt: payload.ExpiresAt}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().GetUserById(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					ReplaceSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.ReplaceSessionParams) (db.Session, error) {
+						require.Equal(t, session.ID, arg.ID)
+						require.Equal(t, familyID, arg.Next.FamilyID)
+						require.NotEqual(t, session.ID, arg.Next.ID)
+						return db.Session{ID: arg.Next.ID, FamilyID: arg.Next.FamilyID, UserID: arg.Next.UserID}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Data refreshTokenResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.NotEmpty(t, response.Data.Token)
+				require.NotEmpty(t, response.Data.RefreshToken)
+			},
+		},
+		{
+			name:      "Unauthorized: Reused Token Revokes Family",
+			tokenType: token.TokenTypeRefreshToken,
+			session: func(payload *token.Payload) db.Session {
+				return db.Session{ID: payload.ID, FamilyID: familyID, UserID: user.ID, RotatedAt: &rotatedAt}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().RevokeSessionFamily(gomock.Any(), gomock.Eq(familyID)).Times(1).Return(nil)
+				store.EXPECT().ReplaceSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errRefreshTokenReused.Error())
+			},
+		},
+		{
+			name:      "Unauthorized: Concurrent Reuse Revokes Family",
+			tokenType: token.TokenTypeRefreshToken,
+			session: func(payload *token.Payload) db.Session {
+				return db.Session{ID: payload.ID, FamilyID: familyID, UserID: user.ID}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().GetUserById(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil)
+				store.EXPECT().
+					ReplaceSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Session{}, db.ErrSessionRotated)
+				store.EXPECT().RevokeSessionFamily(gomock.Any(), gomock.Eq(familyID)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "Unauthorized: Revoked Session",
+			tokenType: token.TokenTypeRefreshToken,
+			session: func(payload *token.Payload) db.Session {
+				return db.Session{ID: payload.ID, FamilyID: familyID, UserID: user.ID, IsRevoked: true}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+				store.EXPECT().ReplaceSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errSessionRevoked.Error())
+			},
+		},
+		{
+			name:      "Unauthorized: Unknown Session",
+			tokenType: token.TokenTypeRefreshToken,
+			session: func(payload *token.Payload) db.Session {
+				return db.Session{ID: payload.ID}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(db.Session{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "Unauthorized: Access Token",
+			tokenType: token.TokenTypeAccessToken,
+			session: func(payload *token.Payload) db.Session {
+				return db.Session{ID: payload.ID}
+			},
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+
+			refreshToken, payload, err := server.tokenMaker.CreateToken(user.ID, user.Email, tt.tokenType, time.Minute)
+			require.NoError(t, err)
+
+			tt.buildStubs(store, tt.session(payload))
+
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(map[string]any{"refreshToken": refreshToken})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/tokens/refresh", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestLogoutApi(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := randomAuthenticatedUser(t)
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+
+	refreshToken, payload, err := server.tokenMaker.CreateToken(user.ID, user.Email, token.TokenTypeRefreshToken, time.Minute)
+	require.NoError(t, err)
+
+	session := db.Session{ID: payload.ID, FamilyID: uuid.New(), UserID: user.ID}
+	store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+	store.EXPECT().RevokeSessionFamily(gomock.Any(), gomock.Eq(session.FamilyID)).Times(1).Return(nil)
+
+	recorder := httptest.NewRecorder()
+
+	jsonBody, err := json.Marshal(map[string]any{"refreshToken": refreshToken})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/api/v1/logout", bytes.NewBuffer(jsonBody))
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
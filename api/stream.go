@@ -0,0 +1,413 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/stream"
+	"github.com/bolusarz/task-manager/token"
+	"github.com/go-chi/render"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	streamPathPrefix = "/api/v1/stream"
+
+	// Browsers cannot set headers on EventSource or WebSocket requests, so
+	// streams also take the last event ID seen from the query, like the
+	// access token.
+	lastEventIDHeader   = "Last-Event-ID"
+	lastEventIDQueryKey = "lastEventId"
+
+	defaultStreamHeartbeat = 15 * time.Second
+	// streamRetryDelay is how long browsers wait before reconnecting a
+	// Server-Sent Events stream that dropped.
+	streamRetryDelay      = 3 * time.Second
+	streamWriteTimeout    = 10 * time.Second
+	streamReplayBatchSize = 100
+	// streamReplayWindow is how far back a resumed stream replays. A client
+	// that was away longer misses the older events and should reload.
+	streamReplayWindow = 24 * time.Hour
+)
+
+var errUnknownLastEvent = errors.New("unknown last event ID")
+
+// eventWriter writes the events of a stream in the format of its transport.
+type eventWriter interface {
+	event(event stream.Event) error
+	heartbeat() error
+}
+
+// StreamEvents streams the task and team events of the caller's teams as
+// Server-Sent Events. Each event is named after its type and carries its ID,
+// which the browser sends back as Last-Event-ID when it reconnects, so the
+// events missed in between are replayed first.
+func (s *Server) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	lastEvent, ok := s.loadLastEvent(w, r)
+	if !ok {
+		return
+	}
+
+	sub, teamIDs, ok := s.subscribe(w, r)
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	out := &sseWriter{w: w, controller: http.NewResponseController(w)}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// Proxies that buffer responses would hold the events back.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if err := out.write("retry: %d\n\n", streamRetryDelay.Milliseconds()); err != nil {
+		return
+	}
+
+	s.serveStream(ctx, sub, teamIDs, lastEvent, out)
+}
+
+// StreamEventsWebSocket is StreamEvents over a WebSocket: every event is a
+// JSON text message, the heartbeat is a ping, and the last event ID seen is
+// passed in the query. Messages from the client are ignored.
+func (s *Server) StreamEventsWebSocket(w http.ResponseWriter, r *http.Request) {
+	lastEvent, ok := s.loadLastEvent(w, r)
+	if !ok {
+		return
+	}
+
+	sub, teamIDs, ok := s.subscribe(w, r)
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	server := websocket.Server{
+		// The stream is authenticated by a token rather than cookies, so
+		// other origins get nothing they could not have asked for anyway.
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(conn *websocket.Conn) {
+			ctx, cancel := context.WithCancel(r.Context())
+			defer cancel()
+
+			// Reading is what answers the client's pings and notices it
+			// going away.
+			go func() {
+				defer cancel()
+				for {
+					var message []byte
+					if err := websocket.Message.Receive(conn, &message); err != nil {
+						return
+					}
+				}
+			}()
+
+			s.serveStream(ctx, sub, teamIDs, lastEvent, &webSocketWriter{conn: conn})
+		},
+	}
+
+	server.ServeHTTP(w, r)
+}
+
+// subscribe subscribes the caller to the events of the teams they belong to.
+func (s *Server) subscribe(w http.ResponseWriter, r *http.Request) (*stream.Subscription, []int32, bool) {
+	principal := principalFromContext(r.Context())
+
+	teams, err := s.store.ListTeamsByUser(r.Context(), principal.User.ID)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return nil, nil, false
+	}
+
+	teamIDs := make([]int32, len(teams))
+	for i, team := range teams {
+		teamIDs[i] = team.ID
+	}
+
+	return s.hub.Subscribe(principal.User.ID, teamIDs), teamIDs, true
+}
+
+// serveStream replays the events of teamIDs after lastEvent and then writes
+// the events of sub as they come, until the client goes away, cannot keep up
+// or is no longer allowed the stream. Subscribing before replaying leaves no
+// gap between the two; the events in both are only written once.
+func (s *Server) serveStream(ctx context.Context, sub *stream.Subscription, teamIDs []int32, lastEvent db.OutboxEvent, out eventWriter) {
+	principal := principalFromContext(ctx)
+
+	replayed, err := s.replayEvents(ctx, teamIDs, lastEvent, out)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("unable to replay stream events: %v", err)
+		}
+		return
+	}
+
+	heartbeat := time.NewTicker(s.streamHeartbeat())
+	defer heartbeat.Stop()
+
+	// The client reconnects with a fresh token once this one expires.
+	expired := time.NewTimer(time.Until(principal.Payload.ExpiresAt))
+	defer expired.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-expired.C:
+			return
+		case event, ok := <-sub.Events():
+			// The hub dropped the stream for falling behind. The client
+			// resumes it from the last event it got.
+			if !ok {
+				return
+			}
+
+			switch e := event.Data.(type) {
+			case db.SessionsRevoked:
+				// The event is for the server, not the client.
+				if !s.streamAllowed(ctx, principal, sub) {
+					return
+				}
+				continue
+			case db.MemberLeftTeam:
+				if e.UserID == principal.User.ID && !s.streamAllowed(ctx, principal, sub) {
+					return
+				}
+			}
+
+			if replayed[event.ID] {
+				continue
+			}
+			if err := out.event(event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := out.heartbeat(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// streamAllowed reports whether the caller may still have sub: their token
+// was not revoked since it was issued, and they still belong to every team
+// sub follows. A stream that fails the check is ended, and the client's
+// reconnecting checks them afresh.
+func (s *Server) streamAllowed(ctx context.Context, principal *Principal, sub *stream.Subscription) bool {
+	if _, err := s.tokenUser(ctx, principal.Payload); err != nil {
+		if !errors.Is(err, token.ErrInvalidToken) && !errors.Is(err, errTokenRevoked) {
+			log.Printf("unable to check stream of user %d: %v", principal.User.ID, err)
+		}
+		return false
+	}
+
+	teams, err := s.store.ListTeamsByUser(ctx, principal.User.ID)
+	if err != nil {
+		log.Printf("unable to check stream of user %d: %v", principal.User.ID, err)
+		return false
+	}
+
+	for _, teamID := range sub.Teams() {
+		if !slices.ContainsFunc(teams, func(team db.Team) bool { return team.ID == teamID }) {
+			return false
+		}
+	}
+	return true
+}
+
+// replayEvents writes the events of teamIDs committed after lastEvent and
+// returns the IDs of those it wrote. A lastEvent with no ID is a new stream,
+// which starts from now. Only events of the last streamReplayWindow are
+// replayed, and of each team only those since the caller joined it.
+func (s *Server) replayEvents(ctx context.Context, teamIDs []int32, lastEvent db.OutboxEvent, out eventWriter) (map[int64]bool, error) {
+	replayed := make(map[int64]bool)
+	if lastEvent.ID == 0 || len(teamIDs) == 0 {
+		return replayed, nil
+	}
+
+	arg := db.ListTeamOutboxEventsAfterParams{
+		UserID:    principalFromContext(ctx).User.ID,
+		TeamIds:   teamIDs,
+		Since:     time.Now().Add(-streamReplayWindow),
+		AfterTxID: lastEvent.TxID,
+		AfterID:   lastEvent.ID,
+		Limit:     streamReplayBatchSize,
+	}
+
+	for {
+		rows, err := s.store.ListTeamOutboxEventsAfter(ctx, arg)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			arg.AfterTxID, arg.AfterID = row.TxID, row.ID
+
+			event, err := stream.NewEvent(row)
+			if err != nil {
+				log.Printf("not replaying outbox event %d (%s): %v", row.ID, row.EventType, err)
+				continue
+			}
+
+			if err := out.event(event); err != nil {
+				return nil, err
+			}
+			replayed[event.ID] = true
+		}
+
+		if len(rows) < streamReplayBatchSize {
+			return replayed, nil
+		}
+	}
+}
+
+func (s *Server) streamHeartbeat() time.Duration {
+	if s.config.StreamHeartbeatInterval > 0 {
+		return s.config.StreamHeartbeatInterval
+	}
+	return defaultStreamHeartbeat
+}
+
+// loadLastEvent loads the last event the client got, which the stream resumes
+// after. It has no ID for a new stream. An event the caller could not have
+// been streamed, one of a team they are not in or from before they joined
+// it, is reported as unknown like an ID that does not exist.
+func (s *Server) loadLastEvent(w http.ResponseWriter, r *http.Request) (db.OutboxEvent, bool) {
+	ctx := r.Context()
+
+	lastEventID, err := parseLastEventID(r)
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return db.OutboxEvent{}, false
+	}
+	if lastEventID == 0 {
+		return db.OutboxEvent{}, true
+	}
+
+	unknown := ErrInvalidRequest(fmt.Errorf("%w %d", errUnknownLastEvent, lastEventID))
+
+	lastEvent, err := s.store.GetOutboxEvent(ctx, lastEventID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			render.Render(w, r, unknown)
+			return db.OutboxEvent{}, false
+		}
+		render.Render(w, r, ErrInternalServer())
+		return db.OutboxEvent{}, false
+	}
+
+	if lastEvent.TeamID == nil {
+		render.Render(w, r, unknown)
+		return db.OutboxEvent{}, false
+	}
+
+	member, err := s.store.GetTeamMember(ctx, db.GetTeamMemberParams{
+		TeamID: *lastEvent.TeamID,
+		UserID: principalFromContext(ctx).User.ID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			render.Render(w, r, unknown)
+			return db.OutboxEvent{}, false
+		}
+		render.Render(w, r, ErrInternalServer())
+		return db.OutboxEvent{}, false
+	}
+
+	if lastEvent.CreatedAt.Before(member.JoinedAt) {
+		render.Render(w, r, unknown)
+		return db.OutboxEvent{}, false
+	}
+	return lastEvent, true
+}
+
+// parseLastEventID reads the ID of the last event the client got, from the
+// Last-Event-ID header or the lastEventId query parameter. It is zero for a
+// new stream.
+func parseLastEventID(r *http.Request) (int64, error) {
+	raw := r.Header.Get(lastEventIDHeader)
+	if raw == "" {
+		raw = r.URL.Query().Get(lastEventIDQueryKey)
+	}
+	if raw == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id < 0 {
+		return 0, fmt.Errorf("invalid last event ID %q", raw)
+	}
+	return id, nil
+}
+
+// isStreamRequest reports whether r is for one of the long-lived stream
+// endpoints: the stream path itself or one below it, but not a path that
+// merely starts with the same letters.
+func isStreamRequest(r *http.Request) bool {
+	return r.URL.Path == streamPathPrefix || strings.HasPrefix(r.URL.Path, streamPathPrefix+"/")
+}
+
+type sseWriter struct {
+	w          http.ResponseWriter
+	controller *http.ResponseController
+}
+
+func (sw *sseWriter) event(event stream.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return sw.write("id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+}
+
+func (sw *sseWriter) heartbeat() error {
+	return sw.write(": heartbeat\n\n")
+}
+
+// write writes and flushes one message, giving up on a client that does not
+// take it in time.
+func (sw *sseWriter) write(format string, args ...any) error {
+	// Not every writer supports deadlines; the write is then only bounded
+	// by the client going away.
+	sw.controller.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+
+	if _, err := fmt.Fprintf(sw.w, format, args...); err != nil {
+		return err
+	}
+	return sw.controller.Flush()
+}
+
+type webSocketWriter struct {
+	conn *websocket.Conn
+}
+
+// ping sends an empty ping frame.
+var ping = websocket.Codec{
+	Marshal: func(any) ([]byte, byte, error) {
+		return nil, websocket.PingFrame, nil
+	},
+}
+
+func (ww *webSocketWriter) event(event stream.Event) error {
+	ww.conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	return websocket.JSON.Send(ww.conn, event)
+}
+
+func (ww *webSocketWriter) heartbeat() error {
+	ww.conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	return ping.Send(ww.conn, nil)
+}
@@ -0,0 +1,551 @@
+package api
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/stream"
+	"github.com/bolusarz/task-manager/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/net/websocket"
+)
+
+func randomOutboxEvent(t *testing.T, id int64, teamID int32) db.OutboxEvent {
+	payload, err := json.Marshal(db.TaskDeleted{TaskID: int32(id), TeamID: &teamID})
+	require.NoError(t, err)
+
+	return db.OutboxEvent{
+		ID:        id,
+		EventType: string(db.EventTaskDeleted),
+		Payload:   payload,
+		TeamID:    &teamID,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+func streamEvent(t *testing.T, row db.OutboxEvent) stream.Event {
+	event, err := stream.NewEvent(row)
+	require.NoError(t, err)
+	return event
+}
+
+// stubLastEvent stubs loading last as the last event user got, as a member
+// of its team since an hour before it.
+func stubLastEvent(store *mockdb.MockStore, user db.User, last db.OutboxEvent) {
+	store.EXPECT().
+		GetOutboxEvent(gomock.Any(), gomock.Eq(last.ID)).
+		Times(1).
+		Return(last, nil)
+	store.EXPECT().
+		GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: *last.TeamID, UserID: user.ID})).
+		Times(1).
+		Return(db.TeamMember{TeamID: *last.TeamID, UserID: user.ID, JoinedAt: last.CreatedAt.Add(-time.Hour)}, nil)
+}
+
+// replayArgMatcher matches the params of a replay after last for user, whose
+// window starts streamReplayWindow before now.
+func replayArgMatcher(user db.User, last db.OutboxEvent) gomock.Matcher {
+	return gomock.Cond(func(arg db.ListTeamOutboxEventsAfterParams) bool {
+		if arg.Since.Before(time.Now().Add(-streamReplayWindow-time.Minute)) || arg.Since.After(time.Now().Add(-streamReplayWindow)) {
+			return false
+		}
+		arg.Since = time.Time{}
+		return reflect.DeepEqual(arg, db.ListTeamOutboxEventsAfterParams{
+			UserID:    user.ID,
+			TeamIds:   []int32{*last.TeamID},
+			AfterTxID: last.TxID,
+			AfterID:   last.ID,
+			Limit:     streamReplayBatchSize,
+		})
+	})
+}
+
+// streamURL is the URL of a stream endpoint that authenticates user through
+// the query, as a browser would.
+func streamURL(t *testing.T, server *Server, base, path string, user db.User, query url.Values) string {
+	accessToken, _, err := server.tokenMaker.CreateToken(user.ID, user.Email, token.TokenTypeAccessToken, time.Minute)
+	require.NoError(t, err)
+
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set(accessTokenQueryKey, accessToken)
+
+	return base + path + "?" + query.Encode()
+}
+
+// readSSE reads the next message of a Server-Sent Events stream, as its
+// lines.
+func readSSE(t *testing.T, reader *bufio.Reader) []string {
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
+
+// readSSEEvent reads the next event of a Server-Sent Events stream, skipping
+// heartbeats.
+func readSSEEvent(t *testing.T, reader *bufio.Reader) []string {
+	for {
+		if lines := readSSE(t, reader); !slices.Equal(lines, []string{": heartbeat"}) {
+			return lines
+		}
+	}
+}
+
+func TestStreamEventsAPI(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	team := randomTeam(user.ID)
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.TokenMaker)
+		lastEventID   string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "Unauthorized",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.TokenMaker) {},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListTeamsByUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Invalid Last Event ID",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.TokenMaker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user, time.Minute)
+			},
+			lastEventID: "last",
+			buildStubs: func(store *mockdb.MockStore) {
+				stubAuthenticatedUser(store, user)
+				store.EXPECT().ListTeamsByUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Unknown Last Event ID",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.TokenMaker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user, time.Minute)
+			},
+			lastEventID: "5",
+			buildStubs: func(store *mockdb.MockStore) {
+				stubAuthenticatedUser(store, user)
+				store.EXPECT().
+					GetOutboxEvent(gomock.Any(), gomock.Eq(int64(5))).
+					Times(1).
+					Return(db.OutboxEvent{}, db.ErrRecordNotFound)
+				store.EXPECT().ListTeamsByUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Last Event Of Other Team",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.TokenMaker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user, time.Minute)
+			},
+			lastEventID: "5",
+			buildStubs: func(store *mockdb.MockStore) {
+				stubAuthenticatedUser(store, user)
+				store.EXPECT().
+					GetOutboxEvent(gomock.Any(), gomock.Eq(int64(5))).
+					Times(1).
+					Return(randomOutboxEvent(t, 5, team.ID+1), nil)
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: team.ID + 1, UserID: user.ID})).
+					Times(1).
+					Return(db.TeamMember{}, db.ErrRecordNotFound)
+				store.EXPECT().ListTeamsByUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "unknown last event ID 5")
+			},
+		},
+		{
+			name: "Last Event Before Joining",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.TokenMaker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user, time.Minute)
+			},
+			lastEventID: "5",
+			buildStubs: func(store *mockdb.MockStore) {
+				last := randomOutboxEvent(t, 5, team.ID)
+
+				stubAuthenticatedUser(store, user)
+				store.EXPECT().
+					GetOutboxEvent(gomock.Any(), gomock.Eq(int64(5))).
+					Times(1).
+					Return(last, nil)
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: team.ID, UserID: user.ID})).
+					Times(1).
+					Return(db.TeamMember{TeamID: team.ID, UserID: user.ID, JoinedAt: last.CreatedAt.Add(time.Minute)}, nil)
+				store.EXPECT().ListTeamsByUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "unknown last event ID 5")
+			},
+		},
+		{
+			name: "Internal Server Error",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.TokenMaker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				stubAuthenticatedUser(store, user)
+				store.EXPECT().
+					ListTeamsByUser(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "Replay Fails",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.TokenMaker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user, time.Minute)
+			},
+			lastEventID: "5",
+			buildStubs: func(store *mockdb.MockStore) {
+				stubAuthenticatedUser(store, user)
+				stubLastEvent(store, user, randomOutboxEvent(t, 5, team.ID))
+				store.EXPECT().
+					ListTeamsByUser(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return([]db.Team{team}, nil)
+				store.EXPECT().
+					ListTeamOutboxEventsAfter(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				// The stream had started, so it just ends for the client to
+				// reconnect.
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, fmt.Sprintf("retry: %d\n\n", streamRetryDelay.Milliseconds()), recorder.Body.String())
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/v1/stream", nil)
+			require.NoError(t, err)
+			if tc.lastEventID != "" {
+				request.Header.Set(lastEventIDHeader, tc.lastEventID)
+			}
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestStreamEventsResumesAPI(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	team := randomTeam(user.ID)
+
+	last := randomOutboxEvent(t, 5, team.ID)
+	last.TxID = 100
+	// The missed event was given a lower ID than the last one, but
+	// committed after it.
+	missed := randomOutboxEvent(t, 4, team.ID)
+	missed.TxID = 101
+	next := randomOutboxEvent(t, 7, team.ID)
+	next.TxID = 102
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	stubAuthenticatedUser(store, user)
+	stubLastEvent(store, user, last)
+	store.EXPECT().
+		ListTeamsByUser(gomock.Any(), gomock.Eq(user.ID)).
+		Times(1).
+		Return([]db.Team{team}, nil)
+	store.EXPECT().
+		ListTeamOutboxEventsAfter(gomock.Any(), replayArgMatcher(user, last)).
+		Times(1).
+		Return([]db.OutboxEvent{missed}, nil)
+
+	server := newTestServer(t, store)
+	server.config.StreamHeartbeatInterval = 50 * time.Millisecond
+
+	ts := httptest.NewServer(server.router)
+	defer ts.Close()
+
+	request, err := http.NewRequest(http.MethodGet, streamURL(t, server, ts.URL, "/api/v1/stream", user, nil), nil)
+	require.NoError(t, err)
+	request.Header.Set(lastEventIDHeader, "5")
+
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(response.Body)
+	require.Equal(t, []string{fmt.Sprintf("retry: %d", streamRetryDelay.Milliseconds())}, readSSE(t, reader))
+
+	data, err := json.Marshal(streamEvent(t, missed))
+	require.NoError(t, err)
+	require.Equal(t, []string{"id: 4", "event: task.deleted", "data: " + string(data)}, readSSE(t, reader))
+
+	// The replayed event coming in live as well is only sent once.
+	server.hub.Publish(streamEvent(t, missed))
+	server.hub.Publish(streamEvent(t, randomOutboxEvent(t, 8, team.ID+1)))
+	server.hub.Publish(streamEvent(t, next))
+
+	data, err = json.Marshal(streamEvent(t, next))
+	require.NoError(t, err)
+	require.Equal(t, []string{"id: 7", "event: task.deleted", "data: " + string(data)}, readSSEEvent(t, reader))
+
+	require.Equal(t, []string{": heartbeat"}, readSSE(t, reader))
+}
+
+func TestStreamEventsEndsAPI(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	team := randomTeam(user.ID)
+	other := randomTeam(user.ID)
+
+	revoked := user
+	changedAt := time.Now().Add(time.Minute)
+	revoked.PasswordChangedAt = &changedAt
+
+	left := streamEvent(t, randomOutboxEvent(t, 6, team.ID))
+	left.Type = db.EventMemberLeftTeam
+	left.Data = db.MemberLeftTeam{TeamID: team.ID, UserID: user.ID}
+
+	testCases := []struct {
+		name          string
+		tokenDuration time.Duration
+		buildStubs    func(store *mockdb.MockStore)
+		publish       func(hub *stream.Hub)
+	}{
+		{
+			name:          "Token Expired",
+			tokenDuration: 500 * time.Millisecond,
+			buildStubs: func(store *mockdb.MockStore) {
+				stubAuthenticatedUser(store, user)
+			},
+			publish: func(hub *stream.Hub) {},
+		},
+		{
+			name:          "Sessions Revoked",
+			tokenDuration: time.Minute,
+			buildStubs: func(store *mockdb.MockStore) {
+				gomock.InOrder(
+					store.EXPECT().GetUserById(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(user, nil),
+					store.EXPECT().GetUserById(gomock.Any(), gomock.Eq(user.ID)).Times(1).Return(revoked, nil),
+				)
+			},
+			publish: func(hub *stream.Hub) {
+				hub.Publish(stream.Event{ID: 6, Type: db.EventSessionsRevoked, Data: db.SessionsRevoked{UserID: user.ID}})
+			},
+		},
+		{
+			// The user left the other team as well, which the stream has
+			// not heard of yet.
+			name:          "Left Teams",
+			tokenDuration: time.Minute,
+			buildStubs: func(store *mockdb.MockStore) {
+				stubAuthenticatedUser(store, user)
+				store.EXPECT().
+					ListTeamsByUser(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return([]db.Team{}, nil)
+			},
+			publish: func(hub *stream.Hub) {
+				hub.Publish(left)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mockdb.NewMockStore(ctrl)
+
+			store.EXPECT().
+				ListTeamsByUser(gomock.Any(), gomock.Eq(user.ID)).
+				Times(1).
+				Return([]db.Team{team, other}, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+
+			ts := httptest.NewServer(server.router)
+			defer ts.Close()
+
+			request, err := http.NewRequest(http.MethodGet, ts.URL+"/api/v1/stream", nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, tc.tokenDuration)
+
+			response, err := http.DefaultClient.Do(request)
+			require.NoError(t, err)
+			defer response.Body.Close()
+			require.Equal(t, http.StatusOK, response.StatusCode)
+
+			reader := bufio.NewReader(response.Body)
+			require.Equal(t, []string{fmt.Sprintf("retry: %d", streamRetryDelay.Milliseconds())}, readSSE(t, reader))
+
+			tc.publish(server.hub)
+
+			// Whatever was sent before, the stream ends.
+			body, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			require.NotContains(t, string(body), string(db.EventSessionsRevoked))
+		})
+	}
+}
+
+func TestStreamEventsWebSocketAPI(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	team := randomTeam(user.ID)
+
+	last := randomOutboxEvent(t, 5, team.ID)
+	last.TxID = 100
+	missed := randomOutboxEvent(t, 4, team.ID)
+	missed.TxID = 101
+	next := randomOutboxEvent(t, 7, team.ID)
+	next.TxID = 102
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	stubAuthenticatedUser(store, user)
+	stubLastEvent(store, user, last)
+	store.EXPECT().
+		ListTeamsByUser(gomock.Any(), gomock.Eq(user.ID)).
+		Times(1).
+		Return([]db.Team{team}, nil)
+	store.EXPECT().
+		ListTeamOutboxEventsAfter(gomock.Any(), replayArgMatcher(user, last)).
+		Times(1).
+		Return([]db.OutboxEvent{missed}, nil)
+
+	server := newTestServer(t, store)
+
+	ts := httptest.NewServer(server.router)
+	defer ts.Close()
+
+	base := "ws" + strings.TrimPrefix(ts.URL, "http")
+	location := streamURL(t, server, base, "/api/v1/stream/ws", user, url.Values{lastEventIDQueryKey: {"5"}})
+
+	conn, err := websocket.Dial(location, "", ts.URL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	var message struct {
+		ID     int64        `json:"id"`
+		Type   db.EventType `json:"type"`
+		TeamID int32        `json:"teamId"`
+	}
+
+	require.NoError(t, websocket.JSON.Receive(conn, &message))
+	require.Equal(t, missed.ID, message.ID)
+	require.Equal(t, db.EventTaskDeleted, message.Type)
+	require.Equal(t, team.ID, message.TeamID)
+
+	server.hub.Publish(streamEvent(t, missed))
+	server.hub.Publish(streamEvent(t, next))
+
+	require.NoError(t, websocket.JSON.Receive(conn, &message))
+	require.Equal(t, next.ID, message.ID)
+}
+
+func TestStreamTokenFromQuery(t *testing.T) {
+	testCases := []struct {
+		name              string
+		target            string
+		header            string
+		wantAuthorization string
+		wantRequestURI    string
+	}{
+		{
+			name:              "Stream",
+			target:            "/api/v1/stream?access_token=secret&lastEventId=3",
+			wantAuthorization: "bearer secret",
+			wantRequestURI:    "/api/v1/stream?lastEventId=3",
+		},
+		{
+			name:              "Header Wins",
+			target:            "/api/v1/stream/ws?access_token=secret",
+			header:            "bearer other",
+			wantAuthorization: "bearer other",
+			wantRequestURI:    "/api/v1/stream/ws",
+		},
+		{
+			name:           "Not A Stream",
+			target:         "/api/v1/tasks?access_token=secret",
+			wantRequestURI: "/api/v1/tasks?access_token=secret",
+		},
+		{
+			name:           "Same Prefix",
+			target:         "/api/v1/streams?access_token=secret",
+			wantRequestURI: "/api/v1/streams?access_token=secret",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, mockdb.NewMockStore(gomock.NewController(t)))
+
+			var got *http.Request
+			handler := server.StreamTokenFromQuery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+			}))
+
+			request := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			if tc.header != "" {
+				request.Header.Set(authorizationHeaderKey, tc.header)
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+
+			require.Equal(t, tc.wantAuthorization, got.Header.Get(authorizationHeaderKey))
+			require.Equal(t, tc.wantRequestURI, got.RequestURI)
+			require.Equal(t, tc.wantRequestURI, got.URL.RequestURI())
+		})
+	}
+}
@@ -0,0 +1,612 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/go-chi/render"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultTaskSort   = "createdAt"
+	maxTaskSortFields = 3
+)
+
+var taskSortFields = map[string]db.TaskSortField{
+	"createdAt": db.TaskSortCreatedAt,
+	"dueDate":   db.TaskSortDueDate,
+	"title":     db.TaskSortTitle,
+}
+
+var (
+	errInvalidTaskSort       = errors.New("sort must list up to 3 of createdAt, dueDate and title, each optionally prefixed with - for descending order")
+	errInvalidCategory       = errors.New("statusCategory must be one of [todo in_progress done]")
+	errCursorSortMismatch    = errors.New("cursor was issued for a different sort order")
+	errTaskNotFound          = errors.New("task not found")
+	errAssigneeNotTeamMember = errors.New("assignees must be members of the task's team")
+	errAssigneeNotCreator    = errors.New("tasks without a team can only be assigned to their creator")
+)
+
+type createTaskPayload struct {
+	Title       string   `json:"title" validate:"required,max=100"`
+	Description string   `json:"description"`
+	TeamID      *int32   `json:"teamId" validate:"omitempty,min=1"`
+	DueDate     *dueDate `json:"dueDate"`
+	StatusID    *int32   `json:"statusId" validate:"omitempty,min=1"`
+	// AssigneeIDs assigns the new task right away, which takes
+	// ClaimAssignTask like the assignment endpoints do.
+	AssigneeIDs []int32 `json:"assigneeIds" validate:"omitempty,max=50,dive,min=1"`
+}
+
+// CreateTask creates a task, along with its assignments when the payload
+// lists assignees, in a single transaction.
+func (s *Server) CreateTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload createTaskPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	principal := principalFromContext(ctx)
+
+	if payload.TeamID != nil {
+		isMember, err := s.isTeamMember(ctx, *payload.TeamID, principal.User.ID)
+		if err != nil {
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+		if !isMember {
+			render.Render(w, r, ErrForbidden(errNotTeamMember))
+			return
+		}
+	}
+
+	status, ok := s.resolveTaskStatus(w, r, payload.TeamID, payload.StatusID)
+	if !ok {
+		return
+	}
+
+	assigneeIDs := uniqueIDs(payload.AssigneeIDs)
+	if len(assigneeIDs) > 0 {
+		canAssign, err := s.hasClaim(ctx, principal, ClaimAssignTask)
+		if err != nil {
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+		if !canAssign {
+			render.Render(w, r, ErrForbidden(errPermissionDenied))
+			return
+		}
+
+		if !s.checkTaskAssignees(w, r, payload.TeamID, &principal.User.ID, assigneeIDs) {
+			return
+		}
+	}
+
+	arg := db.CreateTaskParams{
+		Title:       util.SanitizeInput(payload.Title),
+		Description: newText(payload.Description),
+		CreatedBy:   &principal.User.ID,
+		TeamID:      payload.TeamID,
+		StatusID:    status.ID,
+	}
+
+	// A plain date is the end of that day for the first assignee, the one
+	// dueDateLocation reads it for later, or for the creator when nobody is
+	// assigned.
+	if payload.DueDate != nil {
+		location := userLocation(principal.User.Timezone)
+		if len(assigneeIDs) > 0 {
+			assignee, err := s.store.GetUserById(ctx, slices.Min(assigneeIDs))
+			if err != nil {
+				render.Render(w, r, ErrInternalServer())
+				return
+			}
+			location = userLocation(assignee.Timezone)
+		}
+
+		due := payload.DueDate.In(location)
+		arg.DueDate = &due
+	}
+
+	result, err := s.store.CreateTaskWithAssignees(ctx, db.CreateTaskWithAssigneesParams{
+		CreateTaskParams: arg,
+		AssigneeIDs:      assigneeIDs,
+	})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponseWithCode(result.Task, http.StatusCreated))
+}
+
+func (s *Server) GetTask(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.loadVisibleTask(w, r)
+	if !ok {
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(task))
+}
+
+// ListTasks returns one page of tasks matching the query filters. Pages are
+// keyset based: the response carries a cursor for the next page instead of
+// an offset, so deep pages cost the same as the first one. Callers without
+// ClaimViewAllRecords only get the tasks they can see.
+func (s *Server) ListTasks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	principal := principalFromContext(ctx)
+
+	arg, sortKey, err := parseListTasksQuery(r)
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	arg.UserID = principal.User.ID
+	arg.ViewAll, err = s.hasClaim(ctx, principal, ClaimViewAllRecords)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	// One extra task tells whether there is another page.
+	limit := arg.Limit
+	arg.Limit = limit + 1
+
+	tasks, err := s.store.ListTasks(ctx, arg)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	var nextCursor *string
+	if len(tasks) > int(limit) {
+		tasks = tasks[:limit]
+
+		cursor, err := encodeCursor(taskListCursor{
+			Sort:  sortKey,
+			After: db.NewTaskCursor(tasks[len(tasks)-1]),
+		})
+		if err != nil {
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+		nextCursor = &cursor
+	}
+
+	render.Render(w, r, SuccessfulPaginatedResponse(tasks, nextCursor))
+}
+
+type updateTaskPayload struct {
+	Title       *string  `json:"title" validate:"omitempty,min=1,max=100"`
+	Description *string  `json:"description"`
+	DueDate     *dueDate `json:"dueDate"`
+}
+
+// UpdateTask edits a task's details. Its status can only be changed through
+// TransitionTask so that the workflow of the task's team is enforced.
+func (s *Server) UpdateTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload updateTaskPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	task, ok := s.loadVisibleTask(w, r)
+	if !ok {
+		return
+	}
+
+	arg := db.UpdateTaskParams{
+		ID:          task.ID,
+		Title:       task.Title,
+		Description: task.Description,
+		DueDate:     task.DueDate,
+	}
+
+	if payload.Title != nil {
+		arg.Title = util.SanitizeInput(*payload.Title)
+	}
+	if payload.Description != nil {
+		arg.Description = newText(*payload.Description)
+	}
+	if payload.DueDate != nil {
+		location, err := s.dueDateLocation(ctx, task, principalFromContext(ctx).User)
+		if err != nil {
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+		due := payload.DueDate.In(location)
+		arg.DueDate = &due
+	}
+
+	task, err := s.store.UpdateTask(ctx, arg)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(task))
+}
+
+func (s *Server) DeleteTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	task, ok := s.loadVisibleTask(w, r)
+	if !ok {
+		return
+	}
+
+	attachments, err := s.store.ListTaskAttachments(ctx, task.ID)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	if err := s.store.DeleteTask(ctx, task.ID); err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	keys := make([]string, len(attachments))
+	for i, attachment := range attachments {
+		keys[i] = attachment.StorageKey
+	}
+	s.deleteBlobs(ctx, keys...)
+
+	render.Render(w, r, SuccessfulResponse(nil))
+}
+
+func (s *Server) ListTaskAssignees(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	task, ok := s.loadVisibleTask(w, r)
+	if !ok {
+		return
+	}
+
+	assignees, err := s.store.ListTaskAssignees(ctx, task.ID)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(assignees))
+}
+
+type taskAssigneesPayload struct {
+	UserIDs []int32 `json:"userIds" validate:"required,min=1,max=50,dive,min=1"`
+}
+
+// AddTaskAssignees assigns the task to the given users. Team tasks can only
+// be assigned to members of the team and personal tasks only to whoever
+// created them.
+func (s *Server) AddTaskAssignees(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userIDs, ok := s.decodeTaskAssignees(w, r)
+	if !ok {
+		return
+	}
+
+	task, ok := s.loadVisibleTask(w, r)
+	if !ok {
+		return
+	}
+
+	if !s.checkTaskAssignees(w, r, task.TeamID, task.CreatedBy, userIDs) {
+		return
+	}
+
+	assignees, err := s.store.UpdateTaskAssignees(ctx, db.UpdateTaskAssigneesParams{
+		TaskID: task.ID,
+		Add:    userIDs,
+	})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(assignees))
+}
+
+func (s *Server) RemoveTaskAssignees(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userIDs, ok := s.decodeTaskAssignees(w, r)
+	if !ok {
+		return
+	}
+
+	task, ok := s.loadVisibleTask(w, r)
+	if !ok {
+		return
+	}
+
+	assignees, err := s.store.UpdateTaskAssignees(ctx, db.UpdateTaskAssigneesParams{
+		TaskID: task.ID,
+		Remove: userIDs,
+	})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(assignees))
+}
+
+// decodeTaskAssignees reads the user IDs from the request body with
+// duplicates removed, writing the error response itself when it cannot.
+func (s *Server) decodeTaskAssignees(w http.ResponseWriter, r *http.Request) ([]int32, bool) {
+	var payload taskAssigneesPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return nil, false
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return nil, false
+	}
+
+	return uniqueIDs(payload.UserIDs), true
+}
+
+// checkTaskAssignees reports whether userIDs can be assigned to a task of
+// teamID created by createdBy, writing the error response itself when they
+// cannot. Team tasks can only be assigned to members of the team and personal
+// tasks only to whoever created them.
+func (s *Server) checkTaskAssignees(w http.ResponseWriter, r *http.Request, teamID *int32, createdBy *int32, userIDs []int32) bool {
+	if teamID == nil {
+		for _, userID := range userIDs {
+			if createdBy == nil || *createdBy != userID {
+				render.Render(w, r, ErrInvalidRequest(errAssigneeNotCreator))
+				return false
+			}
+		}
+		return true
+	}
+
+	members, err := s.store.FilterTeamMembers(r.Context(), db.FilterTeamMembersParams{
+		TeamID:  *teamID,
+		UserIds: userIDs,
+	})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return false
+	}
+	if len(members) != len(userIDs) {
+		render.Render(w, r, ErrInvalidRequest(errAssigneeNotTeamMember))
+		return false
+	}
+	return true
+}
+
+// uniqueIDs returns ids in order with duplicates removed.
+func uniqueIDs(ids []int32) []int32 {
+	seen := make(map[int32]bool, len(ids))
+	unique := make([]int32, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// loadTask fetches the task identified by the "id" URL parameter and writes
+// the error response itself when it cannot.
+func (s *Server) loadTask(w http.ResponseWriter, r *http.Request) (db.Task, bool) {
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return db.Task{}, false
+	}
+
+	task, err := s.store.GetTask(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			render.Render(w, r, ErrNotFound(errTaskNotFound))
+			return db.Task{}, false
+		}
+		render.Render(w, r, ErrInternalServer())
+		return db.Task{}, false
+	}
+
+	return task, true
+}
+
+// loadVisibleTask is loadTask for endpoints that also require the caller to
+// be able to see the task. Tasks the caller cannot see are reported as not
+// found, so their IDs cannot be probed.
+func (s *Server) loadVisibleTask(w http.ResponseWriter, r *http.Request) (db.Task, bool) {
+	task, ok := s.loadTask(w, r)
+	if !ok {
+		return db.Task{}, false
+	}
+
+	canView, err := s.canViewTask(r.Context(), principalFromContext(r.Context()), task)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return db.Task{}, false
+	}
+	if !canView {
+		render.Render(w, r, ErrNotFound(errTaskNotFound))
+		return db.Task{}, false
+	}
+
+	return task, true
+}
+
+// canViewTask reports whether the principal can see the task: team tasks are
+// visible to the team's members, personal tasks to their creator and
+// assignees, and every task to holders of ClaimViewAllRecords.
+func (s *Server) canViewTask(ctx context.Context, principal *Principal, task db.Task) (bool, error) {
+	viewAll, err := s.hasClaim(ctx, principal, ClaimViewAllRecords)
+	if err != nil || viewAll {
+		return viewAll, err
+	}
+
+	if task.TeamID != nil {
+		return s.isTeamMember(ctx, *task.TeamID, principal.User.ID)
+	}
+
+	if task.CreatedBy != nil && *task.CreatedBy == principal.User.ID {
+		return true, nil
+	}
+
+	assignees, err := s.store.ListTaskAssignees(ctx, task.ID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, assignee := range assignees {
+		if assignee.UserID == principal.User.ID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// taskListCursor is the state behind the opaque cursor of the task list. The
+// sort is kept so a cursor cannot be replayed against a different order.
+type taskListCursor struct {
+	Sort  string        `json:"sort"`
+	After db.TaskCursor `json:"after"`
+}
+
+// parseListTasksQuery reads the filters, sort order, page size and cursor of
+// a task list request. It also returns the normalised sort order the cursor
+// is bound to.
+func parseListTasksQuery(r *http.Request) (db.ListTasksParams, string, error) {
+	var (
+		arg db.ListTasksParams
+		err error
+	)
+
+	query := r.URL.Query()
+
+	if arg.Limit, err = parseIntQuery(r, "limit", 20, 1, 100); err != nil {
+		return arg, "", err
+	}
+	if arg.StatusID, err = parseOptionalIDQuery(r, "statusId"); err != nil {
+		return arg, "", err
+	}
+	if arg.AssigneeID, err = parseOptionalIDQuery(r, "assigneeId"); err != nil {
+		return arg, "", err
+	}
+	if arg.CreatedBy, err = parseOptionalIDQuery(r, "createdBy"); err != nil {
+		return arg, "", err
+	}
+	if arg.TeamID, err = parseOptionalIDQuery(r, "teamId"); err != nil {
+		return arg, "", err
+	}
+	if arg.DueAfter, err = parseTimeQuery(r, "dueAfter"); err != nil {
+		return arg, "", err
+	}
+	if arg.DueBefore, err = parseTimeQuery(r, "dueBefore"); err != nil {
+		return arg, "", err
+	}
+
+	if category := query.Get("statusCategory"); category != "" {
+		switch category {
+		case "todo", "in_progress", "done":
+			arg.StatusCategory = &category
+		default:
+			return arg, "", errInvalidCategory
+		}
+	}
+
+	overdue, err := parseBoolQuery(r, "overdue")
+	if err != nil {
+		return arg, "", err
+	}
+	if overdue {
+		now := time.Now()
+		arg.OverdueAt = &now
+	}
+
+	sortKey := query.Get("sort")
+	if sortKey == "" {
+		sortKey = defaultTaskSort
+	}
+	if arg.Sort, err = parseTaskSort(sortKey); err != nil {
+		return arg, "", err
+	}
+
+	if raw := query.Get("cursor"); raw != "" {
+		var cursor taskListCursor
+		if err := decodeCursor(raw, &cursor); err != nil {
+			return arg, "", err
+		}
+		if cursor.Sort != sortKey {
+			return arg, "", errCursorSortMismatch
+		}
+		arg.After = &cursor.After
+	}
+
+	return arg, sortKey, nil
+}
+
+// parseTaskSort parses a comma separated list of sort fields such as
+// "dueDate,-createdAt", where a leading - sorts that field descending.
+func parseTaskSort(raw string) ([]db.TaskSort, error) {
+	parts := strings.Split(raw, ",")
+	if len(parts) > maxTaskSortFields {
+		return nil, errInvalidTaskSort
+	}
+
+	sort := make([]db.TaskSort, 0, len(parts))
+	seen := make(map[db.TaskSortField]bool, len(parts))
+
+	for _, part := range parts {
+		name, desc := strings.CutPrefix(part, "-")
+
+		field, ok := taskSortFields[name]
+		if !ok || seen[field] {
+			return nil, errInvalidTaskSort
+		}
+		seen[field] = true
+
+		sort = append(sort, db.TaskSort{Field: field, Desc: desc})
+	}
+
+	return sort, nil
+}
+
+func newText(s string) pgtype.Text {
+	s = util.SanitizeInput(s)
+	return pgtype.Text{String: s, Valid: s != ""}
+}
@@ -0,0 +1,228 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/recurrence"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/go-chi/render"
+)
+
+var (
+	errTaskTemplateNotFound    = errors.New("task template not found")
+	errCannotViewTaskTemplate  = errors.New("you do not have access to this task template")
+	errTemplateHasNoOccurrence = errors.New("the rule has no occurrences from now on")
+)
+
+type createTaskTemplatePayload struct {
+	Title       string `json:"title" validate:"required,max=100"`
+	Description string `json:"description"`
+	TeamID      *int32 `json:"teamId" validate:"omitempty,min=1"`
+	// RRule is an RFC 5545 recurrence rule such as "FREQ=WEEKLY;BYDAY=MO".
+	RRule string `json:"rrule" validate:"required,max=500"`
+	// Timezone is the IANA zone the rule is expanded in. It defaults to the
+	// creator's timezone.
+	Timezone string `json:"timezone" validate:"max=64"`
+	// StartsAt is the first occurrence, DTSTART in RFC 5545 terms. Its time
+	// of day in Timezone is the time every occurrence is due at.
+	StartsAt time.Time `json:"startsAt" validate:"required"`
+}
+
+// CreateTaskTemplate starts a recurring task. The task for the first
+// occurrence from now on is created straight away; the generator creates the
+// rest.
+func (s *Server) CreateTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload createTaskTemplatePayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	principal := principalFromContext(ctx)
+
+	if payload.Timezone == "" {
+		payload.Timezone = userLocation(principal.User.Timezone).String()
+	}
+
+	schedule, err := recurrence.NewSchedule(payload.RRule, payload.StartsAt, payload.Timezone)
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if payload.TeamID != nil {
+		isMember, err := s.isTeamMember(ctx, *payload.TeamID, principal.User.ID)
+		if err != nil {
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+		if !isMember {
+			render.Render(w, r, ErrForbidden(errNotTeamMember))
+			return
+		}
+	}
+
+	// Occurrences start in the workflow's first status, so the workflow
+	// must have one.
+	if _, ok := s.resolveTaskStatus(w, r, payload.TeamID, nil); !ok {
+		return
+	}
+
+	first, ok := schedule.Upcoming(time.Now())
+	if !ok {
+		render.Render(w, r, ErrInvalidRequest(errTemplateHasNoOccurrence))
+		return
+	}
+
+	var following *time.Time
+	if next, ok := schedule.Next(first); ok {
+		following = &next
+	}
+
+	result, err := s.store.CreateTaskTemplateWithOccurrence(ctx, db.CreateTaskTemplateWithOccurrenceParams{
+		CreateTaskTemplateParams: db.CreateTaskTemplateParams{
+			Title:       util.SanitizeInput(payload.Title),
+			Description: newText(payload.Description),
+			CreatedBy:   &principal.User.ID,
+			TeamID:      payload.TeamID,
+			Rrule:       schedule.Rule.String(),
+			Timezone:    schedule.Location.String(),
+			StartsAt:    payload.StartsAt.UTC(),
+			NextDueAt:   &first,
+		},
+		FollowingDueAt: following,
+	})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponseWithCode(result, http.StatusCreated))
+}
+
+// ListTaskTemplates returns the templates of the team in the teamId query
+// parameter, or the caller's personal templates without one.
+func (s *Server) ListTaskTemplates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	teamID, err := parseOptionalIDQuery(r, "teamId")
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	principal := principalFromContext(ctx)
+
+	if teamID != nil {
+		canView, err := s.canViewTaskTemplate(ctx, principal, db.TaskTemplate{TeamID: teamID})
+		if err != nil {
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+		if !canView {
+			render.Render(w, r, ErrForbidden(errNotTeamMember))
+			return
+		}
+	}
+
+	templates, err := s.store.ListTaskTemplates(ctx, db.ListTaskTemplatesParams{
+		TeamID: teamID,
+		UserID: principal.User.ID,
+	})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(templates))
+}
+
+func (s *Server) GetTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	template, ok := s.loadTaskTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(template))
+}
+
+// DeleteTaskTemplate ends a recurring task. Tasks already created for it
+// are kept.
+func (s *Server) DeleteTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	template, ok := s.loadTaskTemplate(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.store.DeleteTaskTemplate(r.Context(), template.ID); err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(nil))
+}
+
+// loadTaskTemplate fetches the template identified by the "id" URL parameter
+// when the caller can see it, and writes the error response itself when it
+// cannot.
+func (s *Server) loadTaskTemplate(w http.ResponseWriter, r *http.Request) (db.TaskTemplate, bool) {
+	ctx := r.Context()
+
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return db.TaskTemplate{}, false
+	}
+
+	template, err := s.store.GetTaskTemplate(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			render.Render(w, r, ErrNotFound(errTaskTemplateNotFound))
+			return db.TaskTemplate{}, false
+		}
+		render.Render(w, r, ErrInternalServer())
+		return db.TaskTemplate{}, false
+	}
+
+	canView, err := s.canViewTaskTemplate(ctx, principalFromContext(ctx), template)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return db.TaskTemplate{}, false
+	}
+	if !canView {
+		render.Render(w, r, ErrForbidden(errCannotViewTaskTemplate))
+		return db.TaskTemplate{}, false
+	}
+
+	return template, true
+}
+
+// canViewTaskTemplate reports whether the principal can see the template:
+// team templates are visible to the team's members, personal ones to their
+// creator, and every template to holders of ClaimViewAllRecords.
+func (s *Server) canViewTaskTemplate(ctx context.Context, principal *Principal, template db.TaskTemplate) (bool, error) {
+	viewAll, err := s.hasClaim(ctx, principal, ClaimViewAllRecords)
+	if err != nil || viewAll {
+		return viewAll, err
+	}
+
+	if template.TeamID != nil {
+		return s.isTeamMember(ctx, *template.TeamID, principal.User.ID)
+	}
+
+	return template.CreatedBy != nil && *template.CreatedBy == principal.User.ID, nil
+}
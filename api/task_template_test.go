@@ -0,0 +1,370 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func randomTaskTemplate(createdBy int32) db.TaskTemplate {
+	startsAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	return db.TaskTemplate{
+		ID:        int32(util.RandomInt(1, 1000)),
+		Title:     util.RandomString(12),
+		CreatedBy: &createdBy,
+		Rrule:     "FREQ=DAILY",
+		Timezone:  "UTC",
+		StartsAt:  startsAt,
+		NextDueAt: &startsAt,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+func TestCreateTaskTemplateApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	initialStatus := db.TaskStatus{ID: int32(util.RandomInt(1, 1000)), Name: "To Do", Category: "todo"}
+	teamID := int32(util.RandomInt(1, 1000))
+
+	// A Monday morning in Berlin, a little over a week away.
+	startsAt := time.Now().AddDate(0, 0, 8)
+	for startsAt.Weekday() != time.Monday {
+		startsAt = startsAt.AddDate(0, 0, 1)
+	}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	startsAt = time.Date(startsAt.Year(), startsAt.Month(), startsAt.Day(), 9, 0, 0, 0, berlin)
+	following := time.Date(startsAt.Year(), startsAt.Month(), startsAt.Day()+2, 9, 0, 0, 0, berlin).UTC()
+
+	testCases := []struct {
+		name          string
+		payload       map[string]any
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			payload: map[string]any{
+				"title":    "Water the plants",
+				"rrule":    "freq=weekly;byday=MO,WE",
+				"timezone": "Europe/Berlin",
+				"startsAt": startsAt.Format(time.RFC3339),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				var noTeam *int32
+				store.EXPECT().GetInitialTaskStatus(gomock.Any(), gomock.Eq(noTeam)).Times(1).Return(initialStatus, nil)
+
+				first := startsAt.UTC()
+				arg := db.CreateTaskTemplateWithOccurrenceParams{
+					CreateTaskTemplateParams: db.CreateTaskTemplateParams{
+						Title:       "Water the plants",
+						Description: newText(""),
+						CreatedBy:   &user.ID,
+						Rrule:       "FREQ=WEEKLY;BYDAY=MO,WE",
+						Timezone:    "Europe/Berlin",
+						StartsAt:    first,
+						NextDueAt:   &first,
+					},
+					FollowingDueAt: &following,
+				}
+				store.EXPECT().
+					CreateTaskTemplateWithOccurrence(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateTaskTemplateWithOccurrenceParams) (db.TaskOccurrenceResult, error) {
+						return db.TaskOccurrenceResult{
+							Template: db.TaskTemplate{ID: 1, Title: arg.Title, NextDueAt: arg.FollowingDueAt},
+							Task:     db.Task{ID: 2, Title: arg.Title, DueDate: arg.NextDueAt},
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var response struct {
+					Data db.TaskOccurrenceResult `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.True(t, startsAt.Equal(*response.Data.Task.DueDate))
+				require.True(t, following.Equal(*response.Data.Template.NextDueAt))
+			},
+		},
+		{
+			name: "OK: Past Start Begins Today",
+			payload: map[string]any{
+				"title":    "Daily report",
+				"rrule":    "FREQ=DAILY",
+				"startsAt": "2020-01-01T23:59:59Z",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetInitialTaskStatus(gomock.Any(), gomock.Any()).Times(1).Return(initialStatus, nil)
+				store.EXPECT().
+					CreateTaskTemplateWithOccurrence(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateTaskTemplateWithOccurrenceParams) (db.TaskOccurrenceResult, error) {
+						require.Equal(t, user.Timezone, arg.Timezone)
+						require.True(t, arg.NextDueAt.After(time.Now()))
+						require.True(t, arg.NextDueAt.Before(time.Now().Add(24*time.Hour)))
+						require.Equal(t, arg.NextDueAt.Add(24*time.Hour), *arg.FollowingDueAt)
+						return db.TaskOccurrenceResult{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest: Invalid Rule",
+			payload: map[string]any{
+				"title":    "Daily report",
+				"rrule":    "FREQ=HOURLY",
+				"startsAt": startsAt.Format(time.RFC3339),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateTaskTemplateWithOccurrence(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "FREQ must be DAILY, WEEKLY or MONTHLY")
+			},
+		},
+		{
+			name: "BadRequest: Invalid Timezone",
+			payload: map[string]any{
+				"title":    "Daily report",
+				"rrule":    "FREQ=DAILY",
+				"timezone": "Mars/Olympus_Mons",
+				"startsAt": startsAt.Format(time.RFC3339),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateTaskTemplateWithOccurrence(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "invalid timezone")
+			},
+		},
+		{
+			name: "BadRequest: No Occurrences Left",
+			payload: map[string]any{
+				"title":    "Daily report",
+				"rrule":    "FREQ=DAILY;COUNT=3",
+				"startsAt": "2020-01-01T09:00:00Z",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetInitialTaskStatus(gomock.Any(), gomock.Any()).Times(1).Return(initialStatus, nil)
+				store.EXPECT().CreateTaskTemplateWithOccurrence(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errTemplateHasNoOccurrence.Error())
+			},
+		},
+		{
+			name: "BadRequest: Missing Start",
+			payload: map[string]any{
+				"title": "Daily report",
+				"rrule": "FREQ=DAILY",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateTaskTemplateWithOccurrence(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Forbidden: Not Team Member",
+			payload: map[string]any{
+				"title":    "Daily report",
+				"rrule":    "FREQ=DAILY",
+				"teamId":   teamID,
+				"startsAt": startsAt.Format(time.RFC3339),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: teamID, UserID: user.ID})).
+					Times(1).
+					Return(db.TeamMember{}, db.ErrRecordNotFound)
+				store.EXPECT().CreateTaskTemplateWithOccurrence(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimCreateTask)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/task-templates", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListTaskTemplatesApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	teamID := int32(util.RandomInt(1, 1000))
+	templates := []db.TaskTemplate{randomTaskTemplate(user.ID), randomTaskTemplate(user.ID)}
+
+	testCases := []struct {
+		name       string
+		query      string
+		buildStubs func(store *mockdb.MockStore)
+		code       int
+	}{
+		{
+			name:  "OK: Personal",
+			query: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListTaskTemplates(gomock.Any(), gomock.Eq(db.ListTaskTemplatesParams{UserID: user.ID})).
+					Times(1).
+					Return(templates, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:  "OK: Team",
+			query: fmt.Sprintf("?teamId=%d", teamID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeamMember(gomock.Any(), gomock.Any()).Times(1).Return(db.TeamMember{TeamID: teamID, UserID: user.ID}, nil)
+				store.EXPECT().
+					ListTaskTemplates(gomock.Any(), gomock.Eq(db.ListTaskTemplatesParams{TeamID: &teamID, UserID: user.ID})).
+					Times(1).
+					Return(templates, nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:  "Forbidden: Not Team Member",
+			query: fmt.Sprintf("?teamId=%d", teamID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeamMember(gomock.Any(), gomock.Any()).Times(1).Return(db.TeamMember{}, db.ErrRecordNotFound)
+				store.EXPECT().ListTaskTemplates(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: http.StatusForbidden,
+		},
+		{
+			name:  "BadRequest: Invalid Team",
+			query: "?teamId=abc",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListTaskTemplates(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/v1/task-templates"+tt.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tt.code, recorder.Code)
+		})
+	}
+}
+
+func TestDeleteTaskTemplateApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	other := randomAuthenticatedUser(t)
+
+	template := randomTaskTemplate(user.ID)
+	othersTemplate := randomTaskTemplate(other.ID)
+
+	testCases := []struct {
+		name       string
+		template   db.TaskTemplate
+		buildStubs func(store *mockdb.MockStore)
+		code       int
+	}{
+		{
+			name:     "OK",
+			template: template,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskTemplate(gomock.Any(), gomock.Eq(template.ID)).Times(1).Return(template, nil)
+				store.EXPECT().DeleteTaskTemplate(gomock.Any(), gomock.Eq(template.ID)).Times(1).Return(nil)
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:     "Forbidden: Other Person",
+			template: othersTemplate,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskTemplate(gomock.Any(), gomock.Eq(othersTemplate.ID)).Times(1).Return(othersTemplate, nil)
+				store.EXPECT().DeleteTaskTemplate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: http.StatusForbidden,
+		},
+		{
+			name:     "NotFound",
+			template: template,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskTemplate(gomock.Any(), gomock.Eq(template.ID)).Times(1).Return(db.TaskTemplate{}, db.ErrRecordNotFound)
+				store.EXPECT().DeleteTaskTemplate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimDeleteTask)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/task-templates/%d", tt.template.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tt.code, recorder.Code)
+		})
+	}
+}
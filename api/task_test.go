@@ -0,0 +1,1200 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func randomTask() db.Task {
+	dueDate := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	return db.Task{
+		ID:          int32(util.RandomInt(1, 1000)),
+		Title:       util.RandomString(12),
+		Description: pgtype.Text{String: util.RandomString(30), Valid: true},
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+		DueDate:     &dueDate,
+		StatusID:    int32(util.RandomInt(1, 1000)),
+	}
+}
+
+func TestCreateTaskApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	user.Timezone = "America/New_York"
+	task := randomTask()
+	initialStatus := db.TaskStatus{ID: task.StatusID, Name: "To Do", Category: "todo"}
+
+	teamID := int32(util.RandomInt(1, 1000))
+	teamStatus := db.TaskStatus{ID: task.StatusID + 1, TeamID: &teamID, Name: "Review", Category: "in_progress"}
+
+	assignee := randomAuthenticatedUser(t)
+	assignee.ID = user.ID + 1
+	assignee.Timezone = "Asia/Tokyo"
+
+	testCases := []struct {
+		name          string
+		payload       map[string]any
+		claims        []string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			payload: map[string]any{
+				"title":       task.Title,
+				"description": task.Description.String,
+				"dueDate":     task.DueDate,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				var noTeam *int32
+				store.EXPECT().
+					GetInitialTaskStatus(gomock.Any(), gomock.Eq(noTeam)).
+					Times(1).
+					Return(initialStatus, nil)
+
+				arg := db.CreateTaskParams{
+					Title:       task.Title,
+					Description: task.Description,
+					CreatedBy:   &user.ID,
+					DueDate:     task.DueDate,
+					StatusID:    initialStatus.ID,
+				}
+				store.EXPECT().
+					CreateTaskWithAssignees(gomock.Any(), gomock.Eq(db.CreateTaskWithAssigneesParams{CreateTaskParams: arg, AssigneeIDs: []int32{}})).
+					Times(1).
+					Return(db.CreateTaskWithAssigneesResult{Task: task}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				requireBodyMatchTask(t, recorder.Body, task)
+			},
+		},
+		{
+			name: "OK: Due Date With Offset",
+			payload: map[string]any{
+				"title":   task.Title,
+				"dueDate": "2025-03-09T17:00:00+01:00",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetInitialTaskStatus(gomock.Any(), gomock.Any()).Times(1).Return(initialStatus, nil)
+				store.EXPECT().
+					CreateTaskWithAssignees(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateTaskWithAssigneesParams) (db.CreateTaskWithAssigneesResult, error) {
+						require.Equal(t, time.Date(2025, 3, 9, 16, 0, 0, 0, time.UTC), *arg.DueDate)
+						return db.CreateTaskWithAssigneesResult{Task: task}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "OK: Due Date Is End Of Day For Creator",
+			payload: map[string]any{
+				"title":   task.Title,
+				"dueDate": "2025-03-09",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetInitialTaskStatus(gomock.Any(), gomock.Any()).Times(1).Return(initialStatus, nil)
+				store.EXPECT().
+					CreateTaskWithAssignees(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateTaskWithAssigneesParams) (db.CreateTaskWithAssigneesResult, error) {
+						// 23:59:59 EDT, daylight saving time having started that morning.
+						require.Equal(t, time.Date(2025, 3, 10, 3, 59, 59, 0, time.UTC), *arg.DueDate)
+						return db.CreateTaskWithAssigneesResult{Task: task}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest: Invalid Due Date",
+			payload: map[string]any{
+				"title":   task.Title,
+				"dueDate": "next friday",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateTaskWithAssignees(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errInvalidDueDate.Error())
+			},
+		},
+		{
+			name: "BadRequest: Missing Title",
+			payload: map[string]any{
+				"description": task.Description.String,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateTaskWithAssignees(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "OK: Team Status",
+			payload: map[string]any{
+				"title":    task.Title,
+				"teamId":   teamID,
+				"statusId": teamStatus.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: teamID, UserID: user.ID})).
+					Times(1).
+					Return(db.TeamMember{TeamID: teamID, UserID: user.ID}, nil)
+				store.EXPECT().
+					GetTaskStatus(gomock.Any(), gomock.Eq(teamStatus.ID)).
+					Times(1).
+					Return(teamStatus, nil)
+				store.EXPECT().
+					CreateTaskWithAssignees(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateTaskWithAssigneesParams) (db.CreateTaskWithAssigneesResult, error) {
+						require.Equal(t, teamStatus.ID, arg.StatusID)
+						require.Equal(t, &teamID, arg.TeamID)
+						return db.CreateTaskWithAssigneesResult{Task: task}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest: Invalid Status",
+			payload: map[string]any{
+				"title":    task.Title,
+				"statusId": -1,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateTaskWithAssignees(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest: Status From Another Workflow",
+			payload: map[string]any{
+				"title":    task.Title,
+				"statusId": teamStatus.ID,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTaskStatus(gomock.Any(), gomock.Eq(teamStatus.ID)).
+					Times(1).
+					Return(teamStatus, nil)
+				store.EXPECT().CreateTaskWithAssignees(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errStatusNotInWorkflow.Error())
+			},
+		},
+		{
+			name: "Forbidden: Not A Team Member",
+			payload: map[string]any{
+				"title":  task.Title,
+				"teamId": 42,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: 42, UserID: user.ID})).
+					Times(1).
+					Return(db.TeamMember{}, db.ErrRecordNotFound)
+				store.EXPECT().CreateTaskWithAssignees(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "OK: Assignees",
+			payload: map[string]any{
+				"title":       task.Title,
+				"teamId":      teamID,
+				"dueDate":     "2025-03-09",
+				"assigneeIds": []int32{assignee.ID, assignee.ID},
+			},
+			claims: []string{ClaimAssignTask},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: teamID, UserID: user.ID})).
+					Times(1).
+					Return(db.TeamMember{TeamID: teamID, UserID: user.ID}, nil)
+				store.EXPECT().GetInitialTaskStatus(gomock.Any(), gomock.Eq(&teamID)).Times(1).Return(teamStatus, nil)
+				store.EXPECT().
+					FilterTeamMembers(gomock.Any(), gomock.Eq(db.FilterTeamMembersParams{TeamID: teamID, UserIds: []int32{assignee.ID}})).
+					Times(1).
+					Return([]int32{assignee.ID}, nil)
+				store.EXPECT().GetUserById(gomock.Any(), gomock.Eq(assignee.ID)).Times(1).Return(assignee, nil)
+				store.EXPECT().
+					CreateTaskWithAssignees(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateTaskWithAssigneesParams) (db.CreateTaskWithAssigneesResult, error) {
+						require.Equal(t, []int32{assignee.ID}, arg.AssigneeIDs)
+						// 23:59:59 JST, the assignee's end of that day.
+						require.Equal(t, time.Date(2025, 3, 9, 14, 59, 59, 0, time.UTC), *arg.DueDate)
+						return db.CreateTaskWithAssigneesResult{Task: task, AssigneeIDs: arg.AssigneeIDs}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				requireBodyMatchTask(t, recorder.Body, task)
+			},
+		},
+		{
+			name: "Forbidden: Cannot Assign",
+			payload: map[string]any{
+				"title":       task.Title,
+				"assigneeIds": []int32{user.ID},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetInitialTaskStatus(gomock.Any(), gomock.Any()).Times(1).Return(initialStatus, nil)
+				store.EXPECT().CreateTaskWithAssignees(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest: Personal Task Assigned To Someone Else",
+			payload: map[string]any{
+				"title":       task.Title,
+				"assigneeIds": []int32{assignee.ID},
+			},
+			claims: []string{ClaimAssignTask},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetInitialTaskStatus(gomock.Any(), gomock.Any()).Times(1).Return(initialStatus, nil)
+				store.EXPECT().CreateTaskWithAssignees(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errAssigneeNotCreator.Error())
+			},
+		},
+		{
+			name: "Internal Server Error",
+			payload: map[string]any{
+				"title": task.Title,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetInitialTaskStatus(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(initialStatus, nil)
+				store.EXPECT().
+					CreateTaskWithAssignees(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateTaskWithAssigneesResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, append([]string{ClaimCreateTask, ClaimEditTask, ClaimDeleteTask}, tt.claims...)...)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/tasks", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetTaskApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	task := randomTask()
+	task.CreatedBy = &user.ID
+
+	teamID := int32(util.RandomInt(1, 1000))
+	teamTask := randomTask()
+	teamTask.TeamID = &teamID
+
+	testCases := []struct {
+		name          string
+		taskID        string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			taskID: fmt.Sprint(task.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTask(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchTask(t, recorder.Body, task)
+			},
+		},
+		{
+			name:   "NotFound",
+			taskID: fmt.Sprint(task.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTask(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(db.Task{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "NotFound: Not A Team Member",
+			taskID: fmt.Sprint(teamTask.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTask(gomock.Any(), gomock.Eq(teamTask.ID)).
+					Times(1).
+					Return(teamTask, nil)
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: teamID, UserID: user.ID})).
+					Times(1).
+					Return(db.TeamMember{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "BadRequest: Invalid ID",
+			taskID: "abc",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Internal Server Error",
+			taskID: fmt.Sprint(task.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Task{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimCreateTask, ClaimEditTask, ClaimDeleteTask)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/v1/tasks/"+tt.taskID, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListTasksApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	tasks := []db.Task{randomTask(), randomTask(), randomTask()}
+
+	cursor, err := encodeCursor(taskListCursor{Sort: "-dueDate", After: db.NewTaskCursor(tasks[0])})
+	require.NoError(t, err)
+
+	type listResponse struct {
+		Data       []db.Task `json:"data"`
+		NextCursor *string   `json:"nextCursor"`
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK: Next Page",
+			query: "?limit=2",
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListTasksParams{
+					UserID: user.ID,
+					Sort:   []db.TaskSort{{Field: db.TaskSortCreatedAt}},
+					Limit:  3,
+				}
+				store.EXPECT().
+					ListTasks(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(tasks, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response listResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response.Data, 2)
+				require.NotNil(t, response.NextCursor)
+
+				var next taskListCursor
+				require.NoError(t, decodeCursor(*response.NextCursor, &next))
+				require.Equal(t, defaultTaskSort, next.Sort)
+				require.Equal(t, tasks[1].ID, next.After.ID)
+			},
+		},
+		{
+			name:  "OK: Last Page",
+			query: "?limit=5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListTasks(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(tasks, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response listResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response.Data, 3)
+				require.Nil(t, response.NextCursor)
+			},
+		},
+		{
+			name:  "OK: Filters And Sort",
+			query: "?teamId=4&assigneeId=5&createdBy=6&statusId=7&statusCategory=in_progress&dueAfter=2025-01-01T00:00:00Z&overdue=true&sort=-dueDate,title",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListTasks(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ListTasksParams) ([]db.Task, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.False(t, arg.ViewAll)
+						require.Equal(t, int32(4), *arg.TeamID)
+						require.Equal(t, int32(5), *arg.AssigneeID)
+						require.Equal(t, int32(6), *arg.CreatedBy)
+						require.Equal(t, int32(7), *arg.StatusID)
+						require.Equal(t, "in_progress", *arg.StatusCategory)
+						require.True(t, arg.DueAfter.Equal(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)))
+						require.Nil(t, arg.DueBefore)
+						require.WithinDuration(t, time.Now(), *arg.OverdueAt, time.Minute)
+						require.Equal(t, []db.TaskSort{
+							{Field: db.TaskSortDueDate, Desc: true},
+							{Field: db.TaskSortTitle},
+						}, arg.Sort)
+						require.Nil(t, arg.After)
+						return []db.Task{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "OK: Cursor",
+			query: "?sort=-dueDate&cursor=" + cursor,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListTasks(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ListTasksParams) ([]db.Task, error) {
+						require.NotNil(t, arg.After)
+						require.Equal(t, tasks[0].ID, arg.After.ID)
+						require.True(t, arg.After.CreatedAt.Equal(tasks[0].CreatedAt))
+						return []db.Task{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "BadRequest: Cursor For Another Sort",
+			query: "?sort=title&cursor=" + cursor,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errCursorSortMismatch.Error())
+			},
+		},
+		{
+			name:  "BadRequest: Malformed Cursor",
+			query: "?cursor=not-a-cursor",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errInvalidCursor.Error())
+			},
+		},
+		{
+			name:  "BadRequest: Unknown Sort Field",
+			query: "?sort=priority",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "BadRequest: Repeated Sort Field",
+			query: "?sort=title,-title",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "BadRequest: Invalid Limit",
+			query: "?limit=1000",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "BadRequest: Invalid Due Date",
+			query: "?dueBefore=tomorrow",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "Internal Server Error",
+			query: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimCreateTask, ClaimEditTask, ClaimDeleteTask)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/v1/tasks"+tt.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateTaskApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	task := randomTask()
+	task.CreatedBy = &user.ID
+	newTitle := util.RandomString(15)
+
+	teamID := int32(util.RandomInt(1, 1000))
+	teamTask := randomTask()
+	teamTask.TeamID = &teamID
+	teamTask.ID = task.ID
+
+	testCases := []struct {
+		name          string
+		payload       map[string]any
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			payload: map[string]any{
+				"title": newTitle,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTask(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+
+				arg := db.UpdateTaskParams{
+					ID:          task.ID,
+					Title:       newTitle,
+					Description: task.Description,
+					DueDate:     task.DueDate,
+				}
+				updated := task
+				updated.Title = newTitle
+				store.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(updated, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			payload: map[string]any{
+				"title": newTitle,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTask(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(db.Task{}, db.ErrRecordNotFound)
+				store.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound: Not A Team Member",
+			payload: map[string]any{
+				"title": newTitle,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTask(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(teamTask, nil)
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: teamID, UserID: user.ID})).
+					Times(1).
+					Return(db.TeamMember{}, db.ErrRecordNotFound)
+				store.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "OK: Due Date Is End Of Day For Assignee",
+			payload: map[string]any{
+				"dueDate": "2025-03-09",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().
+					ListTaskAssignees(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return([]db.ListTaskAssigneesRow{{UserID: user.ID + 1, Timezone: "Asia/Tokyo"}, {UserID: user.ID}}, nil)
+				store.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateTaskParams) (db.Task, error) {
+						require.Equal(t, time.Date(2025, 3, 9, 14, 59, 59, 0, time.UTC), *arg.DueDate)
+						return task, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "OK: Due Date Without Assignees",
+			payload: map[string]any{
+				"dueDate": "2025-03-09",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().ListTaskAssignees(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return([]db.ListTaskAssigneesRow{}, nil)
+				store.EXPECT().
+					UpdateTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateTaskParams) (db.Task, error) {
+						require.Equal(t, time.Date(2025, 3, 9, 23, 59, 59, 0, time.UTC), *arg.DueDate)
+						return task, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest: Title Too Long",
+			payload: map[string]any{
+				"title": util.RandomString(101),
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdateTask(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimCreateTask, ClaimEditTask, ClaimDeleteTask)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/tasks/%d", task.ID)
+			request, err := http.NewRequest(http.MethodPatch, url, bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteTaskApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	task := randomTask()
+	task.CreatedBy = &user.ID
+
+	teamID := int32(util.RandomInt(1, 1000))
+	teamTask := randomTask()
+	teamTask.TeamID = &teamID
+	teamTask.ID = task.ID
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTask(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(task, nil)
+				store.EXPECT().
+					ListTaskAttachments(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return([]db.TaskAttachment{{ID: 1, TaskID: task.ID, StorageKey: "tasks/1/missing"}}, nil)
+				store.EXPECT().
+					DeleteTask(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTask(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(db.Task{}, db.ErrRecordNotFound)
+				store.EXPECT().DeleteTask(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound: Not A Team Member",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTask(gomock.Any(), gomock.Eq(task.ID)).
+					Times(1).
+					Return(teamTask, nil)
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: teamID, UserID: user.ID})).
+					Times(1).
+					Return(db.TeamMember{}, db.ErrRecordNotFound)
+				store.EXPECT().ListTaskAttachments(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().DeleteTask(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimCreateTask, ClaimEditTask, ClaimDeleteTask)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/tasks/%d", task.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAddTaskAssigneesApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	teamID := int32(util.RandomInt(1, 1000))
+
+	teamTask := randomTask()
+	teamTask.TeamID = &teamID
+
+	personalTask := randomTask()
+	personalTask.CreatedBy = &user.ID
+
+	memberID := int32(util.RandomInt(1001, 2000))
+	outsiderID := int32(util.RandomInt(2001, 3000))
+
+	testCases := []struct {
+		name          string
+		task          db.Task
+		payload       map[string]any
+		buildStubs    func(store *mockdb.MockStore, task db.Task)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK: Team Task",
+			task:    teamTask,
+			payload: map[string]any{"userIds": []int32{memberID, memberID}},
+			buildStubs: func(store *mockdb.MockStore, task db.Task) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: teamID, UserID: user.ID})).
+					Times(1).
+					Return(db.TeamMember{TeamID: teamID, UserID: user.ID}, nil)
+				store.EXPECT().
+					FilterTeamMembers(gomock.Any(), gomock.Eq(db.FilterTeamMembersParams{TeamID: teamID, UserIds: []int32{memberID}})).
+					Times(1).
+					Return([]int32{memberID}, nil)
+				store.EXPECT().
+					UpdateTaskAssignees(gomock.Any(), gomock.Eq(db.UpdateTaskAssigneesParams{TaskID: task.ID, Add: []int32{memberID}})).
+					Times(1).
+					Return([]db.ListTaskAssigneesRow{{UserID: memberID, AssignedAt: time.Now()}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Data []db.ListTaskAssigneesRow `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response.Data, 1)
+				require.Equal(t, memberID, response.Data[0].UserID)
+			},
+		},
+		{
+			name:    "OK: Personal Task",
+			task:    personalTask,
+			payload: map[string]any{"userIds": []int32{user.ID}},
+			buildStubs: func(store *mockdb.MockStore, task db.Task) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().FilterTeamMembers(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					UpdateTaskAssignees(gomock.Any(), gomock.Eq(db.UpdateTaskAssigneesParams{TaskID: task.ID, Add: []int32{user.ID}})).
+					Times(1).
+					Return([]db.ListTaskAssigneesRow{{UserID: user.ID}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:    "BadRequest: Not A Team Member",
+			task:    teamTask,
+			payload: map[string]any{"userIds": []int32{memberID, outsiderID}},
+			buildStubs: func(store *mockdb.MockStore, task db.Task) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: teamID, UserID: user.ID})).
+					Times(1).
+					Return(db.TeamMember{TeamID: teamID, UserID: user.ID}, nil)
+				store.EXPECT().FilterTeamMembers(gomock.Any(), gomock.Any()).Times(1).Return([]int32{memberID}, nil)
+				store.EXPECT().UpdateTaskAssignees(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errAssigneeNotTeamMember.Error())
+			},
+		},
+		{
+			name:    "BadRequest: Personal Task Assigned To Someone Else",
+			task:    personalTask,
+			payload: map[string]any{"userIds": []int32{outsiderID}},
+			buildStubs: func(store *mockdb.MockStore, task db.Task) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().UpdateTaskAssignees(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errAssigneeNotCreator.Error())
+			},
+		},
+		{
+			name:    "BadRequest: Empty",
+			task:    teamTask,
+			payload: map[string]any{"userIds": []int32{}},
+			buildStubs: func(store *mockdb.MockStore, task db.Task) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdateTaskAssignees(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "NotFound",
+			task:    teamTask,
+			payload: map[string]any{"userIds": []int32{memberID}},
+			buildStubs: func(store *mockdb.MockStore, task db.Task) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(db.Task{}, db.ErrRecordNotFound)
+				store.EXPECT().UpdateTaskAssignees(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:    "NotFound: Task Not Visible",
+			task:    teamTask,
+			payload: map[string]any{"userIds": []int32{memberID}},
+			buildStubs: func(store *mockdb.MockStore, task db.Task) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: teamID, UserID: user.ID})).
+					Times(1).
+					Return(db.TeamMember{}, db.ErrRecordNotFound)
+				store.EXPECT().FilterTeamMembers(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdateTaskAssignees(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errTaskNotFound.Error())
+			},
+		},
+		{
+			name:    "Internal Server Error",
+			task:    teamTask,
+			payload: map[string]any{"userIds": []int32{memberID}},
+			buildStubs: func(store *mockdb.MockStore, task db.Task) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: teamID, UserID: user.ID})).
+					Times(1).
+					Return(db.TeamMember{TeamID: teamID, UserID: user.ID}, nil)
+				store.EXPECT().FilterTeamMembers(gomock.Any(), gomock.Any()).Times(1).Return([]int32{memberID}, nil)
+				store.EXPECT().UpdateTaskAssignees(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimAssignTask)
+			tt.buildStubs(store, tt.task)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/tasks/%d/assignees", tt.task.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRemoveTaskAssigneesApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	task := randomTask()
+	task.CreatedBy = &user.ID
+	assigneeID := int32(util.RandomInt(1001, 2000))
+
+	teamID := int32(util.RandomInt(1, 1000))
+	teamTask := randomTask()
+	teamTask.TeamID = &teamID
+	teamTask.ID = task.ID
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().
+					UpdateTaskAssignees(gomock.Any(), gomock.Eq(db.UpdateTaskAssigneesParams{TaskID: task.ID, Remove: []int32{assigneeID}})).
+					Times(1).
+					Return([]db.ListTaskAssigneesRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(db.Task{}, db.ErrRecordNotFound)
+				store.EXPECT().UpdateTaskAssignees(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound: Not A Team Member",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(teamTask, nil)
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: teamID, UserID: user.ID})).
+					Times(1).
+					Return(db.TeamMember{}, db.ErrRecordNotFound)
+				store.EXPECT().UpdateTaskAssignees(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimAssignTask)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(map[string]any{"userIds": []int32{assigneeID}})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/tasks/%d/assignees", task.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func requireBodyMatchTask(t *testing.T, body *bytes.Buffer, task db.Task) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var responseData struct {
+		Data db.Task `json:"data"`
+	}
+	err = json.Unmarshal(data, &responseData)
+	require.NoError(t, err)
+
+	gotTask := responseData.Data
+	require.Equal(t, task.ID, gotTask.ID)
+	require.Equal(t, task.Title, gotTask.Title)
+	require.Equal(t, task.Description, gotTask.Description)
+	require.Equal(t, task.StatusID, gotTask.StatusID)
+	require.WithinDuration(t, *task.DueDate, *gotTask.DueDate, time.Second)
+}
@@ -0,0 +1,394 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/go-chi/render"
+)
+
+var (
+	errTeamNotFound       = errors.New("team not found")
+	errNotTeamMember      = errors.New("you are not a member of this team")
+	errAlreadyTeamMember  = errors.New("user is already a member of this team")
+	errTeamMemberNotFound = errors.New("user is not a member of this team")
+	errNotTeamOwner       = errors.New("only the owner of this team can transfer it")
+	errRemoveTeamOwner    = errors.New("the owner of this team cannot be removed until they transfer it")
+	errUserNotFound       = errors.New("user not found")
+)
+
+type teamPayload struct {
+	Name        string `json:"name" validate:"required,min=2,max=100"`
+	Description string `json:"description" validate:"max=1000"`
+}
+
+func (s *Server) CreateTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload teamPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	principal := principalFromContext(ctx)
+
+	arg := db.CreateTeamWithOwnerParams{
+		Name:        util.SanitizeInput(payload.Name),
+		Description: newText(payload.Description),
+		OwnerID:     principal.User.ID,
+	}
+
+	team, err := s.store.CreateTeamWithOwner(ctx, arg)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponseWithCode(team, http.StatusCreated))
+}
+
+func (s *Server) ListMyTeams(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal := principalFromContext(ctx)
+
+	teams, err := s.store.ListTeamsByUser(ctx, principal.User.ID)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(teams))
+}
+
+func (s *Server) GetTeam(w http.ResponseWriter, r *http.Request) {
+	team, ok := s.loadMemberTeam(w, r)
+	if !ok {
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(team))
+}
+
+type updateTeamPayload struct {
+	Name        *string `json:"name" validate:"omitempty,min=2,max=100"`
+	Description *string `json:"description" validate:"omitempty,max=1000"`
+}
+
+func (s *Server) UpdateTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload updateTeamPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	team, ok := s.loadMemberTeam(w, r)
+	if !ok {
+		return
+	}
+
+	arg := db.UpdateTeamParams{
+		ID:          team.ID,
+		Name:        team.Name,
+		Description: team.Description,
+	}
+
+	if payload.Name != nil {
+		arg.Name = util.SanitizeInput(*payload.Name)
+	}
+	if payload.Description != nil {
+		arg.Description = newText(*payload.Description)
+	}
+
+	team, err := s.store.UpdateTeam(ctx, arg)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(team))
+}
+
+func (s *Server) DeleteTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	team, ok := s.loadMemberTeam(w, r)
+	if !ok {
+		return
+	}
+
+	// The attachment rows go with the team's tasks, so their keys are
+	// collected first.
+	keys, err := s.store.ListTeamAttachmentKeys(ctx, team.ID)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	if err := s.store.DeleteTeam(ctx, team.ID); err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	s.deleteBlobs(ctx, keys...)
+
+	render.Render(w, r, SuccessfulResponse(nil))
+}
+
+func (s *Server) ListTeamMembers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	team, ok := s.loadMemberTeam(w, r)
+	if !ok {
+		return
+	}
+
+	members, err := s.store.ListTeamMembers(ctx, team.ID)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(members))
+}
+
+type teamMemberPayload struct {
+	UserID int32 `json:"userId" validate:"required,min=1"`
+}
+
+func (s *Server) AddTeamMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload teamMemberPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	team, ok := s.loadMemberTeam(w, r)
+	if !ok {
+		return
+	}
+
+	member, err := s.store.AddTeamMember(ctx, db.AddTeamMemberParams{
+		TeamID: team.ID,
+		UserID: payload.UserID,
+	})
+	if err != nil {
+		switch db.ErrorCode(err) {
+		case db.UniqueViolation:
+			render.Render(w, r, ErrInvalidRequestWithCode(errAlreadyTeamMember, http.StatusConflict))
+		case db.ForeignKeyViolation:
+			render.Render(w, r, ErrNotFound(errUserNotFound))
+		default:
+			render.Render(w, r, ErrInternalServer())
+		}
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponseWithCode(member, http.StatusCreated))
+}
+
+func (s *Server) RemoveTeamMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := parseIDParam(r, "userId")
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	team, ok := s.loadMemberTeam(w, r)
+	if !ok {
+		return
+	}
+
+	_, err = s.store.GetTeamMember(ctx, db.GetTeamMemberParams{
+		TeamID: team.ID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			render.Render(w, r, ErrNotFound(errTeamMemberNotFound))
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	err = s.store.RemoveTeamMember(ctx, db.RemoveTeamMemberParams{
+		TeamID: team.ID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrTeamOwner) {
+			render.Render(w, r, ErrInvalidRequestWithCode(errRemoveTeamOwner, http.StatusConflict))
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(nil))
+}
+
+// TransferTeamOwnership hands the team over to one of its members. Only the
+// current owner can do so, unless the caller holds ClaimViewAllRecords.
+func (s *Server) TransferTeamOwnership(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload teamMemberPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	team, ok := s.loadMemberTeam(w, r)
+	if !ok {
+		return
+	}
+
+	principal := principalFromContext(ctx)
+
+	if !isTeamOwner(team, principal.User.ID) {
+		viewAll, err := s.hasClaim(ctx, principal, ClaimViewAllRecords)
+		if err != nil {
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+		if !viewAll {
+			render.Render(w, r, ErrForbidden(errNotTeamOwner))
+			return
+		}
+	}
+
+	team, err := s.store.TransferTeamOwnership(ctx, db.TransferTeamOwnershipParams{
+		TeamID:     team.ID,
+		NewOwnerID: payload.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			render.Render(w, r, ErrNotFound(errTeamMemberNotFound))
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(team))
+}
+
+// loadTeam fetches the team identified by the "id" URL parameter and writes
+// the error response itself when it cannot.
+func (s *Server) loadTeam(w http.ResponseWriter, r *http.Request) (db.Team, bool) {
+	id, err := parseIDParam(r, "id")
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return db.Team{}, false
+	}
+
+	team, err := s.store.GetTeam(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			render.Render(w, r, ErrNotFound(errTeamNotFound))
+			return db.Team{}, false
+		}
+		render.Render(w, r, ErrInternalServer())
+		return db.Team{}, false
+	}
+
+	return team, true
+}
+
+// loadMemberTeam is loadTeam for endpoints that require the caller to be
+// part of the team. Teams the caller cannot access are reported as not found,
+// so their IDs cannot be probed.
+func (s *Server) loadMemberTeam(w http.ResponseWriter, r *http.Request) (db.Team, bool) {
+	team, ok := s.loadTeam(w, r)
+	if !ok {
+		return db.Team{}, false
+	}
+
+	canAccess, err := s.canAccessTeam(r.Context(), principalFromContext(r.Context()), team)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return db.Team{}, false
+	}
+
+	if !canAccess {
+		render.Render(w, r, ErrNotFound(errTeamNotFound))
+		return db.Team{}, false
+	}
+
+	return team, true
+}
+
+// canAccessTeam reports whether the principal owns or belongs to the team, or
+// holds ClaimViewAllRecords.
+func (s *Server) canAccessTeam(ctx context.Context, principal *Principal, team db.Team) (bool, error) {
+	viewAll, err := s.hasClaim(ctx, principal, ClaimViewAllRecords)
+	if err != nil || viewAll {
+		return viewAll, err
+	}
+
+	if isTeamOwner(team, principal.User.ID) {
+		return true, nil
+	}
+
+	return s.isTeamMember(ctx, team.ID, principal.User.ID)
+}
+
+func isTeamOwner(team db.Team, userID int32) bool {
+	return team.CreatedBy != nil && *team.CreatedBy == userID
+}
+
+// isTeamMember reports whether the user belongs to the given team.
+func (s *Server) isTeamMember(ctx context.Context, teamID, userID int32) (bool, error) {
+	_, err := s.store.GetTeamMember(ctx, db.GetTeamMemberParams{
+		TeamID: teamID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
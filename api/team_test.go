@@ -0,0 +1,523 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func randomTeam(createdBy int32) db.Team {
+	return db.Team{
+		ID:          int32(util.RandomInt(1, 1000)),
+		Name:        util.RandomString(10),
+		Description: pgtype.Text{String: util.RandomString(30), Valid: true},
+		CreatedBy:   &createdBy,
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+func TestCreateTeamApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	team := randomTeam(user.ID)
+
+	testCases := []struct {
+		name          string
+		payload       map[string]any
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			payload: map[string]any{
+				"name":        team.Name,
+				"description": team.Description.String,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateTeamWithOwnerParams{
+					Name:        team.Name,
+					Description: team.Description,
+					OwnerID:     user.ID,
+				}
+				store.EXPECT().
+					CreateTeamWithOwner(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(team, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest: Missing Name",
+			payload: map[string]any{
+				"description": team.Description.String,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateTeamWithOwner(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Server Error",
+			payload: map[string]any{
+				"name": team.Name,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateTeamWithOwner(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Team{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimCreateTeam)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/teams", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListMyTeamsApi(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := randomAuthenticatedUser(t)
+	teams := []db.Team{randomTeam(user.ID), randomTeam(user.ID)}
+
+	store := mockdb.NewMockStore(ctrl)
+	stubAuthenticatedUser(store, user)
+	store.EXPECT().
+		ListTeamsByUser(gomock.Any(), gomock.Eq(user.ID)).
+		Times(1).
+		Return(teams, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/v1/teams", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Data []db.Team `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response.Data, len(teams))
+}
+
+func TestGetTeamApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	team := randomTeam(user.ID + 1)
+	memberArg := db.GetTeamMemberParams{TeamID: team.ID, UserID: user.ID}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK: Owner",
+			buildStubs: func(store *mockdb.MockStore) {
+				owned := team
+				owned.CreatedBy = &user.ID
+
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(owned, nil)
+				store.EXPECT().GetTeamMember(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "OK: Member",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(memberArg)).
+					Times(1).
+					Return(db.TeamMember{TeamID: team.ID, UserID: user.ID}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Data db.Team `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, team.ID, response.Data.ID)
+			},
+		},
+		{
+			name: "NotFound: Not A Team Member",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(memberArg)).
+					Times(1).
+					Return(db.TeamMember{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errTeamNotFound.Error())
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(db.Team{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Server Error",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+				store.EXPECT().GetTeamMember(gomock.Any(), gomock.Any()).Times(1).Return(db.TeamMember{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/teams/%d", team.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAddTeamMemberApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	team := randomTeam(user.ID)
+	newMemberID := user.ID + 1
+
+	otherTeam := randomTeam(user.ID + 2)
+	otherTeam.ID = team.ID
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+				store.EXPECT().
+					AddTeamMember(gomock.Any(), gomock.Eq(db.AddTeamMemberParams{TeamID: team.ID, UserID: newMemberID})).
+					Times(1).
+					Return(db.TeamMember{TeamID: team.ID, UserID: newMemberID}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "Conflict: Already Member",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+				store.EXPECT().
+					AddTeamMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TeamMember{}, db.ErrUniqueViolation)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound: Unknown User",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+				store.EXPECT().
+					AddTeamMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TeamMember{}, &pgconn.PgError{Code: db.ForeignKeyViolation})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound: Not A Team Member",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(otherTeam, nil)
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: team.ID, UserID: user.ID})).
+					Times(1).
+					Return(db.TeamMember{}, db.ErrRecordNotFound)
+				store.EXPECT().AddTeamMember(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound: Unknown Team",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(db.Team{}, db.ErrRecordNotFound)
+				store.EXPECT().AddTeamMember(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimManageTeamMembers)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(map[string]any{"userId": newMemberID})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/teams/%d/members", team.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRemoveTeamMemberApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	team := randomTeam(user.ID)
+	memberID := user.ID + 1
+	memberArg := db.GetTeamMemberParams{TeamID: team.ID, UserID: memberID}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+				store.EXPECT().GetTeamMember(gomock.Any(), gomock.Eq(memberArg)).Times(1).Return(db.TeamMember{}, nil)
+				store.EXPECT().
+					RemoveTeamMember(gomock.Any(), gomock.Eq(db.RemoveTeamMemberParams{TeamID: team.ID, UserID: memberID})).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound: Not A Member",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+				store.EXPECT().GetTeamMember(gomock.Any(), gomock.Eq(memberArg)).Times(1).Return(db.TeamMember{}, db.ErrRecordNotFound)
+				store.EXPECT().RemoveTeamMember(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "Conflict: Owner",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+				store.EXPECT().GetTeamMember(gomock.Any(), gomock.Eq(memberArg)).Times(1).Return(db.TeamMember{}, nil)
+				store.EXPECT().RemoveTeamMember(gomock.Any(), gomock.Any()).Times(1).Return(db.ErrTeamOwner)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errRemoveTeamOwner.Error())
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimManageTeamMembers)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/teams/%d/members/%d", team.ID, memberID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestTransferTeamOwnershipApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	team := randomTeam(user.ID)
+	newOwnerID := user.ID + 1
+	arg := db.TransferTeamOwnershipParams{TeamID: team.ID, NewOwnerID: newOwnerID}
+
+	// A team the user only belongs to.
+	memberTeam := randomTeam(newOwnerID)
+	memberTeam.ID = team.ID
+	memberArg := db.GetTeamMemberParams{TeamID: team.ID, UserID: user.ID}
+
+	testCases := []struct {
+		name          string
+		claims        []string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				transferred := team
+				transferred.CreatedBy = &newOwnerID
+
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+				store.EXPECT().TransferTeamOwnership(gomock.Any(), gomock.Eq(arg)).Times(1).Return(transferred, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "OK: View All Records",
+			claims: []string{ClaimViewAllRecords},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(memberTeam, nil)
+				store.EXPECT().TransferTeamOwnership(gomock.Any(), gomock.Eq(arg)).Times(1).Return(memberTeam, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Forbidden: Not The Owner",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(memberTeam, nil)
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(memberArg)).
+					Times(1).
+					Return(db.TeamMember{TeamID: team.ID, UserID: user.ID}, nil)
+				store.EXPECT().TransferTeamOwnership(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errNotTeamOwner.Error())
+			},
+		},
+		{
+			name: "NotFound: New Owner Not A Member",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+				store.EXPECT().TransferTeamOwnership(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.Team{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, append(tt.claims, ClaimEditTeam)...)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(map[string]any{"userId": newOwnerID})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/teams/%d/owner", team.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/util"
+)
+
+// defaultTimezone is the zone of accounts that did not pick one.
+const defaultTimezone = "UTC"
+
+var errInvalidDueDate = errors.New("dueDate must be an RFC 3339 timestamp or a date such as 2025-10-31")
+
+// dueDate is a due date as clients send it: either an RFC 3339 timestamp
+// with an offset, or a plain calendar date. A plain date means the end of
+// that day for whoever the task is due for, which depends on their timezone.
+type dueDate struct {
+	at     time.Time
+	isDate bool
+}
+
+func (d *dueDate) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return errInvalidDueDate
+	}
+
+	if at, err := time.Parse(time.RFC3339, raw); err == nil {
+		*d = dueDate{at: at.UTC()}
+		return nil
+	}
+
+	at, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return errInvalidDueDate
+	}
+
+	*d = dueDate{at: at, isDate: true}
+	return nil
+}
+
+// In resolves the due date, reading a plain date on the wall clock of
+// location.
+func (d dueDate) In(location *time.Location) time.Time {
+	if !d.isDate {
+		return d.at
+	}
+	return util.EndOfDay(d.at.Year(), d.at.Month(), d.at.Day(), location)
+}
+
+// userLocation returns the user's timezone. Users predating the timezone
+// setting, and any name the host cannot resolve, fall back to UTC.
+func userLocation(timezone string) *time.Location {
+	location, err := util.LoadTimezone(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// dueDateLocation is the timezone a plain due date on task is read in: that
+// of its first assignee, or the caller's when nobody is assigned yet.
+func (s *Server) dueDateLocation(ctx context.Context, task db.Task, caller db.User) (*time.Location, error) {
+	assignees, err := s.store.ListTaskAssignees(ctx, task.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(assignees) > 0 {
+		return userLocation(assignees[0].Timezone), nil
+	}
+	return userLocation(caller.Timezone), nil
+}
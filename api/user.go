@@ -3,12 +3,15 @@ package api
 import (
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"time"
 
 	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/token"
 	"github.com/bolusarz/task-manager/util"
 	"github.com/go-chi/render"
+	"github.com/google/uuid"
 )
 
 type createAccountPayload struct {
@@ -16,6 +19,9 @@ type createAccountPayload struct {
 	LastName  string `json:"lastName" validate:"required,alpha,min=3,max=50"`
 	Email     string `json:"email" validate:"required,email,max=100"`
 	Password  string `json:"password" validate:"required,strong"`
+	// Timezone is the IANA zone plain due dates are read in. It defaults to
+	// UTC.
+	Timezone string `json:"timezone" validate:"omitempty,iana_timezone,max=64"`
 }
 
 type createAccountResponse struct {
@@ -24,6 +30,7 @@ type createAccountResponse struct {
 	Email           string `json:"email"`
 	IsEmailVerified bool   `json:"isEmailVerified"`
 	ProfilePicture  string `json:"profilePicture"`
+	Timezone        string `json:"timezone"`
 }
 
 func newCreateAccountResponse(user db.User) createAccountResponse {
@@ -33,6 +40,7 @@ func newCreateAccountResponse(user db.User) createAccountResponse {
 		Email:           user.Email,
 		IsEmailVerified: user.IsEmailVerified,
 		ProfilePicture:  user.ProfilePictureUrl,
+		Timezone:        user.Timezone,
 	}
 }
 
@@ -59,14 +67,24 @@ func (s *Server) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	arg := db.CreateUserParams{
-		FirstName:    util.SanitizeInput(payload.FirstName),
-		LastName:     util.SanitizeInput(payload.LastName),
-		Email:        util.SanitizeInput(payload.Email),
-		PasswordHash: hashedPassword,
+	if payload.Timezone == "" {
+		payload.Timezone = defaultTimezone
 	}
 
-	user, err := s.store.CreateUser(ctx, arg)
+	arg := db.CreateUserWithRolesParams{
+		CreateUserParams: db.CreateUserParams{
+			FirstName:    util.SanitizeInput(payload.FirstName),
+			LastName:     util.SanitizeInput(payload.LastName),
+			Email:        util.SanitizeInput(payload.Email),
+			PasswordHash: hashedPassword,
+			Timezone:     payload.Timezone,
+		},
+		AfterCreate: func(q db.Querier, user db.User) error {
+			return s.grantDefaultRoles(ctx, q, user)
+		},
+	}
+
+	user, err := s.store.CreateUserWithRoles(ctx, arg)
 	if err != nil {
 		errCode := db.ErrorCode(err)
 		if errCode == db.UniqueViolation {
@@ -77,6 +95,12 @@ func (s *Server) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The account exists at this point; a failed email can be retried through
+	// the resend endpoint, so it must not fail the registration.
+	if err := s.sendVerificationEmail(ctx, user); err != nil {
+		log.Printf("unable to send verification email to %s: %v", user.Email, err)
+	}
+
 	render.Render(w, r, SuccessfulResponse(newCreateAccountResponse(user)))
 }
 
@@ -86,13 +110,16 @@ type loginPayload struct {
 }
 
 type loginResponse struct {
-	FirstName       string    `json:"firstName"`
-	LastName        string    `json:"lastName"`
-	Email           string    `json:"email"`
-	IsEmailVerified bool      `json:"isEmailVerified"`
-	ProfilePicture  string    `json:"profilePicture"`
-	Token           string    `json:"token"`
-	ExpiresAt       time.Time `json:"expiresAt"`
+	FirstName             string    `json:"firstName"`
+	LastName              string    `json:"lastName"`
+	Email                 string    `json:"email"`
+	IsEmailVerified       bool      `json:"isEmailVerified"`
+	ProfilePicture        string    `json:"profilePicture"`
+	Timezone              string    `json:"timezone"`
+	Token                 string    `json:"token"`
+	ExpiresAt             time.Time `json:"expiresAt"`
+	RefreshToken          string    `json:"refreshToken"`
+	RefreshTokenExpiresAt time.Time `json:"refreshTokenExpiresAt"`
 }
 
 func (s *Server) Login(w http.ResponseWriter, r *http.Request) {
@@ -127,20 +154,34 @@ func (s *Server) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, tokenPayload, err := s.tokenMaker.CreateToken(user.Email, s.config.AccessTokenDuration)
+	accessToken, accessPayload, err := s.tokenMaker.CreateToken(user.ID, user.Email, token.TokenTypeAccessToken, s.config.AccessTokenDuration)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	refreshToken, refreshPayload, session, err := s.newSession(r, user, uuid.New())
 	if err != nil {
 		render.Render(w, r, ErrInternalServer())
 		return
 	}
 
+	if _, err := s.store.CreateSession(ctx, session); err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
 	response := loginResponse{
-		FirstName:       user.FirstName,
-		LastName:        user.LastName,
-		Email:           user.Email,
-		IsEmailVerified: user.IsEmailVerified,
-		ProfilePicture:  user.ProfilePictureUrl,
-		Token:           token,
-		ExpiresAt:       tokenPayload.ExpiresAt,
+		FirstName:             user.FirstName,
+		LastName:              user.LastName,
+		Email:                 user.Email,
+		IsEmailVerified:       user.IsEmailVerified,
+		ProfilePicture:        user.ProfilePictureUrl,
+		Timezone:              user.Timezone,
+		Token:                 accessToken,
+		ExpiresAt:             accessPayload.ExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshPayload.ExpiresAt,
 	}
 
 	render.Render(w, r, SuccessfulResponse(response))
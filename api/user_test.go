@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -24,10 +25,11 @@ type eqCreateUserParamsMatcher struct {
 }
 
 func (e eqCreateUserParamsMatcher) Matches(x interface{}) bool {
-	arg, ok := x.(db.CreateUserParams)
+	txArg, ok := x.(db.CreateUserWithRolesParams)
 	if !ok {
 		return false
 	}
+	arg := txArg.CreateUserParams
 
 	err := util.ComparePassword(e.password, arg.PasswordHash)
 	if err != nil {
@@ -58,6 +60,7 @@ func randomUser(t *testing.T) (user db.User, password string) {
 		LastName:     util.RandomString(10),
 		PasswordHash: hashedPassword,
 		Email:        util.RandomEmail(),
+		Timezone:     defaultTimezone,
 	}
 	return
 }
@@ -84,11 +87,26 @@ func TestCreateUserApi(t *testing.T) {
 					FirstName: user.FirstName,
 					LastName:  user.LastName,
 					Email:     user.Email,
+					Timezone:  defaultTimezone,
 				}
 				store.EXPECT().
-					CreateUser(gomock.Any(), EqCreateUserParams(args, password)).
+					CreateUserWithRoles(gomock.Any(), EqCreateUserParams(args, password)).
 					Times(1).
-					Return(user, nil)
+					DoAndReturn(func(_ context.Context, arg db.CreateUserWithRolesParams) (db.User, error) {
+						return user, arg.AfterCreate(store, user)
+					})
+				store.EXPECT().
+					AddUserRoleByName(gomock.Any(), gomock.Eq(db.AddUserRoleByNameParams{UserID: user.ID, Name: memberRoleName})).
+					Times(1).
+					Return(nil)
+				store.EXPECT().
+					InvalidateUserTokens(gomock.Any(), gomock.Eq(db.InvalidateUserTokensParams{UserID: user.ID, Purpose: tokenPurposeEmailVerification})).
+					Times(1).
+					Return(nil)
+				store.EXPECT().
+					CreateUserToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UserToken{}, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -102,7 +120,23 @@ func TestCreateUserApi(t *testing.T) {
 				"password": password,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateUserWithRoles(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest: Invalid Timezone",
+			payload: map[string]any{
+				"firstName": user.FirstName,
+				"lastName":  user.LastName,
+				"email":     user.Email,
+				"password":  password,
+				"timezone":  "Local",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateUserWithRoles(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -117,7 +151,7 @@ func TestCreateUserApi(t *testing.T) {
 				"password":  password,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateUserWithRoles(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -132,14 +166,14 @@ func TestCreateUserApi(t *testing.T) {
 				"password":  util.RandomString(7),
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateUserWithRoles(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 		{
-			name: "BadRequest: User Already Exists",
+			name: "Conflict: User Already Exists",
 			payload: map[string]any{
 				"firstName": user.FirstName,
 				"lastName":  user.LastName,
@@ -148,11 +182,11 @@ func TestCreateUserApi(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserWithRoles(gomock.Any(), gomock.Any()).
 					Times(1).Return(user, db.ErrUniqueViolation)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Equal(t, http.StatusConflict, recorder.Code)
 			},
 		},
 		{
@@ -165,7 +199,7 @@ func TestCreateUserApi(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserWithRoles(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(user, sql.ErrConnDone)
 			},
@@ -215,5 +249,6 @@ func requireBodyMatchUser(t *testing.T, body *bytes.Buffer, user db.User) {
 	require.Equal(t, user.LastName, gotUser["lastName"])
 	require.Equal(t, user.Email, gotUser["email"])
 	require.Equal(t, false, gotUser["isEmailVerified"])
+	require.Equal(t, user.Timezone, gotUser["timezone"])
 	require.NotContains(t, gotUser, "passwordHash")
 }
@@ -2,8 +2,11 @@ package api
 
 import (
 	"fmt"
+	"slices"
 	"unicode"
 
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/util"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -24,6 +27,14 @@ func TransformValidationErrors(err error) []string {
 				errorMessages = append(errorMessages, fmt.Sprintf("%s is not a valid email address", e.Field()))
 			case "strong":
 				errorMessages = append(errorMessages, fmt.Sprintf("%s is not strong enough", e.Field()))
+			case "iana_timezone":
+				errorMessages = append(errorMessages, fmt.Sprintf("%s must be an IANA timezone such as Europe/London", e.Field()))
+			case "event_type":
+				errorMessages = append(errorMessages, fmt.Sprintf("%s must be an event type such as task.created", e.Field()))
+			case "oneof":
+				errorMessages = append(errorMessages, fmt.Sprintf("%s must be one of [%s]", e.Field(), e.Param()))
+			default:
+				errorMessages = append(errorMessages, fmt.Sprintf("%s is invalid", e.Field()))
 			}
 		}
 	}
@@ -55,3 +66,14 @@ func IsPasswordStrong(fl validator.FieldLevel) bool {
 
 	return hasLower && hasUpper && hasDigit && hasSpecial
 }
+
+// IsTimezone accepts IANA timezone names such as "Europe/London".
+func IsTimezone(fl validator.FieldLevel) bool {
+	_, err := util.LoadTimezone(fl.Field().String())
+	return err == nil
+}
+
+// IsEventType accepts the names of domain events, such as "task.created".
+func IsEventType(fl validator.FieldLevel) bool {
+	return slices.Contains(db.EventTypes, db.EventType(fl.Field().String()))
+}
@@ -151,6 +151,28 @@ func TestTransformValidationErrors(t *testing.T) {
 				"FirstName exceeds the maximum length of 10",
 			},
 		},
+		{
+			name: "Invalid case: 'iana_timezone'",
+			data: struct {
+				Timezone string `validate:"iana_timezone"`
+			}{
+				Timezone: "Local",
+			},
+			errorMessages: []string{
+				"Timezone must be an IANA timezone such as Europe/London",
+			},
+		},
+		{
+			name: "Invalid case: 'event_type'",
+			data: struct {
+				EventType string `validate:"event_type"`
+			}{
+				EventType: "task.archived",
+			},
+			errorMessages: []string{
+				"EventType must be an event type such as task.created",
+			},
+		},
 		{
 			name: "No Errors",
 			data: struct {
@@ -0,0 +1,227 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/mail"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/go-chi/render"
+)
+
+const (
+	tokenPurposeEmailVerification = "email_verification"
+
+	// secureTokenBytes is the amount of randomness in every emailed token.
+	secureTokenBytes = 32
+
+	defaultEmailVerificationTokenDuration = 24 * time.Hour
+	defaultEmailVerificationResendDelay   = time.Minute
+)
+
+var (
+	errInvalidVerificationToken = errors.New("invalid or expired verification token")
+	errEmailAlreadyVerified     = errors.New("email is already verified")
+	errEmailNotVerified         = errors.New("please verify your email address first")
+	errVerificationThrottled    = errors.New("a verification email was sent recently, please try again later")
+)
+
+type verifyEmailPayload struct {
+	Token string `json:"token" validate:"required"`
+}
+
+func (s *Server) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload verifyEmailPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	userToken, ok := s.loadUserToken(w, r, payload.Token, tokenPurposeEmailVerification, errInvalidVerificationToken)
+	if !ok {
+		return
+	}
+
+	err := s.store.ConsumeUserToken(ctx, db.ConsumeUserTokenParams{
+		TokenID: userToken.ID,
+		Apply: func(q db.Querier) error {
+			// The token only verifies the address it was sent to, which
+			// is no longer the account's if the email changed since.
+			verified, err := q.MarkUserEmailVerified(ctx, db.MarkUserEmailVerifiedParams{
+				ID:    userToken.UserID,
+				Email: userToken.Email,
+			})
+			if err != nil {
+				return err
+			}
+			if verified == 0 {
+				return errInvalidVerificationToken
+			}
+			return s.grantBootstrapAdmin(ctx, q, userToken.UserID)
+		},
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrUserTokenUsed) || errors.Is(err, errInvalidVerificationToken) {
+			render.Render(w, r, ErrInvalidRequest(errInvalidVerificationToken))
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(nil))
+}
+
+func (s *Server) ResendVerificationEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	principal := principalFromContext(ctx)
+
+	if principal.User.IsEmailVerified {
+		render.Render(w, r, ErrInvalidRequest(errEmailAlreadyVerified))
+		return
+	}
+
+	latest, err := s.store.GetLatestUserToken(ctx, db.GetLatestUserTokenParams{
+		UserID:  principal.User.ID,
+		Purpose: tokenPurposeEmailVerification,
+	})
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	delay := durationOrDefault(s.config.EmailVerificationResendDelay, defaultEmailVerificationResendDelay)
+	if err == nil && time.Since(latest.CreatedAt) < delay {
+		w.Header().Set("Retry-After", fmt.Sprintf("%d", int(delay.Seconds())))
+		render.Render(w, r, ErrTooManyRequests(errVerificationThrottled))
+		return
+	}
+
+	if err := s.sendVerificationEmail(ctx, principal.User); err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponseWithCode(nil, http.StatusAccepted))
+}
+
+// RequireVerifiedEmail rejects callers who have not verified their email
+// address. It only takes effect when REQUIRE_VERIFIED_EMAIL is set and must be
+// mounted behind Authenticate.
+func (s *Server) RequireVerifiedEmail(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.config.RequireVerifiedEmail && !principalFromContext(r.Context()).User.IsEmailVerified {
+			render.Render(w, r, ErrForbidden(errEmailNotVerified))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// sendVerificationEmail replaces any outstanding verification token for the
+// user with a new one and emails it to them.
+func (s *Server) sendVerificationEmail(ctx context.Context, user db.User) error {
+	err := s.store.InvalidateUserTokens(ctx, db.InvalidateUserTokensParams{
+		UserID:  user.ID,
+		Purpose: tokenPurposeEmailVerification,
+	})
+	if err != nil {
+		return err
+	}
+
+	duration := durationOrDefault(s.config.EmailVerificationTokenDuration, defaultEmailVerificationTokenDuration)
+
+	token, err := s.issueUserToken(ctx, user, tokenPurposeEmailVerification, duration)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address:\n\n%s\n\nThis link expires in %s.\n",
+			user.FirstName,
+			s.appLink("/verify-email", token),
+			duration,
+		),
+	})
+}
+
+// issueUserToken creates a one-time token for the given purpose, bound to the
+// user's current email address, and returns it. Only its hash is stored.
+func (s *Server) issueUserToken(ctx context.Context, user db.User, purpose string, duration time.Duration) (string, error) {
+	token, err := util.SecureToken(secureTokenBytes)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = s.store.CreateUserToken(ctx, db.CreateUserTokenParams{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: util.HashToken(token),
+		ExpiresAt: time.Now().UTC().Add(duration),
+		Email:     user.Email,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// loadUserToken looks up an unused, unexpired token for the given purpose and
+// writes invalidErr as a bad request when there is none.
+func (s *Server) loadUserToken(w http.ResponseWriter, r *http.Request, token, purpose string, invalidErr error) (db.UserToken, bool) {
+	userToken, err := s.store.GetUserTokenByHash(r.Context(), db.GetUserTokenByHashParams{
+		TokenHash: util.HashToken(token),
+		Purpose:   purpose,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			render.Render(w, r, ErrInvalidRequest(invalidErr))
+			return db.UserToken{}, false
+		}
+		render.Render(w, r, ErrInternalServer())
+		return db.UserToken{}, false
+	}
+
+	if userToken.UsedAt != nil || time.Now().After(userToken.ExpiresAt) {
+		render.Render(w, r, ErrInvalidRequest(invalidErr))
+		return db.UserToken{}, false
+	}
+
+	return userToken, true
+}
+
+// appLink builds a link into the client application carrying token, falling
+// back to the bare token when APP_BASE_URL is not configured.
+func (s *Server) appLink(path, token string) string {
+	if s.config.AppBaseURL == "" {
+		return token
+	}
+	return fmt.Sprintf("%s%s?token=%s", s.config.AppBaseURL, path, url.QueryEscape(token))
+}
+
+func durationOrDefault(d, fallback time.Duration) time.Duration {
+	if d <= 0 {
+		return fallback
+	}
+	return d
+}
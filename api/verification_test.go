@@ -0,0 +1,292 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/mail"
+	mockmail "github.com/bolusarz/task-manager/mail/mock"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestVerifyEmailApi(t *testing.T) {
+	token, err := util.SecureToken(secureTokenBytes)
+	require.NoError(t, err)
+
+	hashArg := db.GetUserTokenByHashParams{
+		TokenHash: util.HashToken(token),
+		Purpose:   tokenPurposeEmailVerification,
+	}
+	userToken := db.UserToken{
+		ID:        int32(util.RandomInt(1, 1000)),
+		UserID:    int32(util.RandomInt(1, 1000)),
+		Purpose:   tokenPurposeEmailVerification,
+		TokenHash: hashArg.TokenHash,
+		ExpiresAt: time.Now().Add(time.Hour),
+		Email:     util.RandomEmail(),
+	}
+	verifiedArg := db.MarkUserEmailVerifiedParams{ID: userToken.UserID, Email: userToken.Email}
+	usedAt := time.Now()
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTokenByHash(gomock.Any(), gomock.Eq(hashArg)).Times(1).Return(userToken, nil)
+				store.EXPECT().
+					ConsumeUserToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ConsumeUserTokenParams) error {
+						require.Equal(t, userToken.ID, arg.TokenID)
+						return arg.Apply(store)
+					})
+				store.EXPECT().MarkUserEmailVerified(gomock.Any(), gomock.Eq(verifiedArg)).Times(1).Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest: Email Changed",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTokenByHash(gomock.Any(), gomock.Eq(hashArg)).Times(1).Return(userToken, nil)
+				store.EXPECT().
+					ConsumeUserToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ConsumeUserTokenParams) error {
+						return arg.Apply(store)
+					})
+				store.EXPECT().MarkUserEmailVerified(gomock.Any(), gomock.Eq(verifiedArg)).Times(1).Return(int64(0), nil)
+				store.EXPECT().GetUserById(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errInvalidVerificationToken.Error())
+			},
+		},
+		{
+			name: "BadRequest: Unknown Token",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTokenByHash(gomock.Any(), gomock.Eq(hashArg)).Times(1).Return(db.UserToken{}, db.ErrRecordNotFound)
+				store.EXPECT().ConsumeUserToken(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest: Expired Token",
+			buildStubs: func(store *mockdb.MockStore) {
+				expired := userToken
+				expired.ExpiresAt = time.Now().Add(-time.Minute)
+
+				store.EXPECT().GetUserTokenByHash(gomock.Any(), gomock.Eq(hashArg)).Times(1).Return(expired, nil)
+				store.EXPECT().ConsumeUserToken(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest: Used Token",
+			buildStubs: func(store *mockdb.MockStore) {
+				used := userToken
+				used.UsedAt = &usedAt
+
+				store.EXPECT().GetUserTokenByHash(gomock.Any(), gomock.Eq(hashArg)).Times(1).Return(used, nil)
+				store.EXPECT().ConsumeUserToken(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest: Used Concurrently",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserTokenByHash(gomock.Any(), gomock.Eq(hashArg)).Times(1).Return(userToken, nil)
+				store.EXPECT().ConsumeUserToken(gomock.Any(), gomock.Any()).Times(1).Return(db.ErrUserTokenUsed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(map[string]any{"token": token})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/users/verify-email", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestResendVerificationEmailApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	latestArg := db.GetLatestUserTokenParams{UserID: user.ID, Purpose: tokenPurposeEmailVerification}
+
+	testCases := []struct {
+		name          string
+		user          func() db.User
+		buildStubs    func(store *mockdb.MockStore, mailer *mockmail.MockMailer)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			user: func() db.User { return user },
+			buildStubs: func(store *mockdb.MockStore, mailer *mockmail.MockMailer) {
+				var tokenHash string
+
+				store.EXPECT().
+					GetLatestUserToken(gomock.Any(), gomock.Eq(latestArg)).
+					Times(1).
+					Return(db.UserToken{CreatedAt: time.Now().Add(-time.Hour)}, nil)
+				store.EXPECT().
+					InvalidateUserTokens(gomock.Any(), gomock.Eq(db.InvalidateUserTokensParams{UserID: user.ID, Purpose: tokenPurposeEmailVerification})).
+					Times(1).
+					Return(nil)
+				store.EXPECT().
+					CreateUserToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateUserTokenParams) (db.UserToken, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.Equal(t, user.Email, arg.Email)
+						require.True(t, arg.ExpiresAt.After(time.Now()))
+						tokenHash = arg.TokenHash
+						return db.UserToken{}, nil
+					})
+				mailer.EXPECT().
+					Send(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, message mail.Message) error {
+						require.Equal(t, user.Email, message.To)
+
+						// The emailed token, not its hash, is what the user receives.
+						fields := strings.Fields(message.Body)
+						var found bool
+						for _, field := range fields {
+							if util.HashToken(field) == tokenHash {
+								found = true
+							}
+						}
+						require.True(t, found)
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name: "TooManyRequests",
+			user: func() db.User { return user },
+			buildStubs: func(store *mockdb.MockStore, mailer *mockmail.MockMailer) {
+				store.EXPECT().
+					GetLatestUserToken(gomock.Any(), gomock.Eq(latestArg)).
+					Times(1).
+					Return(db.UserToken{CreatedAt: time.Now()}, nil)
+				store.EXPECT().CreateUserToken(gomock.Any(), gomock.Any()).Times(0)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+				require.NotEmpty(t, recorder.Header().Get("Retry-After"))
+			},
+		},
+		{
+			name: "BadRequest: Already Verified",
+			user: func() db.User {
+				verified := user
+				verified.IsEmailVerified = true
+				return verified
+			},
+			buildStubs: func(store *mockdb.MockStore, mailer *mockmail.MockMailer) {
+				store.EXPECT().GetLatestUserToken(gomock.Any(), gomock.Any()).Times(0)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			mailer := mockmail.NewMockMailer(ctrl)
+			user := tt.user()
+			stubAuthenticatedUser(store, user)
+			tt.buildStubs(store, mailer)
+
+			server := newTestServer(t, store)
+			server.mailer = mailer
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/users/verify-email/resend", nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRequireVerifiedEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := randomAuthenticatedUser(t)
+
+	store := mockdb.NewMockStore(ctrl)
+	stubAuthenticatedUser(store, user, ClaimCreateTask)
+	store.EXPECT().CreateTaskWithAssignees(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	server.config.RequireVerifiedEmail = true
+	recorder := httptest.NewRecorder()
+
+	jsonBody, err := json.Marshal(map[string]any{"title": util.RandomString(10)})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/api/v1/tasks", bytes.NewBuffer(jsonBody))
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+	require.Contains(t, recorder.Body.String(), errEmailNotVerified.Error())
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/go-chi/render"
+)
+
+func (s *Server) ListTaskWatchers(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.loadVisibleTask(w, r)
+	if !ok {
+		return
+	}
+
+	watchers, err := s.store.ListTaskWatchers(r.Context(), task.ID)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(watchers))
+}
+
+// WatchTask has the current user notified whenever the task changes.
+// Watching a task twice is not an error.
+func (s *Server) WatchTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	task, ok := s.loadVisibleTask(w, r)
+	if !ok {
+		return
+	}
+
+	principal := principalFromContext(ctx)
+
+	err := s.store.AddTaskWatcher(ctx, db.AddTaskWatcherParams{
+		TaskID: task.ID,
+		UserID: principal.User.ID,
+	})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(nil))
+}
+
+func (s *Server) UnwatchTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	task, ok := s.loadTask(w, r)
+	if !ok {
+		return
+	}
+
+	principal := principalFromContext(ctx)
+
+	err := s.store.RemoveTaskWatcher(ctx, db.RemoveTaskWatcherParams{
+		TaskID: task.ID,
+		UserID: principal.User.ID,
+	})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(nil))
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWatchTaskApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	other := randomAuthenticatedUser(t)
+
+	ownTask := randomTask()
+	ownTask.CreatedBy = &user.ID
+
+	othersTask := randomTask()
+	othersTask.CreatedBy = &other.ID
+
+	testCases := []struct {
+		name          string
+		method        string
+		task          db.Task
+		buildStubs    func(store *mockdb.MockStore, task db.Task)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Watch",
+			method: http.MethodPut,
+			task:   ownTask,
+			buildStubs: func(store *mockdb.MockStore, task db.Task) {
+				store.EXPECT().
+					AddTaskWatcher(gomock.Any(), gomock.Eq(db.AddTaskWatcherParams{TaskID: task.ID, UserID: user.ID})).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Watch: Not Visible",
+			method: http.MethodPut,
+			task:   othersTask,
+			buildStubs: func(store *mockdb.MockStore, task db.Task) {
+				store.EXPECT().ListTaskAssignees(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return([]db.ListTaskAssigneesRow{}, nil)
+				store.EXPECT().AddTaskWatcher(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			// Anyone can stop watching, including after losing access.
+			name:   "Unwatch",
+			method: http.MethodDelete,
+			task:   othersTask,
+			buildStubs: func(store *mockdb.MockStore, task db.Task) {
+				store.EXPECT().
+					RemoveTaskWatcher(gomock.Any(), gomock.Eq(db.RemoveTaskWatcherParams{TaskID: task.ID, UserID: user.ID})).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user)
+			store.EXPECT().GetTask(gomock.Any(), gomock.Eq(tt.task.ID)).Times(1).Return(tt.task, nil)
+			tt.buildStubs(store, tt.task)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(tt.method, fmt.Sprintf("/api/v1/tasks/%d/watch", tt.task.ID), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
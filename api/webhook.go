@@ -0,0 +1,330 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/bolusarz/task-manager/webhook"
+	"github.com/go-chi/render"
+)
+
+// webhookSecretBytes is the randomness behind secrets the server generates.
+const webhookSecretBytes = 32
+
+var (
+	errWebhookNotFound         = errors.New("webhook not found")
+	errWebhookDeliveryNotFound = errors.New("delivery not found")
+	errWebhookDisabled         = errors.New("the webhook is disabled; enable it before redelivering")
+)
+
+// webhookSecretResponse is a webhook along with its signing secret, which is
+// only ever shown when the webhook is created.
+type webhookSecretResponse struct {
+	db.Webhook
+	Secret string `json:"secret"`
+}
+
+type createWebhookPayload struct {
+	URL        string   `json:"url" validate:"required,http_url,max=2048"`
+	EventTypes []string `json:"eventTypes" validate:"required,min=1,dive,event_type"`
+	// Secret is generated when it is left out.
+	Secret string `json:"secret" validate:"omitempty,min=16,max=128"`
+}
+
+// CreateWebhook subscribes a URL to events of the team. Deliveries are signed
+// with the secret, which the response shows this one time.
+func (s *Server) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload createWebhookPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	if err := webhook.CheckURL(ctx, payload.URL); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	team, ok := s.loadMemberTeam(w, r)
+	if !ok {
+		return
+	}
+
+	secret := payload.Secret
+	if secret == "" {
+		var err error
+		if secret, err = util.SecureToken(webhookSecretBytes); err != nil {
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+	}
+
+	principal := principalFromContext(ctx)
+
+	created, err := s.store.CreateWebhook(ctx, db.CreateWebhookParams{
+		TeamID:     team.ID,
+		Url:        payload.URL,
+		EventTypes: normalizeEventTypes(payload.EventTypes),
+		Secret:     secret,
+		CreatedBy:  &principal.User.ID,
+	})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponseWithCode(webhookSecretResponse{Webhook: created, Secret: created.Secret}, http.StatusCreated))
+}
+
+func (s *Server) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	team, ok := s.loadMemberTeam(w, r)
+	if !ok {
+		return
+	}
+
+	webhooks, err := s.store.ListTeamWebhooks(r.Context(), team.ID)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(webhooks))
+}
+
+func (s *Server) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	hook, ok := s.loadWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(hook))
+}
+
+type updateWebhookPayload struct {
+	URL        *string  `json:"url" validate:"omitempty,http_url,max=2048"`
+	EventTypes []string `json:"eventTypes" validate:"omitempty,min=1,dive,event_type"`
+	// Active re-enables a webhook that was disabled, which also forgives
+	// the failures that got it disabled.
+	Active *bool `json:"active"`
+}
+
+func (s *Server) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload updateWebhookPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	if payload.URL != nil {
+		if err := webhook.CheckURL(ctx, *payload.URL); err != nil {
+			render.Render(w, r, ErrInvalidRequest(err))
+			return
+		}
+	}
+
+	hook, ok := s.loadWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	arg := db.UpdateWebhookParams{
+		ID:         hook.ID,
+		Url:        hook.Url,
+		EventTypes: hook.EventTypes,
+		Active:     hook.Active,
+	}
+
+	if payload.URL != nil {
+		arg.Url = *payload.URL
+	}
+	if payload.EventTypes != nil {
+		arg.EventTypes = normalizeEventTypes(payload.EventTypes)
+	}
+	if payload.Active != nil {
+		arg.Active = *payload.Active
+	}
+
+	hook, err := s.store.UpdateWebhook(ctx, arg)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(hook))
+}
+
+func (s *Server) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	hook, ok := s.loadWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.store.DeleteWebhook(r.Context(), hook.ID); err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(nil))
+}
+
+// webhookDeliveryCursor is the state behind the opaque cursor of the
+// delivery log.
+type webhookDeliveryCursor struct {
+	BeforeID int64 `json:"beforeId"`
+}
+
+// ListWebhookDeliveries returns the delivery log of a webhook, newest first.
+func (s *Server) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, err := parseIntQuery(r, "limit", 20, 1, 100)
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	arg := db.ListWebhookDeliveriesParams{Limit: limit + 1}
+
+	if raw := r.URL.Query().Get("cursor"); raw != "" {
+		var cursor webhookDeliveryCursor
+		if err := decodeCursor(raw, &cursor); err != nil {
+			render.Render(w, r, ErrInvalidRequest(err))
+			return
+		}
+		arg.BeforeID = &cursor.BeforeID
+	}
+
+	hook, ok := s.loadWebhook(w, r)
+	if !ok {
+		return
+	}
+	arg.WebhookID = hook.ID
+
+	deliveries, err := s.store.ListWebhookDeliveries(ctx, arg)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	var nextCursor *string
+	if len(deliveries) > int(limit) {
+		deliveries = deliveries[:limit]
+
+		cursor, err := encodeCursor(webhookDeliveryCursor{BeforeID: deliveries[len(deliveries)-1].ID})
+		if err != nil {
+			render.Render(w, r, ErrInternalServer())
+			return
+		}
+		nextCursor = &cursor
+	}
+
+	render.Render(w, r, SuccessfulPaginatedResponse(deliveries, nextCursor))
+}
+
+// RedeliverWebhookDelivery queues another delivery of the same event to the
+// same webhook. The original stays in the log as it was.
+func (s *Server) RedeliverWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	deliveryID, err := parseID64Param(r, "deliveryId")
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	hook, ok := s.loadWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	delivery, err := s.store.GetWebhookDelivery(ctx, deliveryID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			render.Render(w, r, ErrNotFound(errWebhookDeliveryNotFound))
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	if delivery.WebhookID != hook.ID {
+		render.Render(w, r, ErrNotFound(errWebhookDeliveryNotFound))
+		return
+	}
+
+	if !hook.Active {
+		render.Render(w, r, ErrInvalidRequestWithCode(errWebhookDisabled, http.StatusConflict))
+		return
+	}
+
+	redelivery, err := s.store.CreateWebhookRedelivery(ctx, delivery.ID)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponseWithCode(redelivery, http.StatusAccepted))
+}
+
+// normalizeEventTypes sorts the event types a webhook subscribes to and drops
+// repeats.
+func normalizeEventTypes(eventTypes []string) []string {
+	eventTypes = slices.Clone(eventTypes)
+	slices.Sort(eventTypes)
+	return slices.Compact(eventTypes)
+}
+
+// loadWebhook fetches the webhook identified by the "webhookId" URL
+// parameter on the team identified by "id", which the caller has to be part
+// of like for every webhook endpoint.
+func (s *Server) loadWebhook(w http.ResponseWriter, r *http.Request) (db.Webhook, bool) {
+	webhookID, err := parseIDParam(r, "webhookId")
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return db.Webhook{}, false
+	}
+
+	team, ok := s.loadMemberTeam(w, r)
+	if !ok {
+		return db.Webhook{}, false
+	}
+
+	hook, err := s.store.GetWebhook(r.Context(), webhookID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			render.Render(w, r, ErrNotFound(errWebhookNotFound))
+			return db.Webhook{}, false
+		}
+		render.Render(w, r, ErrInternalServer())
+		return db.Webhook{}, false
+	}
+
+	if hook.TeamID != team.ID {
+		render.Render(w, r, ErrNotFound(errWebhookNotFound))
+		return db.Webhook{}, false
+	}
+
+	return hook, true
+}
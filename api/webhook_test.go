@@ -0,0 +1,565 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/bolusarz/task-manager/webhook"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func randomWebhook(teamID int32) db.Webhook {
+	return db.Webhook{
+		ID:         int32(util.RandomInt(1, 1000)),
+		TeamID:     teamID,
+		Url:        "https://example.com/hooks",
+		EventTypes: []string{"task.created"},
+		Secret:     util.RandomString(32),
+		Active:     true,
+	}
+}
+
+func TestCreateWebhookApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	team := randomTeam(user.ID)
+
+	otherTeam := randomTeam(user.ID + 1)
+	otherTeam.ID = team.ID
+
+	testCases := []struct {
+		name          string
+		claims        []string
+		payload       map[string]any
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK: Generated Secret",
+			claims:  []string{ClaimEditTeam},
+			payload: map[string]any{"url": "https://example.com/hooks", "eventTypes": []string{"task.updated", "task.created", "task.updated"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+				store.EXPECT().
+					CreateWebhook(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateWebhookParams) (db.Webhook, error) {
+						require.Equal(t, team.ID, arg.TeamID)
+						require.Equal(t, "https://example.com/hooks", arg.Url)
+						require.Equal(t, []string{"task.created", "task.updated"}, arg.EventTypes)
+						require.Len(t, arg.Secret, 43)
+						require.Equal(t, &user.ID, arg.CreatedBy)
+						return db.Webhook{ID: 1, TeamID: arg.TeamID, Url: arg.Url, EventTypes: arg.EventTypes, Secret: arg.Secret, Active: true}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var response struct {
+					Data map[string]any `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response.Data["secret"], 43)
+				require.Equal(t, true, response.Data["active"])
+			},
+		},
+		{
+			name:    "OK: Own Secret",
+			claims:  []string{ClaimEditTeam},
+			payload: map[string]any{"url": "http://hooks.example.org:8080/in", "eventTypes": []string{"team.member_joined"}, "secret": "0123456789abcdef"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+				store.EXPECT().
+					CreateWebhook(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateWebhookParams) (db.Webhook, error) {
+						require.Equal(t, "0123456789abcdef", arg.Secret)
+						return db.Webhook{ID: 1, Secret: arg.Secret}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"secret":"0123456789abcdef"`)
+			},
+		},
+		{
+			name:    "NotFound: Not A Team Member",
+			claims:  []string{ClaimEditTeam},
+			payload: map[string]any{"url": "https://example.com/hooks", "eventTypes": []string{"task.created"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(otherTeam, nil)
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: team.ID, UserID: user.ID})).
+					Times(1).
+					Return(db.TeamMember{}, db.ErrRecordNotFound)
+				store.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errTeamNotFound.Error())
+			},
+		},
+		{
+			name:    "BadRequest: Unknown Event Type",
+			claims:  []string{ClaimEditTeam},
+			payload: map[string]any{"url": "https://example.com/hooks", "eventTypes": []string{"task.archived"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "must be an event type")
+			},
+		},
+		{
+			name:    "BadRequest: No Event Types",
+			claims:  []string{ClaimEditTeam},
+			payload: map[string]any{"url": "https://example.com/hooks", "eventTypes": []string{}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "BadRequest: Invalid URL",
+			claims:  []string{ClaimEditTeam},
+			payload: map[string]any{"url": "ftp://example.com/hooks", "eventTypes": []string{"task.created"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "BadRequest: Private Address",
+			claims:  []string{ClaimEditTeam},
+			payload: map[string]any{"url": "http://10.0.0.5/hooks", "eventTypes": []string{"task.created"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), webhook.ErrForbiddenTarget.Error())
+			},
+		},
+		{
+			name:    "BadRequest: Metadata Endpoint",
+			claims:  []string{ClaimEditTeam},
+			payload: map[string]any{"url": "http://169.254.169.254/latest/meta-data", "eventTypes": []string{"task.created"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "BadRequest: Short Secret",
+			claims:  []string{ClaimEditTeam},
+			payload: map[string]any{"url": "https://example.com/hooks", "eventTypes": []string{"task.created"}, "secret": "short"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "Forbidden",
+			payload: map[string]any{"url": "https://example.com/hooks", "eventTypes": []string{"task.created"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, tt.claims...)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/teams/%d/webhooks", team.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetWebhookApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	team := randomTeam(user.ID)
+	hook := randomWebhook(team.ID)
+	otherTeams := randomWebhook(team.ID + 1)
+
+	// A team the user neither owns nor belongs to.
+	foreignTeam := randomTeam(user.ID + 1)
+	foreignTeam.ID = team.ID
+
+	testCases := []struct {
+		name          string
+		team          db.Team
+		webhook       db.Webhook
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			team:    team,
+			webhook: hook,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebhook(gomock.Any(), gomock.Eq(hook.ID)).Times(1).Return(hook, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), hook.Secret)
+			},
+		},
+		{
+			name:    "NotFound: Other Team",
+			team:    team,
+			webhook: otherTeams,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebhook(gomock.Any(), gomock.Eq(otherTeams.ID)).Times(1).Return(otherTeams, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:    "NotFound",
+			team:    team,
+			webhook: hook,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebhook(gomock.Any(), gomock.Any()).Times(1).Return(db.Webhook{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:    "NotFound: Not A Team Member",
+			team:    foreignTeam,
+			webhook: hook,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: team.ID, UserID: user.ID})).
+					Times(1).
+					Return(db.TeamMember{}, db.ErrRecordNotFound)
+				store.EXPECT().GetWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimEditTeam)
+			store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(tt.team, nil)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/teams/%d/webhooks/%d", team.ID, tt.webhook.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateWebhookApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	team := randomTeam(user.ID)
+	hook := randomWebhook(team.ID)
+	hook.Active = false
+	hook.ConsecutiveFailures = 5
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	stubAuthenticatedUser(store, user, ClaimEditTeam)
+	store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+	store.EXPECT().GetWebhook(gomock.Any(), gomock.Eq(hook.ID)).Times(1).Return(hook, nil)
+	store.EXPECT().
+		UpdateWebhook(gomock.Any(), gomock.Eq(db.UpdateWebhookParams{
+			Url:        hook.Url,
+			EventTypes: []string{"task.created", "task.deleted"},
+			Active:     true,
+			ID:         hook.ID,
+		})).
+		Times(1).
+		Return(db.Webhook{ID: hook.ID, Active: true}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	jsonBody, err := json.Marshal(map[string]any{"active": true, "eventTypes": []string{"task.deleted", "task.created"}})
+	require.NoError(t, err)
+
+	url := fmt.Sprintf("/api/v1/teams/%d/webhooks/%d", team.ID, hook.ID)
+	request, err := http.NewRequest(http.MethodPatch, url, bytes.NewBuffer(jsonBody))
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestListWebhookDeliveriesApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	team := randomTeam(user.ID)
+	hook := randomWebhook(team.ID)
+	deliveries := []db.WebhookDelivery{
+		{ID: 30, WebhookID: hook.ID, Status: "failed"},
+		{ID: 20, WebhookID: hook.ID, Status: "succeeded"},
+		{ID: 10, WebhookID: hook.ID, Status: "succeeded"},
+	}
+
+	cursor, err := encodeCursor(webhookDeliveryCursor{BeforeID: 40})
+	require.NoError(t, err)
+
+	type listResponse struct {
+		Data       []db.WebhookDelivery `json:"data"`
+		NextCursor *string              `json:"nextCursor"`
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK: Next Page",
+			query: "?limit=2",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+				store.EXPECT().GetWebhook(gomock.Any(), gomock.Eq(hook.ID)).Times(1).Return(hook, nil)
+				store.EXPECT().
+					ListWebhookDeliveries(gomock.Any(), gomock.Eq(db.ListWebhookDeliveriesParams{WebhookID: hook.ID, Limit: 3})).
+					Times(1).
+					Return(deliveries, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response listResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response.Data, 2)
+				require.NotNil(t, response.NextCursor)
+
+				var next webhookDeliveryCursor
+				require.NoError(t, decodeCursor(*response.NextCursor, &next))
+				require.Equal(t, int64(20), next.BeforeID)
+			},
+		},
+		{
+			name:  "OK: Cursor",
+			query: "?cursor=" + cursor,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+				store.EXPECT().GetWebhook(gomock.Any(), gomock.Eq(hook.ID)).Times(1).Return(hook, nil)
+
+				beforeID := int64(40)
+				store.EXPECT().
+					ListWebhookDeliveries(gomock.Any(), gomock.Eq(db.ListWebhookDeliveriesParams{WebhookID: hook.ID, BeforeID: &beforeID, Limit: 21})).
+					Times(1).
+					Return(deliveries, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response listResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response.Data, 3)
+				require.Nil(t, response.NextCursor)
+			},
+		},
+		{
+			name:  "BadRequest: Invalid Cursor",
+			query: "?cursor=not-a-cursor",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListWebhookDeliveries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Server Error",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+				store.EXPECT().GetWebhook(gomock.Any(), gomock.Eq(hook.ID)).Times(1).Return(hook, nil)
+				store.EXPECT().ListWebhookDeliveries(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimEditTeam)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/teams/%d/webhooks/%d/deliveries%s", team.ID, hook.ID, tt.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRedeliverWebhookDeliveryApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	team := randomTeam(user.ID)
+	hook := randomWebhook(team.ID)
+	delivery := db.WebhookDelivery{ID: 30, WebhookID: hook.ID, EventID: 7, Status: "failed"}
+
+	disabled := hook
+	disabled.Active = false
+
+	testCases := []struct {
+		name          string
+		webhook       db.Webhook
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			webhook: hook,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(delivery, nil)
+
+				redeliveryOf := delivery.ID
+				store.EXPECT().
+					CreateWebhookRedelivery(gomock.Any(), gomock.Eq(delivery.ID)).
+					Times(1).
+					Return(db.WebhookDelivery{ID: 31, WebhookID: hook.ID, EventID: 7, RedeliveryOf: &redeliveryOf, Status: "pending"}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var response struct {
+					Data db.WebhookDelivery `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, int64(31), response.Data.ID)
+				require.Equal(t, delivery.ID, *response.Data.RedeliveryOf)
+			},
+		},
+		{
+			name:    "Conflict: Disabled Webhook",
+			webhook: disabled,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(delivery, nil)
+				store.EXPECT().CreateWebhookRedelivery(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errWebhookDisabled.Error())
+			},
+		},
+		{
+			name:    "NotFound: Other Webhook",
+			webhook: hook,
+			buildStubs: func(store *mockdb.MockStore) {
+				other := delivery
+				other.WebhookID = hook.ID + 1
+				store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(other, nil)
+				store.EXPECT().CreateWebhookRedelivery(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errWebhookDeliveryNotFound.Error())
+			},
+		},
+		{
+			name:    "NotFound",
+			webhook: hook,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Any()).Times(1).Return(db.WebhookDelivery{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimEditTeam)
+			store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+			store.EXPECT().GetWebhook(gomock.Any(), gomock.Eq(hook.ID)).Times(1).Return(tt.webhook, nil)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/teams/%d/webhooks/%d/deliveries/%d/redeliver", team.ID, hook.ID, delivery.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
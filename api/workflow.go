@@ -0,0 +1,452 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/go-chi/render"
+)
+
+const defaultTaskStatusColor = "#94a3b8"
+
+var (
+	errTaskStatusNotFound      = errors.New("status not found")
+	errTaskStatusExists        = errors.New("a status with this name already exists")
+	errTaskStatusInUse         = errors.New("status is still used by tasks")
+	errStatusNotInWorkflow     = errors.New("status does not belong to the task's workflow")
+	errEmptyWorkflow           = errors.New("the workflow has no statuses")
+	errTransitionExists        = errors.New("transition already exists")
+	errTransitionNotFound      = errors.New("transition not found")
+	errTransitionNotAllowed    = errors.New("the workflow does not allow moving the task to this status")
+	errTransitionSameStatus    = errors.New("a transition needs two different statuses")
+	errTransitionOtherWorkflow = errors.New("both statuses must belong to this team")
+)
+
+// workflowResponse describes the statuses a task can be in and the moves
+// allowed between them.
+type workflowResponse struct {
+	Statuses    []db.TaskStatus           `json:"statuses"`
+	Transitions []db.TaskStatusTransition `json:"transitions"`
+}
+
+// GetDefaultWorkflow returns the workflow used by tasks that do not belong
+// to a team.
+func (s *Server) GetDefaultWorkflow(w http.ResponseWriter, r *http.Request) {
+	s.renderWorkflow(w, r, nil)
+}
+
+func (s *Server) GetTeamWorkflow(w http.ResponseWriter, r *http.Request) {
+	team, ok := s.loadMemberTeam(w, r)
+	if !ok {
+		return
+	}
+
+	s.renderWorkflow(w, r, &team.ID)
+}
+
+type createTaskStatusPayload struct {
+	Name     string `json:"name" validate:"required,max=50"`
+	Category string `json:"category" validate:"required,oneof=todo in_progress done"`
+	Position int32  `json:"position" validate:"min=0"`
+	Color    string `json:"color" validate:"omitempty,hexcolor"`
+}
+
+func (s *Server) CreateTaskStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload createTaskStatusPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	team, ok := s.loadMemberTeam(w, r)
+	if !ok {
+		return
+	}
+
+	color := payload.Color
+	if color == "" {
+		color = defaultTaskStatusColor
+	}
+
+	status, err := s.store.CreateTaskStatus(ctx, db.CreateTaskStatusParams{
+		TeamID:   &team.ID,
+		Name:     util.SanitizeInput(payload.Name),
+		Category: payload.Category,
+		Position: payload.Position,
+		Color:    color,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			render.Render(w, r, ErrInvalidRequestWithCode(errTaskStatusExists, http.StatusConflict))
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponseWithCode(status, http.StatusCreated))
+}
+
+type updateTaskStatusPayload struct {
+	Name     *string `json:"name" validate:"omitempty,min=1,max=50"`
+	Category *string `json:"category" validate:"omitempty,oneof=todo in_progress done"`
+	Position *int32  `json:"position" validate:"omitempty,min=0"`
+	Color    *string `json:"color" validate:"omitempty,hexcolor"`
+}
+
+func (s *Server) UpdateTaskStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload updateTaskStatusPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	status, ok := s.loadTeamTaskStatus(w, r)
+	if !ok {
+		return
+	}
+
+	arg := db.UpdateTaskStatusParams{
+		ID:       status.ID,
+		Name:     status.Name,
+		Category: status.Category,
+		Position: status.Position,
+		Color:    status.Color,
+	}
+
+	if payload.Name != nil {
+		arg.Name = util.SanitizeInput(*payload.Name)
+	}
+	if payload.Category != nil {
+		arg.Category = *payload.Category
+	}
+	if payload.Position != nil {
+		arg.Position = *payload.Position
+	}
+	if payload.Color != nil {
+		arg.Color = *payload.Color
+	}
+
+	status, err := s.store.UpdateTaskStatus(ctx, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			render.Render(w, r, ErrInvalidRequestWithCode(errTaskStatusExists, http.StatusConflict))
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(status))
+}
+
+// DeleteTaskStatus removes a status from the team's workflow along with its
+// transitions. Statuses that tasks are still in cannot be deleted.
+func (s *Server) DeleteTaskStatus(w http.ResponseWriter, r *http.Request) {
+	status, ok := s.loadTeamTaskStatus(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.store.DeleteTaskStatus(r.Context(), status.ID); err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			render.Render(w, r, ErrInvalidRequestWithCode(errTaskStatusInUse, http.StatusConflict))
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(nil))
+}
+
+type taskStatusTransitionPayload struct {
+	FromStatusID int32 `json:"fromStatusId" validate:"required,min=1"`
+	ToStatusID   int32 `json:"toStatusId" validate:"required,min=1"`
+}
+
+func (s *Server) AddTaskStatusTransition(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	arg, ok := s.decodeTaskStatusTransition(w, r)
+	if !ok {
+		return
+	}
+
+	transition, err := s.store.CreateTaskStatusTransition(ctx, db.CreateTaskStatusTransitionParams{
+		FromStatusID: arg.FromStatusID,
+		ToStatusID:   arg.ToStatusID,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			render.Render(w, r, ErrInvalidRequestWithCode(errTransitionExists, http.StatusConflict))
+			return
+		}
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponseWithCode(transition, http.StatusCreated))
+}
+
+func (s *Server) RemoveTaskStatusTransition(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	arg, ok := s.decodeTaskStatusTransition(w, r)
+	if !ok {
+		return
+	}
+
+	deleted, err := s.store.DeleteTaskStatusTransition(ctx, db.DeleteTaskStatusTransitionParams{
+		FromStatusID: arg.FromStatusID,
+		ToStatusID:   arg.ToStatusID,
+	})
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	if deleted == 0 {
+		render.Render(w, r, ErrNotFound(errTransitionNotFound))
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(nil))
+}
+
+type transitionTaskPayload struct {
+	StatusID int32 `json:"statusId" validate:"required,min=1"`
+}
+
+// TransitionTask moves a task to another status. The move has to be one of
+// the transitions defined by the workflow of the task's team, and a task
+// cannot move to a done status while tasks blocking it are still open.
+func (s *Server) TransitionTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var payload transitionTaskPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return
+	}
+
+	task, ok := s.loadVisibleTask(w, r)
+	if !ok {
+		return
+	}
+
+	// The transition is checked against the status the task is in when the
+	// update runs, so two concurrent moves cannot skip a step, and a move to
+	// a done status against the blockers open at that time.
+	task, err := s.store.TransitionTask(ctx, db.TransitionTaskParams{
+		ToStatusID: payload.StatusID,
+		ID:         task.ID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			render.Render(w, r, ErrInvalidRequestWithCode(errTransitionNotAllowed, http.StatusConflict))
+			return
+		}
+
+		var blocked *db.TaskBlockedError
+		if errors.As(err, &blocked) {
+			visible, err := s.visibleTasks(ctx, principalFromContext(ctx), blocked.Blockers)
+			if err != nil {
+				render.Render(w, r, ErrInternalServer())
+				return
+			}
+			render.Render(w, r, ErrInvalidRequestWithCode(openBlockersError(visible), http.StatusConflict))
+			return
+		}
+
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	// Finishing an occurrence of a recurring task brings the next one
+	// forward. The transition stands even when that fails; the generator
+	// still creates the next occurrence when it is due.
+	if err := s.recurrences.TaskTransitioned(ctx, task); err != nil {
+		log.Printf("unable to materialize the next occurrence after task %d: %v", task.ID, err)
+	}
+
+	render.Render(w, r, SuccessfulResponse(task))
+}
+
+func (s *Server) renderWorkflow(w http.ResponseWriter, r *http.Request, teamID *int32) {
+	ctx := r.Context()
+
+	statuses, err := s.store.ListTaskStatuses(ctx, teamID)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	transitions, err := s.store.ListTaskStatusTransitions(ctx, teamID)
+	if err != nil {
+		render.Render(w, r, ErrInternalServer())
+		return
+	}
+
+	render.Render(w, r, SuccessfulResponse(workflowResponse{
+		Statuses:    statuses,
+		Transitions: transitions,
+	}))
+}
+
+// resolveTaskStatus returns the status a new task should start in: the
+// requested one when it belongs to the task's workflow, otherwise the first
+// status of that workflow. It writes the error response itself when it cannot.
+func (s *Server) resolveTaskStatus(w http.ResponseWriter, r *http.Request, teamID, statusID *int32) (db.TaskStatus, bool) {
+	ctx := r.Context()
+
+	if statusID == nil {
+		status, err := s.store.GetInitialTaskStatus(ctx, teamID)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				render.Render(w, r, ErrInvalidRequestWithCode(errEmptyWorkflow, http.StatusConflict))
+				return db.TaskStatus{}, false
+			}
+			render.Render(w, r, ErrInternalServer())
+			return db.TaskStatus{}, false
+		}
+		return status, true
+	}
+
+	status, err := s.store.GetTaskStatus(ctx, *statusID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			render.Render(w, r, ErrInvalidRequest(errStatusNotInWorkflow))
+			return db.TaskStatus{}, false
+		}
+		render.Render(w, r, ErrInternalServer())
+		return db.TaskStatus{}, false
+	}
+
+	if !sameTeam(status.TeamID, teamID) {
+		render.Render(w, r, ErrInvalidRequest(errStatusNotInWorkflow))
+		return db.TaskStatus{}, false
+	}
+
+	return status, true
+}
+
+// loadTeamTaskStatus fetches the status identified by the "statusId" URL
+// parameter, making sure it belongs to the team in the "id" parameter. It
+// writes the error response itself when it cannot.
+func (s *Server) loadTeamTaskStatus(w http.ResponseWriter, r *http.Request) (db.TaskStatus, bool) {
+	statusID, err := parseIDParam(r, "statusId")
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return db.TaskStatus{}, false
+	}
+
+	team, ok := s.loadMemberTeam(w, r)
+	if !ok {
+		return db.TaskStatus{}, false
+	}
+
+	status, err := s.store.GetTaskStatus(r.Context(), statusID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			render.Render(w, r, ErrNotFound(errTaskStatusNotFound))
+			return db.TaskStatus{}, false
+		}
+		render.Render(w, r, ErrInternalServer())
+		return db.TaskStatus{}, false
+	}
+
+	if !sameTeam(status.TeamID, &team.ID) {
+		render.Render(w, r, ErrNotFound(errTaskStatusNotFound))
+		return db.TaskStatus{}, false
+	}
+
+	return status, true
+}
+
+// decodeTaskStatusTransition reads a transition from the request body and
+// checks that both of its statuses belong to the team in the "id" URL
+// parameter, writing the error response itself when they do not.
+func (s *Server) decodeTaskStatusTransition(w http.ResponseWriter, r *http.Request) (taskStatusTransitionPayload, bool) {
+	ctx := r.Context()
+
+	var payload taskStatusTransitionPayload
+
+	if err := render.DecodeJSON(r.Body, &payload); err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return payload, false
+	}
+
+	if err := s.validate.Struct(payload); err != nil {
+		fieldErrors := TransformValidationErrors(err)
+		render.Render(w, r, ErrInvalidRequest(fmt.Errorf("%s", fieldErrors[0])))
+		return payload, false
+	}
+
+	if payload.FromStatusID == payload.ToStatusID {
+		render.Render(w, r, ErrInvalidRequest(errTransitionSameStatus))
+		return payload, false
+	}
+
+	team, ok := s.loadMemberTeam(w, r)
+	if !ok {
+		return payload, false
+	}
+
+	for _, statusID := range []int32{payload.FromStatusID, payload.ToStatusID} {
+		status, err := s.store.GetTaskStatus(ctx, statusID)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				render.Render(w, r, ErrNotFound(errTaskStatusNotFound))
+				return payload, false
+			}
+			render.Render(w, r, ErrInternalServer())
+			return payload, false
+		}
+
+		if !sameTeam(status.TeamID, &team.ID) {
+			render.Render(w, r, ErrInvalidRequest(errTransitionOtherWorkflow))
+			return payload, false
+		}
+	}
+
+	return payload, true
+}
+
+// sameTeam reports whether two optional team IDs refer to the same team,
+// treating two missing IDs as equal.
+func sameTeam(a, b *int32) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
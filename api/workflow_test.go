@@ -0,0 +1,557 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/util"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func randomTaskStatus(teamID *int32) db.TaskStatus {
+	return db.TaskStatus{
+		ID:       int32(util.RandomInt(1, 1000)),
+		TeamID:   teamID,
+		Name:     util.RandomString(8),
+		Category: "in_progress",
+		Position: int32(util.RandomInt(0, 10)),
+		Color:    defaultTaskStatusColor,
+	}
+}
+
+func TestGetTeamWorkflowApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	team := randomTeam(user.ID)
+	statuses := []db.TaskStatus{randomTaskStatus(&team.ID), randomTaskStatus(&team.ID)}
+	transitions := []db.TaskStatusTransition{{FromStatusID: statuses[0].ID, ToStatusID: statuses[1].ID}}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	stubAuthenticatedUser(store, user)
+	store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+	store.EXPECT().ListTaskStatuses(gomock.Any(), gomock.Eq(&team.ID)).Times(1).Return(statuses, nil)
+	store.EXPECT().ListTaskStatusTransitions(gomock.Any(), gomock.Eq(&team.ID)).Times(1).Return(transitions, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	url := fmt.Sprintf("/api/v1/teams/%d/workflow", team.ID)
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Data workflowResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response.Data.Statuses, 2)
+	require.Equal(t, transitions, response.Data.Transitions)
+}
+
+func TestCreateTaskStatusApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	team := randomTeam(user.ID)
+
+	otherTeam := randomTeam(user.ID + 1)
+	otherTeam.ID = team.ID
+
+	testCases := []struct {
+		name          string
+		payload       map[string]any
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			payload: map[string]any{"name": "Blocked", "category": "in_progress", "position": 3},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+
+				arg := db.CreateTaskStatusParams{
+					TeamID:   &team.ID,
+					Name:     "Blocked",
+					Category: "in_progress",
+					Position: 3,
+					Color:    defaultTaskStatusColor,
+				}
+				store.EXPECT().
+					CreateTaskStatus(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.TaskStatus{ID: 1, TeamID: arg.TeamID, Name: arg.Name}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:    "Conflict: Duplicate Name",
+			payload: map[string]any{"name": "Review", "category": "in_progress", "color": "#a855f7"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+				store.EXPECT().
+					CreateTaskStatus(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TaskStatus{}, &pgconn.PgError{Code: db.UniqueViolation})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errTaskStatusExists.Error())
+			},
+		},
+		{
+			name:    "NotFound: Not A Team Member",
+			payload: map[string]any{"name": "Blocked", "category": "in_progress"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(otherTeam, nil)
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: team.ID, UserID: user.ID})).
+					Times(1).
+					Return(db.TeamMember{}, db.ErrRecordNotFound)
+				store.EXPECT().CreateTaskStatus(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errTeamNotFound.Error())
+			},
+		},
+		{
+			name:    "BadRequest: Unknown Category",
+			payload: map[string]any{"name": "Review", "category": "waiting"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateTaskStatus(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "BadRequest: Invalid Color",
+			payload: map[string]any{"name": "Review", "category": "todo", "color": "purple"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateTaskStatus(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimEditTeam)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/teams/%d/workflow/statuses", team.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteTaskStatusApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	team := randomTeam(user.ID)
+	status := randomTaskStatus(&team.ID)
+
+	otherTeamID := team.ID + 1
+	foreignStatus := randomTaskStatus(&otherTeamID)
+
+	testCases := []struct {
+		name          string
+		status        db.TaskStatus
+		buildStubs    func(store *mockdb.MockStore, status db.TaskStatus)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			status: status,
+			buildStubs: func(store *mockdb.MockStore, status db.TaskStatus) {
+				store.EXPECT().GetTaskStatus(gomock.Any(), gomock.Eq(status.ID)).Times(1).Return(status, nil)
+				store.EXPECT().DeleteTaskStatus(gomock.Any(), gomock.Eq(status.ID)).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Conflict: In Use",
+			status: status,
+			buildStubs: func(store *mockdb.MockStore, status db.TaskStatus) {
+				store.EXPECT().GetTaskStatus(gomock.Any(), gomock.Eq(status.ID)).Times(1).Return(status, nil)
+				store.EXPECT().
+					DeleteTaskStatus(gomock.Any(), gomock.Eq(status.ID)).
+					Times(1).
+					Return(&pgconn.PgError{Code: db.ForeignKeyViolation})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errTaskStatusInUse.Error())
+			},
+		},
+		{
+			name:   "NotFound: Another Team's Status",
+			status: foreignStatus,
+			buildStubs: func(store *mockdb.MockStore, status db.TaskStatus) {
+				store.EXPECT().GetTaskStatus(gomock.Any(), gomock.Eq(status.ID)).Times(1).Return(status, nil)
+				store.EXPECT().DeleteTaskStatus(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimEditTeam)
+			store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+			tt.buildStubs(store, tt.status)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/teams/%d/workflow/statuses/%d", team.ID, tt.status.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAddTaskStatusTransitionApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	team := randomTeam(user.ID)
+
+	from := randomTaskStatus(&team.ID)
+	to := randomTaskStatus(&team.ID)
+	to.ID = from.ID + 1
+
+	otherTeamID := team.ID + 1
+	foreign := randomTaskStatus(&otherTeamID)
+	foreign.ID = from.ID + 2
+
+	testCases := []struct {
+		name          string
+		payload       map[string]any
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			payload: map[string]any{"fromStatusId": from.ID, "toStatusId": to.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+				store.EXPECT().GetTaskStatus(gomock.Any(), gomock.Eq(from.ID)).Times(1).Return(from, nil)
+				store.EXPECT().GetTaskStatus(gomock.Any(), gomock.Eq(to.ID)).Times(1).Return(to, nil)
+
+				arg := db.CreateTaskStatusTransitionParams{FromStatusID: from.ID, ToStatusID: to.ID}
+				store.EXPECT().
+					CreateTaskStatusTransition(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.TaskStatusTransition(arg), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:    "Conflict: Already Exists",
+			payload: map[string]any{"fromStatusId": from.ID, "toStatusId": to.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+				store.EXPECT().GetTaskStatus(gomock.Any(), gomock.Eq(from.ID)).Times(1).Return(from, nil)
+				store.EXPECT().GetTaskStatus(gomock.Any(), gomock.Eq(to.ID)).Times(1).Return(to, nil)
+				store.EXPECT().
+					CreateTaskStatusTransition(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TaskStatusTransition{}, &pgconn.PgError{Code: db.UniqueViolation})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:    "BadRequest: Status From Another Team",
+			payload: map[string]any{"fromStatusId": from.ID, "toStatusId": foreign.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTeam(gomock.Any(), gomock.Eq(team.ID)).Times(1).Return(team, nil)
+				store.EXPECT().GetTaskStatus(gomock.Any(), gomock.Eq(from.ID)).Times(1).Return(from, nil)
+				store.EXPECT().GetTaskStatus(gomock.Any(), gomock.Eq(foreign.ID)).Times(1).Return(foreign, nil)
+				store.EXPECT().CreateTaskStatusTransition(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errTransitionOtherWorkflow.Error())
+			},
+		},
+		{
+			name:    "BadRequest: Same Status",
+			payload: map[string]any{"fromStatusId": from.ID, "toStatusId": from.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTaskStatus(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateTaskStatusTransition(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errTransitionSameStatus.Error())
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimEditTeam)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/teams/%d/workflow/transitions", team.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestTransitionTaskApi(t *testing.T) {
+	user := randomAuthenticatedUser(t)
+	task := randomTask()
+	task.CreatedBy = &user.ID
+	targetID := task.StatusID + 1
+	otherID := user.ID + 1
+
+	teamID := int32(util.RandomInt(1, 1000))
+	teamTask := task
+	teamTask.TeamID = &teamID
+
+	testCases := []struct {
+		name          string
+		payload       map[string]any
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			payload: map[string]any{"statusId": targetID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().
+					TransitionTask(gomock.Any(), gomock.Eq(db.TransitionTaskParams{ToStatusID: targetID, ID: task.ID})).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.TransitionTaskParams) (db.Task, error) {
+						moved := task
+						moved.StatusID = arg.ToStatusID
+						return moved, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Data db.Task `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, targetID, response.Data.StatusID)
+			},
+		},
+		{
+			name:    "OK: Brings Next Occurrence Forward",
+			payload: map[string]any{"statusId": targetID},
+			buildStubs: func(store *mockdb.MockStore) {
+				templateID := int32(util.RandomInt(1, 1000))
+				dueAt := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+				template := db.TaskTemplate{
+					ID:        templateID,
+					Rrule:     "FREQ=DAILY",
+					Timezone:  "UTC",
+					StartsAt:  dueAt.AddDate(0, 0, -7),
+					NextDueAt: &dueAt,
+				}
+				recurring := task
+				recurring.TemplateID = &templateID
+
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(recurring, nil)
+				store.EXPECT().TransitionTask(gomock.Any(), gomock.Any()).Times(1).Return(recurring, nil)
+				store.EXPECT().CountOpenTemplateTasks(gomock.Any(), gomock.Eq(&templateID)).Times(1).Return(int64(0), nil)
+				store.EXPECT().GetTaskTemplate(gomock.Any(), gomock.Eq(templateID)).Times(1).Return(template, nil)
+
+				next := dueAt.AddDate(0, 0, 1)
+				store.EXPECT().
+					MaterializeTaskOccurrence(gomock.Any(), gomock.Eq(db.MaterializeTaskOccurrenceParams{Template: template, NextDueAt: &next})).
+					Times(1).
+					Return(db.TaskOccurrenceResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:    "Conflict: Transition Not Allowed",
+			payload: map[string]any{"statusId": targetID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().
+					TransitionTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Task{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errTransitionNotAllowed.Error())
+			},
+		},
+		{
+			name:    "NotFound",
+			payload: map[string]any{"statusId": targetID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(db.Task{}, db.ErrRecordNotFound)
+				store.EXPECT().TransitionTask(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:    "NotFound: Not A Team Member",
+			payload: map[string]any{"statusId": targetID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(teamTask, nil)
+				store.EXPECT().
+					GetTeamMember(gomock.Any(), gomock.Eq(db.GetTeamMemberParams{TeamID: teamID, UserID: user.ID})).
+					Times(1).
+					Return(db.TeamMember{}, db.ErrRecordNotFound)
+				store.EXPECT().TransitionTask(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errTaskNotFound.Error())
+			},
+		},
+		{
+			name:    "BadRequest: Missing Status",
+			payload: map[string]any{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().TransitionTask(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "Internal Server Error",
+			payload: map[string]any{"statusId": targetID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().TransitionTask(gomock.Any(), gomock.Any()).Times(1).Return(db.Task{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:    "Conflict: Open Blockers",
+			payload: map[string]any{"statusId": targetID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().
+					TransitionTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Task{}, &db.TaskBlockedError{Blockers: []db.Task{{ID: 41, CreatedBy: &user.ID}, {ID: 42, CreatedBy: &user.ID}}})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errTaskBlocked.Error()+": 41, 42")
+			},
+		},
+		{
+			name:    "Conflict: Hidden Blockers",
+			payload: map[string]any{"statusId": targetID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTask(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(task, nil)
+				store.EXPECT().
+					TransitionTask(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Task{}, &db.TaskBlockedError{Blockers: []db.Task{{ID: 41, CreatedBy: &user.ID}, {ID: 42, CreatedBy: &otherID}}})
+				store.EXPECT().ListTaskAssignees(gomock.Any(), gomock.Eq(int32(42))).Times(1).Return([]db.ListTaskAssigneesRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errTaskBlocked.Error()+": 41\"")
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			stubAuthenticatedUser(store, user, ClaimEditTask)
+			tt.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tt.payload)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/tasks/%d/transition", task.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(t, recorder)
+		})
+	}
+}
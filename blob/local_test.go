@@ -0,0 +1,40 @@
+package blob
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+	ctx := context.Background()
+
+	err := store.Put(ctx, "tasks/1/report.txt", strings.NewReader("hello"), 5, "text/plain")
+	require.NoError(t, err)
+
+	body, err := store.Get(ctx, "tasks/1/report.txt")
+	require.NoError(t, err)
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	require.Equal(t, "hello", string(data))
+
+	require.NoError(t, store.Delete(ctx, "tasks/1/report.txt"))
+	require.NoError(t, store.Delete(ctx, "tasks/1/report.txt"))
+
+	_, err = store.Get(ctx, "tasks/1/report.txt")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLocalStoreRejectsEscapingKeys(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+
+	for _, key := range []string{"../secret", "/etc/passwd", "tasks/../../x", ""} {
+		err := store.Put(context.Background(), key, strings.NewReader("x"), 1, "text/plain")
+		require.ErrorIs(t, err, errInvalidKey, key)
+	}
+}
@@ -0,0 +1,59 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/bolusarz/task-manager/util"
+)
+
+const defaultLocalDir = "data/blobs"
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	errInvalidKey = errors.New("invalid blob key")
+)
+
+// BlobStore keeps file contents outside the database. Keys are slash
+// separated paths such as "tasks/12/3f2c...".
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get returns ErrNotFound when nothing is stored under key.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete succeeds when nothing is stored under key.
+	Delete(ctx context.Context, key string) error
+}
+
+// NewBlobStore returns the BlobStore selected by config.BlobStore: "s3"
+// talks to any S3 compatible service, while "local" (the default) keeps
+// files under config.BlobLocalDir.
+func NewBlobStore(config util.Config) (BlobStore, error) {
+	switch config.BlobStore {
+	case "s3":
+		return NewS3Store(
+			config.S3Endpoint,
+			config.S3Region,
+			config.S3Bucket,
+			config.S3AccessKeyID,
+			config.S3SecretAccessKey,
+		)
+	case "", "local":
+		dir := config.BlobLocalDir
+		if dir == "" {
+			dir = defaultLocalDir
+		}
+		return NewLocalStore(dir), nil
+	default:
+		return nil, fmt.Errorf("unknown blob store %q", config.BlobStore)
+	}
+}
+
+func validateKey(key string) error {
+	if !fs.ValidPath(key) || key == "." {
+		return errInvalidKey
+	}
+	return nil
+}
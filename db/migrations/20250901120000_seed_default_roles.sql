@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO roles (name, description) VALUES
+('admin', 'Full access to every part of the system'),
+('member', 'Default role granted to every registered user');
+
+INSERT INTO role_claims (role_id, claim_id)
+SELECT r.id, c.id FROM roles r CROSS JOIN claims c
+WHERE r.name = 'admin';
+
+INSERT INTO role_claims (role_id, claim_id)
+SELECT r.id, c.id FROM roles r JOIN claims c ON c.name IN (
+    'can_create_task',
+    'can_edit_task',
+    'can_assign_task',
+    'can_create_team'
+)
+WHERE r.name = 'member';
+
+INSERT INTO user_roles (user_id, role_id)
+SELECT u.id, r.id FROM users u CROSS JOIN roles r
+WHERE r.name = 'member'
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM roles WHERE name IN ('admin', 'member');
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE tasks ADD COLUMN team_id INT REFERENCES teams(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_tasks_team_id ON tasks(team_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_tasks_team_id;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS team_id;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Lets the holder see every task and team, not only the ones they can reach
+-- through their teams.
+INSERT INTO claims (name) VALUES ('can_view_all_records');
+
+INSERT INTO role_claims (role_id, claim_id)
+SELECT r.id, c.id FROM roles r JOIN claims c ON c.name = 'can_view_all_records'
+WHERE r.name = 'admin';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM claims WHERE name = 'can_view_all_records';
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS sessions (
+    id UUID PRIMARY KEY,
+    family_id UUID NOT NULL,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_agent VARCHAR(255) NOT NULL,
+    client_ip VARCHAR(45) NOT NULL,
+    is_revoked BOOLEAN NOT NULL DEFAULT FALSE,
+    rotated_at TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_sessions_family_id ON sessions(family_id);
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS sessions;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE tasks
+    DROP CONSTRAINT IF EXISTS tasks_created_by_fkey,
+    ADD CONSTRAINT tasks_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE tasks
+    DROP CONSTRAINT IF EXISTS tasks_assigned_to_fkey,
+    ADD CONSTRAINT tasks_assigned_to_fkey FOREIGN KEY (assigned_to) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE teams
+    DROP CONSTRAINT IF EXISTS teams_created_by_fkey,
+    ADD CONSTRAINT teams_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE teams
+    DROP CONSTRAINT IF EXISTS teams_created_by_fkey,
+    ADD CONSTRAINT teams_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id);
+
+ALTER TABLE tasks
+    DROP CONSTRAINT IF EXISTS tasks_assigned_to_fkey,
+    ADD CONSTRAINT tasks_assigned_to_fkey FOREIGN KEY (assigned_to) REFERENCES users(id);
+
+ALTER TABLE tasks
+    DROP CONSTRAINT IF EXISTS tasks_created_by_fkey,
+    ADD CONSTRAINT tasks_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id);
+-- +goose StatementEnd
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO user_tasks (user_id, task_id, assigned_at)
+SELECT assigned_to, id, COALESCE(assigned_at, created_at, CURRENT_TIMESTAMP)
+FROM tasks
+WHERE assigned_to IS NOT NULL
+ON CONFLICT (user_id, task_id) DO NOTHING;
+
+UPDATE user_tasks SET assigned_at = CURRENT_TIMESTAMP WHERE assigned_at IS NULL;
+
+ALTER TABLE user_tasks ALTER COLUMN assigned_at SET NOT NULL;
+
+CREATE INDEX idx_user_tasks_task_id ON user_tasks(task_id);
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS assigned_to,
+    DROP COLUMN IF EXISTS assigned_at;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE tasks
+    ADD COLUMN assigned_to INT REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN assigned_at TIMESTAMP;
+
+-- Only one assignee fits in the old columns, so keep the earliest.
+UPDATE tasks t
+SET assigned_to = first.user_id,
+    assigned_at = first.assigned_at
+FROM (
+    SELECT DISTINCT ON (task_id) task_id, user_id, assigned_at
+    FROM user_tasks
+    ORDER BY task_id, assigned_at, user_id
+) first
+WHERE first.task_id = t.id;
+
+DROP INDEX IF EXISTS idx_user_tasks_task_id;
+
+ALTER TABLE user_tasks ALTER COLUMN assigned_at DROP NOT NULL;
+-- +goose StatementEnd
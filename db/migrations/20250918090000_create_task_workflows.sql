@@ -0,0 +1,77 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS task_statuses (
+    id SERIAL PRIMARY KEY,
+    team_id INT REFERENCES teams(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    category VARCHAR(20) NOT NULL CHECK (category IN ('todo', 'in_progress', 'done')),
+    position INT NOT NULL DEFAULT 0,
+    color VARCHAR(7) NOT NULL DEFAULT '#94a3b8',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Statuses without a team make up the default workflow. Personal tasks use it
+-- and every new team starts with a copy of it.
+CREATE UNIQUE INDEX idx_task_statuses_team_name ON task_statuses (COALESCE(team_id, 0), lower(name));
+
+CREATE TABLE IF NOT EXISTS task_status_transitions (
+    from_status_id INT REFERENCES task_statuses(id) ON DELETE CASCADE,
+    to_status_id INT REFERENCES task_statuses(id) ON DELETE CASCADE,
+    PRIMARY KEY (from_status_id, to_status_id),
+    CHECK (from_status_id <> to_status_id)
+);
+
+INSERT INTO task_statuses (name, category, position, color) VALUES
+    ('To Do', 'todo', 0, '#94a3b8'),
+    ('In Progress', 'in_progress', 1, '#3b82f6'),
+    ('Done', 'done', 2, '#22c55e');
+
+INSERT INTO task_status_transitions (from_status_id, to_status_id)
+SELECT f.id, t.id
+FROM task_statuses f
+JOIN task_statuses t ON t.team_id IS NULL AND t.id <> f.id
+WHERE f.team_id IS NULL;
+
+INSERT INTO task_statuses (team_id, name, category, position, color)
+SELECT tm.id, s.name, s.category, s.position, s.color
+FROM teams tm
+CROSS JOIN task_statuses s
+WHERE s.team_id IS NULL;
+
+INSERT INTO task_status_transitions (from_status_id, to_status_id)
+SELECT nf.id, nt.id
+FROM task_status_transitions tr
+JOIN task_statuses f ON f.id = tr.from_status_id AND f.team_id IS NULL
+JOIN task_statuses t ON t.id = tr.to_status_id
+JOIN task_statuses nf ON nf.team_id IS NOT NULL AND nf.name = f.name
+JOIN task_statuses nt ON nt.team_id = nf.team_id AND nt.name = t.name;
+
+ALTER TABLE tasks ADD COLUMN status_id INT REFERENCES task_statuses(id);
+
+-- The old status codes match the positions of the default statuses.
+UPDATE tasks SET status_id = s.id
+FROM task_statuses s
+WHERE s.team_id IS NOT DISTINCT FROM tasks.team_id AND s.position = tasks.status;
+
+ALTER TABLE tasks ALTER COLUMN status_id SET NOT NULL;
+
+CREATE INDEX idx_tasks_status_id ON tasks(status_id);
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE tasks ADD COLUMN status SMALLINT DEFAULT 0 CHECK (status IN (0, 1, 2));
+
+UPDATE tasks SET status = CASE s.category WHEN 'in_progress' THEN 1 WHEN 'done' THEN 2 ELSE 0 END
+FROM task_statuses s
+WHERE s.id = tasks.status_id;
+
+DROP INDEX IF EXISTS idx_tasks_status_id;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS status_id;
+
+DROP TABLE IF EXISTS task_status_transitions;
+DROP TABLE IF EXISTS task_statuses;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Each sortable key is paired with the id tie-breaker so keyset pages can be
+-- read straight off the index in either direction.
+CREATE INDEX idx_tasks_created_at_id ON tasks(created_at, id);
+CREATE INDEX idx_tasks_due_date_id ON tasks((COALESCE(due_date, '9999-12-31 00:00:00'::timestamp)), id);
+CREATE INDEX idx_tasks_title_id ON tasks(title, id);
+
+CREATE INDEX idx_tasks_created_by ON tasks(created_by);
+CREATE INDEX idx_tasks_team_status ON tasks(team_id, status_id);
+CREATE INDEX idx_tasks_due_date ON tasks(due_date) WHERE due_date IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_tasks_due_date;
+DROP INDEX IF EXISTS idx_tasks_team_status;
+DROP INDEX IF EXISTS idx_tasks_created_by;
+DROP INDEX IF EXISTS idx_tasks_title_id;
+DROP INDEX IF EXISTS idx_tasks_due_date_id;
+DROP INDEX IF EXISTS idx_tasks_created_at_id;
+-- +goose StatementEnd
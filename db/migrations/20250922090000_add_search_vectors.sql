@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE tasks ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'B')
+) STORED;
+
+ALTER TABLE teams ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'B')
+) STORED;
+
+CREATE INDEX idx_tasks_search_vector ON tasks USING GIN (search_vector);
+CREATE INDEX idx_teams_search_vector ON teams USING GIN (search_vector);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_teams_search_vector;
+DROP INDEX IF EXISTS idx_tasks_search_vector;
+
+ALTER TABLE teams DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE tasks DROP COLUMN IF EXISTS search_vector;
+-- +goose StatementEnd
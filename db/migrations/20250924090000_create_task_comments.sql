@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS task_comments (
+    id SERIAL PRIMARY KEY,
+    task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    author_id INT REFERENCES users(id) ON DELETE SET NULL,
+    body TEXT NOT NULL,
+    body_html TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    edited_at TIMESTAMP,
+    search_vector tsvector GENERATED ALWAYS AS (to_tsvector('english', body)) STORED
+);
+
+CREATE INDEX idx_task_comments_task_id ON task_comments(task_id, created_at, id);
+CREATE INDEX idx_task_comments_search_vector ON task_comments USING GIN (search_vector);
+
+-- Every edit keeps the body it replaced.
+CREATE TABLE IF NOT EXISTS task_comment_revisions (
+    id SERIAL PRIMARY KEY,
+    comment_id INT NOT NULL REFERENCES task_comments(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    edited_by INT REFERENCES users(id) ON DELETE SET NULL,
+    edited_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_task_comment_revisions_comment_id ON task_comment_revisions(comment_id);
+
+CREATE TABLE IF NOT EXISTS task_comment_mentions (
+    comment_id INT NOT NULL REFERENCES task_comments(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (comment_id, user_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS task_comment_mentions;
+DROP TABLE IF EXISTS task_comment_revisions;
+DROP TABLE IF EXISTS task_comments;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Subtasks outlive a deleted parent and become standalone tasks.
+ALTER TABLE tasks ADD COLUMN parent_id INT REFERENCES tasks(id) ON DELETE SET NULL CHECK (parent_id <> id);
+
+CREATE INDEX idx_tasks_parent_id ON tasks(parent_id);
+
+CREATE TABLE IF NOT EXISTS task_dependencies (
+    blocker_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    blocked_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (blocker_id, blocked_id),
+    CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX idx_task_dependencies_blocked_id ON task_dependencies(blocked_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS task_dependencies;
+
+DROP INDEX IF EXISTS idx_tasks_parent_id;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS parent_id;
+-- +goose StatementEnd
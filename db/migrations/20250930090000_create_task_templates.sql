@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A template holds an RFC 5545 RRULE and materializes one task per
+-- occurrence. starts_at and next_due_at are UTC like tasks.due_date; the rule
+-- is expanded in timezone so occurrences keep their wall clock time across
+-- DST changes. next_due_at is the first occurrence without a task yet, and
+-- NULL once the rule has run out.
+CREATE TABLE IF NOT EXISTS task_templates (
+    id SERIAL PRIMARY KEY,
+    title VARCHAR(100) NOT NULL,
+    description TEXT,
+    created_by INT REFERENCES users(id) ON DELETE SET NULL,
+    team_id INT REFERENCES teams(id) ON DELETE CASCADE,
+    rrule TEXT NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    starts_at TIMESTAMP NOT NULL,
+    next_due_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_task_templates_next_due_at ON task_templates(next_due_at) WHERE next_due_at IS NOT NULL;
+CREATE INDEX idx_task_templates_team_id ON task_templates(team_id);
+CREATE INDEX idx_task_templates_created_by ON task_templates(created_by);
+
+-- Occurrences outlive a deleted template as ordinary tasks.
+ALTER TABLE tasks ADD COLUMN template_id INT REFERENCES task_templates(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_tasks_template_id ON tasks(template_id) WHERE template_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_tasks_template_id;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS template_id;
+
+DROP TABLE IF EXISTS task_templates;
+-- +goose StatementEnd
@@ -0,0 +1,122 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every timestamp so far was written as a UTC wall clock time, so existing
+-- values are read as UTC while the columns become TIMESTAMPTZ.
+
+-- The due date sort key has to be rebuilt with a TIMESTAMPTZ sentinel, which
+-- needs an explicit offset to keep the index expression immutable.
+DROP INDEX IF EXISTS idx_tasks_due_date_id;
+
+ALTER TABLE users
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN password_changed_at TYPE TIMESTAMPTZ USING password_changed_at AT TIME ZONE 'UTC',
+    ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+ALTER TABLE tasks
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN due_date TYPE TIMESTAMPTZ USING due_date AT TIME ZONE 'UTC';
+
+ALTER TABLE teams
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC';
+
+ALTER TABLE team_members
+    ALTER COLUMN joined_at TYPE TIMESTAMPTZ USING joined_at AT TIME ZONE 'UTC';
+
+ALTER TABLE user_roles
+    ALTER COLUMN assigned_at TYPE TIMESTAMPTZ USING assigned_at AT TIME ZONE 'UTC';
+
+ALTER TABLE user_tasks
+    ALTER COLUMN assigned_at TYPE TIMESTAMPTZ USING assigned_at AT TIME ZONE 'UTC';
+
+ALTER TABLE sessions
+    ALTER COLUMN rotated_at TYPE TIMESTAMPTZ USING rotated_at AT TIME ZONE 'UTC',
+    ALTER COLUMN expires_at TYPE TIMESTAMPTZ USING expires_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC';
+
+ALTER TABLE user_tokens
+    ALTER COLUMN expires_at TYPE TIMESTAMPTZ USING expires_at AT TIME ZONE 'UTC',
+    ALTER COLUMN used_at TYPE TIMESTAMPTZ USING used_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC';
+
+ALTER TABLE task_statuses
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC';
+
+ALTER TABLE task_comments
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN edited_at TYPE TIMESTAMPTZ USING edited_at AT TIME ZONE 'UTC';
+
+ALTER TABLE task_comment_revisions
+    ALTER COLUMN edited_at TYPE TIMESTAMPTZ USING edited_at AT TIME ZONE 'UTC';
+
+ALTER TABLE task_attachments
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC';
+
+ALTER TABLE task_dependencies
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC';
+
+ALTER TABLE task_templates
+    ALTER COLUMN starts_at TYPE TIMESTAMPTZ USING starts_at AT TIME ZONE 'UTC',
+    ALTER COLUMN next_due_at TYPE TIMESTAMPTZ USING next_due_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC';
+
+CREATE INDEX idx_tasks_due_date_id ON tasks((COALESCE(due_date, '9999-12-31 00:00:00+00'::timestamptz)), id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_tasks_due_date_id;
+
+ALTER TABLE task_templates
+    ALTER COLUMN starts_at TYPE TIMESTAMP USING starts_at AT TIME ZONE 'UTC',
+    ALTER COLUMN next_due_at TYPE TIMESTAMP USING next_due_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC';
+
+ALTER TABLE task_dependencies
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC';
+
+ALTER TABLE task_attachments
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC';
+
+ALTER TABLE task_comment_revisions
+    ALTER COLUMN edited_at TYPE TIMESTAMP USING edited_at AT TIME ZONE 'UTC';
+
+ALTER TABLE task_comments
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN edited_at TYPE TIMESTAMP USING edited_at AT TIME ZONE 'UTC';
+
+ALTER TABLE task_statuses
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC';
+
+ALTER TABLE user_tokens
+    ALTER COLUMN expires_at TYPE TIMESTAMP USING expires_at AT TIME ZONE 'UTC',
+    ALTER COLUMN used_at TYPE TIMESTAMP USING used_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC';
+
+ALTER TABLE sessions
+    ALTER COLUMN rotated_at TYPE TIMESTAMP USING rotated_at AT TIME ZONE 'UTC',
+    ALTER COLUMN expires_at TYPE TIMESTAMP USING expires_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC';
+
+ALTER TABLE user_tasks
+    ALTER COLUMN assigned_at TYPE TIMESTAMP USING assigned_at AT TIME ZONE 'UTC';
+
+ALTER TABLE user_roles
+    ALTER COLUMN assigned_at TYPE TIMESTAMP USING assigned_at AT TIME ZONE 'UTC';
+
+ALTER TABLE team_members
+    ALTER COLUMN joined_at TYPE TIMESTAMP USING joined_at AT TIME ZONE 'UTC';
+
+ALTER TABLE teams
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC';
+
+ALTER TABLE tasks
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN due_date TYPE TIMESTAMP USING due_date AT TIME ZONE 'UTC';
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS timezone,
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN password_changed_at TYPE TIMESTAMP USING password_changed_at AT TIME ZONE 'UTC';
+
+CREATE INDEX idx_tasks_due_date_id ON tasks((COALESCE(due_date, '9999-12-31 00:00:00'::timestamp)), id);
+-- +goose StatementEnd
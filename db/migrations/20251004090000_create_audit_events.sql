@@ -0,0 +1,156 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Audit events outlive the users and rows they describe, so nothing here
+-- references other tables.
+CREATE TABLE IF NOT EXISTS audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id INT,
+    action VARCHAR(16) NOT NULL,
+    entity_type VARCHAR(64) NOT NULL,
+    entity_id VARCHAR(255) NOT NULL,
+    before JSONB,
+    after JSONB,
+    request_id VARCHAR(255) NOT NULL DEFAULT '',
+    client_ip VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_events_actor_id ON audit_events(actor_id, id);
+CREATE INDEX idx_audit_events_entity ON audit_events(entity_type, entity_id, id);
+CREATE INDEX idx_audit_events_created_at ON audit_events(created_at);
+
+CREATE FUNCTION prevent_audit_event_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit events are append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION prevent_audit_event_change();
+
+-- record_audit_event logs a row change. The first trigger argument lists the
+-- comma separated key columns that identify the row, the rest are columns
+-- left out of the log, such as secrets. Updates only log the columns that
+-- changed. The actor, request ID and client IP are the audit.* settings the
+-- application puts on the connection.
+CREATE FUNCTION record_audit_event() RETURNS trigger AS $$
+DECLARE
+    redacted TEXT[] := TG_ARGV[1:];
+    source JSONB;
+    old_row JSONB;
+    new_row JSONB;
+    entity TEXT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        source := to_jsonb(OLD);
+    ELSE
+        source := to_jsonb(NEW);
+    END IF;
+
+    IF TG_OP <> 'INSERT' THEN
+        old_row := to_jsonb(OLD) - redacted;
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        new_row := to_jsonb(NEW) - redacted;
+    END IF;
+
+    IF TG_OP = 'UPDATE' THEN
+        SELECT jsonb_object_agg(o.key, o.value), jsonb_object_agg(o.key, new_row -> o.key)
+        INTO old_row, new_row
+        FROM jsonb_each(old_row) o
+        WHERE o.value IS DISTINCT FROM new_row -> o.key;
+
+        IF new_row IS NULL THEN
+            RETURN NULL;
+        END IF;
+    END IF;
+
+    SELECT string_agg(source ->> k.name, ':' ORDER BY k.ord)
+    INTO entity
+    FROM unnest(string_to_array(TG_ARGV[0], ',')) WITH ORDINALITY AS k(name, ord);
+
+    INSERT INTO audit_events (actor_id, action, entity_type, entity_id, before, after, request_id, client_ip)
+    VALUES (
+        nullif(current_setting('audit.actor_id', true), '')::int,
+        CASE TG_OP WHEN 'INSERT' THEN 'create' WHEN 'UPDATE' THEN 'update' ELSE 'delete' END,
+        TG_TABLE_NAME,
+        entity,
+        old_row,
+        new_row,
+        coalesce(current_setting('audit.request_id', true), ''),
+        coalesce(current_setting('audit.client_ip', true), '')
+    );
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_users AFTER INSERT OR UPDATE OR DELETE ON users
+    FOR EACH ROW EXECUTE FUNCTION record_audit_event('id', 'password_hash');
+CREATE TRIGGER audit_tasks AFTER INSERT OR UPDATE OR DELETE ON tasks
+    FOR EACH ROW EXECUTE FUNCTION record_audit_event('id', 'search_vector');
+CREATE TRIGGER audit_teams AFTER INSERT OR UPDATE OR DELETE ON teams
+    FOR EACH ROW EXECUTE FUNCTION record_audit_event('id', 'search_vector');
+CREATE TRIGGER audit_team_members AFTER INSERT OR UPDATE OR DELETE ON team_members
+    FOR EACH ROW EXECUTE FUNCTION record_audit_event('team_id,user_id');
+CREATE TRIGGER audit_roles AFTER INSERT OR UPDATE OR DELETE ON roles
+    FOR EACH ROW EXECUTE FUNCTION record_audit_event('id');
+CREATE TRIGGER audit_user_roles AFTER INSERT OR UPDATE OR DELETE ON user_roles
+    FOR EACH ROW EXECUTE FUNCTION record_audit_event('user_id,role_id');
+CREATE TRIGGER audit_claims AFTER INSERT OR UPDATE OR DELETE ON claims
+    FOR EACH ROW EXECUTE FUNCTION record_audit_event('id');
+CREATE TRIGGER audit_role_claims AFTER INSERT OR UPDATE OR DELETE ON role_claims
+    FOR EACH ROW EXECUTE FUNCTION record_audit_event('role_id,claim_id');
+CREATE TRIGGER audit_user_tasks AFTER INSERT OR UPDATE OR DELETE ON user_tasks
+    FOR EACH ROW EXECUTE FUNCTION record_audit_event('task_id,user_id');
+CREATE TRIGGER audit_sessions AFTER INSERT OR UPDATE OR DELETE ON sessions
+    FOR EACH ROW EXECUTE FUNCTION record_audit_event('id');
+CREATE TRIGGER audit_user_tokens AFTER INSERT OR UPDATE OR DELETE ON user_tokens
+    FOR EACH ROW EXECUTE FUNCTION record_audit_event('id', 'token_hash');
+CREATE TRIGGER audit_task_statuses AFTER INSERT OR UPDATE OR DELETE ON task_statuses
+    FOR EACH ROW EXECUTE FUNCTION record_audit_event('id');
+CREATE TRIGGER audit_task_status_transitions AFTER INSERT OR UPDATE OR DELETE ON task_status_transitions
+    FOR EACH ROW EXECUTE FUNCTION record_audit_event('from_status_id,to_status_id');
+CREATE TRIGGER audit_task_comments AFTER INSERT OR UPDATE OR DELETE ON task_comments
+    FOR EACH ROW EXECUTE FUNCTION record_audit_event('id', 'search_vector');
+CREATE TRIGGER audit_task_comment_revisions AFTER INSERT OR UPDATE OR DELETE ON task_comment_revisions
+    FOR EACH ROW EXECUTE FUNCTION record_audit_event('id');
+CREATE TRIGGER audit_task_comment_mentions AFTER INSERT OR UPDATE OR DELETE ON task_comment_mentions
+    FOR EACH ROW EXECUTE FUNCTION record_audit_event('comment_id,user_id');
+CREATE TRIGGER audit_task_attachments AFTER INSERT OR UPDATE OR DELETE ON task_attachments
+    FOR EACH ROW EXECUTE FUNCTION record_audit_event('id', 'storage_key');
+CREATE TRIGGER audit_task_dependencies AFTER INSERT OR UPDATE OR DELETE ON task_dependencies
+    FOR EACH ROW EXECUTE FUNCTION record_audit_event('blocker_id,blocked_id');
+CREATE TRIGGER audit_task_templates AFTER INSERT OR UPDATE OR DELETE ON task_templates
+    FOR EACH ROW EXECUTE FUNCTION record_audit_event('id');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS audit_task_templates ON task_templates;
+DROP TRIGGER IF EXISTS audit_task_dependencies ON task_dependencies;
+DROP TRIGGER IF EXISTS audit_task_attachments ON task_attachments;
+DROP TRIGGER IF EXISTS audit_task_comment_mentions ON task_comment_mentions;
+DROP TRIGGER IF EXISTS audit_task_comment_revisions ON task_comment_revisions;
+DROP TRIGGER IF EXISTS audit_task_comments ON task_comments;
+DROP TRIGGER IF EXISTS audit_task_status_transitions ON task_status_transitions;
+DROP TRIGGER IF EXISTS audit_task_statuses ON task_statuses;
+DROP TRIGGER IF EXISTS audit_user_tokens ON user_tokens;
+DROP TRIGGER IF EXISTS audit_sessions ON sessions;
+DROP TRIGGER IF EXISTS audit_user_tasks ON user_tasks;
+DROP TRIGGER IF EXISTS audit_role_claims ON role_claims;
+DROP TRIGGER IF EXISTS audit_claims ON claims;
+DROP TRIGGER IF EXISTS audit_user_roles ON user_roles;
+DROP TRIGGER IF EXISTS audit_roles ON roles;
+DROP TRIGGER IF EXISTS audit_team_members ON team_members;
+DROP TRIGGER IF EXISTS audit_teams ON teams;
+DROP TRIGGER IF EXISTS audit_tasks ON tasks;
+DROP TRIGGER IF EXISTS audit_users ON users;
+
+DROP FUNCTION IF EXISTS record_audit_event();
+
+DROP TABLE IF EXISTS audit_events;
+
+DROP FUNCTION IF EXISTS prevent_audit_event_change();
+-- +goose StatementEnd
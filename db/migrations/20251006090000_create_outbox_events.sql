@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Domain events are written here in the same transaction as the change they
+-- describe and delivered afterwards by the dispatcher. Events of one
+-- aggregate are delivered in ID order, so a failing event holds back the
+-- ones after it until it is delivered or given up on.
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    aggregate_type VARCHAR(32) NOT NULL,
+    aggregate_id INT NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    dispatched_at TIMESTAMPTZ,
+    failed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_outbox_events_pending ON outbox_events(next_attempt_at, id)
+    WHERE dispatched_at IS NULL AND failed_at IS NULL;
+CREATE INDEX idx_outbox_events_aggregate ON outbox_events(aggregate_type, aggregate_id, id)
+    WHERE dispatched_at IS NULL AND failed_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outbox_events;
+-- +goose StatementEnd
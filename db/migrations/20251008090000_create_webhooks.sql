@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS webhooks (
+    id SERIAL PRIMARY KEY,
+    team_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    event_types TEXT[] NOT NULL,
+    secret VARCHAR(128) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    -- consecutive_failures counts deliveries given up on since the last
+    -- successful one; the webhook is disabled once it gets too high.
+    consecutive_failures INT NOT NULL DEFAULT 0,
+    disabled_at TIMESTAMPTZ,
+    created_by INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_webhooks_team_id ON webhooks(team_id);
+
+-- Every event is delivered to a webhook once; a redelivery is a new row
+-- pointing at the delivery it repeats.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    webhook_id INT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    redelivery_of BIGINT REFERENCES webhook_deliveries(id) ON DELETE SET NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    response_status INT,
+    response_body TEXT NOT NULL DEFAULT '',
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_webhook_deliveries_event ON webhook_deliveries(webhook_id, event_id)
+    WHERE redelivery_of IS NULL;
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, id);
+CREATE INDEX idx_webhook_deliveries_pending ON webhook_deliveries(next_attempt_at, id)
+    WHERE status = 'pending';
+
+CREATE TRIGGER audit_webhooks AFTER INSERT OR UPDATE OR DELETE ON webhooks
+    FOR EACH ROW EXECUTE FUNCTION record_audit_event('id', 'secret');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+-- +goose StatementEnd
@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Events remember who caused them, so the people notified about an event
+-- can leave out whoever made it happen.
+ALTER TABLE outbox_events ADD COLUMN actor_id INT;
+
+CREATE TABLE IF NOT EXISTS task_watchers (
+    task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, user_id)
+);
+
+CREATE INDEX idx_task_watchers_user_id ON task_watchers(user_id);
+
+-- A notification is made once per user and outbox event, however often the
+-- event is delivered.
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(32) NOT NULL,
+    task_id INT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    comment_id INT REFERENCES task_comments(id) ON DELETE CASCADE,
+    actor_id INT REFERENCES users(id) ON DELETE SET NULL,
+    event_id BIGINT NOT NULL,
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, event_id)
+);
+
+CREATE INDEX idx_notifications_user_id ON notifications(user_id, id);
+CREATE INDEX idx_notifications_unread ON notifications(user_id, id) WHERE read_at IS NULL;
+
+-- Users without a row here get every notification and no digest. Sending a
+-- digest is not a change the user made, so last_digest_at is left out of the
+-- audit log.
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    task_assigned BOOLEAN NOT NULL DEFAULT TRUE,
+    mentioned BOOLEAN NOT NULL DEFAULT TRUE,
+    watched_task_changed BOOLEAN NOT NULL DEFAULT TRUE,
+    daily_digest BOOLEAN NOT NULL DEFAULT FALSE,
+    last_digest_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_notification_preferences_digest ON notification_preferences(user_id) WHERE daily_digest;
+
+CREATE TRIGGER audit_task_watchers AFTER INSERT OR UPDATE OR DELETE ON task_watchers
+    FOR EACH ROW EXECUTE FUNCTION record_audit_event('task_id,user_id');
+CREATE TRIGGER audit_notification_preferences AFTER INSERT OR UPDATE OR DELETE ON notification_preferences
+    FOR EACH ROW EXECUTE FUNCTION record_audit_event('user_id', 'last_digest_at');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS task_watchers;
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS actor_id;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Events remember the team they happened in, so the stream of a team can be
+-- replayed from the outbox. Events of personal tasks have none.
+ALTER TABLE outbox_events ADD COLUMN team_id INT;
+
+UPDATE outbox_events
+    SET team_id = COALESCE(
+        (payload->'task'->>'teamId')::INT,
+        (payload->'team'->>'id')::INT,
+        (payload->>'teamId')::INT
+    );
+
+CREATE INDEX idx_outbox_events_team_id ON outbox_events(team_id, id) WHERE team_id IS NOT NULL;
+
+-- notify_outbox_event announces every team event on the outbox_events
+-- channel with its ID, so every server instance can push it to the clients
+-- streaming from it. Notifications are only sent once the transaction
+-- commits.
+CREATE FUNCTION notify_outbox_event() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('outbox_events', NEW.id::TEXT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notify_outbox_events AFTER INSERT ON outbox_events
+    FOR EACH ROW WHEN (NEW.team_id IS NOT NULL) EXECUTE FUNCTION notify_outbox_event();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS notify_outbox_events ON outbox_events;
+DROP FUNCTION IF EXISTS notify_outbox_event();
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS team_id;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Outbox IDs are handed out as events are written, not as they commit, so a
+-- stream resumed after an ID could skip an event committed later under a lower
+-- one. Events remember the transaction that wrote them instead, and streams
+-- read them in transaction order, only up to the oldest transaction still
+-- running: nothing can commit behind a position once it has been read.
+ALTER TABLE outbox_events ADD COLUMN tx_id BIGINT NOT NULL DEFAULT pg_current_xact_id()::TEXT::BIGINT;
+
+DROP INDEX idx_outbox_events_team_id;
+CREATE INDEX idx_outbox_events_stream ON outbox_events(tx_id, id) WHERE team_id IS NOT NULL;
+CREATE INDEX idx_outbox_events_team_stream ON outbox_events(team_id, tx_id, id) WHERE team_id IS NOT NULL;
+
+-- outbox_stream_horizon is the ID of the oldest transaction still running.
+-- Every event written by an older one has committed, or never will.
+CREATE FUNCTION outbox_stream_horizon() RETURNS BIGINT AS $$
+    SELECT pg_snapshot_xmin(pg_current_snapshot())::TEXT::BIGINT;
+$$ LANGUAGE sql STABLE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS outbox_stream_horizon();
+DROP INDEX IF EXISTS idx_outbox_events_team_stream;
+DROP INDEX IF EXISTS idx_outbox_events_stream;
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS tx_id;
+CREATE INDEX idx_outbox_events_team_id ON outbox_events(team_id, id) WHERE team_id IS NOT NULL;
+-- +goose StatementEnd
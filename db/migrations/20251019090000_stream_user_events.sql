@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Events of a user, such as their sessions being revoked, are streamed to the
+-- servers holding that user's streams, alongside the team events.
+DROP INDEX idx_outbox_events_stream;
+CREATE INDEX idx_outbox_events_stream ON outbox_events(tx_id, id) WHERE team_id IS NOT NULL OR aggregate_type = 'user';
+
+DROP TRIGGER notify_outbox_events ON outbox_events;
+CREATE TRIGGER notify_outbox_events AFTER INSERT ON outbox_events
+    FOR EACH ROW WHEN (NEW.team_id IS NOT NULL OR NEW.aggregate_type = 'user') EXECUTE FUNCTION notify_outbox_event();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS notify_outbox_events ON outbox_events;
+CREATE TRIGGER notify_outbox_events AFTER INSERT ON outbox_events
+    FOR EACH ROW WHEN (NEW.team_id IS NOT NULL) EXECUTE FUNCTION notify_outbox_event();
+
+DROP INDEX IF EXISTS idx_outbox_events_stream;
+CREATE INDEX idx_outbox_events_stream ON outbox_events(tx_id, id) WHERE team_id IS NOT NULL;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Deliveries no longer keep what the receiver responded with: the log is
+-- readable by every team editor and a receiver's response is not theirs to
+-- see.
+ALTER TABLE webhook_deliveries DROP COLUMN response_body;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE webhook_deliveries ADD COLUMN response_body TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The address a token was sent to, so a verification link mailed to an old
+-- address cannot verify the one that replaced it.
+ALTER TABLE user_tokens ADD COLUMN email VARCHAR(100) NOT NULL DEFAULT '';
+
+-- Outstanding verification tokens do not say which address they were sent
+-- to, so they are retired; a new one can be requested.
+UPDATE user_tokens SET used_at = now()
+WHERE purpose = 'email_verification' AND used_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE user_tokens DROP COLUMN IF EXISTS email;
+-- +goose StatementEnd
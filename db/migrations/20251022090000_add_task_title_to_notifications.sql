@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Notifications keep the title the task had when they were made, so someone
+-- who can no longer see the task does not learn what it was renamed to.
+ALTER TABLE notifications ADD COLUMN task_title VARCHAR(100) NOT NULL DEFAULT '';
+
+UPDATE notifications n SET task_title = t.title
+FROM tasks t
+WHERE t.id = n.task_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE notifications DROP COLUMN IF EXISTS task_title;
+-- +goose StatementEnd
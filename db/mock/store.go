@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 
 	sqlc "github.com/bolusarz/task-manager/db/sqlc"
+	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,63 +42,2276 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AddRoleClaim mocks base method.
+func (m *MockStore) AddRoleClaim(ctx context.Context, arg sqlc.AddRoleClaimParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRoleClaim", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRoleClaim indicates an expected call of AddRoleClaim.
+func (mr *MockStoreMockRecorder) AddRoleClaim(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRoleClaim", reflect.TypeOf((*MockStore)(nil).AddRoleClaim), ctx, arg)
+}
+
+// AddTaskAssignee mocks base method.
+func (m *MockStore) AddTaskAssignee(ctx context.Context, arg sqlc.AddTaskAssigneeParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTaskAssignee", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTaskAssignee indicates an expected call of AddTaskAssignee.
+func (mr *MockStoreMockRecorder) AddTaskAssignee(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTaskAssignee", reflect.TypeOf((*MockStore)(nil).AddTaskAssignee), ctx, arg)
+}
+
+// AddTaskCommentMentions mocks base method.
+func (m *MockStore) AddTaskCommentMentions(ctx context.Context, arg sqlc.AddTaskCommentMentionsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTaskCommentMentions", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTaskCommentMentions indicates an expected call of AddTaskCommentMentions.
+func (mr *MockStoreMockRecorder) AddTaskCommentMentions(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTaskCommentMentions", reflect.TypeOf((*MockStore)(nil).AddTaskCommentMentions), ctx, arg)
+}
+
+// AddTaskDependency mocks base method.
+func (m *MockStore) AddTaskDependency(ctx context.Context, arg sqlc.CreateTaskDependencyParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTaskDependency", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTaskDependency indicates an expected call of AddTaskDependency.
+func (mr *MockStoreMockRecorder) AddTaskDependency(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTaskDependency", reflect.TypeOf((*MockStore)(nil).AddTaskDependency), ctx, arg)
+}
+
+// AddTaskWatcher mocks base method.
+func (m *MockStore) AddTaskWatcher(ctx context.Context, arg sqlc.AddTaskWatcherParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTaskWatcher", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTaskWatcher indicates an expected call of AddTaskWatcher.
+func (mr *MockStoreMockRecorder) AddTaskWatcher(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTaskWatcher", reflect.TypeOf((*MockStore)(nil).AddTaskWatcher), ctx, arg)
+}
+
+// AddTeamMember mocks base method.
+func (m *MockStore) AddTeamMember(ctx context.Context, arg sqlc.AddTeamMemberParams) (sqlc.TeamMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTeamMember", ctx, arg)
+	ret0, _ := ret[0].(sqlc.TeamMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddTeamMember indicates an expected call of AddTeamMember.
+func (mr *MockStoreMockRecorder) AddTeamMember(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTeamMember", reflect.TypeOf((*MockStore)(nil).AddTeamMember), ctx, arg)
+}
+
+// AddUserRole mocks base method.
+func (m *MockStore) AddUserRole(ctx context.Context, arg sqlc.AddUserRoleParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddUserRole", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddUserRole indicates an expected call of AddUserRole.
+func (mr *MockStoreMockRecorder) AddUserRole(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUserRole", reflect.TypeOf((*MockStore)(nil).AddUserRole), ctx, arg)
+}
+
+// AddUserRoleByName mocks base method.
+func (m *MockStore) AddUserRoleByName(ctx context.Context, arg sqlc.AddUserRoleByNameParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddUserRoleByName", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddUserRoleByName indicates an expected call of AddUserRoleByName.
+func (mr *MockStoreMockRecorder) AddUserRoleByName(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUserRoleByName", reflect.TypeOf((*MockStore)(nil).AddUserRoleByName), ctx, arg)
+}
+
+// AdvanceTaskTemplate mocks base method.
+func (m *MockStore) AdvanceTaskTemplate(ctx context.Context, arg sqlc.AdvanceTaskTemplateParams) (sqlc.TaskTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceTaskTemplate", ctx, arg)
+	ret0, _ := ret[0].(sqlc.TaskTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdvanceTaskTemplate indicates an expected call of AdvanceTaskTemplate.
+func (mr *MockStoreMockRecorder) AdvanceTaskTemplate(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceTaskTemplate", reflect.TypeOf((*MockStore)(nil).AdvanceTaskTemplate), ctx, arg)
+}
+
+// ChangeUserPassword mocks base method.
+func (m *MockStore) ChangeUserPassword(ctx context.Context, arg sqlc.UpdateUserPasswordParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeUserPassword", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangeUserPassword indicates an expected call of ChangeUserPassword.
+func (mr *MockStoreMockRecorder) ChangeUserPassword(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeUserPassword", reflect.TypeOf((*MockStore)(nil).ChangeUserPassword), ctx, arg)
+}
+
+// ClaimOutboxEvents mocks base method.
+func (m *MockStore) ClaimOutboxEvents(ctx context.Context, arg sqlc.ClaimOutboxEventsParams) ([]sqlc.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimOutboxEvents", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimOutboxEvents indicates an expected call of ClaimOutboxEvents.
+func (mr *MockStoreMockRecorder) ClaimOutboxEvents(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimOutboxEvents", reflect.TypeOf((*MockStore)(nil).ClaimOutboxEvents), ctx, arg)
+}
+
+// ClaimWebhookDeliveries mocks base method.
+func (m *MockStore) ClaimWebhookDeliveries(ctx context.Context, arg sqlc.ClaimWebhookDeliveriesParams) ([]sqlc.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookDeliveries", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebhookDeliveries indicates an expected call of ClaimWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ClaimWebhookDeliveries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ClaimWebhookDeliveries), ctx, arg)
+}
+
+// ConsumeUserToken mocks base method.
+func (m *MockStore) ConsumeUserToken(ctx context.Context, arg sqlc.ConsumeUserTokenParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeUserToken", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConsumeUserToken indicates an expected call of ConsumeUserToken.
+func (mr *MockStoreMockRecorder) ConsumeUserToken(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeUserToken", reflect.TypeOf((*MockStore)(nil).ConsumeUserToken), ctx, arg)
+}
+
+// CopyDefaultTaskStatusTransitions mocks base method.
+func (m *MockStore) CopyDefaultTaskStatusTransitions(ctx context.Context, teamID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyDefaultTaskStatusTransitions", ctx, teamID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopyDefaultTaskStatusTransitions indicates an expected call of CopyDefaultTaskStatusTransitions.
+func (mr *MockStoreMockRecorder) CopyDefaultTaskStatusTransitions(ctx, teamID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyDefaultTaskStatusTransitions", reflect.TypeOf((*MockStore)(nil).CopyDefaultTaskStatusTransitions), ctx, teamID)
+}
+
+// CopyDefaultTaskStatuses mocks base method.
+func (m *MockStore) CopyDefaultTaskStatuses(ctx context.Context, teamID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyDefaultTaskStatuses", ctx, teamID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopyDefaultTaskStatuses indicates an expected call of CopyDefaultTaskStatuses.
+func (mr *MockStoreMockRecorder) CopyDefaultTaskStatuses(ctx, teamID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyDefaultTaskStatuses", reflect.TypeOf((*MockStore)(nil).CopyDefaultTaskStatuses), ctx, teamID)
+}
+
+// CountOpenTemplateTasks mocks base method.
+func (m *MockStore) CountOpenTemplateTasks(ctx context.Context, templateID *int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOpenTemplateTasks", ctx, templateID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOpenTemplateTasks indicates an expected call of CountOpenTemplateTasks.
+func (mr *MockStoreMockRecorder) CountOpenTemplateTasks(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOpenTemplateTasks", reflect.TypeOf((*MockStore)(nil).CountOpenTemplateTasks), ctx, templateID)
+}
+
+// CountUnreadNotifications mocks base method.
+func (m *MockStore) CountUnreadNotifications(ctx context.Context, userID int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnreadNotifications", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnreadNotifications indicates an expected call of CountUnreadNotifications.
+func (mr *MockStoreMockRecorder) CountUnreadNotifications(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnreadNotifications", reflect.TypeOf((*MockStore)(nil).CountUnreadNotifications), ctx, userID)
+}
+
+// CountUsersWithRole mocks base method.
+func (m *MockStore) CountUsersWithRole(ctx context.Context, name string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUsersWithRole", ctx, name)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUsersWithRole indicates an expected call of CountUsersWithRole.
+func (mr *MockStoreMockRecorder) CountUsersWithRole(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUsersWithRole", reflect.TypeOf((*MockStore)(nil).CountUsersWithRole), ctx, name)
+}
+
+// CreateNotifications mocks base method.
+func (m *MockStore) CreateNotifications(ctx context.Context, arg sqlc.CreateNotificationsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotifications", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateNotifications indicates an expected call of CreateNotifications.
+func (mr *MockStoreMockRecorder) CreateNotifications(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotifications", reflect.TypeOf((*MockStore)(nil).CreateNotifications), ctx, arg)
+}
+
+// CreateOutboxEvent mocks base method.
+func (m *MockStore) CreateOutboxEvent(ctx context.Context, arg sqlc.CreateOutboxEventParams) (sqlc.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutboxEvent", ctx, arg)
+	ret0, _ := ret[0].(sqlc.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOutboxEvent indicates an expected call of CreateOutboxEvent.
+func (mr *MockStoreMockRecorder) CreateOutboxEvent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxEvent", reflect.TypeOf((*MockStore)(nil).CreateOutboxEvent), ctx, arg)
+}
+
+// CreateRole mocks base method.
+func (m *MockStore) CreateRole(ctx context.Context, arg sqlc.CreateRoleParams) (sqlc.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRole", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRole indicates an expected call of CreateRole.
+func (mr *MockStoreMockRecorder) CreateRole(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRole", reflect.TypeOf((*MockStore)(nil).CreateRole), ctx, arg)
+}
+
+// CreateSession mocks base method.
+func (m *MockStore) CreateSession(ctx context.Context, arg sqlc.CreateSessionParams) (sqlc.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockStoreMockRecorder) CreateSession(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStore)(nil).CreateSession), ctx, arg)
+}
+
+// CreateTask mocks base method.
+func (m *MockStore) CreateTask(ctx context.Context, arg sqlc.CreateTaskParams) (sqlc.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTask", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTask indicates an expected call of CreateTask.
+func (mr *MockStoreMockRecorder) CreateTask(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTask", reflect.TypeOf((*MockStore)(nil).CreateTask), ctx, arg)
+}
+
+// CreateTaskAttachment mocks base method.
+func (m *MockStore) CreateTaskAttachment(ctx context.Context, arg sqlc.CreateTaskAttachmentParams) (sqlc.TaskAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTaskAttachment", ctx, arg)
+	ret0, _ := ret[0].(sqlc.TaskAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTaskAttachment indicates an expected call of CreateTaskAttachment.
+func (mr *MockStoreMockRecorder) CreateTaskAttachment(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTaskAttachment", reflect.TypeOf((*MockStore)(nil).CreateTaskAttachment), ctx, arg)
+}
+
+// CreateTaskComment mocks base method.
+func (m *MockStore) CreateTaskComment(ctx context.Context, arg sqlc.CreateTaskCommentParams) (sqlc.TaskComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTaskComment", ctx, arg)
+	ret0, _ := ret[0].(sqlc.TaskComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTaskComment indicates an expected call of CreateTaskComment.
+func (mr *MockStoreMockRecorder) CreateTaskComment(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTaskComment", reflect.TypeOf((*MockStore)(nil).CreateTaskComment), ctx, arg)
+}
+
+// CreateTaskCommentRevision mocks base method.
+func (m *MockStore) CreateTaskCommentRevision(ctx context.Context, arg sqlc.CreateTaskCommentRevisionParams) (sqlc.TaskCommentRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTaskCommentRevision", ctx, arg)
+	ret0, _ := ret[0].(sqlc.TaskCommentRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTaskCommentRevision indicates an expected call of CreateTaskCommentRevision.
+func (mr *MockStoreMockRecorder) CreateTaskCommentRevision(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTaskCommentRevision", reflect.TypeOf((*MockStore)(nil).CreateTaskCommentRevision), ctx, arg)
+}
+
+// CreateTaskCommentWithMentions mocks base method.
+func (m *MockStore) CreateTaskCommentWithMentions(ctx context.Context, arg sqlc.CreateTaskCommentWithMentionsParams) (sqlc.TaskComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTaskCommentWithMentions", ctx, arg)
+	ret0, _ := ret[0].(sqlc.TaskComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTaskCommentWithMentions indicates an expected call of CreateTaskCommentWithMentions.
+func (mr *MockStoreMockRecorder) CreateTaskCommentWithMentions(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTaskCommentWithMentions", reflect.TypeOf((*MockStore)(nil).CreateTaskCommentWithMentions), ctx, arg)
+}
+
+// CreateTaskDependency mocks base method.
+func (m *MockStore) CreateTaskDependency(ctx context.Context, arg sqlc.CreateTaskDependencyParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTaskDependency", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTaskDependency indicates an expected call of CreateTaskDependency.
+func (mr *MockStoreMockRecorder) CreateTaskDependency(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTaskDependency", reflect.TypeOf((*MockStore)(nil).CreateTaskDependency), ctx, arg)
+}
+
+// CreateTaskStatus mocks base method.
+func (m *MockStore) CreateTaskStatus(ctx context.Context, arg sqlc.CreateTaskStatusParams) (sqlc.TaskStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTaskStatus", ctx, arg)
+	ret0, _ := ret[0].(sqlc.TaskStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTaskStatus indicates an expected call of CreateTaskStatus.
+func (mr *MockStoreMockRecorder) CreateTaskStatus(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTaskStatus", reflect.TypeOf((*MockStore)(nil).CreateTaskStatus), ctx, arg)
+}
+
+// CreateTaskStatusTransition mocks base method.
+func (m *MockStore) CreateTaskStatusTransition(ctx context.Context, arg sqlc.CreateTaskStatusTransitionParams) (sqlc.TaskStatusTransition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTaskStatusTransition", ctx, arg)
+	ret0, _ := ret[0].(sqlc.TaskStatusTransition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTaskStatusTransition indicates an expected call of CreateTaskStatusTransition.
+func (mr *MockStoreMockRecorder) CreateTaskStatusTransition(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTaskStatusTransition", reflect.TypeOf((*MockStore)(nil).CreateTaskStatusTransition), ctx, arg)
+}
+
+// CreateTaskTemplate mocks base method.
+func (m *MockStore) CreateTaskTemplate(ctx context.Context, arg sqlc.CreateTaskTemplateParams) (sqlc.TaskTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTaskTemplate", ctx, arg)
+	ret0, _ := ret[0].(sqlc.TaskTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTaskTemplate indicates an expected call of CreateTaskTemplate.
+func (mr *MockStoreMockRecorder) CreateTaskTemplate(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTaskTemplate", reflect.TypeOf((*MockStore)(nil).CreateTaskTemplate), ctx, arg)
+}
+
+// CreateTaskTemplateWithOccurrence mocks base method.
+func (m *MockStore) CreateTaskTemplateWithOccurrence(ctx context.Context, arg sqlc.CreateTaskTemplateWithOccurrenceParams) (sqlc.TaskOccurrenceResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTaskTemplateWithOccurrence", ctx, arg)
+	ret0, _ := ret[0].(sqlc.TaskOccurrenceResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTaskTemplateWithOccurrence indicates an expected call of CreateTaskTemplateWithOccurrence.
+func (mr *MockStoreMockRecorder) CreateTaskTemplateWithOccurrence(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTaskTemplateWithOccurrence", reflect.TypeOf((*MockStore)(nil).CreateTaskTemplateWithOccurrence), ctx, arg)
+}
+
+// CreateTaskWithAssignees mocks base method.
+func (m *MockStore) CreateTaskWithAssignees(ctx context.Context, arg sqlc.CreateTaskWithAssigneesParams) (sqlc.CreateTaskWithAssigneesResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTaskWithAssignees", ctx, arg)
+	ret0, _ := ret[0].(sqlc.CreateTaskWithAssigneesResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTaskWithAssignees indicates an expected call of CreateTaskWithAssignees.
+func (mr *MockStoreMockRecorder) CreateTaskWithAssignees(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTaskWithAssignees", reflect.TypeOf((*MockStore)(nil).CreateTaskWithAssignees), ctx, arg)
+}
+
+// CreateTeam mocks base method.
+func (m *MockStore) CreateTeam(ctx context.Context, arg sqlc.CreateTeamParams) (sqlc.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTeam", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTeam indicates an expected call of CreateTeam.
+func (mr *MockStoreMockRecorder) CreateTeam(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTeam", reflect.TypeOf((*MockStore)(nil).CreateTeam), ctx, arg)
+}
+
+// CreateTeamWithOwner mocks base method.
+func (m *MockStore) CreateTeamWithOwner(ctx context.Context, arg sqlc.CreateTeamWithOwnerParams) (sqlc.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTeamWithOwner", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTeamWithOwner indicates an expected call of CreateTeamWithOwner.
+func (mr *MockStoreMockRecorder) CreateTeamWithOwner(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTeamWithOwner", reflect.TypeOf((*MockStore)(nil).CreateTeamWithOwner), ctx, arg)
+}
+
+// CreateTemplateTask mocks base method.
+func (m *MockStore) CreateTemplateTask(ctx context.Context, arg sqlc.CreateTemplateTaskParams) (sqlc.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTemplateTask", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTemplateTask indicates an expected call of CreateTemplateTask.
+func (mr *MockStoreMockRecorder) CreateTemplateTask(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTemplateTask", reflect.TypeOf((*MockStore)(nil).CreateTemplateTask), ctx, arg)
+}
+
 // CreateUser mocks base method.
 func (m *MockStore) CreateUser(ctx context.Context, arg sqlc.CreateUserParams) (sqlc.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", ctx, arg)
-	ret0, _ := ret[0].(sqlc.User)
+	ret := m.ctrl.Call(m, "CreateUser", ctx, arg)
+	ret0, _ := ret[0].(sqlc.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStoreMockRecorder) CreateUser(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), ctx, arg)
+}
+
+// CreateUserToken mocks base method.
+func (m *MockStore) CreateUserToken(ctx context.Context, arg sqlc.CreateUserTokenParams) (sqlc.UserToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserToken", ctx, arg)
+	ret0, _ := ret[0].(sqlc.UserToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserToken indicates an expected call of CreateUserToken.
+func (mr *MockStoreMockRecorder) CreateUserToken(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserToken", reflect.TypeOf((*MockStore)(nil).CreateUserToken), ctx, arg)
+}
+
+// CreateUserWithRoles mocks base method.
+func (m *MockStore) CreateUserWithRoles(ctx context.Context, arg sqlc.CreateUserWithRolesParams) (sqlc.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserWithRoles", ctx, arg)
+	ret0, _ := ret[0].(sqlc.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserWithRoles indicates an expected call of CreateUserWithRoles.
+func (mr *MockStoreMockRecorder) CreateUserWithRoles(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserWithRoles", reflect.TypeOf((*MockStore)(nil).CreateUserWithRoles), ctx, arg)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockStore) CreateWebhook(ctx context.Context, arg sqlc.CreateWebhookParams) (sqlc.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockStoreMockRecorder) CreateWebhook(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockStore)(nil).CreateWebhook), ctx, arg)
+}
+
+// CreateWebhookDelivery mocks base method.
+func (m *MockStore) CreateWebhookDelivery(ctx context.Context, arg sqlc.CreateWebhookDeliveryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDelivery", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhookDelivery indicates an expected call of CreateWebhookDelivery.
+func (mr *MockStoreMockRecorder) CreateWebhookDelivery(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDelivery", reflect.TypeOf((*MockStore)(nil).CreateWebhookDelivery), ctx, arg)
+}
+
+// CreateWebhookRedelivery mocks base method.
+func (m *MockStore) CreateWebhookRedelivery(ctx context.Context, id int64) (sqlc.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookRedelivery", ctx, id)
+	ret0, _ := ret[0].(sqlc.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookRedelivery indicates an expected call of CreateWebhookRedelivery.
+func (mr *MockStoreMockRecorder) CreateWebhookRedelivery(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookRedelivery", reflect.TypeOf((*MockStore)(nil).CreateWebhookRedelivery), ctx, id)
+}
+
+// DeleteRole mocks base method.
+func (m *MockStore) DeleteRole(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRole", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRole indicates an expected call of DeleteRole.
+func (mr *MockStoreMockRecorder) DeleteRole(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRole", reflect.TypeOf((*MockStore)(nil).DeleteRole), ctx, id)
+}
+
+// DeleteTask mocks base method.
+func (m *MockStore) DeleteTask(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTask", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTask indicates an expected call of DeleteTask.
+func (mr *MockStoreMockRecorder) DeleteTask(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockStore)(nil).DeleteTask), ctx, id)
+}
+
+// DeleteTaskAttachment mocks base method.
+func (m *MockStore) DeleteTaskAttachment(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTaskAttachment", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTaskAttachment indicates an expected call of DeleteTaskAttachment.
+func (mr *MockStoreMockRecorder) DeleteTaskAttachment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTaskAttachment", reflect.TypeOf((*MockStore)(nil).DeleteTaskAttachment), ctx, id)
+}
+
+// DeleteTaskComment mocks base method.
+func (m *MockStore) DeleteTaskComment(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTaskComment", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTaskComment indicates an expected call of DeleteTaskComment.
+func (mr *MockStoreMockRecorder) DeleteTaskComment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTaskComment", reflect.TypeOf((*MockStore)(nil).DeleteTaskComment), ctx, id)
+}
+
+// DeleteTaskCommentMentions mocks base method.
+func (m *MockStore) DeleteTaskCommentMentions(ctx context.Context, commentID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTaskCommentMentions", ctx, commentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTaskCommentMentions indicates an expected call of DeleteTaskCommentMentions.
+func (mr *MockStoreMockRecorder) DeleteTaskCommentMentions(ctx, commentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTaskCommentMentions", reflect.TypeOf((*MockStore)(nil).DeleteTaskCommentMentions), ctx, commentID)
+}
+
+// DeleteTaskDependency mocks base method.
+func (m *MockStore) DeleteTaskDependency(ctx context.Context, arg sqlc.DeleteTaskDependencyParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTaskDependency", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTaskDependency indicates an expected call of DeleteTaskDependency.
+func (mr *MockStoreMockRecorder) DeleteTaskDependency(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTaskDependency", reflect.TypeOf((*MockStore)(nil).DeleteTaskDependency), ctx, arg)
+}
+
+// DeleteTaskStatus mocks base method.
+func (m *MockStore) DeleteTaskStatus(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTaskStatus", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTaskStatus indicates an expected call of DeleteTaskStatus.
+func (mr *MockStoreMockRecorder) DeleteTaskStatus(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTaskStatus", reflect.TypeOf((*MockStore)(nil).DeleteTaskStatus), ctx, id)
+}
+
+// DeleteTaskStatusTransition mocks base method.
+func (m *MockStore) DeleteTaskStatusTransition(ctx context.Context, arg sqlc.DeleteTaskStatusTransitionParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTaskStatusTransition", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTaskStatusTransition indicates an expected call of DeleteTaskStatusTransition.
+func (mr *MockStoreMockRecorder) DeleteTaskStatusTransition(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTaskStatusTransition", reflect.TypeOf((*MockStore)(nil).DeleteTaskStatusTransition), ctx, arg)
+}
+
+// DeleteTaskTemplate mocks base method.
+func (m *MockStore) DeleteTaskTemplate(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTaskTemplate", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTaskTemplate indicates an expected call of DeleteTaskTemplate.
+func (mr *MockStoreMockRecorder) DeleteTaskTemplate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTaskTemplate", reflect.TypeOf((*MockStore)(nil).DeleteTaskTemplate), ctx, id)
+}
+
+// DeleteTeam mocks base method.
+func (m *MockStore) DeleteTeam(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTeam", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTeam indicates an expected call of DeleteTeam.
+func (mr *MockStoreMockRecorder) DeleteTeam(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTeam", reflect.TypeOf((*MockStore)(nil).DeleteTeam), ctx, id)
+}
+
+// DeleteUser mocks base method.
+func (m *MockStore) DeleteUser(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockStoreMockRecorder) DeleteUser(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockStore)(nil).DeleteUser), ctx, id)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockStore) DeleteWebhook(ctx context.Context, id int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockStoreMockRecorder) DeleteWebhook(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockStore)(nil).DeleteWebhook), ctx, id)
+}
+
+// EditTaskComment mocks base method.
+func (m *MockStore) EditTaskComment(ctx context.Context, arg sqlc.EditTaskCommentParams) (sqlc.TaskComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditTaskComment", ctx, arg)
+	ret0, _ := ret[0].(sqlc.TaskComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditTaskComment indicates an expected call of EditTaskComment.
+func (mr *MockStoreMockRecorder) EditTaskComment(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditTaskComment", reflect.TypeOf((*MockStore)(nil).EditTaskComment), ctx, arg)
+}
+
+// ExecTx mocks base method.
+func (m *MockStore) ExecTx(ctx context.Context, fn func(*sqlc.Queries) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecTx indicates an expected call of ExecTx.
+func (mr *MockStoreMockRecorder) ExecTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecTx", reflect.TypeOf((*MockStore)(nil).ExecTx), ctx, fn)
+}
+
+// FailOutboxEvent mocks base method.
+func (m *MockStore) FailOutboxEvent(ctx context.Context, arg sqlc.FailOutboxEventParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailOutboxEvent", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailOutboxEvent indicates an expected call of FailOutboxEvent.
+func (mr *MockStoreMockRecorder) FailOutboxEvent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailOutboxEvent", reflect.TypeOf((*MockStore)(nil).FailOutboxEvent), ctx, arg)
+}
+
+// FilterTeamMembers mocks base method.
+func (m *MockStore) FilterTeamMembers(ctx context.Context, arg sqlc.FilterTeamMembersParams) ([]int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterTeamMembers", ctx, arg)
+	ret0, _ := ret[0].([]int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FilterTeamMembers indicates an expected call of FilterTeamMembers.
+func (mr *MockStoreMockRecorder) FilterTeamMembers(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterTeamMembers", reflect.TypeOf((*MockStore)(nil).FilterTeamMembers), ctx, arg)
+}
+
+// GetClaim mocks base method.
+func (m *MockStore) GetClaim(ctx context.Context, id int32) (sqlc.Claim, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaim", ctx, id)
+	ret0, _ := ret[0].(sqlc.Claim)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaim indicates an expected call of GetClaim.
+func (mr *MockStoreMockRecorder) GetClaim(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaim", reflect.TypeOf((*MockStore)(nil).GetClaim), ctx, id)
+}
+
+// GetClaimByName mocks base method.
+func (m *MockStore) GetClaimByName(ctx context.Context, name string) (sqlc.Claim, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaimByName", ctx, name)
+	ret0, _ := ret[0].(sqlc.Claim)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaimByName indicates an expected call of GetClaimByName.
+func (mr *MockStoreMockRecorder) GetClaimByName(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaimByName", reflect.TypeOf((*MockStore)(nil).GetClaimByName), ctx, name)
+}
+
+// GetInitialTaskStatus mocks base method.
+func (m *MockStore) GetInitialTaskStatus(ctx context.Context, teamID *int32) (sqlc.TaskStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInitialTaskStatus", ctx, teamID)
+	ret0, _ := ret[0].(sqlc.TaskStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInitialTaskStatus indicates an expected call of GetInitialTaskStatus.
+func (mr *MockStoreMockRecorder) GetInitialTaskStatus(ctx, teamID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInitialTaskStatus", reflect.TypeOf((*MockStore)(nil).GetInitialTaskStatus), ctx, teamID)
+}
+
+// GetLastStreamOutboxEvent mocks base method.
+func (m *MockStore) GetLastStreamOutboxEvent(ctx context.Context) (sqlc.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastStreamOutboxEvent", ctx)
+	ret0, _ := ret[0].(sqlc.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastStreamOutboxEvent indicates an expected call of GetLastStreamOutboxEvent.
+func (mr *MockStoreMockRecorder) GetLastStreamOutboxEvent(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastStreamOutboxEvent", reflect.TypeOf((*MockStore)(nil).GetLastStreamOutboxEvent), ctx)
+}
+
+// GetLatestUserToken mocks base method.
+func (m *MockStore) GetLatestUserToken(ctx context.Context, arg sqlc.GetLatestUserTokenParams) (sqlc.UserToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestUserToken", ctx, arg)
+	ret0, _ := ret[0].(sqlc.UserToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestUserToken indicates an expected call of GetLatestUserToken.
+func (mr *MockStoreMockRecorder) GetLatestUserToken(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestUserToken", reflect.TypeOf((*MockStore)(nil).GetLatestUserToken), ctx, arg)
+}
+
+// GetNotificationPreferences mocks base method.
+func (m *MockStore) GetNotificationPreferences(ctx context.Context, userID int32) (sqlc.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationPreferences", ctx, userID)
+	ret0, _ := ret[0].(sqlc.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationPreferences indicates an expected call of GetNotificationPreferences.
+func (mr *MockStoreMockRecorder) GetNotificationPreferences(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationPreferences", reflect.TypeOf((*MockStore)(nil).GetNotificationPreferences), ctx, userID)
+}
+
+// GetOutboxEvent mocks base method.
+func (m *MockStore) GetOutboxEvent(ctx context.Context, id int64) (sqlc.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutboxEvent", ctx, id)
+	ret0, _ := ret[0].(sqlc.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutboxEvent indicates an expected call of GetOutboxEvent.
+func (mr *MockStoreMockRecorder) GetOutboxEvent(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboxEvent", reflect.TypeOf((*MockStore)(nil).GetOutboxEvent), ctx, id)
+}
+
+// GetRole mocks base method.
+func (m *MockStore) GetRole(ctx context.Context, id int32) (sqlc.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRole", ctx, id)
+	ret0, _ := ret[0].(sqlc.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRole indicates an expected call of GetRole.
+func (mr *MockStoreMockRecorder) GetRole(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRole", reflect.TypeOf((*MockStore)(nil).GetRole), ctx, id)
+}
+
+// GetRoleByName mocks base method.
+func (m *MockStore) GetRoleByName(ctx context.Context, name string) (sqlc.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoleByName", ctx, name)
+	ret0, _ := ret[0].(sqlc.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoleByName indicates an expected call of GetRoleByName.
+func (mr *MockStoreMockRecorder) GetRoleByName(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoleByName", reflect.TypeOf((*MockStore)(nil).GetRoleByName), ctx, name)
+}
+
+// GetSession mocks base method.
+func (m *MockStore) GetSession(ctx context.Context, id uuid.UUID) (sqlc.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", ctx, id)
+	ret0, _ := ret[0].(sqlc.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockStoreMockRecorder) GetSession(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), ctx, id)
+}
+
+// GetTask mocks base method.
+func (m *MockStore) GetTask(ctx context.Context, id int32) (sqlc.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTask", ctx, id)
+	ret0, _ := ret[0].(sqlc.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTask indicates an expected call of GetTask.
+func (mr *MockStoreMockRecorder) GetTask(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTask", reflect.TypeOf((*MockStore)(nil).GetTask), ctx, id)
+}
+
+// GetTaskAttachment mocks base method.
+func (m *MockStore) GetTaskAttachment(ctx context.Context, id int32) (sqlc.TaskAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskAttachment", ctx, id)
+	ret0, _ := ret[0].(sqlc.TaskAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskAttachment indicates an expected call of GetTaskAttachment.
+func (mr *MockStoreMockRecorder) GetTaskAttachment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskAttachment", reflect.TypeOf((*MockStore)(nil).GetTaskAttachment), ctx, id)
+}
+
+// GetTaskComment mocks base method.
+func (m *MockStore) GetTaskComment(ctx context.Context, id int32) (sqlc.TaskComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskComment", ctx, id)
+	ret0, _ := ret[0].(sqlc.TaskComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskComment indicates an expected call of GetTaskComment.
+func (mr *MockStoreMockRecorder) GetTaskComment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskComment", reflect.TypeOf((*MockStore)(nil).GetTaskComment), ctx, id)
+}
+
+// GetTaskCommentForUpdate mocks base method.
+func (m *MockStore) GetTaskCommentForUpdate(ctx context.Context, id int32) (sqlc.TaskComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskCommentForUpdate", ctx, id)
+	ret0, _ := ret[0].(sqlc.TaskComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskCommentForUpdate indicates an expected call of GetTaskCommentForUpdate.
+func (mr *MockStoreMockRecorder) GetTaskCommentForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskCommentForUpdate", reflect.TypeOf((*MockStore)(nil).GetTaskCommentForUpdate), ctx, id)
+}
+
+// GetTaskStatus mocks base method.
+func (m *MockStore) GetTaskStatus(ctx context.Context, id int32) (sqlc.TaskStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskStatus", ctx, id)
+	ret0, _ := ret[0].(sqlc.TaskStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskStatus indicates an expected call of GetTaskStatus.
+func (mr *MockStoreMockRecorder) GetTaskStatus(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskStatus", reflect.TypeOf((*MockStore)(nil).GetTaskStatus), ctx, id)
+}
+
+// GetTaskTemplate mocks base method.
+func (m *MockStore) GetTaskTemplate(ctx context.Context, id int32) (sqlc.TaskTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskTemplate", ctx, id)
+	ret0, _ := ret[0].(sqlc.TaskTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskTemplate indicates an expected call of GetTaskTemplate.
+func (mr *MockStoreMockRecorder) GetTaskTemplate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskTemplate", reflect.TypeOf((*MockStore)(nil).GetTaskTemplate), ctx, id)
+}
+
+// GetTeam mocks base method.
+func (m *MockStore) GetTeam(ctx context.Context, id int32) (sqlc.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeam", ctx, id)
+	ret0, _ := ret[0].(sqlc.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeam indicates an expected call of GetTeam.
+func (mr *MockStoreMockRecorder) GetTeam(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeam", reflect.TypeOf((*MockStore)(nil).GetTeam), ctx, id)
+}
+
+// GetTeamMember mocks base method.
+func (m *MockStore) GetTeamMember(ctx context.Context, arg sqlc.GetTeamMemberParams) (sqlc.TeamMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamMember", ctx, arg)
+	ret0, _ := ret[0].(sqlc.TeamMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeamMember indicates an expected call of GetTeamMember.
+func (mr *MockStoreMockRecorder) GetTeamMember(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamMember", reflect.TypeOf((*MockStore)(nil).GetTeamMember), ctx, arg)
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockStore) GetUserByEmail(ctx context.Context, email string) (sqlc.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", ctx, email)
+	ret0, _ := ret[0].(sqlc.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockStoreMockRecorder) GetUserByEmail(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockStore)(nil).GetUserByEmail), ctx, email)
+}
+
+// GetUserById mocks base method.
+func (m *MockStore) GetUserById(ctx context.Context, id int32) (sqlc.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserById", ctx, id)
+	ret0, _ := ret[0].(sqlc.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserById indicates an expected call of GetUserById.
+func (mr *MockStoreMockRecorder) GetUserById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserById", reflect.TypeOf((*MockStore)(nil).GetUserById), ctx, id)
+}
+
+// GetUserTokenByHash mocks base method.
+func (m *MockStore) GetUserTokenByHash(ctx context.Context, arg sqlc.GetUserTokenByHashParams) (sqlc.UserToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserTokenByHash", ctx, arg)
+	ret0, _ := ret[0].(sqlc.UserToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserTokenByHash indicates an expected call of GetUserTokenByHash.
+func (mr *MockStoreMockRecorder) GetUserTokenByHash(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTokenByHash", reflect.TypeOf((*MockStore)(nil).GetUserTokenByHash), ctx, arg)
+}
+
+// GetWebhook mocks base method.
+func (m *MockStore) GetWebhook(ctx context.Context, id int32) (sqlc.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, id)
+	ret0, _ := ret[0].(sqlc.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockStoreMockRecorder) GetWebhook(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockStore)(nil).GetWebhook), ctx, id)
+}
+
+// GetWebhookDelivery mocks base method.
+func (m *MockStore) GetWebhookDelivery(ctx context.Context, id int64) (sqlc.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDelivery", ctx, id)
+	ret0, _ := ret[0].(sqlc.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDelivery indicates an expected call of GetWebhookDelivery.
+func (mr *MockStoreMockRecorder) GetWebhookDelivery(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDelivery", reflect.TypeOf((*MockStore)(nil).GetWebhookDelivery), ctx, id)
+}
+
+// HasTaskDependencyPath mocks base method.
+func (m *MockStore) HasTaskDependencyPath(ctx context.Context, arg sqlc.HasTaskDependencyPathParams) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasTaskDependencyPath", ctx, arg)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasTaskDependencyPath indicates an expected call of HasTaskDependencyPath.
+func (mr *MockStoreMockRecorder) HasTaskDependencyPath(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasTaskDependencyPath", reflect.TypeOf((*MockStore)(nil).HasTaskDependencyPath), ctx, arg)
+}
+
+// InvalidateUserTokens mocks base method.
+func (m *MockStore) InvalidateUserTokens(ctx context.Context, arg sqlc.InvalidateUserTokensParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateUserTokens", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateUserTokens indicates an expected call of InvalidateUserTokens.
+func (mr *MockStoreMockRecorder) InvalidateUserTokens(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateUserTokens", reflect.TypeOf((*MockStore)(nil).InvalidateUserTokens), ctx, arg)
+}
+
+// IsTaskAncestor mocks base method.
+func (m *MockStore) IsTaskAncestor(ctx context.Context, arg sqlc.IsTaskAncestorParams) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTaskAncestor", ctx, arg)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTaskAncestor indicates an expected call of IsTaskAncestor.
+func (mr *MockStoreMockRecorder) IsTaskAncestor(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTaskAncestor", reflect.TypeOf((*MockStore)(nil).IsTaskAncestor), ctx, arg)
+}
+
+// ListAuditEvents mocks base method.
+func (m *MockStore) ListAuditEvents(ctx context.Context, arg sqlc.ListAuditEventsParams) ([]sqlc.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditEvents", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditEvents indicates an expected call of ListAuditEvents.
+func (mr *MockStoreMockRecorder) ListAuditEvents(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEvents", reflect.TypeOf((*MockStore)(nil).ListAuditEvents), ctx, arg)
+}
+
+// ListBlockedTasks mocks base method.
+func (m *MockStore) ListBlockedTasks(ctx context.Context, blockerID int32) ([]sqlc.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBlockedTasks", ctx, blockerID)
+	ret0, _ := ret[0].([]sqlc.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBlockedTasks indicates an expected call of ListBlockedTasks.
+func (mr *MockStoreMockRecorder) ListBlockedTasks(ctx, blockerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBlockedTasks", reflect.TypeOf((*MockStore)(nil).ListBlockedTasks), ctx, blockerID)
+}
+
+// ListBlockerGraph mocks base method.
+func (m *MockStore) ListBlockerGraph(ctx context.Context, taskID int32) ([]sqlc.ListBlockerGraphRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBlockerGraph", ctx, taskID)
+	ret0, _ := ret[0].([]sqlc.ListBlockerGraphRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBlockerGraph indicates an expected call of ListBlockerGraph.
+func (mr *MockStoreMockRecorder) ListBlockerGraph(ctx, taskID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBlockerGraph", reflect.TypeOf((*MockStore)(nil).ListBlockerGraph), ctx, taskID)
+}
+
+// ListClaims mocks base method.
+func (m *MockStore) ListClaims(ctx context.Context) ([]sqlc.Claim, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClaims", ctx)
+	ret0, _ := ret[0].([]sqlc.Claim)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClaims indicates an expected call of ListClaims.
+func (mr *MockStoreMockRecorder) ListClaims(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClaims", reflect.TypeOf((*MockStore)(nil).ListClaims), ctx)
+}
+
+// ListDigestRecipients mocks base method.
+func (m *MockStore) ListDigestRecipients(ctx context.Context, arg sqlc.ListDigestRecipientsParams) ([]sqlc.ListDigestRecipientsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDigestRecipients", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.ListDigestRecipientsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDigestRecipients indicates an expected call of ListDigestRecipients.
+func (mr *MockStoreMockRecorder) ListDigestRecipients(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDigestRecipients", reflect.TypeOf((*MockStore)(nil).ListDigestRecipients), ctx, arg)
+}
+
+// ListDueTaskTemplates mocks base method.
+func (m *MockStore) ListDueTaskTemplates(ctx context.Context, arg sqlc.ListDueTaskTemplatesParams) ([]sqlc.TaskTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueTaskTemplates", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.TaskTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueTaskTemplates indicates an expected call of ListDueTaskTemplates.
+func (mr *MockStoreMockRecorder) ListDueTaskTemplates(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueTaskTemplates", reflect.TypeOf((*MockStore)(nil).ListDueTaskTemplates), ctx, arg)
+}
+
+// ListNotifications mocks base method.
+func (m *MockStore) ListNotifications(ctx context.Context, arg sqlc.ListNotificationsParams) ([]sqlc.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotifications", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotifications indicates an expected call of ListNotifications.
+func (mr *MockStoreMockRecorder) ListNotifications(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotifications", reflect.TypeOf((*MockStore)(nil).ListNotifications), ctx, arg)
+}
+
+// ListOpenBlockers mocks base method.
+func (m *MockStore) ListOpenBlockers(ctx context.Context, blockedID int32) ([]sqlc.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOpenBlockers", ctx, blockedID)
+	ret0, _ := ret[0].([]sqlc.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOpenBlockers indicates an expected call of ListOpenBlockers.
+func (mr *MockStoreMockRecorder) ListOpenBlockers(ctx, blockedID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOpenBlockers", reflect.TypeOf((*MockStore)(nil).ListOpenBlockers), ctx, blockedID)
+}
+
+// ListOutboxEventsAfter mocks base method.
+func (m *MockStore) ListOutboxEventsAfter(ctx context.Context, arg sqlc.ListOutboxEventsAfterParams) ([]sqlc.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOutboxEventsAfter", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOutboxEventsAfter indicates an expected call of ListOutboxEventsAfter.
+func (mr *MockStoreMockRecorder) ListOutboxEventsAfter(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutboxEventsAfter", reflect.TypeOf((*MockStore)(nil).ListOutboxEventsAfter), ctx, arg)
+}
+
+// ListRoleClaims mocks base method.
+func (m *MockStore) ListRoleClaims(ctx context.Context, roleID int32) ([]sqlc.Claim, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoleClaims", ctx, roleID)
+	ret0, _ := ret[0].([]sqlc.Claim)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRoleClaims indicates an expected call of ListRoleClaims.
+func (mr *MockStoreMockRecorder) ListRoleClaims(ctx, roleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoleClaims", reflect.TypeOf((*MockStore)(nil).ListRoleClaims), ctx, roleID)
+}
+
+// ListRoles mocks base method.
+func (m *MockStore) ListRoles(ctx context.Context) ([]sqlc.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoles", ctx)
+	ret0, _ := ret[0].([]sqlc.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRoles indicates an expected call of ListRoles.
+func (mr *MockStoreMockRecorder) ListRoles(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoles", reflect.TypeOf((*MockStore)(nil).ListRoles), ctx)
+}
+
+// ListSubtaskTree mocks base method.
+func (m *MockStore) ListSubtaskTree(ctx context.Context, taskID int32) ([]sqlc.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubtaskTree", ctx, taskID)
+	ret0, _ := ret[0].([]sqlc.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubtaskTree indicates an expected call of ListSubtaskTree.
+func (mr *MockStoreMockRecorder) ListSubtaskTree(ctx, taskID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubtaskTree", reflect.TypeOf((*MockStore)(nil).ListSubtaskTree), ctx, taskID)
+}
+
+// ListSubtasks mocks base method.
+func (m *MockStore) ListSubtasks(ctx context.Context, parentID int32) ([]sqlc.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubtasks", ctx, parentID)
+	ret0, _ := ret[0].([]sqlc.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubtasks indicates an expected call of ListSubtasks.
+func (mr *MockStoreMockRecorder) ListSubtasks(ctx, parentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubtasks", reflect.TypeOf((*MockStore)(nil).ListSubtasks), ctx, parentID)
+}
+
+// ListTaskAssignees mocks base method.
+func (m *MockStore) ListTaskAssignees(ctx context.Context, taskID int32) ([]sqlc.ListTaskAssigneesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaskAssignees", ctx, taskID)
+	ret0, _ := ret[0].([]sqlc.ListTaskAssigneesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaskAssignees indicates an expected call of ListTaskAssignees.
+func (mr *MockStoreMockRecorder) ListTaskAssignees(ctx, taskID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaskAssignees", reflect.TypeOf((*MockStore)(nil).ListTaskAssignees), ctx, taskID)
+}
+
+// ListTaskAttachments mocks base method.
+func (m *MockStore) ListTaskAttachments(ctx context.Context, taskID int32) ([]sqlc.TaskAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaskAttachments", ctx, taskID)
+	ret0, _ := ret[0].([]sqlc.TaskAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaskAttachments indicates an expected call of ListTaskAttachments.
+func (mr *MockStoreMockRecorder) ListTaskAttachments(ctx, taskID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaskAttachments", reflect.TypeOf((*MockStore)(nil).ListTaskAttachments), ctx, taskID)
+}
+
+// ListTaskCommentMentions mocks base method.
+func (m *MockStore) ListTaskCommentMentions(ctx context.Context, commentIds []int32) ([]sqlc.ListTaskCommentMentionsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaskCommentMentions", ctx, commentIds)
+	ret0, _ := ret[0].([]sqlc.ListTaskCommentMentionsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaskCommentMentions indicates an expected call of ListTaskCommentMentions.
+func (mr *MockStoreMockRecorder) ListTaskCommentMentions(ctx, commentIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaskCommentMentions", reflect.TypeOf((*MockStore)(nil).ListTaskCommentMentions), ctx, commentIds)
+}
+
+// ListTaskCommentRevisions mocks base method.
+func (m *MockStore) ListTaskCommentRevisions(ctx context.Context, commentID int32) ([]sqlc.TaskCommentRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaskCommentRevisions", ctx, commentID)
+	ret0, _ := ret[0].([]sqlc.TaskCommentRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaskCommentRevisions indicates an expected call of ListTaskCommentRevisions.
+func (mr *MockStoreMockRecorder) ListTaskCommentRevisions(ctx, commentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaskCommentRevisions", reflect.TypeOf((*MockStore)(nil).ListTaskCommentRevisions), ctx, commentID)
+}
+
+// ListTaskComments mocks base method.
+func (m *MockStore) ListTaskComments(ctx context.Context, taskID int32) ([]sqlc.TaskComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaskComments", ctx, taskID)
+	ret0, _ := ret[0].([]sqlc.TaskComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaskComments indicates an expected call of ListTaskComments.
+func (mr *MockStoreMockRecorder) ListTaskComments(ctx, taskID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaskComments", reflect.TypeOf((*MockStore)(nil).ListTaskComments), ctx, taskID)
+}
+
+// ListTaskStatusTransitions mocks base method.
+func (m *MockStore) ListTaskStatusTransitions(ctx context.Context, teamID *int32) ([]sqlc.TaskStatusTransition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaskStatusTransitions", ctx, teamID)
+	ret0, _ := ret[0].([]sqlc.TaskStatusTransition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaskStatusTransitions indicates an expected call of ListTaskStatusTransitions.
+func (mr *MockStoreMockRecorder) ListTaskStatusTransitions(ctx, teamID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaskStatusTransitions", reflect.TypeOf((*MockStore)(nil).ListTaskStatusTransitions), ctx, teamID)
+}
+
+// ListTaskStatuses mocks base method.
+func (m *MockStore) ListTaskStatuses(ctx context.Context, teamID *int32) ([]sqlc.TaskStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaskStatuses", ctx, teamID)
+	ret0, _ := ret[0].([]sqlc.TaskStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaskStatuses indicates an expected call of ListTaskStatuses.
+func (mr *MockStoreMockRecorder) ListTaskStatuses(ctx, teamID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaskStatuses", reflect.TypeOf((*MockStore)(nil).ListTaskStatuses), ctx, teamID)
+}
+
+// ListTaskTemplates mocks base method.
+func (m *MockStore) ListTaskTemplates(ctx context.Context, arg sqlc.ListTaskTemplatesParams) ([]sqlc.TaskTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaskTemplates", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.TaskTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaskTemplates indicates an expected call of ListTaskTemplates.
+func (mr *MockStoreMockRecorder) ListTaskTemplates(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaskTemplates", reflect.TypeOf((*MockStore)(nil).ListTaskTemplates), ctx, arg)
+}
+
+// ListTaskWatchers mocks base method.
+func (m *MockStore) ListTaskWatchers(ctx context.Context, taskID int32) ([]sqlc.ListTaskWatchersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaskWatchers", ctx, taskID)
+	ret0, _ := ret[0].([]sqlc.ListTaskWatchersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaskWatchers indicates an expected call of ListTaskWatchers.
+func (mr *MockStoreMockRecorder) ListTaskWatchers(ctx, taskID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaskWatchers", reflect.TypeOf((*MockStore)(nil).ListTaskWatchers), ctx, taskID)
+}
+
+// ListTasks mocks base method.
+func (m *MockStore) ListTasks(ctx context.Context, arg sqlc.ListTasksParams) ([]sqlc.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTasks", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTasks indicates an expected call of ListTasks.
+func (mr *MockStoreMockRecorder) ListTasks(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasks", reflect.TypeOf((*MockStore)(nil).ListTasks), ctx, arg)
+}
+
+// ListTasksByIDs mocks base method.
+func (m *MockStore) ListTasksByIDs(ctx context.Context, ids []int32) ([]sqlc.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTasksByIDs", ctx, ids)
+	ret0, _ := ret[0].([]sqlc.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTasksByIDs indicates an expected call of ListTasksByIDs.
+func (mr *MockStoreMockRecorder) ListTasksByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasksByIDs", reflect.TypeOf((*MockStore)(nil).ListTasksByIDs), ctx, ids)
+}
+
+// ListTeamAttachmentKeys mocks base method.
+func (m *MockStore) ListTeamAttachmentKeys(ctx context.Context, teamID int32) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTeamAttachmentKeys", ctx, teamID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTeamAttachmentKeys indicates an expected call of ListTeamAttachmentKeys.
+func (mr *MockStoreMockRecorder) ListTeamAttachmentKeys(ctx, teamID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTeamAttachmentKeys", reflect.TypeOf((*MockStore)(nil).ListTeamAttachmentKeys), ctx, teamID)
+}
+
+// ListTeamMembers mocks base method.
+func (m *MockStore) ListTeamMembers(ctx context.Context, teamID int32) ([]sqlc.ListTeamMembersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTeamMembers", ctx, teamID)
+	ret0, _ := ret[0].([]sqlc.ListTeamMembersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTeamMembers indicates an expected call of ListTeamMembers.
+func (mr *MockStoreMockRecorder) ListTeamMembers(ctx, teamID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTeamMembers", reflect.TypeOf((*MockStore)(nil).ListTeamMembers), ctx, teamID)
+}
+
+// ListTeamOutboxEventsAfter mocks base method.
+func (m *MockStore) ListTeamOutboxEventsAfter(ctx context.Context, arg sqlc.ListTeamOutboxEventsAfterParams) ([]sqlc.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTeamOutboxEventsAfter", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTeamOutboxEventsAfter indicates an expected call of ListTeamOutboxEventsAfter.
+func (mr *MockStoreMockRecorder) ListTeamOutboxEventsAfter(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTeamOutboxEventsAfter", reflect.TypeOf((*MockStore)(nil).ListTeamOutboxEventsAfter), ctx, arg)
+}
+
+// ListTeamTaskIDs mocks base method.
+func (m *MockStore) ListTeamTaskIDs(ctx context.Context, teamID int32) ([]int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTeamTaskIDs", ctx, teamID)
+	ret0, _ := ret[0].([]int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTeamTaskIDs indicates an expected call of ListTeamTaskIDs.
+func (mr *MockStoreMockRecorder) ListTeamTaskIDs(ctx, teamID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTeamTaskIDs", reflect.TypeOf((*MockStore)(nil).ListTeamTaskIDs), ctx, teamID)
+}
+
+// ListTeamWebhooks mocks base method.
+func (m *MockStore) ListTeamWebhooks(ctx context.Context, teamID int32) ([]sqlc.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTeamWebhooks", ctx, teamID)
+	ret0, _ := ret[0].([]sqlc.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTeamWebhooks indicates an expected call of ListTeamWebhooks.
+func (mr *MockStoreMockRecorder) ListTeamWebhooks(ctx, teamID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTeamWebhooks", reflect.TypeOf((*MockStore)(nil).ListTeamWebhooks), ctx, teamID)
+}
+
+// ListTeamsByUser mocks base method.
+func (m *MockStore) ListTeamsByUser(ctx context.Context, userID int32) ([]sqlc.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTeamsByUser", ctx, userID)
+	ret0, _ := ret[0].([]sqlc.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTeamsByUser indicates an expected call of ListTeamsByUser.
+func (mr *MockStoreMockRecorder) ListTeamsByUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTeamsByUser", reflect.TypeOf((*MockStore)(nil).ListTeamsByUser), ctx, userID)
+}
+
+// ListUnreadNotificationsSince mocks base method.
+func (m *MockStore) ListUnreadNotificationsSince(ctx context.Context, arg sqlc.ListUnreadNotificationsSinceParams) ([]sqlc.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnreadNotificationsSince", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnreadNotificationsSince indicates an expected call of ListUnreadNotificationsSince.
+func (mr *MockStoreMockRecorder) ListUnreadNotificationsSince(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnreadNotificationsSince", reflect.TypeOf((*MockStore)(nil).ListUnreadNotificationsSince), ctx, arg)
+}
+
+// ListUserClaims mocks base method.
+func (m *MockStore) ListUserClaims(ctx context.Context, userID int32) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserClaims", ctx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserClaims indicates an expected call of ListUserClaims.
+func (mr *MockStoreMockRecorder) ListUserClaims(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserClaims", reflect.TypeOf((*MockStore)(nil).ListUserClaims), ctx, userID)
+}
+
+// ListUserRoles mocks base method.
+func (m *MockStore) ListUserRoles(ctx context.Context, userID int32) ([]sqlc.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserRoles", ctx, userID)
+	ret0, _ := ret[0].([]sqlc.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserRoles indicates an expected call of ListUserRoles.
+func (mr *MockStoreMockRecorder) ListUserRoles(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserRoles", reflect.TypeOf((*MockStore)(nil).ListUserRoles), ctx, userID)
+}
+
+// ListUsersByEmails mocks base method.
+func (m *MockStore) ListUsersByEmails(ctx context.Context, emails []string) ([]sqlc.ListUsersByEmailsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsersByEmails", ctx, emails)
+	ret0, _ := ret[0].([]sqlc.ListUsersByEmailsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsersByEmails indicates an expected call of ListUsersByEmails.
+func (mr *MockStoreMockRecorder) ListUsersByEmails(ctx, emails any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsersByEmails", reflect.TypeOf((*MockStore)(nil).ListUsersByEmails), ctx, emails)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockStore) ListWebhookDeliveries(ctx context.Context, arg sqlc.ListWebhookDeliveriesParams) ([]sqlc.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ListWebhookDeliveries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ListWebhookDeliveries), ctx, arg)
+}
+
+// ListWebhooksForEvent mocks base method.
+func (m *MockStore) ListWebhooksForEvent(ctx context.Context, arg sqlc.ListWebhooksForEventParams) ([]sqlc.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooksForEvent", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooksForEvent indicates an expected call of ListWebhooksForEvent.
+func (mr *MockStoreMockRecorder) ListWebhooksForEvent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooksForEvent", reflect.TypeOf((*MockStore)(nil).ListWebhooksForEvent), ctx, arg)
+}
+
+// MarkAllNotificationsRead mocks base method.
+func (m *MockStore) MarkAllNotificationsRead(ctx context.Context, userID int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllNotificationsRead", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAllNotificationsRead indicates an expected call of MarkAllNotificationsRead.
+func (mr *MockStoreMockRecorder) MarkAllNotificationsRead(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsRead", reflect.TypeOf((*MockStore)(nil).MarkAllNotificationsRead), ctx, userID)
+}
+
+// MarkDigestSent mocks base method.
+func (m *MockStore) MarkDigestSent(ctx context.Context, arg sqlc.MarkDigestSentParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDigestSent", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDigestSent indicates an expected call of MarkDigestSent.
+func (mr *MockStoreMockRecorder) MarkDigestSent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDigestSent", reflect.TypeOf((*MockStore)(nil).MarkDigestSent), ctx, arg)
+}
+
+// MarkNotificationRead mocks base method.
+func (m *MockStore) MarkNotificationRead(ctx context.Context, arg sqlc.MarkNotificationReadParams) (sqlc.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationRead", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationRead indicates an expected call of MarkNotificationRead.
+func (mr *MockStoreMockRecorder) MarkNotificationRead(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MockStore)(nil).MarkNotificationRead), ctx, arg)
+}
+
+// MarkNotificationUnread mocks base method.
+func (m *MockStore) MarkNotificationUnread(ctx context.Context, arg sqlc.MarkNotificationUnreadParams) (sqlc.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationUnread", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationUnread indicates an expected call of MarkNotificationUnread.
+func (mr *MockStoreMockRecorder) MarkNotificationUnread(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationUnread", reflect.TypeOf((*MockStore)(nil).MarkNotificationUnread), ctx, arg)
+}
+
+// MarkOutboxEventDispatched mocks base method.
+func (m *MockStore) MarkOutboxEventDispatched(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxEventDispatched", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxEventDispatched indicates an expected call of MarkOutboxEventDispatched.
+func (mr *MockStoreMockRecorder) MarkOutboxEventDispatched(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxEventDispatched", reflect.TypeOf((*MockStore)(nil).MarkOutboxEventDispatched), ctx, id)
+}
+
+// MarkUserEmailVerified mocks base method.
+func (m *MockStore) MarkUserEmailVerified(ctx context.Context, arg sqlc.MarkUserEmailVerifiedParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkUserEmailVerified", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkUserEmailVerified indicates an expected call of MarkUserEmailVerified.
+func (mr *MockStoreMockRecorder) MarkUserEmailVerified(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkUserEmailVerified", reflect.TypeOf((*MockStore)(nil).MarkUserEmailVerified), ctx, arg)
+}
+
+// MaterializeTaskOccurrence mocks base method.
+func (m *MockStore) MaterializeTaskOccurrence(ctx context.Context, arg sqlc.MaterializeTaskOccurrenceParams) (sqlc.TaskOccurrenceResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaterializeTaskOccurrence", ctx, arg)
+	ret0, _ := ret[0].(sqlc.TaskOccurrenceResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockStoreMockRecorder) CreateUser(ctx, arg any) *gomock.Call {
+// MaterializeTaskOccurrence indicates an expected call of MaterializeTaskOccurrence.
+func (mr *MockStoreMockRecorder) MaterializeTaskOccurrence(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaterializeTaskOccurrence", reflect.TypeOf((*MockStore)(nil).MaterializeTaskOccurrence), ctx, arg)
 }
 
-// DeleteUser mocks base method.
-func (m *MockStore) DeleteUser(ctx context.Context, id int32) error {
+// RecordWebhookDeliveryAttempt mocks base method.
+func (m *MockStore) RecordWebhookDeliveryAttempt(ctx context.Context, arg sqlc.RecordWebhookDeliveryAttemptParams) (sqlc.WebhookDelivery, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", ctx, id)
+	ret := m.ctrl.Call(m, "RecordWebhookDeliveryAttempt", ctx, arg)
+	ret0, _ := ret[0].(sqlc.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordWebhookDeliveryAttempt indicates an expected call of RecordWebhookDeliveryAttempt.
+func (mr *MockStoreMockRecorder) RecordWebhookDeliveryAttempt(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWebhookDeliveryAttempt", reflect.TypeOf((*MockStore)(nil).RecordWebhookDeliveryAttempt), ctx, arg)
+}
+
+// RecordWebhookFailure mocks base method.
+func (m *MockStore) RecordWebhookFailure(ctx context.Context, arg sqlc.RecordWebhookFailureParams) (sqlc.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWebhookFailure", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordWebhookFailure indicates an expected call of RecordWebhookFailure.
+func (mr *MockStoreMockRecorder) RecordWebhookFailure(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWebhookFailure", reflect.TypeOf((*MockStore)(nil).RecordWebhookFailure), ctx, arg)
+}
+
+// RemoveRoleClaim mocks base method.
+func (m *MockStore) RemoveRoleClaim(ctx context.Context, arg sqlc.RemoveRoleClaimParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveRoleClaim", ctx, arg)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteUser indicates an expected call of DeleteUser.
-func (mr *MockStoreMockRecorder) DeleteUser(ctx, id any) *gomock.Call {
+// RemoveRoleClaim indicates an expected call of RemoveRoleClaim.
+func (mr *MockStoreMockRecorder) RemoveRoleClaim(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockStore)(nil).DeleteUser), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRoleClaim", reflect.TypeOf((*MockStore)(nil).RemoveRoleClaim), ctx, arg)
 }
 
-// GetUserByEmail mocks base method.
-func (m *MockStore) GetUserByEmail(ctx context.Context, email string) (sqlc.User, error) {
+// RemoveTaskAssignee mocks base method.
+func (m *MockStore) RemoveTaskAssignee(ctx context.Context, arg sqlc.RemoveTaskAssigneeParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByEmail", ctx, email)
-	ret0, _ := ret[0].(sqlc.User)
+	ret := m.ctrl.Call(m, "RemoveTaskAssignee", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveTaskAssignee indicates an expected call of RemoveTaskAssignee.
+func (mr *MockStoreMockRecorder) RemoveTaskAssignee(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTaskAssignee", reflect.TypeOf((*MockStore)(nil).RemoveTaskAssignee), ctx, arg)
+}
+
+// RemoveTaskWatcher mocks base method.
+func (m *MockStore) RemoveTaskWatcher(ctx context.Context, arg sqlc.RemoveTaskWatcherParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTaskWatcher", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveTaskWatcher indicates an expected call of RemoveTaskWatcher.
+func (mr *MockStoreMockRecorder) RemoveTaskWatcher(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTaskWatcher", reflect.TypeOf((*MockStore)(nil).RemoveTaskWatcher), ctx, arg)
+}
+
+// RemoveTeamMember mocks base method.
+func (m *MockStore) RemoveTeamMember(ctx context.Context, arg sqlc.RemoveTeamMemberParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveTeamMember", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveTeamMember indicates an expected call of RemoveTeamMember.
+func (mr *MockStoreMockRecorder) RemoveTeamMember(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTeamMember", reflect.TypeOf((*MockStore)(nil).RemoveTeamMember), ctx, arg)
+}
+
+// RemoveUserRole mocks base method.
+func (m *MockStore) RemoveUserRole(ctx context.Context, arg sqlc.RemoveUserRoleParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveUserRole", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveUserRole indicates an expected call of RemoveUserRole.
+func (mr *MockStoreMockRecorder) RemoveUserRole(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUserRole", reflect.TypeOf((*MockStore)(nil).RemoveUserRole), ctx, arg)
+}
+
+// ReplaceSession mocks base method.
+func (m *MockStore) ReplaceSession(ctx context.Context, arg sqlc.ReplaceSessionParams) (sqlc.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceSession", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Session)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserByEmail indicates an expected call of GetUserByEmail.
-func (mr *MockStoreMockRecorder) GetUserByEmail(ctx, email any) *gomock.Call {
+// ReplaceSession indicates an expected call of ReplaceSession.
+func (mr *MockStoreMockRecorder) ReplaceSession(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockStore)(nil).GetUserByEmail), ctx, email)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceSession", reflect.TypeOf((*MockStore)(nil).ReplaceSession), ctx, arg)
 }
 
-// GetUserById mocks base method.
-func (m *MockStore) GetUserById(ctx context.Context, id int32) (sqlc.User, error) {
+// ResetWebhookFailures mocks base method.
+func (m *MockStore) ResetWebhookFailures(ctx context.Context, id int32) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserById", ctx, id)
-	ret0, _ := ret[0].(sqlc.User)
+	ret := m.ctrl.Call(m, "ResetWebhookFailures", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetWebhookFailures indicates an expected call of ResetWebhookFailures.
+func (mr *MockStoreMockRecorder) ResetWebhookFailures(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetWebhookFailures", reflect.TypeOf((*MockStore)(nil).ResetWebhookFailures), ctx, id)
+}
+
+// RetryOutboxEvent mocks base method.
+func (m *MockStore) RetryOutboxEvent(ctx context.Context, arg sqlc.RetryOutboxEventParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryOutboxEvent", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryOutboxEvent indicates an expected call of RetryOutboxEvent.
+func (mr *MockStoreMockRecorder) RetryOutboxEvent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryOutboxEvent", reflect.TypeOf((*MockStore)(nil).RetryOutboxEvent), ctx, arg)
+}
+
+// RevokeSessionFamily mocks base method.
+func (m *MockStore) RevokeSessionFamily(ctx context.Context, familyID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSessionFamily", ctx, familyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSessionFamily indicates an expected call of RevokeSessionFamily.
+func (mr *MockStoreMockRecorder) RevokeSessionFamily(ctx, familyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSessionFamily", reflect.TypeOf((*MockStore)(nil).RevokeSessionFamily), ctx, familyID)
+}
+
+// RevokeUserSessions mocks base method.
+func (m *MockStore) RevokeUserSessions(ctx context.Context, userID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserSessions", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserSessions indicates an expected call of RevokeUserSessions.
+func (mr *MockStoreMockRecorder) RevokeUserSessions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserSessions", reflect.TypeOf((*MockStore)(nil).RevokeUserSessions), ctx, userID)
+}
+
+// RotateSession mocks base method.
+func (m *MockStore) RotateSession(ctx context.Context, id uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateSession", ctx, id)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserById indicates an expected call of GetUserById.
-func (mr *MockStoreMockRecorder) GetUserById(ctx, id any) *gomock.Call {
+// RotateSession indicates an expected call of RotateSession.
+func (mr *MockStoreMockRecorder) RotateSession(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserById", reflect.TypeOf((*MockStore)(nil).GetUserById), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateSession", reflect.TypeOf((*MockStore)(nil).RotateSession), ctx, id)
+}
+
+// SearchTaskComments mocks base method.
+func (m *MockStore) SearchTaskComments(ctx context.Context, arg sqlc.SearchTaskCommentsParams) ([]sqlc.SearchTaskCommentsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTaskComments", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.SearchTaskCommentsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTaskComments indicates an expected call of SearchTaskComments.
+func (mr *MockStoreMockRecorder) SearchTaskComments(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTaskComments", reflect.TypeOf((*MockStore)(nil).SearchTaskComments), ctx, arg)
+}
+
+// SearchTasks mocks base method.
+func (m *MockStore) SearchTasks(ctx context.Context, arg sqlc.SearchTasksParams) ([]sqlc.SearchTasksRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTasks", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.SearchTasksRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTasks indicates an expected call of SearchTasks.
+func (mr *MockStoreMockRecorder) SearchTasks(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTasks", reflect.TypeOf((*MockStore)(nil).SearchTasks), ctx, arg)
+}
+
+// SearchTeams mocks base method.
+func (m *MockStore) SearchTeams(ctx context.Context, arg sqlc.SearchTeamsParams) ([]sqlc.SearchTeamsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTeams", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.SearchTeamsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTeams indicates an expected call of SearchTeams.
+func (mr *MockStoreMockRecorder) SearchTeams(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTeams", reflect.TypeOf((*MockStore)(nil).SearchTeams), ctx, arg)
+}
+
+// SetTaskParent mocks base method.
+func (m *MockStore) SetTaskParent(ctx context.Context, arg sqlc.SetTaskParentParams) (sqlc.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTaskParent", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetTaskParent indicates an expected call of SetTaskParent.
+func (mr *MockStoreMockRecorder) SetTaskParent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTaskParent", reflect.TypeOf((*MockStore)(nil).SetTaskParent), ctx, arg)
+}
+
+// TransferTeamOwnership mocks base method.
+func (m *MockStore) TransferTeamOwnership(ctx context.Context, arg sqlc.TransferTeamOwnershipParams) (sqlc.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferTeamOwnership", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferTeamOwnership indicates an expected call of TransferTeamOwnership.
+func (mr *MockStoreMockRecorder) TransferTeamOwnership(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferTeamOwnership", reflect.TypeOf((*MockStore)(nil).TransferTeamOwnership), ctx, arg)
+}
+
+// TransitionTask mocks base method.
+func (m *MockStore) TransitionTask(ctx context.Context, arg sqlc.TransitionTaskParams) (sqlc.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransitionTask", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransitionTask indicates an expected call of TransitionTask.
+func (mr *MockStoreMockRecorder) TransitionTask(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionTask", reflect.TypeOf((*MockStore)(nil).TransitionTask), ctx, arg)
+}
+
+// UpdateRole mocks base method.
+func (m *MockStore) UpdateRole(ctx context.Context, arg sqlc.UpdateRoleParams) (sqlc.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockStoreMockRecorder) UpdateRole(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockStore)(nil).UpdateRole), ctx, arg)
+}
+
+// UpdateTask mocks base method.
+func (m *MockStore) UpdateTask(ctx context.Context, arg sqlc.UpdateTaskParams) (sqlc.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTask", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTask indicates an expected call of UpdateTask.
+func (mr *MockStoreMockRecorder) UpdateTask(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockStore)(nil).UpdateTask), ctx, arg)
+}
+
+// UpdateTaskAssignees mocks base method.
+func (m *MockStore) UpdateTaskAssignees(ctx context.Context, arg sqlc.UpdateTaskAssigneesParams) ([]sqlc.ListTaskAssigneesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTaskAssignees", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.ListTaskAssigneesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTaskAssignees indicates an expected call of UpdateTaskAssignees.
+func (mr *MockStoreMockRecorder) UpdateTaskAssignees(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTaskAssignees", reflect.TypeOf((*MockStore)(nil).UpdateTaskAssignees), ctx, arg)
+}
+
+// UpdateTaskComment mocks base method.
+func (m *MockStore) UpdateTaskComment(ctx context.Context, arg sqlc.UpdateTaskCommentParams) (sqlc.TaskComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTaskComment", ctx, arg)
+	ret0, _ := ret[0].(sqlc.TaskComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTaskComment indicates an expected call of UpdateTaskComment.
+func (mr *MockStoreMockRecorder) UpdateTaskComment(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTaskComment", reflect.TypeOf((*MockStore)(nil).UpdateTaskComment), ctx, arg)
+}
+
+// UpdateTaskParent mocks base method.
+func (m *MockStore) UpdateTaskParent(ctx context.Context, arg sqlc.UpdateTaskParentParams) (sqlc.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTaskParent", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTaskParent indicates an expected call of UpdateTaskParent.
+func (mr *MockStoreMockRecorder) UpdateTaskParent(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTaskParent", reflect.TypeOf((*MockStore)(nil).UpdateTaskParent), ctx, arg)
+}
+
+// UpdateTaskStatus mocks base method.
+func (m *MockStore) UpdateTaskStatus(ctx context.Context, arg sqlc.UpdateTaskStatusParams) (sqlc.TaskStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTaskStatus", ctx, arg)
+	ret0, _ := ret[0].(sqlc.TaskStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTaskStatus indicates an expected call of UpdateTaskStatus.
+func (mr *MockStoreMockRecorder) UpdateTaskStatus(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTaskStatus", reflect.TypeOf((*MockStore)(nil).UpdateTaskStatus), ctx, arg)
+}
+
+// UpdateTeam mocks base method.
+func (m *MockStore) UpdateTeam(ctx context.Context, arg sqlc.UpdateTeamParams) (sqlc.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTeam", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTeam indicates an expected call of UpdateTeam.
+func (mr *MockStoreMockRecorder) UpdateTeam(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTeam", reflect.TypeOf((*MockStore)(nil).UpdateTeam), ctx, arg)
+}
+
+// UpdateTeamOwner mocks base method.
+func (m *MockStore) UpdateTeamOwner(ctx context.Context, arg sqlc.UpdateTeamOwnerParams) (sqlc.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTeamOwner", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTeamOwner indicates an expected call of UpdateTeamOwner.
+func (mr *MockStoreMockRecorder) UpdateTeamOwner(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTeamOwner", reflect.TypeOf((*MockStore)(nil).UpdateTeamOwner), ctx, arg)
 }
 
 // UpdateUser mocks base method.
@@ -128,3 +2342,63 @@ func (mr *MockStoreMockRecorder) UpdateUserPassword(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPassword", reflect.TypeOf((*MockStore)(nil).UpdateUserPassword), ctx, arg)
 }
+
+// UpdateUserProfile mocks base method.
+func (m *MockStore) UpdateUserProfile(ctx context.Context, arg sqlc.UpdateUserProfileParams) (sqlc.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserProfile", ctx, arg)
+	ret0, _ := ret[0].(sqlc.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserProfile indicates an expected call of UpdateUserProfile.
+func (mr *MockStoreMockRecorder) UpdateUserProfile(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserProfile", reflect.TypeOf((*MockStore)(nil).UpdateUserProfile), ctx, arg)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockStore) UpdateWebhook(ctx context.Context, arg sqlc.UpdateWebhookParams) (sqlc.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockStoreMockRecorder) UpdateWebhook(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockStore)(nil).UpdateWebhook), ctx, arg)
+}
+
+// UpsertNotificationPreferences mocks base method.
+func (m *MockStore) UpsertNotificationPreferences(ctx context.Context, arg sqlc.UpsertNotificationPreferencesParams) (sqlc.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertNotificationPreferences", ctx, arg)
+	ret0, _ := ret[0].(sqlc.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertNotificationPreferences indicates an expected call of UpsertNotificationPreferences.
+func (mr *MockStoreMockRecorder) UpsertNotificationPreferences(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertNotificationPreferences", reflect.TypeOf((*MockStore)(nil).UpsertNotificationPreferences), ctx, arg)
+}
+
+// UseUserToken mocks base method.
+func (m *MockStore) UseUserToken(ctx context.Context, id int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseUserToken", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseUserToken indicates an expected call of UseUserToken.
+func (mr *MockStoreMockRecorder) UseUserToken(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseUserToken", reflect.TypeOf((*MockStore)(nil).UseUserToken), ctx, id)
+}
@@ -0,0 +1,21 @@
+-- name: GetClaim :one
+select * from claims where id = $1;
+
+-- name: GetClaimByName :one
+select * from claims where name = $1;
+
+-- name: ListClaims :many
+select * from claims order by name;
+
+-- name: ListRoleClaims :many
+select c.* from claims c
+join role_claims rc on rc.claim_id = c.id
+where rc.role_id = $1
+order by c.name;
+
+-- name: ListUserClaims :many
+select distinct c.name from claims c
+join role_claims rc on rc.claim_id = c.id
+join user_roles ur on ur.role_id = rc.role_id
+where ur.user_id = $1
+order by c.name;
\ No newline at end of file
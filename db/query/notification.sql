@@ -0,0 +1,79 @@
+-- name: CreateNotifications :exec
+insert into notifications (user_id, type, task_id, comment_id, actor_id, event_id, task_title)
+select u.id, sqlc.arg(type)::text, t.id, sqlc.narg(comment_id)::int, sqlc.narg(actor_id)::int, sqlc.arg(event_id)::bigint, t.title
+from users u
+join tasks t on t.id = sqlc.arg(task_id)
+left join notification_preferences p on p.user_id = u.id
+where u.id = any(sqlc.arg(user_ids)::int[])
+	and coalesce(case sqlc.arg(type)::text
+		when 'task_assigned' then p.task_assigned
+		when 'mentioned' then p.mentioned
+		when 'watched_task_changed' then p.watched_task_changed
+	end, true)
+on conflict (user_id, event_id) do nothing;
+
+-- name: ListNotifications :many
+select * from notifications
+where user_id = sqlc.arg(user_id)
+	and (not sqlc.arg(unread_only)::bool or read_at is null)
+	and (sqlc.narg(before_id)::bigint is null or id < sqlc.narg(before_id))
+order by id desc
+limit sqlc.arg('limit');
+
+-- name: ListUnreadNotificationsSince :many
+select * from notifications
+where user_id = sqlc.arg(user_id)
+	and read_at is null
+	and created_at > sqlc.arg(since)
+order by id
+limit sqlc.arg('limit');
+
+-- name: CountUnreadNotifications :one
+select count(*) from notifications
+where user_id = $1 and read_at is null;
+
+-- name: MarkNotificationRead :one
+update notifications
+	set read_at = coalesce(read_at, now())
+where id = $1 and user_id = $2
+returning *;
+
+-- name: MarkNotificationUnread :one
+update notifications
+	set read_at = null
+where id = $1 and user_id = $2
+returning *;
+
+-- name: MarkAllNotificationsRead :execrows
+update notifications
+	set read_at = now()
+where user_id = $1 and read_at is null;
+
+-- name: GetNotificationPreferences :one
+select * from notification_preferences where user_id = $1;
+
+-- name: UpsertNotificationPreferences :one
+insert into notification_preferences (user_id, task_assigned, mentioned, watched_task_changed, daily_digest)
+values ($1, $2, $3, $4, $5)
+on conflict (user_id) do update
+	set task_assigned = excluded.task_assigned,
+		mentioned = excluded.mentioned,
+		watched_task_changed = excluded.watched_task_changed,
+		daily_digest = excluded.daily_digest,
+		updated_at = now()
+returning *;
+
+-- name: ListDigestRecipients :many
+select u.id as user_id, u.first_name, u.email, u.timezone, p.last_digest_at from notification_preferences p
+join users u on u.id = p.user_id
+where p.daily_digest
+	and extract(hour from sqlc.arg(now)::timestamptz at time zone u.timezone) >= sqlc.arg(digest_hour)::int
+	and (p.last_digest_at is null
+		or (p.last_digest_at at time zone u.timezone)::date < (sqlc.arg(now)::timestamptz at time zone u.timezone)::date)
+order by u.id
+limit sqlc.arg('limit');
+
+-- name: MarkDigestSent :exec
+update notification_preferences
+	set last_digest_at = sqlc.arg(sent_at)
+where user_id = sqlc.arg(user_id);
@@ -0,0 +1,75 @@
+-- name: CreateOutboxEvent :one
+insert into outbox_events (aggregate_type, aggregate_id, event_type, payload, actor_id, team_id)
+values ($1, $2, $3, $4, $5, $6)
+returning *;
+
+-- name: GetOutboxEvent :one
+select * from outbox_events where id = $1;
+
+-- name: ListOutboxEventsAfter :many
+select * from outbox_events
+where (team_id is not null or aggregate_type = 'user')
+	and (tx_id, id) > (sqlc.arg(after_tx_id)::bigint, sqlc.arg(after_id)::bigint)
+	and tx_id < outbox_stream_horizon()
+order by tx_id, id
+limit sqlc.arg('limit');
+
+-- name: ListTeamOutboxEventsAfter :many
+select e.* from outbox_events e
+join team_members tm on tm.team_id = e.team_id and tm.user_id = sqlc.arg(user_id)
+where e.team_id = any(sqlc.arg(team_ids)::int[])
+	and e.created_at >= tm.joined_at
+	and e.created_at >= sqlc.arg(since)
+	and (e.tx_id, e.id) > (sqlc.arg(after_tx_id)::bigint, sqlc.arg(after_id)::bigint)
+	and e.tx_id < outbox_stream_horizon()
+order by e.tx_id, e.id
+limit sqlc.arg('limit');
+
+-- name: GetLastStreamOutboxEvent :one
+select * from outbox_events
+where (team_id is not null or aggregate_type = 'user')
+	and tx_id < outbox_stream_horizon()
+order by tx_id desc, id desc
+limit 1;
+
+-- name: ClaimOutboxEvents :many
+update outbox_events
+	set next_attempt_at = sqlc.arg(lease_until)
+where id in (
+	select e.id from outbox_events e
+	where e.dispatched_at is null
+		and e.failed_at is null
+		and e.next_attempt_at <= sqlc.arg(now)
+		and not exists (
+			select 1 from outbox_events p
+			where p.aggregate_type = e.aggregate_type
+				and p.aggregate_id = e.aggregate_id
+				and p.dispatched_at is null
+				and p.failed_at is null
+				and p.id < e.id
+		)
+	order by e.id
+	limit sqlc.arg('limit')
+	for update skip locked
+)
+returning *;
+
+-- name: MarkOutboxEventDispatched :exec
+update outbox_events
+	set dispatched_at = now(),
+		attempts = attempts + 1
+where id = $1;
+
+-- name: RetryOutboxEvent :exec
+update outbox_events
+	set attempts = attempts + 1,
+		last_error = sqlc.arg(last_error),
+		next_attempt_at = sqlc.arg(next_attempt_at)
+where id = sqlc.arg(id);
+
+-- name: FailOutboxEvent :exec
+update outbox_events
+	set attempts = attempts + 1,
+		last_error = sqlc.arg(last_error),
+		failed_at = now()
+where id = sqlc.arg(id);
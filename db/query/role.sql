@@ -0,0 +1,51 @@
+-- name: CreateRole :one
+insert into roles (name, description) values ($1, $2) returning *;
+
+-- name: GetRole :one
+select * from roles where id = $1;
+
+-- name: GetRoleByName :one
+select * from roles where name = $1;
+
+-- name: ListRoles :many
+select * from roles order by name;
+
+-- name: UpdateRole :one
+update roles
+	set name = $2,
+		description = $3
+where id = $1
+returning *;
+
+-- name: DeleteRole :exec
+delete from roles where id = $1;
+
+-- name: AddRoleClaim :exec
+insert into role_claims (role_id, claim_id) values ($1, $2)
+on conflict do nothing;
+
+-- name: RemoveRoleClaim :exec
+delete from role_claims where role_id = $1 and claim_id = $2;
+
+-- name: ListUserRoles :many
+select r.* from roles r
+join user_roles ur on ur.role_id = r.id
+where ur.user_id = $1
+order by r.name;
+
+-- name: AddUserRole :exec
+insert into user_roles (user_id, role_id) values ($1, $2)
+on conflict do nothing;
+
+-- name: AddUserRoleByName :exec
+insert into user_roles (user_id, role_id)
+select sqlc.arg(user_id)::int, id from roles where name = sqlc.arg(name)
+on conflict do nothing;
+
+-- name: RemoveUserRole :exec
+delete from user_roles where user_id = $1 and role_id = $2;
+
+-- name: CountUsersWithRole :one
+select count(*) from user_roles ur
+join roles r on r.id = ur.role_id
+where r.name = $1;
\ No newline at end of file
@@ -0,0 +1,62 @@
+-- name: SearchTasks :many
+select t.id, t.title, t.team_id, t.status_id, t.due_date, t.created_at,
+	ts_rank(t.search_vector, to_tsquery('english', sqlc.arg(query)))::real as rank,
+	ts_headline(
+		'english',
+		t.title || ' ' || coalesce(t.description, ''),
+		to_tsquery('english', sqlc.arg(query)),
+		'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MinWords=5, MaxWords=20'
+	)::text as snippet
+from tasks t
+where t.search_vector @@ to_tsquery('english', sqlc.arg(query))
+	and (
+		sqlc.arg(view_all)::boolean
+		or exists (select 1 from team_members tm where tm.team_id = t.team_id and tm.user_id = sqlc.arg(user_id))
+		or (t.team_id is null and (
+			t.created_by = sqlc.arg(user_id)
+			or exists (select 1 from user_tasks ut where ut.task_id = t.id and ut.user_id = sqlc.arg(user_id))
+		))
+	)
+order by rank desc, t.id desc
+limit sqlc.arg('limit');
+
+-- name: SearchTeams :many
+select t.id, t.name,
+	ts_rank(t.search_vector, to_tsquery('english', sqlc.arg(query)))::real as rank,
+	ts_headline(
+		'english',
+		t.name || ' ' || coalesce(t.description, ''),
+		to_tsquery('english', sqlc.arg(query)),
+		'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MinWords=5, MaxWords=20'
+	)::text as snippet
+from teams t
+where t.search_vector @@ to_tsquery('english', sqlc.arg(query))
+	and (
+		sqlc.arg(view_all)::boolean
+		or exists (select 1 from team_members tm where tm.team_id = t.id and tm.user_id = sqlc.arg(user_id))
+	)
+order by rank desc, t.id desc
+limit sqlc.arg('limit');
+
+-- name: SearchTaskComments :many
+select c.id, c.task_id, t.title as task_title, c.created_at,
+	ts_rank(c.search_vector, to_tsquery('english', sqlc.arg(query)))::real as rank,
+	ts_headline(
+		'english',
+		c.body,
+		to_tsquery('english', sqlc.arg(query)),
+		'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MinWords=5, MaxWords=20'
+	)::text as snippet
+from task_comments c
+join tasks t on t.id = c.task_id
+where c.search_vector @@ to_tsquery('english', sqlc.arg(query))
+	and (
+		sqlc.arg(view_all)::boolean
+		or exists (select 1 from team_members tm where tm.team_id = t.team_id and tm.user_id = sqlc.arg(user_id))
+		or (t.team_id is null and (
+			t.created_by = sqlc.arg(user_id)
+			or exists (select 1 from user_tasks ut where ut.task_id = t.id and ut.user_id = sqlc.arg(user_id))
+		))
+	)
+order by rank desc, c.id desc
+limit sqlc.arg('limit');
@@ -0,0 +1,22 @@
+-- name: CreateSession :one
+insert into sessions (id, family_id, user_id, user_agent, client_ip, expires_at)
+values ($1, $2, $3, $4, $5, $6)
+returning *;
+
+-- name: GetSession :one
+select * from sessions where id = $1;
+
+-- name: RotateSession :execrows
+update sessions
+	set rotated_at = now()
+where id = $1 and rotated_at is null and is_revoked = false;
+
+-- name: RevokeSessionFamily :exec
+update sessions
+	set is_revoked = true
+where family_id = $1;
+
+-- name: RevokeUserSessions :exec
+update sessions
+	set is_revoked = true
+where user_id = $1 and is_revoked = false;
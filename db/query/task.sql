@@ -0,0 +1,44 @@
+-- name: CreateTask :one
+insert into tasks (title, description, created_by, team_id, due_date, status_id) values ($1, $2, $3, $4, $5, $6) returning *;
+
+-- name: GetTask :one
+select * from tasks where id = $1;
+
+-- name: UpdateTask :one
+update tasks
+	set title = $2,
+		description = $3,
+		due_date = $4
+where id = $1
+returning *;
+
+-- name: TransitionTask :one
+update tasks
+	set status_id = sqlc.arg(to_status_id)
+where id = sqlc.arg(id)
+	and exists (
+		select 1 from task_status_transitions tr
+		where tr.from_status_id = tasks.status_id and tr.to_status_id = sqlc.arg(to_status_id)
+	)
+returning *;
+
+-- name: DeleteTask :exec
+delete from tasks where id = $1;
+
+-- name: AddTaskAssignee :exec
+insert into user_tasks (user_id, task_id) values ($1, $2)
+on conflict (user_id, task_id) do nothing;
+
+-- name: RemoveTaskAssignee :exec
+delete from user_tasks where user_id = $1 and task_id = $2;
+
+-- name: ListTaskAssignees :many
+select ut.user_id, u.first_name, u.last_name, u.email, u.timezone, ut.assigned_at from user_tasks ut
+join users u on u.id = ut.user_id
+where ut.task_id = $1
+order by ut.assigned_at, ut.user_id;
+
+-- name: ListTeamTaskIDs :many
+select id from tasks
+where team_id = sqlc.arg(team_id)::int
+order by id;
@@ -0,0 +1,20 @@
+-- name: CreateTaskAttachment :one
+insert into task_attachments (task_id, uploaded_by, file_name, content_type, size_bytes, storage_key)
+values ($1, $2, $3, $4, $5, $6)
+returning *;
+
+-- name: GetTaskAttachment :one
+select * from task_attachments where id = $1;
+
+-- name: ListTaskAttachments :many
+select * from task_attachments
+where task_id = $1
+order by created_at, id;
+
+-- name: ListTeamAttachmentKeys :many
+select a.storage_key from task_attachments a
+join tasks t on t.id = a.task_id
+where t.team_id = sqlc.arg(team_id)::int;
+
+-- name: DeleteTaskAttachment :exec
+delete from task_attachments where id = $1;
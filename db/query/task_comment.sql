@@ -0,0 +1,46 @@
+-- name: CreateTaskComment :one
+insert into task_comments (task_id, author_id, body, body_html) values ($1, $2, $3, $4) returning *;
+
+-- name: GetTaskComment :one
+select * from task_comments where id = $1;
+
+-- name: GetTaskCommentForUpdate :one
+select * from task_comments where id = $1 for update;
+
+-- name: ListTaskComments :many
+select * from task_comments
+where task_id = $1
+order by created_at, id;
+
+-- name: UpdateTaskComment :one
+update task_comments
+	set body = $2,
+		body_html = $3,
+		edited_at = now()
+where id = $1
+returning *;
+
+-- name: DeleteTaskComment :exec
+delete from task_comments where id = $1;
+
+-- name: CreateTaskCommentRevision :one
+insert into task_comment_revisions (comment_id, body, edited_by) values ($1, $2, $3) returning *;
+
+-- name: ListTaskCommentRevisions :many
+select * from task_comment_revisions
+where comment_id = $1
+order by edited_at desc, id desc;
+
+-- name: AddTaskCommentMentions :exec
+insert into task_comment_mentions (comment_id, user_id)
+select sqlc.arg(comment_id), unnest(sqlc.arg(user_ids)::int[])
+on conflict do nothing;
+
+-- name: DeleteTaskCommentMentions :exec
+delete from task_comment_mentions where comment_id = $1;
+
+-- name: ListTaskCommentMentions :many
+select m.comment_id, u.id as user_id, u.first_name, u.last_name, u.email from task_comment_mentions m
+join users u on u.id = m.user_id
+where m.comment_id = any(sqlc.arg(comment_ids)::int[])
+order by m.comment_id, u.id;
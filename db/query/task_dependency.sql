@@ -0,0 +1,74 @@
+-- name: UpdateTaskParent :one
+update tasks
+	set parent_id = sqlc.narg(parent_id)
+where id = sqlc.arg(id)
+returning *;
+
+-- name: IsTaskAncestor :one
+with recursive ancestors(id, parent_id) as (
+	select t.id, t.parent_id from tasks t where t.id = sqlc.arg(task_id)
+	union
+	select t.id, t.parent_id from tasks t
+	join ancestors a on t.id = a.parent_id
+)
+select exists (select 1 from ancestors where id = sqlc.arg(ancestor_id))::bool;
+
+-- name: ListSubtasks :many
+select * from tasks
+where parent_id = $1
+order by created_at, id;
+
+-- name: ListSubtaskTree :many
+with recursive subtree as (
+	select * from tasks where parent_id = sqlc.arg(task_id)
+	union all
+	select t.* from tasks t
+	join subtree s on t.parent_id = s.id
+)
+select * from subtree
+order by created_at, id;
+
+-- name: CreateTaskDependency :exec
+insert into task_dependencies (blocker_id, blocked_id) values ($1, $2)
+on conflict (blocker_id, blocked_id) do nothing;
+
+-- name: DeleteTaskDependency :execrows
+delete from task_dependencies where blocker_id = $1 and blocked_id = $2;
+
+-- name: HasTaskDependencyPath :one
+with recursive downstream(id) as (
+	select d.blocked_id from task_dependencies d where d.blocker_id = sqlc.arg(from_id)
+	union
+	select d.blocked_id from task_dependencies d
+	join downstream s on d.blocker_id = s.id
+)
+select exists (select 1 from downstream where id = sqlc.arg(to_id))::bool;
+
+-- name: ListBlockerGraph :many
+with recursive upstream(id) as (
+	select d.blocker_id from task_dependencies d where d.blocked_id = sqlc.arg(task_id)
+	union
+	select d.blocker_id from task_dependencies d
+	join upstream u on d.blocked_id = u.id
+)
+select d.blocker_id, d.blocked_id from task_dependencies d
+where d.blocked_id = sqlc.arg(task_id) or d.blocked_id in (select id from upstream)
+order by d.blocked_id, d.blocker_id;
+
+-- name: ListBlockedTasks :many
+select t.* from task_dependencies d
+join tasks t on t.id = d.blocked_id
+where d.blocker_id = $1
+order by t.id;
+
+-- name: ListOpenBlockers :many
+select t.* from task_dependencies d
+join tasks t on t.id = d.blocker_id
+join task_statuses s on s.id = t.status_id
+where d.blocked_id = $1 and s.category <> 'done'
+order by t.id;
+
+-- name: ListTasksByIDs :many
+select * from tasks
+where id = any(sqlc.arg(ids)::int[])
+order by id;
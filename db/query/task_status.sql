@@ -0,0 +1,60 @@
+-- name: CreateTaskStatus :one
+insert into task_statuses (team_id, name, category, position, color)
+values ($1, $2, $3, $4, $5)
+returning *;
+
+-- name: GetTaskStatus :one
+select * from task_statuses where id = $1;
+
+-- name: ListTaskStatuses :many
+select * from task_statuses
+where team_id is not distinct from sqlc.narg(team_id)
+order by position, id;
+
+-- name: GetInitialTaskStatus :one
+select * from task_statuses
+where team_id is not distinct from sqlc.narg(team_id)
+order by position, id
+limit 1;
+
+-- name: UpdateTaskStatus :one
+update task_statuses
+	set name = $2,
+		category = $3,
+		position = $4,
+		color = $5
+where id = $1
+returning *;
+
+-- name: DeleteTaskStatus :exec
+delete from task_statuses where id = $1;
+
+-- name: CopyDefaultTaskStatuses :exec
+insert into task_statuses (team_id, name, category, position, color)
+select sqlc.arg(team_id)::int, s.name, s.category, s.position, s.color
+from task_statuses s
+where s.team_id is null;
+
+-- name: CopyDefaultTaskStatusTransitions :exec
+insert into task_status_transitions (from_status_id, to_status_id)
+select nf.id, nt.id
+from task_status_transitions tr
+join task_statuses f on f.id = tr.from_status_id and f.team_id is null
+join task_statuses t on t.id = tr.to_status_id
+join task_statuses nf on nf.team_id = sqlc.arg(team_id) and nf.name = f.name
+join task_statuses nt on nt.team_id = sqlc.arg(team_id) and nt.name = t.name;
+
+-- name: CreateTaskStatusTransition :one
+insert into task_status_transitions (from_status_id, to_status_id)
+values ($1, $2)
+returning *;
+
+-- name: DeleteTaskStatusTransition :execrows
+delete from task_status_transitions
+where from_status_id = $1 and to_status_id = $2;
+
+-- name: ListTaskStatusTransitions :many
+select tr.* from task_status_transitions tr
+join task_statuses s on s.id = tr.from_status_id
+where s.team_id is not distinct from sqlc.narg(team_id)
+order by tr.from_status_id, tr.to_status_id;
@@ -0,0 +1,38 @@
+-- name: CreateTaskTemplate :one
+insert into task_templates (title, description, created_by, team_id, rrule, timezone, starts_at, next_due_at)
+values ($1, $2, $3, $4, $5, $6, $7, $8)
+returning *;
+
+-- name: GetTaskTemplate :one
+select * from task_templates where id = $1;
+
+-- name: ListTaskTemplates :many
+select * from task_templates
+where (sqlc.narg(team_id)::int is null and team_id is null and created_by = sqlc.arg(user_id))
+	or team_id = sqlc.narg(team_id)
+order by created_at, id;
+
+-- name: DeleteTaskTemplate :exec
+delete from task_templates where id = $1;
+
+-- name: ListDueTaskTemplates :many
+select * from task_templates
+where next_due_at <= sqlc.arg(now)
+order by next_due_at, id
+limit sqlc.arg('limit');
+
+-- name: AdvanceTaskTemplate :one
+update task_templates
+	set next_due_at = sqlc.narg(next_due_at)
+where id = sqlc.arg(id) and next_due_at = sqlc.arg(due_at)
+returning *;
+
+-- name: CreateTemplateTask :one
+insert into tasks (title, description, created_by, team_id, due_date, status_id, template_id)
+values ($1, $2, $3, $4, $5, $6, $7)
+returning *;
+
+-- name: CountOpenTemplateTasks :one
+select count(*) from tasks t
+join task_statuses s on s.id = t.status_id
+where t.template_id = $1 and s.category <> 'done';
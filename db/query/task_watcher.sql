@@ -0,0 +1,13 @@
+-- name: AddTaskWatcher :exec
+insert into task_watchers (task_id, user_id)
+values ($1, $2)
+on conflict do nothing;
+
+-- name: RemoveTaskWatcher :exec
+delete from task_watchers where task_id = $1 and user_id = $2;
+
+-- name: ListTaskWatchers :many
+select w.user_id, u.first_name, u.last_name, u.email, w.created_at as watching_since from task_watchers w
+join users u on u.id = w.user_id
+where w.task_id = $1
+order by w.created_at, w.user_id;
@@ -0,0 +1,56 @@
+-- name: CreateTeam :one
+insert into teams (name, description, created_by) values ($1, $2, $3) returning *;
+
+-- name: GetTeam :one
+select * from teams where id = $1;
+
+-- name: UpdateTeam :one
+update teams
+	set name = $2,
+		description = $3
+where id = $1
+returning *;
+
+-- name: DeleteTeam :exec
+delete from teams where id = $1;
+
+-- name: ListTeamsByUser :many
+select t.* from teams t
+join team_members tm on tm.team_id = t.id
+where tm.user_id = $1
+order by t.name;
+
+-- name: AddTeamMember :one
+insert into team_members (team_id, user_id) values ($1, $2) returning *;
+
+-- name: GetTeamMember :one
+select * from team_members where team_id = $1 and user_id = $2;
+
+-- name: ListTeamMembers :many
+select tm.user_id, u.first_name, u.last_name, u.email, tm.joined_at from team_members tm
+join users u on u.id = tm.user_id
+where tm.team_id = $1
+order by tm.joined_at;
+
+-- name: FilterTeamMembers :many
+select user_id from team_members
+where team_id = sqlc.arg(team_id) and user_id = any(sqlc.arg(user_ids)::int[]);
+
+-- name: RemoveTeamMember :exec
+with removed as (
+	delete from team_members where team_id = $1 and user_id = $2
+	returning team_id, user_id
+), unwatched as (
+	delete from task_watchers w
+	using tasks t, removed r
+	where w.task_id = t.id and t.team_id = r.team_id and w.user_id = r.user_id
+)
+delete from user_tasks ut
+using tasks t, removed r
+where ut.task_id = t.id and t.team_id = r.team_id and ut.user_id = r.user_id;
+
+-- name: UpdateTeamOwner :one
+update teams
+	set created_by = $2
+where id = $1
+returning *;
@@ -5,7 +5,7 @@ select * from  users where id = $1;
 select * from users where email = $1;
 
 -- name: CreateUser :one
-insert into users (first_name, last_name, email, password_hash, profile_picture_url) values ($1, $2, $3, $4, $5) returning *;
+insert into users (first_name, last_name, email, password_hash, profile_picture_url, timezone) values ($1, $2, $3, $4, $5, $6) returning *;
 
 -- name: UpdateUser :one
 update users
@@ -13,14 +13,26 @@ update users
 		last_name = $3,
 		email = $4,
 		profile_picture_url = $5,
-        is_email_verified = $6
+        is_email_verified = $6,
+        timezone = $7
 where id = $1
 returning *;
 
 -- name: UpdateUserPassword :exec
 update users 
-	set password_hash = $2
+	set password_hash = $2,
+		password_changed_at = $3
 where id = $1;
 
 -- name: DeleteUser :exec
-delete from users where id = $1;
\ No newline at end of file
+delete from users where id = $1;
+
+-- name: MarkUserEmailVerified :execrows
+update users
+	set is_email_verified = true
+where id = $1 and email = $2;
+
+-- name: ListUsersByEmails :many
+select id, first_name, last_name, email from users
+where lower(email) = any(sqlc.arg(emails)::text[])
+order by id;
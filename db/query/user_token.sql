@@ -0,0 +1,21 @@
+-- name: CreateUserToken :one
+insert into user_tokens (user_id, purpose, token_hash, expires_at, email) values ($1, $2, $3, $4, $5) returning *;
+
+-- name: GetUserTokenByHash :one
+select * from user_tokens where token_hash = $1 and purpose = $2;
+
+-- name: GetLatestUserToken :one
+select * from user_tokens
+where user_id = $1 and purpose = $2
+order by created_at desc, id desc
+limit 1;
+
+-- name: UseUserToken :execrows
+update user_tokens
+	set used_at = now()
+where id = $1 and used_at is null;
+
+-- name: InvalidateUserTokens :exec
+update user_tokens
+	set used_at = now()
+where user_id = $1 and purpose = $2 and used_at is null;
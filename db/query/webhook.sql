@@ -0,0 +1,95 @@
+-- name: CreateWebhook :one
+insert into webhooks (team_id, url, event_types, secret, created_by)
+values ($1, $2, $3, $4, $5)
+returning *;
+
+-- name: GetWebhook :one
+select * from webhooks where id = $1;
+
+-- name: ListTeamWebhooks :many
+select * from webhooks
+where team_id = $1
+order by id;
+
+-- name: UpdateWebhook :one
+update webhooks
+	set url = sqlc.arg(url),
+		event_types = sqlc.arg(event_types),
+		active = sqlc.arg(active),
+		consecutive_failures = case when sqlc.arg(active) and not active then 0 else consecutive_failures end,
+		disabled_at = case when sqlc.arg(active) then null else disabled_at end
+where id = sqlc.arg(id)
+returning *;
+
+-- name: DeleteWebhook :exec
+delete from webhooks where id = $1;
+
+-- name: ListWebhooksForEvent :many
+select * from webhooks
+where team_id = sqlc.arg(team_id)
+	and active
+	and sqlc.arg(event_type)::text = any(event_types)
+order by id;
+
+-- name: ResetWebhookFailures :exec
+update webhooks
+	set consecutive_failures = 0
+where id = $1 and consecutive_failures > 0;
+
+-- name: RecordWebhookFailure :one
+update webhooks
+	set consecutive_failures = consecutive_failures + 1,
+		active = active and consecutive_failures + 1 < sqlc.arg(max_failures),
+		disabled_at = case
+			when active and consecutive_failures + 1 >= sqlc.arg(max_failures) then now()
+			else disabled_at
+		end
+where id = sqlc.arg(id)
+returning *;
+
+-- name: CreateWebhookDelivery :exec
+insert into webhook_deliveries (webhook_id, event_id, event_type, payload)
+values ($1, $2, $3, $4)
+on conflict (webhook_id, event_id) where redelivery_of is null do nothing;
+
+-- name: CreateWebhookRedelivery :one
+insert into webhook_deliveries (webhook_id, event_id, event_type, payload, redelivery_of)
+select webhook_id, event_id, event_type, payload, id from webhook_deliveries
+where id = $1
+returning *;
+
+-- name: GetWebhookDelivery :one
+select * from webhook_deliveries where id = $1;
+
+-- name: ListWebhookDeliveries :many
+select * from webhook_deliveries
+where webhook_id = sqlc.arg(webhook_id)
+	and (sqlc.narg(before_id)::bigint is null or id < sqlc.narg(before_id))
+order by id desc
+limit sqlc.arg('limit');
+
+-- name: ClaimWebhookDeliveries :many
+update webhook_deliveries
+	set next_attempt_at = sqlc.arg(lease_until)
+where id in (
+	select d.id from webhook_deliveries d
+	join webhooks w on w.id = d.webhook_id
+	where d.status = 'pending'
+		and d.next_attempt_at <= sqlc.arg(now)
+		and w.active
+	order by d.next_attempt_at, d.id
+	limit sqlc.arg('limit')
+	for update of d skip locked
+)
+returning *;
+
+-- name: RecordWebhookDeliveryAttempt :one
+update webhook_deliveries
+	set status = sqlc.arg(status),
+		attempts = attempts + 1,
+		response_status = sqlc.narg(response_status),
+		last_error = sqlc.arg(last_error),
+		next_attempt_at = sqlc.arg(next_attempt_at),
+		delivered_at = case when sqlc.arg(status) = 'succeeded' then now() else delivered_at end
+where id = sqlc.arg(id)
+returning *;
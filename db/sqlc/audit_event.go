@@ -0,0 +1,153 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AuditContext describes who a write is made for. Triggers on every table
+// record the change in audit_events along with these details, which reach
+// them as settings on the connection.
+type AuditContext struct {
+	// ActorID is the user making the change, or 0 when it is not made on
+	// behalf of a user, such as by the recurrence generator.
+	ActorID   int32
+	RequestID string
+	ClientIP  string
+}
+
+type auditContextKey struct{}
+
+// auditConnDataKey is where a connection remembers the audit context it was
+// last given, so it is only sent to the server when it changes.
+const auditConnDataKey = "audit"
+
+// WithAuditContext returns a copy of ctx whose writes are attributed to
+// audit.
+func WithAuditContext(ctx context.Context, audit AuditContext) context.Context {
+	return context.WithValue(ctx, auditContextKey{}, audit)
+}
+
+// AuditContextFrom returns the audit context of ctx, or the zero value when
+// there is none.
+func AuditContextFrom(ctx context.Context) AuditContext {
+	audit, _ := ctx.Value(auditContextKey{}).(AuditContext)
+	return audit
+}
+
+// applyAuditContext puts the audit context of ctx on conn before it runs any
+// query for ctx. The settings are session wide, so a connection reused for a
+// context without one has them cleared.
+func applyAuditContext(ctx context.Context, conn *pgx.Conn) error {
+	audit := AuditContextFrom(ctx)
+
+	data := conn.PgConn().CustomData()
+	if current, _ := data[auditConnDataKey].(AuditContext); current == audit {
+		return nil
+	}
+
+	var actorID string
+	if audit.ActorID != 0 {
+		actorID = strconv.Itoa(int(audit.ActorID))
+	}
+
+	_, err := conn.Exec(ctx,
+		"select set_config('audit.actor_id', $1, false), set_config('audit.request_id', $2, false), set_config('audit.client_ip', $3, false)",
+		actorID, audit.RequestID, audit.ClientIP,
+	)
+	if err != nil {
+		return err
+	}
+
+	data[auditConnDataKey] = audit
+	return nil
+}
+
+type ListAuditEventsParams struct {
+	ActorID    *int32     `json:"actorId"`
+	Action     *string    `json:"action"`
+	EntityType *string    `json:"entityType"`
+	EntityID   *string    `json:"entityId"`
+	Since      *time.Time `json:"since"`
+	Until      *time.Time `json:"until"`
+	// BeforeID is the ID of the last event of the previous page.
+	BeforeID *int64 `json:"beforeId"`
+	Limit    int32  `json:"limit"`
+}
+
+// ListAuditEvents returns the events matching the filters, newest first.
+func (q *Queries) ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+
+	param := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if arg.ActorID != nil {
+		conditions = append(conditions, "actor_id = "+param(*arg.ActorID))
+	}
+	if arg.Action != nil {
+		conditions = append(conditions, "action = "+param(*arg.Action))
+	}
+	if arg.EntityType != nil {
+		conditions = append(conditions, "entity_type = "+param(*arg.EntityType))
+	}
+	if arg.EntityID != nil {
+		conditions = append(conditions, "entity_id = "+param(*arg.EntityID))
+	}
+	if arg.Since != nil {
+		conditions = append(conditions, "created_at >= "+param(*arg.Since))
+	}
+	if arg.Until != nil {
+		conditions = append(conditions, "created_at < "+param(*arg.Until))
+	}
+	if arg.BeforeID != nil {
+		conditions = append(conditions, "id < "+param(*arg.BeforeID))
+	}
+
+	var query strings.Builder
+	query.WriteString("select id, actor_id, action, entity_type, entity_id, before, after, request_id, client_ip, created_at from audit_events\n")
+	if len(conditions) > 0 {
+		query.WriteString("where " + strings.Join(conditions, "\n\tand ") + "\n")
+	}
+	query.WriteString("order by id desc\n")
+	query.WriteString("limit " + param(arg.Limit))
+
+	rows, err := q.db.Query(ctx, query.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AuditEvent{}
+	for rows.Next() {
+		var i AuditEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.ActorID,
+			&i.Action,
+			&i.EntityType,
+			&i.EntityID,
+			&i.Before,
+			&i.After,
+			&i.RequestID,
+			&i.ClientIp,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/bolusarz/task-manager/util"
+	"github.com/stretchr/testify/require"
+)
+
+func listEntityAuditEvents(t *testing.T, entityType, entityID string) []AuditEvent {
+	events, err := testQueries.ListAuditEvents(t.Context(), ListAuditEventsParams{
+		EntityType: &entityType,
+		EntityID:   &entityID,
+		Limit:      10,
+	})
+	require.NoError(t, err)
+	return events
+}
+
+func TestAuditEventsRecordWrites(t *testing.T) {
+	user := createRandomUser(t)
+	audit := AuditContext{ActorID: user.ID, RequestID: util.RandomString(12), ClientIP: "10.0.0.1"}
+	ctx := WithAuditContext(t.Context(), audit)
+
+	task, err := testQueries.CreateTask(ctx, CreateTaskParams{
+		Title:     util.RandomString(12),
+		CreatedBy: &user.ID,
+		StatusID:  initialTaskStatus(t, nil).ID,
+	})
+	require.NoError(t, err)
+
+	newTitle := util.RandomString(12)
+	_, err = testQueries.UpdateTask(ctx, UpdateTaskParams{ID: task.ID, Title: newTitle, Description: task.Description})
+	require.NoError(t, err)
+
+	// Writes outside of a request are not attributed to the last actor that
+	// used the connection.
+	require.NoError(t, testQueries.DeleteTask(t.Context(), task.ID))
+
+	events := listEntityAuditEvents(t, "tasks", fmt.Sprint(task.ID))
+	require.Len(t, events, 3)
+
+	deleted, updated, created := events[0], events[1], events[2]
+
+	require.Equal(t, "create", created.Action)
+	require.Equal(t, &user.ID, created.ActorID)
+	require.Equal(t, audit.RequestID, created.RequestID)
+	require.Equal(t, audit.ClientIP, created.ClientIp)
+	require.Nil(t, created.Before)
+	require.NotContains(t, string(created.After), "search_vector")
+
+	require.Equal(t, "update", updated.Action)
+	require.JSONEq(t, mustMarshal(t, map[string]any{"title": task.Title}), string(updated.Before))
+	require.JSONEq(t, mustMarshal(t, map[string]any{"title": newTitle}), string(updated.After))
+
+	require.Equal(t, "delete", deleted.Action)
+	require.Nil(t, deleted.ActorID)
+	require.Empty(t, deleted.RequestID)
+	require.Nil(t, deleted.After)
+}
+
+func TestAuditEventsRedactSecrets(t *testing.T) {
+	user := createRandomUser(t)
+
+	events := listEntityAuditEvents(t, "users", fmt.Sprint(user.ID))
+	require.Len(t, events, 1)
+	require.NotContains(t, string(events[0].After), user.PasswordHash)
+	require.Contains(t, string(events[0].After), user.Email)
+}
+
+func TestAuditEventsKeyCompositeRows(t *testing.T) {
+	owner := createRandomUser(t)
+	team := createRandomTeam(t, owner)
+	member := createRandomUser(t)
+
+	_, err := testQueries.AddTeamMember(t.Context(), AddTeamMemberParams{TeamID: team.ID, UserID: member.ID})
+	require.NoError(t, err)
+
+	events := listEntityAuditEvents(t, "team_members", fmt.Sprintf("%d:%d", team.ID, member.ID))
+	require.Len(t, events, 1)
+	require.Equal(t, "create", events[0].Action)
+}
+
+func TestAuditEventsAreAppendOnly(t *testing.T) {
+	createRandomUser(t)
+
+	_, err := testDB.Exec(t.Context(), "update audit_events set action = 'tampered'")
+	require.Error(t, err)
+
+	_, err = testDB.Exec(t.Context(), "delete from audit_events")
+	require.Error(t, err)
+}
+
+func mustMarshal(t *testing.T, v any) string {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(data)
+}
@@ -0,0 +1,308 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+type EventType string
+
+const (
+	EventTaskCreated       EventType = "task.created"
+	EventTaskUpdated       EventType = "task.updated"
+	EventTaskDeleted       EventType = "task.deleted"
+	EventTaskAssigned      EventType = "task.assigned"
+	EventTaskUnassigned    EventType = "task.unassigned"
+	EventTaskStatusChanged EventType = "task.status_changed"
+	EventUsersMentioned    EventType = "task.users_mentioned"
+	EventTeamCreated       EventType = "team.created"
+	EventTeamUpdated       EventType = "team.updated"
+	EventTeamDeleted       EventType = "team.deleted"
+	EventTeamOwnerChanged  EventType = "team.owner_changed"
+	EventMemberJoinedTeam  EventType = "team.member_joined"
+	EventMemberLeftTeam    EventType = "team.member_left"
+	EventSessionsRevoked   EventType = "user.sessions_revoked"
+)
+
+// EventTypes lists every type of event that is recorded.
+var EventTypes = []EventType{
+	EventTaskCreated,
+	EventTaskUpdated,
+	EventTaskDeleted,
+	EventTaskAssigned,
+	EventTaskUnassigned,
+	EventTaskStatusChanged,
+	EventUsersMentioned,
+	EventTeamCreated,
+	EventTeamUpdated,
+	EventTeamDeleted,
+	EventTeamOwnerChanged,
+	EventMemberJoinedTeam,
+	EventMemberLeftTeam,
+	EventSessionsRevoked,
+}
+
+// Aggregates are what events are ordered by: the events of one task, of one
+// team's membership, or of one user, are delivered in the order they happened.
+const (
+	AggregateTask = "task"
+	AggregateTeam = "team"
+	AggregateUser = "user"
+)
+
+// DomainEvent is something that happened to a task, team or user. Events are
+// written to the outbox in the same transaction as the change they describe
+// and delivered to in-process handlers afterwards.
+type DomainEvent interface {
+	EventType() EventType
+	AggregateType() string
+	AggregateID() int32
+}
+
+type TaskCreated struct {
+	Task Task `json:"task"`
+}
+
+func (TaskCreated) EventType() EventType  { return EventTaskCreated }
+func (TaskCreated) AggregateType() string { return AggregateTask }
+func (e TaskCreated) AggregateID() int32  { return e.Task.ID }
+
+type TaskUpdated struct {
+	Task Task `json:"task"`
+}
+
+func (TaskUpdated) EventType() EventType  { return EventTaskUpdated }
+func (TaskUpdated) AggregateType() string { return AggregateTask }
+func (e TaskUpdated) AggregateID() int32  { return e.Task.ID }
+
+type TaskDeleted struct {
+	TaskID int32  `json:"taskId"`
+	TeamID *int32 `json:"teamId"`
+}
+
+func (TaskDeleted) EventType() EventType  { return EventTaskDeleted }
+func (TaskDeleted) AggregateType() string { return AggregateTask }
+func (e TaskDeleted) AggregateID() int32  { return e.TaskID }
+
+type TaskAssigned struct {
+	TaskID int32  `json:"taskId"`
+	TeamID *int32 `json:"teamId"`
+	UserID int32  `json:"userId"`
+}
+
+func (TaskAssigned) EventType() EventType  { return EventTaskAssigned }
+func (TaskAssigned) AggregateType() string { return AggregateTask }
+func (e TaskAssigned) AggregateID() int32  { return e.TaskID }
+
+type TaskUnassigned struct {
+	TaskID int32  `json:"taskId"`
+	TeamID *int32 `json:"teamId"`
+	UserID int32  `json:"userId"`
+}
+
+func (TaskUnassigned) EventType() EventType  { return EventTaskUnassigned }
+func (TaskUnassigned) AggregateType() string { return AggregateTask }
+func (e TaskUnassigned) AggregateID() int32  { return e.TaskID }
+
+type TaskStatusChanged struct {
+	// Task is the task after the transition; its StatusID is the new status.
+	Task         Task  `json:"task"`
+	FromStatusID int32 `json:"fromStatusId"`
+}
+
+func (TaskStatusChanged) EventType() EventType  { return EventTaskStatusChanged }
+func (TaskStatusChanged) AggregateType() string { return AggregateTask }
+func (e TaskStatusChanged) AggregateID() int32  { return e.Task.ID }
+
+// UsersMentioned is recorded when a comment is posted or edited to mention
+// users. Only the users it did not mention before are listed.
+type UsersMentioned struct {
+	TaskID    int32   `json:"taskId"`
+	TeamID    *int32  `json:"teamId"`
+	CommentID int32   `json:"commentId"`
+	UserIDs   []int32 `json:"userIds"`
+}
+
+func (UsersMentioned) EventType() EventType  { return EventUsersMentioned }
+func (UsersMentioned) AggregateType() string { return AggregateTask }
+func (e UsersMentioned) AggregateID() int32  { return e.TaskID }
+
+type TeamCreated struct {
+	Team Team `json:"team"`
+}
+
+func (TeamCreated) EventType() EventType  { return EventTeamCreated }
+func (TeamCreated) AggregateType() string { return AggregateTeam }
+func (e TeamCreated) AggregateID() int32  { return e.Team.ID }
+
+type TeamUpdated struct {
+	Team Team `json:"team"`
+}
+
+func (TeamUpdated) EventType() EventType  { return EventTeamUpdated }
+func (TeamUpdated) AggregateType() string { return AggregateTeam }
+func (e TeamUpdated) AggregateID() int32  { return e.Team.ID }
+
+// TeamDeleted is recorded after a TaskDeleted for every task that went with
+// the team.
+type TeamDeleted struct {
+	TeamID int32 `json:"teamId"`
+}
+
+func (TeamDeleted) EventType() EventType  { return EventTeamDeleted }
+func (TeamDeleted) AggregateType() string { return AggregateTeam }
+func (e TeamDeleted) AggregateID() int32  { return e.TeamID }
+
+type TeamOwnerChanged struct {
+	TeamID int32 `json:"teamId"`
+	// FromOwnerID is nil when the previous owner's account was deleted.
+	FromOwnerID *int32 `json:"fromOwnerId"`
+	ToOwnerID   int32  `json:"toOwnerId"`
+}
+
+func (TeamOwnerChanged) EventType() EventType  { return EventTeamOwnerChanged }
+func (TeamOwnerChanged) AggregateType() string { return AggregateTeam }
+func (e TeamOwnerChanged) AggregateID() int32  { return e.TeamID }
+
+type MemberJoinedTeam struct {
+	TeamID int32 `json:"teamId"`
+	UserID int32 `json:"userId"`
+}
+
+func (MemberJoinedTeam) EventType() EventType  { return EventMemberJoinedTeam }
+func (MemberJoinedTeam) AggregateType() string { return AggregateTeam }
+func (e MemberJoinedTeam) AggregateID() int32  { return e.TeamID }
+
+// MemberLeftTeam also stands for the user losing every assignment they had
+// on the team's tasks, which are dropped along with the membership.
+type MemberLeftTeam struct {
+	TeamID int32 `json:"teamId"`
+	UserID int32 `json:"userId"`
+}
+
+func (MemberLeftTeam) EventType() EventType  { return EventMemberLeftTeam }
+func (MemberLeftTeam) AggregateType() string { return AggregateTeam }
+func (e MemberLeftTeam) AggregateID() int32  { return e.TeamID }
+
+// SessionsRevoked is recorded when every session of a user is revoked, along
+// with the access tokens issued to them until then.
+type SessionsRevoked struct {
+	UserID int32 `json:"userId"`
+}
+
+func (SessionsRevoked) EventType() EventType  { return EventSessionsRevoked }
+func (SessionsRevoked) AggregateType() string { return AggregateUser }
+func (e SessionsRevoked) AggregateID() int32  { return e.UserID }
+
+// recordEvent writes event to the outbox, attributed to the actor of the
+// audit context of ctx. It must run in the transaction making the change the
+// event describes, so one is never kept without the other.
+func recordEvent(ctx context.Context, q Querier, event DomainEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	var actorID *int32
+	if audit := AuditContextFrom(ctx); audit.ActorID != 0 {
+		actorID = &audit.ActorID
+	}
+
+	_, err = q.CreateOutboxEvent(ctx, CreateOutboxEventParams{
+		AggregateType: event.AggregateType(),
+		AggregateID:   event.AggregateID(),
+		EventType:     string(event.EventType()),
+		Payload:       payload,
+		ActorID:       actorID,
+		TeamID:        EventTeamID(event),
+	})
+	return err
+}
+
+// EventTeamID returns the team event happened in, or nil for events of
+// personal tasks and of users.
+func EventTeamID(event DomainEvent) *int32 {
+	switch e := event.(type) {
+	case TaskCreated:
+		return e.Task.TeamID
+	case TaskUpdated:
+		return e.Task.TeamID
+	case TaskDeleted:
+		return e.TeamID
+	case TaskAssigned:
+		return e.TeamID
+	case TaskUnassigned:
+		return e.TeamID
+	case TaskStatusChanged:
+		return e.Task.TeamID
+	case UsersMentioned:
+		return e.TeamID
+	case TeamCreated:
+		return &e.Team.ID
+	case TeamUpdated:
+		return &e.Team.ID
+	case TeamDeleted:
+		return &e.TeamID
+	case TeamOwnerChanged:
+		return &e.TeamID
+	case MemberJoinedTeam:
+		return &e.TeamID
+	case MemberLeftTeam:
+		return &e.TeamID
+	}
+	return nil
+}
+
+// DecodeDomainEvent turns an outbox row back into the event it was recorded
+// from.
+func DecodeDomainEvent(event OutboxEvent) (DomainEvent, error) {
+	var (
+		decoded DomainEvent
+		err     error
+	)
+
+	switch EventType(event.EventType) {
+	case EventTaskCreated:
+		decoded, err = decodeEvent[TaskCreated](event.Payload)
+	case EventTaskUpdated:
+		decoded, err = decodeEvent[TaskUpdated](event.Payload)
+	case EventTaskDeleted:
+		decoded, err = decodeEvent[TaskDeleted](event.Payload)
+	case EventTaskAssigned:
+		decoded, err = decodeEvent[TaskAssigned](event.Payload)
+	case EventTaskUnassigned:
+		decoded, err = decodeEvent[TaskUnassigned](event.Payload)
+	case EventTaskStatusChanged:
+		decoded, err = decodeEvent[TaskStatusChanged](event.Payload)
+	case EventUsersMentioned:
+		decoded, err = decodeEvent[UsersMentioned](event.Payload)
+	case EventTeamCreated:
+		decoded, err = decodeEvent[TeamCreated](event.Payload)
+	case EventTeamUpdated:
+		decoded, err = decodeEvent[TeamUpdated](event.Payload)
+	case EventTeamDeleted:
+		decoded, err = decodeEvent[TeamDeleted](event.Payload)
+	case EventTeamOwnerChanged:
+		decoded, err = decodeEvent[TeamOwnerChanged](event.Payload)
+	case EventMemberJoinedTeam:
+		decoded, err = decodeEvent[MemberJoinedTeam](event.Payload)
+	case EventMemberLeftTeam:
+		decoded, err = decodeEvent[MemberLeftTeam](event.Payload)
+	case EventSessionsRevoked:
+		decoded, err = decodeEvent[SessionsRevoked](event.Payload)
+	default:
+		return nil, fmt.Errorf("unknown event type %q", event.EventType)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("decode %s event %d: %w", event.EventType, event.ID, err)
+	}
+	return decoded, nil
+}
+
+func decodeEvent[T DomainEvent](payload json.RawMessage) (DomainEvent, error) {
+	var event T
+	err := json.Unmarshal(payload, &event)
+	return event, err
+}
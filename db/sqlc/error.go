@@ -8,8 +8,9 @@ import (
 )
 
 const (
-	ForeignKeyViolation = "23503"
-	UniqueViolation     = "23505"
+	ForeignKeyViolation  = "23503"
+	UniqueViolation      = "23505"
+	SerializationFailure = "40001"
 )
 
 var ErrRecordNotFound = pgx.ErrNoRows
@@ -5,16 +5,71 @@
 package db
 
 import (
+	"encoding/json"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AuditEvent struct {
+	ID         int64           `json:"id"`
+	ActorID    *int32          `json:"actorId"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entityType"`
+	EntityID   string          `json:"entityId"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	RequestID  string          `json:"requestId"`
+	ClientIp   string          `json:"clientIp"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
 type Claim struct {
 	ID   int32  `json:"id"`
 	Name string `json:"name"`
 }
 
+type Notification struct {
+	ID        int64      `json:"id"`
+	UserID    int32      `json:"userId"`
+	Type      string     `json:"type"`
+	TaskID    int32      `json:"taskId"`
+	CommentID *int32     `json:"commentId"`
+	ActorID   *int32     `json:"actorId"`
+	EventID   int64      `json:"eventId"`
+	ReadAt    *time.Time `json:"readAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+	TaskTitle string     `json:"taskTitle"`
+}
+
+type NotificationPreference struct {
+	UserID             int32      `json:"userId"`
+	TaskAssigned       bool       `json:"taskAssigned"`
+	Mentioned          bool       `json:"mentioned"`
+	WatchedTaskChanged bool       `json:"watchedTaskChanged"`
+	DailyDigest        bool       `json:"dailyDigest"`
+	LastDigestAt       *time.Time `json:"lastDigestAt"`
+	UpdatedAt          time.Time  `json:"updatedAt"`
+}
+
+type OutboxEvent struct {
+	ID            int64           `json:"id"`
+	AggregateType string          `json:"aggregateType"`
+	AggregateID   int32           `json:"aggregateId"`
+	EventType     string          `json:"eventType"`
+	Payload       json.RawMessage `json:"payload"`
+	Attempts      int32           `json:"attempts"`
+	LastError     string          `json:"lastError"`
+	NextAttemptAt time.Time       `json:"nextAttemptAt"`
+	DispatchedAt  *time.Time      `json:"dispatchedAt"`
+	FailedAt      *time.Time      `json:"failedAt"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	ActorID       *int32          `json:"actorId"`
+	TeamID        *int32          `json:"teamId"`
+	TxID          int64           `json:"txId"`
+}
+
 type Role struct {
 	ID          int32       `json:"id"`
 	Name        string      `json:"name"`
@@ -26,24 +81,114 @@ type RoleClaim struct {
 	ClaimID int32 `json:"claimId"`
 }
 
+type Session struct {
+	ID        uuid.UUID  `json:"id"`
+	FamilyID  uuid.UUID  `json:"familyId"`
+	UserID    int32      `json:"userId"`
+	UserAgent string     `json:"userAgent"`
+	ClientIp  string     `json:"clientIp"`
+	IsRevoked bool       `json:"isRevoked"`
+	RotatedAt *time.Time `json:"rotatedAt"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
 type Task struct {
+	ID           int32       `json:"id"`
+	Title        string      `json:"title"`
+	Description  pgtype.Text `json:"description"`
+	CreatedBy    *int32      `json:"createdBy"`
+	CreatedAt    time.Time   `json:"createdAt"`
+	DueDate      *time.Time  `json:"dueDate"`
+	TeamID       *int32      `json:"teamId"`
+	StatusID     int32       `json:"statusId"`
+	SearchVector string      `json:"-"`
+	ParentID     *int32      `json:"parentId"`
+	TemplateID   *int32      `json:"templateId"`
+}
+
+type TaskAttachment struct {
+	ID          int32     `json:"id"`
+	TaskID      int32     `json:"taskId"`
+	UploadedBy  *int32    `json:"uploadedBy"`
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType"`
+	SizeBytes   int64     `json:"sizeBytes"`
+	StorageKey  string    `json:"-"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type TaskComment struct {
+	ID           int32      `json:"id"`
+	TaskID       int32      `json:"taskId"`
+	AuthorID     *int32     `json:"authorId"`
+	Body         string     `json:"body"`
+	BodyHtml     string     `json:"bodyHtml"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	EditedAt     *time.Time `json:"editedAt"`
+	SearchVector string     `json:"-"`
+}
+
+type TaskCommentMention struct {
+	CommentID int32 `json:"commentId"`
+	UserID    int32 `json:"userId"`
+}
+
+type TaskCommentRevision struct {
+	ID        int32     `json:"id"`
+	CommentID int32     `json:"commentId"`
+	Body      string    `json:"body"`
+	EditedBy  *int32    `json:"editedBy"`
+	EditedAt  time.Time `json:"editedAt"`
+}
+
+type TaskDependency struct {
+	BlockerID int32     `json:"blockerId"`
+	BlockedID int32     `json:"blockedId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type TaskStatus struct {
+	ID        int32     `json:"id"`
+	TeamID    *int32    `json:"teamId"`
+	Name      string    `json:"name"`
+	Category  string    `json:"category"`
+	Position  int32     `json:"position"`
+	Color     string    `json:"color"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type TaskStatusTransition struct {
+	FromStatusID int32 `json:"fromStatusId"`
+	ToStatusID   int32 `json:"toStatusId"`
+}
+
+type TaskTemplate struct {
 	ID          int32       `json:"id"`
 	Title       string      `json:"title"`
 	Description pgtype.Text `json:"description"`
-	CreatedBy   int32       `json:"createdBy"`
-	AssignedTo  int32       `json:"assignedTo"`
+	CreatedBy   *int32      `json:"createdBy"`
+	TeamID      *int32      `json:"teamId"`
+	Rrule       string      `json:"rrule"`
+	Timezone    string      `json:"timezone"`
+	StartsAt    time.Time   `json:"startsAt"`
+	NextDueAt   *time.Time  `json:"nextDueAt"`
 	CreatedAt   time.Time   `json:"createdAt"`
-	AssignedAt  time.Time   `json:"assignedAt"`
-	DueDate     time.Time   `json:"dueDate"`
-	Status      int8        `json:"status"`
+}
+
+type TaskWatcher struct {
+	TaskID    int32     `json:"taskId"`
+	UserID    int32     `json:"userId"`
+	CreatedAt time.Time `json:"createdAt"`
 }
 
 type Team struct {
-	ID          int32       `json:"id"`
-	Name        string      `json:"name"`
-	Description pgtype.Text `json:"description"`
-	CreatedBy   int32       `json:"createdBy"`
-	CreatedAt   time.Time   `json:"createdAt"`
+	ID           int32       `json:"id"`
+	Name         string      `json:"name"`
+	Description  pgtype.Text `json:"description"`
+	CreatedBy    *int32      `json:"createdBy"`
+	CreatedAt    time.Time   `json:"createdAt"`
+	SearchVector string      `json:"-"`
 }
 
 type TeamMember struct {
@@ -53,14 +198,16 @@ type TeamMember struct {
 }
 
 type User struct {
-	ID                int32     `json:"id"`
-	FirstName         string    `json:"firstName"`
-	LastName          string    `json:"lastName"`
-	Email             string    `json:"email"`
-	IsEmailVerified   bool      `json:"isEmailVerified"`
-	PasswordHash      string    `json:"passwordHash"`
-	ProfilePictureUrl string    `json:"profilePictureUrl"`
-	CreatedAt         time.Time `json:"createdAt"`
+	ID                int32      `json:"id"`
+	FirstName         string     `json:"firstName"`
+	LastName          string     `json:"lastName"`
+	Email             string     `json:"email"`
+	IsEmailVerified   bool       `json:"isEmailVerified"`
+	PasswordHash      string     `json:"passwordHash"`
+	ProfilePictureUrl string     `json:"profilePictureUrl"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	PasswordChangedAt *time.Time `json:"passwordChangedAt"`
+	Timezone          string     `json:"timezone"`
 }
 
 type UserRole struct {
@@ -74,3 +221,43 @@ type UserTask struct {
 	TaskID     int32     `json:"taskId"`
 	AssignedAt time.Time `json:"assignedAt"`
 }
+
+type UserToken struct {
+	ID        int32      `json:"id"`
+	UserID    int32      `json:"userId"`
+	Purpose   string     `json:"purpose"`
+	TokenHash string     `json:"tokenHash"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+	UsedAt    *time.Time `json:"usedAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+	Email     string     `json:"email"`
+}
+
+type Webhook struct {
+	ID                  int32      `json:"id"`
+	TeamID              int32      `json:"teamId"`
+	Url                 string     `json:"url"`
+	EventTypes          []string   `json:"eventTypes"`
+	Secret              string     `json:"-"`
+	Active              bool       `json:"active"`
+	ConsecutiveFailures int32      `json:"consecutiveFailures"`
+	DisabledAt          *time.Time `json:"disabledAt"`
+	CreatedBy           *int32     `json:"createdBy"`
+	CreatedAt           time.Time  `json:"createdAt"`
+}
+
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      int32           `json:"webhookId"`
+	EventID        int64           `json:"eventId"`
+	EventType      string          `json:"eventType"`
+	Payload        json.RawMessage `json:"payload"`
+	RedeliveryOf   *int64          `json:"redeliveryOf"`
+	Status         string          `json:"status"`
+	Attempts       int32           `json:"attempts"`
+	ResponseStatus *int32          `json:"responseStatus"`
+	LastError      string          `json:"lastError"`
+	NextAttemptAt  time.Time       `json:"nextAttemptAt"`
+	DeliveredAt    *time.Time      `json:"deliveredAt"`
+	CreatedAt      time.Time       `json:"createdAt"`
+}
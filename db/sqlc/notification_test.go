@@ -0,0 +1,162 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bolusarz/task-manager/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateNotifications(t *testing.T) {
+	actor := createRandomUser(t)
+	assignee := createRandomUser(t)
+	muted := createRandomUser(t)
+	task := createRandomTask(t)
+
+	_, err := testQueries.UpsertNotificationPreferences(t.Context(), UpsertNotificationPreferencesParams{
+		UserID:             muted.ID,
+		TaskAssigned:       false,
+		Mentioned:          true,
+		WatchedTaskChanged: true,
+	})
+	require.NoError(t, err)
+
+	arg := CreateNotificationsParams{
+		Type:    "task_assigned",
+		ActorID: &actor.ID,
+		EventID: time.Now().UnixNano(),
+		TaskID:  task.ID,
+		UserIds: []int32{assignee.ID, muted.ID, -1},
+	}
+	require.NoError(t, testQueries.CreateNotifications(t.Context(), arg))
+
+	// Notifying of the same event again does nothing.
+	require.NoError(t, testQueries.CreateNotifications(t.Context(), arg))
+
+	notifications, err := testQueries.ListNotifications(t.Context(), ListNotificationsParams{UserID: assignee.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	require.Equal(t, arg.Type, notifications[0].Type)
+	require.Equal(t, task.ID, notifications[0].TaskID)
+	require.Equal(t, task.Title, notifications[0].TaskTitle)
+	require.Equal(t, &actor.ID, notifications[0].ActorID)
+	require.Nil(t, notifications[0].ReadAt)
+
+	// The title is the one the task had when the notification was made.
+	_, err = testDB.Exec(t.Context(), "update tasks set title = $1 where id = $2", util.RandomString(12), task.ID)
+	require.NoError(t, err)
+
+	notifications, err = testQueries.ListNotifications(t.Context(), ListNotificationsParams{UserID: assignee.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, task.Title, notifications[0].TaskTitle)
+
+	// Users who turned the type off get nothing.
+	notifications, err = testQueries.ListNotifications(t.Context(), ListNotificationsParams{UserID: muted.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Empty(t, notifications)
+
+	// Notifications of tasks deleted in the meantime are not made.
+	require.NoError(t, testQueries.DeleteTask(t.Context(), task.ID))
+	arg.EventID++
+	require.NoError(t, testQueries.CreateNotifications(t.Context(), arg))
+}
+
+func TestMarkNotifications(t *testing.T) {
+	user := createRandomUser(t)
+	other := createRandomUser(t)
+	task := createRandomTask(t)
+
+	eventID := time.Now().UnixNano()
+	for i := range 3 {
+		err := testQueries.CreateNotifications(t.Context(), CreateNotificationsParams{
+			Type:    "watched_task_changed",
+			EventID: eventID + int64(i),
+			TaskID:  task.ID,
+			UserIds: []int32{user.ID},
+		})
+		require.NoError(t, err)
+	}
+
+	notifications, err := testQueries.ListNotifications(t.Context(), ListNotificationsParams{UserID: user.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, notifications, 3)
+	require.Greater(t, notifications[0].ID, notifications[1].ID)
+
+	count, err := testQueries.CountUnreadNotifications(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+
+	read, err := testQueries.MarkNotificationRead(t.Context(), MarkNotificationReadParams{ID: notifications[0].ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.NotNil(t, read.ReadAt)
+
+	// Others cannot mark it.
+	_, err = testQueries.MarkNotificationUnread(t.Context(), MarkNotificationUnreadParams{ID: notifications[0].ID, UserID: other.ID})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	unread, err := testQueries.ListNotifications(t.Context(), ListNotificationsParams{UserID: user.ID, UnreadOnly: true, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, unread, 2)
+	require.Equal(t, notifications[1].ID, unread[0].ID)
+
+	// Paging continues below the cursor.
+	page, err := testQueries.ListNotifications(t.Context(), ListNotificationsParams{UserID: user.ID, BeforeID: &notifications[1].ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, notifications[2].ID, page[0].ID)
+
+	marked, err := testQueries.MarkAllNotificationsRead(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), marked)
+
+	unreadAgain, err := testQueries.MarkNotificationUnread(t.Context(), MarkNotificationUnreadParams{ID: notifications[2].ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.Nil(t, unreadAgain.ReadAt)
+
+	count, err = testQueries.CountUnreadNotifications(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
+
+func TestListDigestRecipients(t *testing.T) {
+	user := createRandomUser(t)
+
+	_, err := testQueries.UpsertNotificationPreferences(t.Context(), UpsertNotificationPreferencesParams{
+		UserID:             user.ID,
+		TaskAssigned:       true,
+		Mentioned:          true,
+		WatchedTaskChanged: true,
+		DailyDigest:        true,
+	})
+	require.NoError(t, err)
+
+	isRecipient := func(now time.Time) bool {
+		recipients, err := testQueries.ListDigestRecipients(t.Context(), ListDigestRecipientsParams{
+			Now:        now,
+			DigestHour: 8,
+			Limit:      1000,
+		})
+		require.NoError(t, err)
+
+		for _, recipient := range recipients {
+			if recipient.UserID == user.ID {
+				return true
+			}
+		}
+		return false
+	}
+
+	location, err := time.LoadLocation(user.Timezone)
+	require.NoError(t, err)
+
+	morning := time.Date(2025, 3, 1, 9, 0, 0, 0, location)
+	require.False(t, isRecipient(morning.Add(-2*time.Hour)))
+	require.True(t, isRecipient(morning))
+
+	require.NoError(t, testQueries.MarkDigestSent(t.Context(), MarkDigestSentParams{SentAt: morning, UserID: user.ID}))
+
+	// One digest a day.
+	require.False(t, isRecipient(morning.Add(time.Hour)))
+	require.True(t, isRecipient(morning.Add(24*time.Hour)))
+}
@@ -0,0 +1,392 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bolusarz/task-manager/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func listAggregateOutboxEvents(t *testing.T, aggregateType string, aggregateID int32) []OutboxEvent {
+	rows, err := testDB.Query(t.Context(),
+		"select * from outbox_events where aggregate_type = $1 and aggregate_id = $2 order by id",
+		aggregateType, aggregateID,
+	)
+	require.NoError(t, err)
+
+	events, err := pgx.CollectRows(rows, pgx.RowToStructByPos[OutboxEvent])
+	require.NoError(t, err)
+	return events
+}
+
+func decodeOutboxEvents(t *testing.T, events []OutboxEvent) []DomainEvent {
+	decoded := make([]DomainEvent, 0, len(events))
+	for _, event := range events {
+		data, err := DecodeDomainEvent(event)
+		require.NoError(t, err)
+		decoded = append(decoded, data)
+	}
+	return decoded
+}
+
+// claimAllOutboxEvents claims everything that can be claimed right now,
+// including events left behind by other tests, and returns the IDs.
+func claimAllOutboxEvents(t *testing.T) map[int64]bool {
+	claimed := make(map[int64]bool)
+	now := time.Now()
+
+	for {
+		events, err := testStore.ClaimOutboxEvents(t.Context(), ClaimOutboxEventsParams{
+			LeaseUntil: now.Add(time.Minute),
+			Now:        now,
+			Limit:      100,
+		})
+		require.NoError(t, err)
+
+		if len(events) == 0 {
+			return claimed
+		}
+		for _, event := range events {
+			claimed[event.ID] = true
+		}
+	}
+}
+
+func TestStoreRecordsTaskEvents(t *testing.T) {
+	owner := createRandomUser(t)
+	assignee := createRandomUser(t)
+	team := createRandomTeamWithWorkflow(t, owner)
+
+	result, err := testStore.CreateTaskWithAssignees(t.Context(), CreateTaskWithAssigneesParams{
+		CreateTaskParams: CreateTaskParams{
+			Title:     util.RandomString(12),
+			CreatedBy: &owner.ID,
+			TeamID:    &team.ID,
+			StatusID:  initialTaskStatus(t, &team.ID).ID,
+		},
+		AssigneeIDs: []int32{assignee.ID},
+	})
+	require.NoError(t, err)
+	task := result.Task
+
+	_, err = testStore.UpdateTaskAssignees(t.Context(), UpdateTaskAssigneesParams{
+		TaskID: task.ID,
+		Add:    []int32{assignee.ID, owner.ID},
+		Remove: []int32{assignee.ID},
+	})
+	require.NoError(t, err)
+
+	transitions, err := testQueries.ListTaskStatusTransitions(t.Context(), &team.ID)
+	require.NoError(t, err)
+
+	var toStatusID int32
+	for _, transition := range transitions {
+		if transition.FromStatusID == task.StatusID {
+			toStatusID = transition.ToStatusID
+			break
+		}
+	}
+	require.NotZero(t, toStatusID)
+
+	transitioned, err := testStore.TransitionTask(t.Context(), TransitionTaskParams{ToStatusID: toStatusID, ID: task.ID})
+	require.NoError(t, err)
+
+	// A transition the workflow does not allow records nothing.
+	_, err = testStore.TransitionTask(t.Context(), TransitionTaskParams{ToStatusID: -1, ID: task.ID})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	require.NoError(t, testStore.DeleteTask(t.Context(), task.ID))
+
+	// Search vectors are left out of payloads.
+	task.SearchVector = ""
+	transitioned.SearchVector = ""
+
+	events := decodeOutboxEvents(t, listAggregateOutboxEvents(t, AggregateTask, task.ID))
+	require.Equal(t, []DomainEvent{
+		TaskCreated{Task: task},
+		TaskAssigned{TaskID: task.ID, TeamID: &team.ID, UserID: assignee.ID},
+		TaskAssigned{TaskID: task.ID, TeamID: &team.ID, UserID: owner.ID},
+		TaskUnassigned{TaskID: task.ID, TeamID: &team.ID, UserID: assignee.ID},
+		TaskStatusChanged{Task: transitioned, FromStatusID: task.StatusID},
+		TaskDeleted{TaskID: task.ID, TeamID: &team.ID},
+	}, events)
+}
+
+func TestStoreRecordsTeamEvents(t *testing.T) {
+	owner := createRandomUser(t)
+	member := createRandomUser(t)
+	newOwner := createRandomUser(t)
+	team := createRandomTeamWithWorkflow(t, owner)
+
+	_, err := testStore.AddTeamMember(t.Context(), AddTeamMemberParams{TeamID: team.ID, UserID: member.ID})
+	require.NoError(t, err)
+
+	// Joining twice fails without recording anything.
+	_, err = testStore.AddTeamMember(t.Context(), AddTeamMemberParams{TeamID: team.ID, UserID: member.ID})
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+
+	require.NoError(t, testStore.RemoveTeamMember(t.Context(), RemoveTeamMemberParams{TeamID: team.ID, UserID: member.ID}))
+	require.NoError(t, testStore.RemoveTeamMember(t.Context(), RemoveTeamMemberParams{TeamID: team.ID, UserID: member.ID}))
+
+	updated, err := testStore.UpdateTeam(t.Context(), UpdateTeamParams{
+		ID:          team.ID,
+		Name:        util.RandomString(10),
+		Description: team.Description,
+	})
+	require.NoError(t, err)
+
+	// The owner stays until they hand the team over.
+	err = testStore.RemoveTeamMember(t.Context(), RemoveTeamMemberParams{TeamID: team.ID, UserID: owner.ID})
+	require.ErrorIs(t, err, ErrTeamOwner)
+
+	// Only members can become the owner.
+	_, err = testStore.TransferTeamOwnership(t.Context(), TransferTeamOwnershipParams{TeamID: team.ID, NewOwnerID: newOwner.ID})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	_, err = testStore.AddTeamMember(t.Context(), AddTeamMemberParams{TeamID: team.ID, UserID: newOwner.ID})
+	require.NoError(t, err)
+
+	_, err = testStore.TransferTeamOwnership(t.Context(), TransferTeamOwnershipParams{TeamID: team.ID, NewOwnerID: newOwner.ID})
+	require.NoError(t, err)
+	require.NoError(t, testStore.RemoveTeamMember(t.Context(), RemoveTeamMemberParams{TeamID: team.ID, UserID: owner.ID}))
+
+	task, err := testStore.CreateTask(t.Context(), CreateTaskParams{
+		Title:    util.RandomString(12),
+		TeamID:   &team.ID,
+		StatusID: initialTaskStatus(t, &team.ID).ID,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, testStore.DeleteTeam(t.Context(), team.ID))
+	require.NoError(t, testStore.DeleteTeam(t.Context(), team.ID))
+
+	team.SearchVector = ""
+	updated.SearchVector = ""
+
+	events := decodeOutboxEvents(t, listAggregateOutboxEvents(t, AggregateTeam, team.ID))
+	require.Equal(t, []DomainEvent{
+		TeamCreated{Team: team},
+		MemberJoinedTeam{TeamID: team.ID, UserID: owner.ID},
+		MemberJoinedTeam{TeamID: team.ID, UserID: member.ID},
+		MemberLeftTeam{TeamID: team.ID, UserID: member.ID},
+		TeamUpdated{Team: updated},
+		MemberJoinedTeam{TeamID: team.ID, UserID: newOwner.ID},
+		TeamOwnerChanged{TeamID: team.ID, FromOwnerID: &owner.ID, ToOwnerID: newOwner.ID},
+		MemberLeftTeam{TeamID: team.ID, UserID: owner.ID},
+		TeamDeleted{TeamID: team.ID},
+	}, events)
+
+	// The team's tasks are reported deleted along with it.
+	taskEvents := decodeOutboxEvents(t, listAggregateOutboxEvents(t, AggregateTask, task.ID))
+	require.Equal(t, TaskDeleted{TaskID: task.ID, TeamID: &team.ID}, taskEvents[len(taskEvents)-1])
+}
+
+func TestStoreRecordsMentions(t *testing.T) {
+	owner := createRandomUser(t)
+	mentioned := createRandomUser(t)
+	team := createRandomTeamWithWorkflow(t, owner)
+
+	task, err := testStore.CreateTask(t.Context(), CreateTaskParams{
+		Title:     util.RandomString(12),
+		CreatedBy: &owner.ID,
+		TeamID:    &team.ID,
+		StatusID:  initialTaskStatus(t, &team.ID).ID,
+	})
+	require.NoError(t, err)
+
+	ctx := WithAuditContext(t.Context(), AuditContext{ActorID: owner.ID})
+
+	comment, err := testStore.CreateTaskCommentWithMentions(ctx, CreateTaskCommentWithMentionsParams{
+		CreateTaskCommentParams: CreateTaskCommentParams{
+			TaskID:   task.ID,
+			AuthorID: &owner.ID,
+			Body:     util.RandomString(20),
+		},
+		MentionIDs: []int32{mentioned.ID},
+	})
+	require.NoError(t, err)
+
+	// Editing only records the users the comment did not mention before.
+	_, err = testStore.EditTaskComment(ctx, EditTaskCommentParams{
+		ID:         comment.ID,
+		Body:       util.RandomString(20),
+		EditedBy:   &owner.ID,
+		MentionIDs: []int32{mentioned.ID, owner.ID},
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.EditTaskComment(ctx, EditTaskCommentParams{
+		ID:         comment.ID,
+		Body:       util.RandomString(20),
+		EditedBy:   &owner.ID,
+		MentionIDs: []int32{owner.ID},
+	})
+	require.NoError(t, err)
+
+	// The first event is the task being created.
+	events := listAggregateOutboxEvents(t, AggregateTask, task.ID)
+	require.Len(t, events, 3)
+	require.Equal(t, []DomainEvent{
+		UsersMentioned{TaskID: task.ID, TeamID: &team.ID, CommentID: comment.ID, UserIDs: []int32{mentioned.ID}},
+		UsersMentioned{TaskID: task.ID, TeamID: &team.ID, CommentID: comment.ID, UserIDs: []int32{owner.ID}},
+	}, decodeOutboxEvents(t, events[1:]))
+
+	// Events are attributed to the actor of the context they were recorded
+	// in.
+	require.Nil(t, events[0].ActorID)
+	require.Equal(t, &owner.ID, events[1].ActorID)
+}
+
+func TestClaimOutboxEventsInAggregateOrder(t *testing.T) {
+	owner := createRandomUser(t)
+	team := createRandomTeamWithWorkflow(t, owner)
+	member := createRandomUser(t)
+
+	_, err := testStore.AddTeamMember(t.Context(), AddTeamMemberParams{TeamID: team.ID, UserID: member.ID})
+	require.NoError(t, err)
+
+	events := listAggregateOutboxEvents(t, AggregateTeam, team.ID)
+	require.Len(t, events, 3)
+
+	// Only the oldest pending event of the team can be claimed.
+	claimed := claimAllOutboxEvents(t)
+	require.True(t, claimed[events[0].ID])
+	require.False(t, claimed[events[1].ID])
+	require.False(t, claimed[events[2].ID])
+
+	// Retrying it keeps the next one waiting.
+	err = testStore.RetryOutboxEvent(t.Context(), RetryOutboxEventParams{
+		LastError:     "handler failed",
+		NextAttemptAt: time.Now().Add(-time.Second),
+		ID:            events[0].ID,
+	})
+	require.NoError(t, err)
+
+	claimed = claimAllOutboxEvents(t)
+	require.True(t, claimed[events[0].ID])
+	require.False(t, claimed[events[1].ID])
+
+	require.NoError(t, testStore.MarkOutboxEventDispatched(t.Context(), events[0].ID))
+
+	claimed = claimAllOutboxEvents(t)
+	require.True(t, claimed[events[1].ID])
+	require.False(t, claimed[events[2].ID])
+
+	// Giving up on an event lets the next one through as well.
+	require.NoError(t, testStore.FailOutboxEvent(t.Context(), FailOutboxEventParams{LastError: "handler failed", ID: events[1].ID}))
+
+	claimed = claimAllOutboxEvents(t)
+	require.True(t, claimed[events[2].ID])
+
+	events = listAggregateOutboxEvents(t, AggregateTeam, team.ID)
+	require.Equal(t, int32(2), events[0].Attempts)
+	require.NotNil(t, events[0].DispatchedAt)
+	require.Equal(t, "handler failed", events[1].LastError)
+	require.NotNil(t, events[1].FailedAt)
+	require.Nil(t, events[1].DispatchedAt)
+}
+
+func TestListTeamOutboxEventsAfter(t *testing.T) {
+	owner := createRandomUser(t)
+
+	conn, err := testDB.Acquire(t.Context())
+	require.NoError(t, err)
+	defer conn.Release()
+
+	_, err = conn.Exec(t.Context(), "listen outbox_events")
+	require.NoError(t, err)
+	defer conn.Exec(context.Background(), "unlisten outbox_events")
+
+	team := createRandomTeamWithWorkflow(t, owner)
+	other := createRandomTeamWithWorkflow(t, owner)
+
+	personal, err := testStore.CreateTaskWithAssignees(t.Context(), CreateTaskWithAssigneesParams{
+		CreateTaskParams: CreateTaskParams{
+			Title:     util.RandomString(12),
+			CreatedBy: &owner.ID,
+			StatusID:  initialTaskStatus(t, nil).ID,
+		},
+	})
+	require.NoError(t, err)
+
+	events := listAggregateOutboxEvents(t, AggregateTeam, team.ID)
+	require.Len(t, events, 2)
+	for _, event := range events {
+		require.Equal(t, &team.ID, event.TeamID)
+	}
+
+	// Events of personal tasks belong to no team.
+	personalEvents := listAggregateOutboxEvents(t, AggregateTask, personal.Task.ID)
+	require.Len(t, personalEvents, 1)
+	require.Nil(t, personalEvents[0].TeamID)
+
+	arg := ListTeamOutboxEventsAfterParams{
+		UserID:    owner.ID,
+		TeamIds:   []int32{team.ID},
+		Since:     time.Now().Add(-time.Hour),
+		AfterTxID: events[0].TxID,
+		AfterID:   events[0].ID - 1,
+		Limit:     10,
+	}
+
+	listed, err := testStore.ListTeamOutboxEventsAfter(t.Context(), arg)
+	require.NoError(t, err)
+	require.Equal(t, events, listed)
+
+	// Nothing is replayed from before the window, nor to someone who is not
+	// in the team.
+	window := arg
+	window.Since = time.Now().Add(time.Hour)
+	listed, err = testStore.ListTeamOutboxEventsAfter(t.Context(), window)
+	require.NoError(t, err)
+	require.Empty(t, listed)
+
+	outsider := arg
+	outsider.UserID = createRandomUser(t).ID
+	listed, err = testStore.ListTeamOutboxEventsAfter(t.Context(), outsider)
+	require.NoError(t, err)
+	require.Empty(t, listed)
+
+	listed, err = testStore.ListOutboxEventsAfter(t.Context(), ListOutboxEventsAfterParams{
+		AfterTxID: events[0].TxID,
+		AfterID:   events[0].ID,
+		Limit:     10,
+	})
+	require.NoError(t, err)
+	require.Len(t, listed, 3)
+	require.Equal(t, events[1], listed[0])
+	require.Equal(t, &other.ID, listed[1].TeamID)
+	require.Equal(t, &other.ID, listed[2].TeamID)
+
+	got, err := testStore.GetOutboxEvent(t.Context(), events[1].ID)
+	require.NoError(t, err)
+	require.Equal(t, events[1], got)
+
+	// Every transaction here has ended, so the last of them is the head of
+	// the stream.
+	head, err := testStore.GetLastStreamOutboxEvent(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, listed[2], head)
+
+	// Every team event is announced once committed; personal ones are not.
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	var announced []string
+	for range 4 {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "outbox_events", notification.Channel)
+		announced = append(announced, notification.Payload)
+	}
+	require.Equal(t, []string{
+		fmt.Sprint(events[0].ID),
+		fmt.Sprint(events[1].ID),
+		fmt.Sprint(listed[1].ID),
+		fmt.Sprint(listed[2].ID),
+	}, announced)
+}
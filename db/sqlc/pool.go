@@ -0,0 +1,37 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// NewPool connects to the database at dsn. Timestamps are scanned in UTC
+// rather than the server's local zone, so they compare equal to the values
+// that were written and render with a Z offset. Every connection handed out
+// carries the audit context of the caller's context; one that cannot be
+// given it is discarded rather than used with a stale actor.
+func NewPool(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
+	config, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	config.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		conn.TypeMap().RegisterType(&pgtype.Type{
+			Name:  "timestamptz",
+			OID:   pgtype.TimestamptzOID,
+			Codec: &pgtype.TimestamptzCodec{ScanLocation: time.UTC},
+		})
+		return nil
+	}
+
+	config.BeforeAcquire = func(ctx context.Context, conn *pgx.Conn) bool {
+		return applyAuditContext(ctx, conn) == nil
+	}
+
+	return pgxpool.NewWithConfig(ctx, config)
+}
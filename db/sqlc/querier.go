@@ -6,15 +6,153 @@ package db
 
 import (
 	"context"
+
+	"github.com/google/uuid"
 )
 
 type Querier interface {
+	AddRoleClaim(ctx context.Context, arg AddRoleClaimParams) error
+	AddTaskAssignee(ctx context.Context, arg AddTaskAssigneeParams) error
+	AddTaskCommentMentions(ctx context.Context, arg AddTaskCommentMentionsParams) error
+	AddTaskWatcher(ctx context.Context, arg AddTaskWatcherParams) error
+	AddTeamMember(ctx context.Context, arg AddTeamMemberParams) (TeamMember, error)
+	AddUserRole(ctx context.Context, arg AddUserRoleParams) error
+	AddUserRoleByName(ctx context.Context, arg AddUserRoleByNameParams) error
+	AdvanceTaskTemplate(ctx context.Context, arg AdvanceTaskTemplateParams) (TaskTemplate, error)
+	ClaimOutboxEvents(ctx context.Context, arg ClaimOutboxEventsParams) ([]OutboxEvent, error)
+	ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	CopyDefaultTaskStatusTransitions(ctx context.Context, teamID int32) error
+	CopyDefaultTaskStatuses(ctx context.Context, teamID int32) error
+	CountOpenTemplateTasks(ctx context.Context, templateID *int32) (int64, error)
+	CountUnreadNotifications(ctx context.Context, userID int32) (int64, error)
+	CountUsersWithRole(ctx context.Context, name string) (int64, error)
+	CreateNotifications(ctx context.Context, arg CreateNotificationsParams) error
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (OutboxEvent, error)
+	CreateRole(ctx context.Context, arg CreateRoleParams) (Role, error)
+	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateTask(ctx context.Context, arg CreateTaskParams) (Task, error)
+	CreateTaskAttachment(ctx context.Context, arg CreateTaskAttachmentParams) (TaskAttachment, error)
+	CreateTaskComment(ctx context.Context, arg CreateTaskCommentParams) (TaskComment, error)
+	CreateTaskCommentRevision(ctx context.Context, arg CreateTaskCommentRevisionParams) (TaskCommentRevision, error)
+	CreateTaskDependency(ctx context.Context, arg CreateTaskDependencyParams) error
+	CreateTaskStatus(ctx context.Context, arg CreateTaskStatusParams) (TaskStatus, error)
+	CreateTaskStatusTransition(ctx context.Context, arg CreateTaskStatusTransitionParams) (TaskStatusTransition, error)
+	CreateTaskTemplate(ctx context.Context, arg CreateTaskTemplateParams) (TaskTemplate, error)
+	CreateTeam(ctx context.Context, arg CreateTeamParams) (Team, error)
+	CreateTemplateTask(ctx context.Context, arg CreateTemplateTaskParams) (Task, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserToken(ctx context.Context, arg CreateUserTokenParams) (UserToken, error)
+	CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) error
+	CreateWebhookRedelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	DeleteRole(ctx context.Context, id int32) error
+	DeleteTask(ctx context.Context, id int32) error
+	DeleteTaskAttachment(ctx context.Context, id int32) error
+	DeleteTaskComment(ctx context.Context, id int32) error
+	DeleteTaskCommentMentions(ctx context.Context, commentID int32) error
+	DeleteTaskDependency(ctx context.Context, arg DeleteTaskDependencyParams) (int64, error)
+	DeleteTaskStatus(ctx context.Context, id int32) error
+	DeleteTaskStatusTransition(ctx context.Context, arg DeleteTaskStatusTransitionParams) (int64, error)
+	DeleteTaskTemplate(ctx context.Context, id int32) error
+	DeleteTeam(ctx context.Context, id int32) error
 	DeleteUser(ctx context.Context, id int32) error
+	DeleteWebhook(ctx context.Context, id int32) error
+	FailOutboxEvent(ctx context.Context, arg FailOutboxEventParams) error
+	FilterTeamMembers(ctx context.Context, arg FilterTeamMembersParams) ([]int32, error)
+	GetClaim(ctx context.Context, id int32) (Claim, error)
+	GetClaimByName(ctx context.Context, name string) (Claim, error)
+	GetInitialTaskStatus(ctx context.Context, teamID *int32) (TaskStatus, error)
+	GetLastStreamOutboxEvent(ctx context.Context) (OutboxEvent, error)
+	GetLatestUserToken(ctx context.Context, arg GetLatestUserTokenParams) (UserToken, error)
+	GetNotificationPreferences(ctx context.Context, userID int32) (NotificationPreference, error)
+	GetOutboxEvent(ctx context.Context, id int64) (OutboxEvent, error)
+	GetRole(ctx context.Context, id int32) (Role, error)
+	GetRoleByName(ctx context.Context, name string) (Role, error)
+	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	GetTask(ctx context.Context, id int32) (Task, error)
+	GetTaskAttachment(ctx context.Context, id int32) (TaskAttachment, error)
+	GetTaskComment(ctx context.Context, id int32) (TaskComment, error)
+	GetTaskCommentForUpdate(ctx context.Context, id int32) (TaskComment, error)
+	GetTaskStatus(ctx context.Context, id int32) (TaskStatus, error)
+	GetTaskTemplate(ctx context.Context, id int32) (TaskTemplate, error)
+	GetTeam(ctx context.Context, id int32) (Team, error)
+	GetTeamMember(ctx context.Context, arg GetTeamMemberParams) (TeamMember, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserById(ctx context.Context, id int32) (User, error)
+	GetUserTokenByHash(ctx context.Context, arg GetUserTokenByHashParams) (UserToken, error)
+	GetWebhook(ctx context.Context, id int32) (Webhook, error)
+	GetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	HasTaskDependencyPath(ctx context.Context, arg HasTaskDependencyPathParams) (bool, error)
+	InvalidateUserTokens(ctx context.Context, arg InvalidateUserTokensParams) error
+	IsTaskAncestor(ctx context.Context, arg IsTaskAncestorParams) (bool, error)
+	ListBlockedTasks(ctx context.Context, blockerID int32) ([]Task, error)
+	ListBlockerGraph(ctx context.Context, taskID int32) ([]ListBlockerGraphRow, error)
+	ListClaims(ctx context.Context) ([]Claim, error)
+	ListDigestRecipients(ctx context.Context, arg ListDigestRecipientsParams) ([]ListDigestRecipientsRow, error)
+	ListDueTaskTemplates(ctx context.Context, arg ListDueTaskTemplatesParams) ([]TaskTemplate, error)
+	ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error)
+	ListOpenBlockers(ctx context.Context, blockedID int32) ([]Task, error)
+	ListOutboxEventsAfter(ctx context.Context, arg ListOutboxEventsAfterParams) ([]OutboxEvent, error)
+	ListRoleClaims(ctx context.Context, roleID int32) ([]Claim, error)
+	ListRoles(ctx context.Context) ([]Role, error)
+	ListSubtaskTree(ctx context.Context, taskID int32) ([]Task, error)
+	ListSubtasks(ctx context.Context, parentID int32) ([]Task, error)
+	ListTaskAssignees(ctx context.Context, taskID int32) ([]ListTaskAssigneesRow, error)
+	ListTaskAttachments(ctx context.Context, taskID int32) ([]TaskAttachment, error)
+	ListTaskCommentMentions(ctx context.Context, commentIds []int32) ([]ListTaskCommentMentionsRow, error)
+	ListTaskCommentRevisions(ctx context.Context, commentID int32) ([]TaskCommentRevision, error)
+	ListTaskComments(ctx context.Context, taskID int32) ([]TaskComment, error)
+	ListTaskStatusTransitions(ctx context.Context, teamID *int32) ([]TaskStatusTransition, error)
+	ListTaskStatuses(ctx context.Context, teamID *int32) ([]TaskStatus, error)
+	ListTaskTemplates(ctx context.Context, arg ListTaskTemplatesParams) ([]TaskTemplate, error)
+	ListTaskWatchers(ctx context.Context, taskID int32) ([]ListTaskWatchersRow, error)
+	ListTasksByIDs(ctx context.Context, ids []int32) ([]Task, error)
+	ListTeamAttachmentKeys(ctx context.Context, teamID int32) ([]string, error)
+	ListTeamMembers(ctx context.Context, teamID int32) ([]ListTeamMembersRow, error)
+	ListTeamOutboxEventsAfter(ctx context.Context, arg ListTeamOutboxEventsAfterParams) ([]OutboxEvent, error)
+	ListTeamTaskIDs(ctx context.Context, teamID int32) ([]int32, error)
+	ListTeamWebhooks(ctx context.Context, teamID int32) ([]Webhook, error)
+	ListTeamsByUser(ctx context.Context, userID int32) ([]Team, error)
+	ListUnreadNotificationsSince(ctx context.Context, arg ListUnreadNotificationsSinceParams) ([]Notification, error)
+	ListUserClaims(ctx context.Context, userID int32) ([]string, error)
+	ListUserRoles(ctx context.Context, userID int32) ([]Role, error)
+	ListUsersByEmails(ctx context.Context, emails []string) ([]ListUsersByEmailsRow, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListWebhooksForEvent(ctx context.Context, arg ListWebhooksForEventParams) ([]Webhook, error)
+	MarkAllNotificationsRead(ctx context.Context, userID int32) (int64, error)
+	MarkDigestSent(ctx context.Context, arg MarkDigestSentParams) error
+	MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (Notification, error)
+	MarkNotificationUnread(ctx context.Context, arg MarkNotificationUnreadParams) (Notification, error)
+	MarkOutboxEventDispatched(ctx context.Context, id int64) error
+	MarkUserEmailVerified(ctx context.Context, arg MarkUserEmailVerifiedParams) (int64, error)
+	RecordWebhookDeliveryAttempt(ctx context.Context, arg RecordWebhookDeliveryAttemptParams) (WebhookDelivery, error)
+	RecordWebhookFailure(ctx context.Context, arg RecordWebhookFailureParams) (Webhook, error)
+	RemoveRoleClaim(ctx context.Context, arg RemoveRoleClaimParams) error
+	RemoveTaskAssignee(ctx context.Context, arg RemoveTaskAssigneeParams) error
+	RemoveTaskWatcher(ctx context.Context, arg RemoveTaskWatcherParams) error
+	RemoveTeamMember(ctx context.Context, arg RemoveTeamMemberParams) error
+	RemoveUserRole(ctx context.Context, arg RemoveUserRoleParams) error
+	ResetWebhookFailures(ctx context.Context, id int32) error
+	RetryOutboxEvent(ctx context.Context, arg RetryOutboxEventParams) error
+	RevokeSessionFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeUserSessions(ctx context.Context, userID int32) error
+	RotateSession(ctx context.Context, id uuid.UUID) (int64, error)
+	SearchTaskComments(ctx context.Context, arg SearchTaskCommentsParams) ([]SearchTaskCommentsRow, error)
+	SearchTasks(ctx context.Context, arg SearchTasksParams) ([]SearchTasksRow, error)
+	SearchTeams(ctx context.Context, arg SearchTeamsParams) ([]SearchTeamsRow, error)
+	TransitionTask(ctx context.Context, arg TransitionTaskParams) (Task, error)
+	UpdateRole(ctx context.Context, arg UpdateRoleParams) (Role, error)
+	UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, error)
+	UpdateTaskComment(ctx context.Context, arg UpdateTaskCommentParams) (TaskComment, error)
+	UpdateTaskParent(ctx context.Context, arg UpdateTaskParentParams) (Task, error)
+	UpdateTaskStatus(ctx context.Context, arg UpdateTaskStatusParams) (TaskStatus, error)
+	UpdateTeam(ctx context.Context, arg UpdateTeamParams) (Team, error)
+	UpdateTeamOwner(ctx context.Context, arg UpdateTeamOwnerParams) (Team, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
 	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
+	UpdateWebhook(ctx context.Context, arg UpdateWebhookParams) (Webhook, error)
+	UpsertNotificationPreferences(ctx context.Context, arg UpsertNotificationPreferencesParams) (NotificationPreference, error)
+	UseUserToken(ctx context.Context, id int32) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,131 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/bolusarz/task-manager/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomRole(t *testing.T) Role {
+	arg := CreateRoleParams{
+		Name:        util.RandomString(12),
+		Description: pgtype.Text{String: util.RandomString(20), Valid: true},
+	}
+
+	role, err := testQueries.CreateRole(t.Context(), arg)
+
+	require.NoError(t, err)
+	require.Equal(t, arg.Name, role.Name)
+	require.Equal(t, arg.Description, role.Description)
+	require.NotZero(t, role.ID)
+
+	return role
+}
+
+func TestCreateRole(t *testing.T) {
+	role := createRandomRole(t)
+
+	_, err := testQueries.CreateRole(t.Context(), CreateRoleParams{Name: role.Name})
+	require.Error(t, err)
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+}
+
+func TestGetRole(t *testing.T) {
+	role := createRandomRole(t)
+
+	fetchedRole, err := testQueries.GetRole(t.Context(), role.ID)
+	require.NoError(t, err)
+	require.Equal(t, role, fetchedRole)
+
+	fetchedRole, err = testQueries.GetRoleByName(t.Context(), role.Name)
+	require.NoError(t, err)
+	require.Equal(t, role, fetchedRole)
+}
+
+func TestUpdateRole(t *testing.T) {
+	role := createRandomRole(t)
+
+	arg := UpdateRoleParams{
+		ID:          role.ID,
+		Name:        util.RandomString(12),
+		Description: role.Description,
+	}
+
+	updatedRole, err := testQueries.UpdateRole(t.Context(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Name, updatedRole.Name)
+}
+
+func TestDeleteRole(t *testing.T) {
+	role := createRandomRole(t)
+
+	err := testQueries.DeleteRole(t.Context(), role.ID)
+	require.NoError(t, err)
+
+	_, err = testQueries.GetRole(t.Context(), role.ID)
+	require.EqualError(t, err, ErrRecordNotFound.Error())
+}
+
+func TestRoleClaimsAndUserRoles(t *testing.T) {
+	user := createRandomUser(t)
+	role := createRandomRole(t)
+
+	claim, err := testQueries.GetClaimByName(t.Context(), "can_delete_task")
+	require.NoError(t, err)
+
+	err = testQueries.AddRoleClaim(t.Context(), AddRoleClaimParams{RoleID: role.ID, ClaimID: claim.ID})
+	require.NoError(t, err)
+
+	// Adding the same claim twice is a no-op.
+	err = testQueries.AddRoleClaim(t.Context(), AddRoleClaimParams{RoleID: role.ID, ClaimID: claim.ID})
+	require.NoError(t, err)
+
+	claims, err := testQueries.ListRoleClaims(t.Context(), role.ID)
+	require.NoError(t, err)
+	require.Equal(t, []Claim{claim}, claims)
+
+	err = testQueries.AddUserRole(t.Context(), AddUserRoleParams{UserID: user.ID, RoleID: role.ID})
+	require.NoError(t, err)
+
+	roles, err := testQueries.ListUserRoles(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.Contains(t, roles, role)
+
+	names, err := testQueries.ListUserClaims(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.Contains(t, names, claim.Name)
+
+	err = testQueries.RemoveRoleClaim(t.Context(), RemoveRoleClaimParams{RoleID: role.ID, ClaimID: claim.ID})
+	require.NoError(t, err)
+
+	names, err = testQueries.ListUserClaims(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.NotContains(t, names, claim.Name)
+
+	err = testQueries.RemoveUserRole(t.Context(), RemoveUserRoleParams{UserID: user.ID, RoleID: role.ID})
+	require.NoError(t, err)
+
+	roles, err = testQueries.ListUserRoles(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.NotContains(t, roles, role)
+}
+
+func TestAddUserRoleByName(t *testing.T) {
+	user := createRandomUser(t)
+
+	before, err := testQueries.CountUsersWithRole(t.Context(), "member")
+	require.NoError(t, err)
+
+	err = testQueries.AddUserRoleByName(t.Context(), AddUserRoleByNameParams{UserID: user.ID, Name: "member"})
+	require.NoError(t, err)
+
+	after, err := testQueries.CountUsersWithRole(t.Context(), "member")
+	require.NoError(t, err)
+	require.Equal(t, before+1, after)
+
+	names, err := testQueries.ListUserClaims(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.Contains(t, names, "can_create_task")
+}
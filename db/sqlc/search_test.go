@@ -0,0 +1,148 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/bolusarz/task-manager/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// randomKeyword returns a word no other test row will contain. The k in the
+// middle keeps the stemmer away from its prefix.
+func randomKeyword() string {
+	return "qz" + util.RandomString(4) + "k" + util.RandomString(5)
+}
+
+func TestSearchTasksVisibility(t *testing.T) {
+	keyword := randomKeyword()
+	member := createRandomUser(t)
+	outsider := createRandomUser(t)
+	team := createRandomTeam(t, member)
+	status := initialTaskStatus(t, nil)
+
+	_, err := testQueries.AddTeamMember(t.Context(), AddTeamMemberParams{TeamID: team.ID, UserID: member.ID})
+	require.NoError(t, err)
+
+	teamTask, err := testQueries.CreateTask(t.Context(), CreateTaskParams{
+		Title: keyword + " report", TeamID: &team.ID, StatusID: status.ID,
+	})
+	require.NoError(t, err)
+
+	personalTask, err := testQueries.CreateTask(t.Context(), CreateTaskParams{
+		Title:       util.RandomString(12),
+		Description: pgtype.Text{String: "remember the " + keyword, Valid: true},
+		CreatedBy:   &outsider.ID,
+		StatusID:    status.ID,
+	})
+	require.NoError(t, err)
+
+	search := func(userID int32, viewAll bool) []int32 {
+		rows, err := testQueries.SearchTasks(t.Context(), SearchTasksParams{
+			Query: keyword, ViewAll: viewAll, UserID: userID, Limit: 10,
+		})
+		require.NoError(t, err)
+
+		ids := make([]int32, len(rows))
+		for i, row := range rows {
+			require.Contains(t, row.Snippet, "<mark>")
+			ids[i] = row.ID
+		}
+		return ids
+	}
+
+	require.Equal(t, []int32{teamTask.ID}, search(member.ID, false))
+	require.Equal(t, []int32{personalTask.ID}, search(outsider.ID, false))
+
+	// Title matches are weighted above description matches.
+	require.Equal(t, []int32{teamTask.ID, personalTask.ID}, search(outsider.ID, true))
+}
+
+func TestSearchTasksPrefixAndPhrase(t *testing.T) {
+	keyword := randomKeyword()
+	user := createRandomUser(t)
+
+	task, err := testQueries.CreateTask(t.Context(), CreateTaskParams{
+		Title:     "quarterly " + keyword + " review",
+		CreatedBy: &user.ID,
+		StatusID:  initialTaskStatus(t, nil).ID,
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		query string
+		found bool
+	}{
+		{query: keyword[:7] + ":*", found: true},
+		{query: "quarterly <-> " + keyword, found: true},
+		{query: keyword + " <-> quarterly", found: false},
+	}
+
+	for _, tt := range testCases {
+		rows, err := testQueries.SearchTasks(t.Context(), SearchTasksParams{
+			Query: tt.query, UserID: user.ID, Limit: 10,
+		})
+		require.NoError(t, err)
+
+		if tt.found {
+			require.Len(t, rows, 1, tt.query)
+			require.Equal(t, task.ID, rows[0].ID)
+		} else {
+			require.Empty(t, rows, tt.query)
+		}
+	}
+}
+
+func TestSearchTeams(t *testing.T) {
+	keyword := randomKeyword()
+	member := createRandomUser(t)
+	outsider := createRandomUser(t)
+
+	team, err := testQueries.CreateTeam(t.Context(), CreateTeamParams{
+		Name:        util.RandomString(10),
+		Description: pgtype.Text{String: "owns the " + keyword + " pipeline", Valid: true},
+		CreatedBy:   &member.ID,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.AddTeamMember(t.Context(), AddTeamMemberParams{TeamID: team.ID, UserID: member.ID})
+	require.NoError(t, err)
+
+	rows, err := testQueries.SearchTeams(t.Context(), SearchTeamsParams{Query: keyword, UserID: member.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, team.ID, rows[0].ID)
+	require.Contains(t, rows[0].Snippet, "<mark>"+keyword+"</mark>")
+
+	rows, err = testQueries.SearchTeams(t.Context(), SearchTeamsParams{Query: keyword, UserID: outsider.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Empty(t, rows)
+}
+
+func TestSearchTaskComments(t *testing.T) {
+	keyword := randomKeyword()
+	author := createRandomUser(t)
+	outsider := createRandomUser(t)
+
+	task, err := testQueries.CreateTask(t.Context(), CreateTaskParams{
+		Title:     util.RandomString(12),
+		CreatedBy: &author.ID,
+		StatusID:  initialTaskStatus(t, nil).ID,
+	})
+	require.NoError(t, err)
+
+	comment, err := testQueries.CreateTaskComment(t.Context(), CreateTaskCommentParams{
+		TaskID: task.ID, AuthorID: &author.ID, Body: "blocked on " + keyword, BodyHtml: "",
+	})
+	require.NoError(t, err)
+
+	rows, err := testQueries.SearchTaskComments(t.Context(), SearchTaskCommentsParams{Query: keyword, UserID: author.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, comment.ID, rows[0].ID)
+	require.Equal(t, task.Title, rows[0].TaskTitle)
+
+	rows, err = testQueries.SearchTaskComments(t.Context(), SearchTaskCommentsParams{Query: keyword, UserID: outsider.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Empty(t, rows)
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomSession(t *testing.T, user User, familyID uuid.UUID) Session {
+	arg := CreateSessionParams{
+		ID:        uuid.New(),
+		FamilyID:  familyID,
+		UserID:    user.ID,
+		UserAgent: "test-agent",
+		ClientIp:  "127.0.0.1",
+		ExpiresAt: time.Now().Add(time.Hour).UTC().Truncate(time.Second),
+	}
+
+	session, err := testQueries.CreateSession(t.Context(), arg)
+
+	require.NoError(t, err)
+	require.Equal(t, arg.ID, session.ID)
+	require.Equal(t, arg.FamilyID, session.FamilyID)
+	require.Equal(t, arg.UserID, session.UserID)
+	require.False(t, session.IsRevoked)
+	require.Nil(t, session.RotatedAt)
+
+	return session
+}
+
+func TestRotateSession(t *testing.T) {
+	session := createRandomSession(t, createRandomUser(t), uuid.New())
+
+	rotated, err := testQueries.RotateSession(t.Context(), session.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rotated)
+
+	// A session can only be rotated once.
+	rotated, err = testQueries.RotateSession(t.Context(), session.ID)
+	require.NoError(t, err)
+	require.Zero(t, rotated)
+
+	fetchedSession, err := testQueries.GetSession(t.Context(), session.ID)
+	require.NoError(t, err)
+	require.NotNil(t, fetchedSession.RotatedAt)
+}
+
+func TestRevokeSessionFamily(t *testing.T) {
+	user := createRandomUser(t)
+	familyID := uuid.New()
+
+	first := createRandomSession(t, user, familyID)
+	second := createRandomSession(t, user, familyID)
+	other := createRandomSession(t, user, uuid.New())
+
+	err := testQueries.RevokeSessionFamily(t.Context(), familyID)
+	require.NoError(t, err)
+
+	for _, id := range []uuid.UUID{first.ID, second.ID} {
+		session, err := testQueries.GetSession(t.Context(), id)
+		require.NoError(t, err)
+		require.True(t, session.IsRevoked)
+	}
+
+	session, err := testQueries.GetSession(t.Context(), other.ID)
+	require.NoError(t, err)
+	require.False(t, session.IsRevoked)
+
+	rotated, err := testQueries.RotateSession(t.Context(), first.ID)
+	require.NoError(t, err)
+	require.Zero(t, rotated)
+}
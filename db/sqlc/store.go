@@ -1,9 +1,44 @@
 package db
 
-import "github.com/jackc/pgx/v5/pgxpool"
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// maxTxAttempts bounds how many times a transaction is run when Postgres
+	// keeps aborting it with a serialization failure.
+	maxTxAttempts = 6
+	// txRetryBackoff is the longest wait before the first retry. It doubles
+	// with every further attempt.
+	txRetryBackoff = 10 * time.Millisecond
+)
 
 type Store interface {
 	Querier
+	ExecTx(ctx context.Context, fn func(*Queries) error) error
+	CreateUserWithRoles(ctx context.Context, arg CreateUserWithRolesParams) (User, error)
+	ChangeUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
+	UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error)
+	ListTasks(ctx context.Context, arg ListTasksParams) ([]Task, error)
+	ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error)
+	CreateTaskWithAssignees(ctx context.Context, arg CreateTaskWithAssigneesParams) (CreateTaskWithAssigneesResult, error)
+	UpdateTaskAssignees(ctx context.Context, arg UpdateTaskAssigneesParams) ([]ListTaskAssigneesRow, error)
+	SetTaskParent(ctx context.Context, arg SetTaskParentParams) (Task, error)
+	AddTaskDependency(ctx context.Context, arg CreateTaskDependencyParams) error
+	CreateTaskTemplateWithOccurrence(ctx context.Context, arg CreateTaskTemplateWithOccurrenceParams) (TaskOccurrenceResult, error)
+	MaterializeTaskOccurrence(ctx context.Context, arg MaterializeTaskOccurrenceParams) (TaskOccurrenceResult, error)
+	CreateTaskCommentWithMentions(ctx context.Context, arg CreateTaskCommentWithMentionsParams) (TaskComment, error)
+	EditTaskComment(ctx context.Context, arg EditTaskCommentParams) (TaskComment, error)
+	CreateTeamWithOwner(ctx context.Context, arg CreateTeamWithOwnerParams) (Team, error)
+	TransferTeamOwnership(ctx context.Context, arg TransferTeamOwnershipParams) (Team, error)
+	ReplaceSession(ctx context.Context, arg ReplaceSessionParams) (Session, error)
+	ConsumeUserToken(ctx context.Context, arg ConsumeUserTokenParams) error
 }
 
 type SQLStore struct {
@@ -17,3 +52,43 @@ func NewStore(db *pgxpool.Pool) Store {
 		Queries: New(db),
 	}
 }
+
+// ExecTx runs fn inside a serializable transaction, committing when fn returns
+// nil and rolling back otherwise. When Postgres aborts the transaction with a
+// serialization failure it is retried, so fn may run more than once and must
+// not have side effects outside the database. The composites rely on
+// serializable isolation for the checks they make before writing, such as
+// cycle detection and open blockers, instead of locking rows.
+func (store *SQLStore) ExecTx(ctx context.Context, fn func(*Queries) error) error {
+	for attempt := 1; ; attempt++ {
+		err := store.execTx(ctx, fn)
+		if ErrorCode(err) != SerializationFailure || attempt == maxTxAttempts {
+			return err
+		}
+
+		// Transactions retried at once tend to collide again, so each
+		// waits a random part of a window that grows with the attempts.
+		backoff := txRetryBackoff << (attempt - 1)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(rand.N(backoff)):
+		}
+	}
+}
+
+func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
+	tx, err := store.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return err
+	}
+
+	if err := fn(New(tx)); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			return fmt.Errorf("tx err: %w, rollback err: %v", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
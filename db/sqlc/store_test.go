@@ -0,0 +1,249 @@
+package db
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bolusarz/task-manager/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecTxRollback(t *testing.T) {
+	errAbort := errors.New("abort")
+	email := util.RandomEmail()
+
+	err := testStore.ExecTx(t.Context(), func(q *Queries) error {
+		_, err := q.CreateUser(t.Context(), CreateUserParams{
+			FirstName:    util.RandomString(10),
+			LastName:     util.RandomString(10),
+			Email:        email,
+			PasswordHash: util.RandomString(12),
+			Timezone:     "UTC",
+		})
+		require.NoError(t, err)
+		return errAbort
+	})
+	require.ErrorIs(t, err, errAbort)
+
+	_, err = testQueries.GetUserByEmail(t.Context(), email)
+	require.EqualError(t, err, ErrRecordNotFound.Error())
+}
+
+func TestCreateUserWithRoles(t *testing.T) {
+	arg := CreateUserWithRolesParams{
+		CreateUserParams: CreateUserParams{
+			FirstName:    util.RandomString(10),
+			LastName:     util.RandomString(10),
+			Email:        util.RandomEmail(),
+			PasswordHash: util.RandomString(12),
+			Timezone:     "UTC",
+		},
+		AfterCreate: func(q Querier, user User) error {
+			return q.AddUserRoleByName(t.Context(), AddUserRoleByNameParams{UserID: user.ID, Name: "member"})
+		},
+	}
+
+	user, err := testStore.CreateUserWithRoles(t.Context(), arg)
+	require.NoError(t, err)
+
+	names, err := testQueries.ListUserClaims(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.Contains(t, names, "can_create_task")
+
+	// A failing AfterCreate must not leave the user behind.
+	arg.Email = util.RandomEmail()
+	arg.AfterCreate = func(q Querier, user User) error {
+		return q.AddUserRoleByName(t.Context(), AddUserRoleByNameParams{UserID: user.ID, Name: util.RandomString(12)})
+	}
+
+	_, err = testStore.CreateUserWithRoles(t.Context(), arg)
+	require.Error(t, err)
+
+	_, err = testQueries.GetUserByEmail(t.Context(), arg.Email)
+	require.EqualError(t, err, ErrRecordNotFound.Error())
+}
+
+func TestCreateTaskWithAssignees(t *testing.T) {
+	owner := createRandomUser(t)
+	assignees := []int32{createRandomUser(t).ID, createRandomUser(t).ID}
+
+	result, err := testStore.CreateTaskWithAssignees(t.Context(), CreateTaskWithAssigneesParams{
+		CreateTaskParams: CreateTaskParams{
+			Title:     util.RandomString(20),
+			CreatedBy: &owner.ID,
+			StatusID:  initialTaskStatus(t, nil).ID,
+		},
+		AssigneeIDs: assignees,
+	})
+	require.NoError(t, err)
+	require.NotZero(t, result.Task.ID)
+	require.Equal(t, assignees, result.AssigneeIDs)
+
+	// An unknown assignee rolls the whole task back.
+	title := util.RandomString(20)
+	_, err = testStore.CreateTaskWithAssignees(t.Context(), CreateTaskWithAssigneesParams{
+		CreateTaskParams: CreateTaskParams{Title: title, CreatedBy: &owner.ID, StatusID: initialTaskStatus(t, nil).ID},
+		AssigneeIDs:      []int32{assignees[0], -1},
+	})
+	require.Error(t, err)
+	require.Equal(t, ForeignKeyViolation, ErrorCode(err))
+
+	var count int64
+	err = testDB.QueryRow(t.Context(), "select count(*) from tasks where title = $1", title).Scan(&count)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
+func TestUpdateTaskAssignees(t *testing.T) {
+	task := createRandomTask(t)
+	first := createRandomUser(t)
+	second := createRandomUser(t)
+
+	assignees, err := testStore.UpdateTaskAssignees(t.Context(), UpdateTaskAssigneesParams{
+		TaskID: task.ID,
+		Add:    []int32{first.ID},
+	})
+	require.NoError(t, err)
+	require.Len(t, assignees, 1)
+	require.Equal(t, first.ID, assignees[0].UserID)
+	require.NotZero(t, assignees[0].AssignedAt)
+
+	// Assigning the same user again leaves their assignment untouched.
+	assignees, err = testStore.UpdateTaskAssignees(t.Context(), UpdateTaskAssigneesParams{
+		TaskID: task.ID,
+		Add:    []int32{first.ID, second.ID},
+	})
+	require.NoError(t, err)
+	require.Len(t, assignees, 2)
+	require.Equal(t, first.ID, assignees[0].UserID)
+	require.Equal(t, second.ID, assignees[1].UserID)
+
+	assignees, err = testStore.UpdateTaskAssignees(t.Context(), UpdateTaskAssigneesParams{
+		TaskID: task.ID,
+		Remove: []int32{first.ID},
+	})
+	require.NoError(t, err)
+	require.Len(t, assignees, 1)
+	require.Equal(t, second.ID, assignees[0].UserID)
+}
+
+func TestCreateTeamWithOwner(t *testing.T) {
+	owner := createRandomUser(t)
+
+	team, err := testStore.CreateTeamWithOwner(t.Context(), CreateTeamWithOwnerParams{
+		Name:    util.RandomString(10),
+		OwnerID: owner.ID,
+	})
+	require.NoError(t, err)
+
+	member, err := testQueries.GetTeamMember(t.Context(), GetTeamMemberParams{TeamID: team.ID, UserID: owner.ID})
+	require.NoError(t, err)
+	require.Equal(t, owner.ID, member.UserID)
+
+	defaults, err := testQueries.ListTaskStatuses(t.Context(), nil)
+	require.NoError(t, err)
+
+	statuses, err := testQueries.ListTaskStatuses(t.Context(), &team.ID)
+	require.NoError(t, err)
+	require.Len(t, statuses, len(defaults))
+	for i, status := range statuses {
+		require.Equal(t, defaults[i].Name, status.Name)
+		require.Equal(t, defaults[i].Category, status.Category)
+		require.NotEqual(t, defaults[i].ID, status.ID)
+	}
+
+	defaultTransitions, err := testQueries.ListTaskStatusTransitions(t.Context(), nil)
+	require.NoError(t, err)
+
+	transitions, err := testQueries.ListTaskStatusTransitions(t.Context(), &team.ID)
+	require.NoError(t, err)
+	require.Len(t, transitions, len(defaultTransitions))
+}
+
+func TestTransferTeamOwnership(t *testing.T) {
+	owner := createRandomUser(t)
+	member := createRandomUser(t)
+	outsider := createRandomUser(t)
+	team := createRandomTeam(t, owner)
+
+	_, err := testQueries.AddTeamMember(t.Context(), AddTeamMemberParams{TeamID: team.ID, UserID: member.ID})
+	require.NoError(t, err)
+
+	_, err = testStore.TransferTeamOwnership(t.Context(), TransferTeamOwnershipParams{TeamID: team.ID, NewOwnerID: outsider.ID})
+	require.EqualError(t, err, ErrRecordNotFound.Error())
+
+	updatedTeam, err := testStore.TransferTeamOwnership(t.Context(), TransferTeamOwnershipParams{TeamID: team.ID, NewOwnerID: member.ID})
+	require.NoError(t, err)
+	require.Equal(t, &member.ID, updatedTeam.CreatedBy)
+}
+
+func TestReplaceSessionConcurrently(t *testing.T) {
+	user := createRandomUser(t)
+	session := createRandomSession(t, user, uuid.New())
+
+	const n = 5
+	errs := make(chan error, n)
+
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := testStore.ReplaceSession(t.Context(), ReplaceSessionParams{
+				ID: session.ID,
+				Next: CreateSessionParams{
+					ID:        uuid.New(),
+					FamilyID:  session.FamilyID,
+					UserID:    user.ID,
+					UserAgent: session.UserAgent,
+					ClientIp:  session.ClientIp,
+					ExpiresAt: session.ExpiresAt,
+				},
+			})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		require.ErrorIs(t, err, ErrSessionRotated)
+	}
+	require.Equal(t, 1, succeeded)
+}
+
+func TestChangeUserPassword(t *testing.T) {
+	user := createRandomUser(t)
+	session := createRandomSession(t, user, uuid.New())
+	changedAt := time.Now().UTC().Truncate(time.Second)
+
+	err := testStore.ChangeUserPassword(t.Context(), UpdateUserPasswordParams{
+		ID:                user.ID,
+		PasswordHash:      util.RandomString(12),
+		PasswordChangedAt: &changedAt,
+	})
+	require.NoError(t, err)
+
+	fetchedUser, err := testQueries.GetUserById(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.NotEqual(t, user.PasswordHash, fetchedUser.PasswordHash)
+	require.NotNil(t, fetchedUser.PasswordChangedAt)
+	require.WithinDuration(t, changedAt, *fetchedUser.PasswordChangedAt, time.Second)
+
+	fetchedSession, err := testQueries.GetSession(t.Context(), session.ID)
+	require.NoError(t, err)
+	require.True(t, fetchedSession.IsRevoked)
+
+	events := listAggregateOutboxEvents(t, AggregateUser, user.ID)
+	require.Len(t, events, 1)
+	require.Equal(t, string(EventSessionsRevoked), events[0].EventType)
+	require.Nil(t, events[0].TeamID)
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/bolusarz/task-manager/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomTaskAttachment(t *testing.T, task Task, uploader User) TaskAttachment {
+	arg := CreateTaskAttachmentParams{
+		TaskID:      task.ID,
+		UploadedBy:  &uploader.ID,
+		FileName:    util.RandomString(8) + ".txt",
+		ContentType: "text/plain; charset=utf-8",
+		SizeBytes:   util.RandomInt(1, 1000),
+		StorageKey:  fmt.Sprintf("tasks/%d/%s", task.ID, util.RandomString(16)),
+	}
+
+	attachment, err := testQueries.CreateTaskAttachment(t.Context(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, attachment.ID)
+	require.Equal(t, arg.TaskID, attachment.TaskID)
+	require.Equal(t, arg.UploadedBy, attachment.UploadedBy)
+	require.Equal(t, arg.FileName, attachment.FileName)
+	require.Equal(t, arg.ContentType, attachment.ContentType)
+	require.Equal(t, arg.SizeBytes, attachment.SizeBytes)
+	require.Equal(t, arg.StorageKey, attachment.StorageKey)
+	require.NotZero(t, attachment.CreatedAt)
+
+	return attachment
+}
+
+func TestCreateTaskAttachment(t *testing.T) {
+	task := createRandomTask(t)
+	user := createRandomUser(t)
+
+	attachment := createRandomTaskAttachment(t, task, user)
+
+	fetched, err := testQueries.GetTaskAttachment(t.Context(), attachment.ID)
+	require.NoError(t, err)
+	require.Equal(t, attachment, fetched)
+}
+
+func TestListTaskAttachments(t *testing.T) {
+	task := createRandomTask(t)
+	user := createRandomUser(t)
+
+	first := createRandomTaskAttachment(t, task, user)
+	second := createRandomTaskAttachment(t, task, user)
+	createRandomTaskAttachment(t, createRandomTask(t), user)
+
+	attachments, err := testQueries.ListTaskAttachments(t.Context(), task.ID)
+	require.NoError(t, err)
+	require.Equal(t, []TaskAttachment{first, second}, attachments)
+}
+
+func TestListTeamAttachmentKeys(t *testing.T) {
+	user := createRandomUser(t)
+	team := createRandomTeam(t, user)
+
+	task, err := testQueries.CreateTask(t.Context(), CreateTaskParams{
+		Title:    util.RandomString(12),
+		TeamID:   &team.ID,
+		StatusID: initialTaskStatus(t, nil).ID,
+	})
+	require.NoError(t, err)
+
+	attachment := createRandomTaskAttachment(t, task, user)
+	createRandomTaskAttachment(t, createRandomTask(t), user)
+
+	keys, err := testQueries.ListTeamAttachmentKeys(t.Context(), team.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{attachment.StorageKey}, keys)
+
+	err = testQueries.DeleteTeam(t.Context(), team.ID)
+	require.NoError(t, err)
+
+	_, err = testQueries.GetTaskAttachment(t.Context(), attachment.ID)
+	require.EqualError(t, err, ErrRecordNotFound.Error())
+}
+
+func TestDeleteTaskAttachment(t *testing.T) {
+	attachment := createRandomTaskAttachment(t, createRandomTask(t), createRandomUser(t))
+
+	err := testQueries.DeleteTaskAttachment(t.Context(), attachment.ID)
+	require.NoError(t, err)
+
+	_, err = testQueries.GetTaskAttachment(t.Context(), attachment.ID)
+	require.EqualError(t, err, ErrRecordNotFound.Error())
+}
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/bolusarz/task-manager/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomTaskComment(t *testing.T, task Task, author User, mentions ...int32) TaskComment {
+	arg := CreateTaskCommentWithMentionsParams{
+		CreateTaskCommentParams: CreateTaskCommentParams{
+			TaskID:   task.ID,
+			AuthorID: &author.ID,
+			Body:     util.RandomString(20),
+			BodyHtml: "<p>" + util.RandomString(20) + "</p>",
+		},
+		MentionIDs: mentions,
+	}
+
+	comment, err := testStore.CreateTaskCommentWithMentions(t.Context(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, comment.ID)
+	require.Equal(t, task.ID, comment.TaskID)
+	require.Equal(t, arg.AuthorID, comment.AuthorID)
+	require.Equal(t, arg.Body, comment.Body)
+	require.Equal(t, arg.BodyHtml, comment.BodyHtml)
+	require.Nil(t, comment.EditedAt)
+
+	return comment
+}
+
+func TestCreateTaskCommentWithMentions(t *testing.T) {
+	task := createRandomTask(t)
+	author := createRandomUser(t)
+	mentioned := createRandomUser(t)
+
+	comment := createRandomTaskComment(t, task, author, mentioned.ID)
+	createRandomTaskComment(t, task, author)
+
+	comments, err := testQueries.ListTaskComments(t.Context(), task.ID)
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	require.Equal(t, comment, comments[0])
+
+	mentions, err := testQueries.ListTaskCommentMentions(t.Context(), []int32{comments[0].ID, comments[1].ID})
+	require.NoError(t, err)
+	require.Len(t, mentions, 1)
+	require.Equal(t, comment.ID, mentions[0].CommentID)
+	require.Equal(t, mentioned.ID, mentions[0].UserID)
+	require.Equal(t, mentioned.Email, mentions[0].Email)
+}
+
+func TestEditTaskComment(t *testing.T) {
+	task := createRandomTask(t)
+	author := createRandomUser(t)
+	first := createRandomUser(t)
+	second := createRandomUser(t)
+
+	comment := createRandomTaskComment(t, task, author, first.ID)
+
+	edited, err := testStore.EditTaskComment(t.Context(), EditTaskCommentParams{
+		ID:         comment.ID,
+		Body:       "edited",
+		BodyHtml:   "<p>edited</p>",
+		EditedBy:   &author.ID,
+		MentionIDs: []int32{second.ID},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "edited", edited.Body)
+	require.Equal(t, "<p>edited</p>", edited.BodyHtml)
+	require.NotNil(t, edited.EditedAt)
+
+	revisions, err := testQueries.ListTaskCommentRevisions(t.Context(), comment.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	require.Equal(t, comment.Body, revisions[0].Body)
+	require.Equal(t, author.ID, *revisions[0].EditedBy)
+
+	mentions, err := testQueries.ListTaskCommentMentions(t.Context(), []int32{comment.ID})
+	require.NoError(t, err)
+	require.Len(t, mentions, 1)
+	require.Equal(t, second.ID, mentions[0].UserID)
+}
+
+func TestDeleteTaskComment(t *testing.T) {
+	task := createRandomTask(t)
+	author := createRandomUser(t)
+	comment := createRandomTaskComment(t, task, author, author.ID)
+
+	err := testQueries.DeleteTaskComment(t.Context(), comment.ID)
+	require.NoError(t, err)
+
+	_, err = testQueries.GetTaskComment(t.Context(), comment.ID)
+	require.EqualError(t, err, ErrRecordNotFound.Error())
+
+	mentions, err := testQueries.ListTaskCommentMentions(t.Context(), []int32{comment.ID})
+	require.NoError(t, err)
+	require.Empty(t, mentions)
+}
+
+func TestListUsersByEmails(t *testing.T) {
+	user := createRandomUser(t)
+	createRandomUser(t)
+
+	users, err := testQueries.ListUsersByEmails(t.Context(), []string{user.Email, "nobody@example.com"})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	require.Equal(t, user.ID, users[0].ID)
+}
@@ -0,0 +1,155 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func setRandomTaskParent(t *testing.T, task, parent Task) Task {
+	updated, err := testStore.SetTaskParent(t.Context(), SetTaskParentParams{ID: task.ID, ParentID: &parent.ID})
+	require.NoError(t, err)
+	require.Equal(t, parent.ID, *updated.ParentID)
+	return updated
+}
+
+func addRandomTaskDependency(t *testing.T, blocker, blocked Task) {
+	err := testStore.AddTaskDependency(t.Context(), CreateTaskDependencyParams{BlockerID: blocker.ID, BlockedID: blocked.ID})
+	require.NoError(t, err)
+}
+
+func TestSetTaskParent(t *testing.T) {
+	root := createRandomTask(t)
+	child := setRandomTaskParent(t, createRandomTask(t), root)
+	grandchild := setRandomTaskParent(t, createRandomTask(t), child)
+
+	_, err := testStore.SetTaskParent(t.Context(), SetTaskParentParams{ID: root.ID, ParentID: &root.ID})
+	require.ErrorIs(t, err, ErrDependencyCycle)
+
+	_, err = testStore.SetTaskParent(t.Context(), SetTaskParentParams{ID: root.ID, ParentID: &grandchild.ID})
+	require.ErrorIs(t, err, ErrDependencyCycle)
+
+	subtasks, err := testQueries.ListSubtasks(t.Context(), root.ID)
+	require.NoError(t, err)
+	require.Equal(t, []Task{child}, subtasks)
+
+	tree, err := testQueries.ListSubtaskTree(t.Context(), root.ID)
+	require.NoError(t, err)
+	require.Equal(t, []Task{child, grandchild}, tree)
+
+	detached, err := testStore.SetTaskParent(t.Context(), SetTaskParentParams{ID: grandchild.ID})
+	require.NoError(t, err)
+	require.Nil(t, detached.ParentID)
+}
+
+func TestDeleteParentTask(t *testing.T) {
+	parent := createRandomTask(t)
+	child := setRandomTaskParent(t, createRandomTask(t), parent)
+
+	require.NoError(t, testQueries.DeleteTask(t.Context(), parent.ID))
+
+	fetched, err := testQueries.GetTask(t.Context(), child.ID)
+	require.NoError(t, err)
+	require.Nil(t, fetched.ParentID)
+}
+
+func TestAddTaskDependency(t *testing.T) {
+	first := createRandomTask(t)
+	second := createRandomTask(t)
+	third := createRandomTask(t)
+
+	addRandomTaskDependency(t, first, second)
+	addRandomTaskDependency(t, second, third)
+
+	// Adding the same link twice is not an error.
+	addRandomTaskDependency(t, first, second)
+
+	err := testStore.AddTaskDependency(t.Context(), CreateTaskDependencyParams{BlockerID: first.ID, BlockedID: first.ID})
+	require.ErrorIs(t, err, ErrDependencyCycle)
+
+	err = testStore.AddTaskDependency(t.Context(), CreateTaskDependencyParams{BlockerID: second.ID, BlockedID: first.ID})
+	require.ErrorIs(t, err, ErrDependencyCycle)
+
+	err = testStore.AddTaskDependency(t.Context(), CreateTaskDependencyParams{BlockerID: third.ID, BlockedID: first.ID})
+	require.ErrorIs(t, err, ErrDependencyCycle)
+
+	graph, err := testQueries.ListBlockerGraph(t.Context(), third.ID)
+	require.NoError(t, err)
+	require.Equal(t, []ListBlockerGraphRow{
+		{BlockerID: first.ID, BlockedID: second.ID},
+		{BlockerID: second.ID, BlockedID: third.ID},
+	}, graph)
+
+	blocked, err := testQueries.ListBlockedTasks(t.Context(), first.ID)
+	require.NoError(t, err)
+	require.Equal(t, []Task{second}, blocked)
+
+	deleted, err := testQueries.DeleteTaskDependency(t.Context(), DeleteTaskDependencyParams{BlockerID: first.ID, BlockedID: second.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+
+	deleted, err = testQueries.DeleteTaskDependency(t.Context(), DeleteTaskDependencyParams{BlockerID: first.ID, BlockedID: second.ID})
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+}
+
+func TestListOpenBlockers(t *testing.T) {
+	task := createRandomTask(t)
+	open := createRandomTask(t)
+	finished := createRandomTask(t)
+
+	addRandomTaskDependency(t, open, task)
+	addRandomTaskDependency(t, finished, task)
+
+	statuses, err := testQueries.ListTaskStatuses(t.Context(), nil)
+	require.NoError(t, err)
+
+	for _, status := range statuses {
+		if status.Category == "done" {
+			_, err = testQueries.TransitionTask(t.Context(), TransitionTaskParams{ID: finished.ID, ToStatusID: status.ID})
+			break
+		}
+	}
+	require.NoError(t, err)
+
+	blockers, err := testQueries.ListOpenBlockers(t.Context(), task.ID)
+	require.NoError(t, err)
+	require.Len(t, blockers, 1)
+	require.Equal(t, open.ID, blockers[0].ID)
+}
+
+func TestTransitionBlockedTask(t *testing.T) {
+	task := createRandomTask(t)
+	blocker := createRandomTask(t)
+	addRandomTaskDependency(t, blocker, task)
+
+	statuses, err := testQueries.ListTaskStatuses(t.Context(), nil)
+	require.NoError(t, err)
+
+	var done TaskStatus
+	for _, status := range statuses {
+		if status.Category == "done" {
+			done = status
+			break
+		}
+	}
+	require.NotZero(t, done.ID)
+
+	_, err = testStore.TransitionTask(t.Context(), TransitionTaskParams{ID: task.ID, ToStatusID: done.ID})
+	var blocked *TaskBlockedError
+	require.ErrorAs(t, err, &blocked)
+	require.Len(t, blocked.Blockers, 1)
+	require.Equal(t, blocker.ID, blocked.Blockers[0].ID)
+
+	// The move was rolled back.
+	fetched, err := testQueries.GetTask(t.Context(), task.ID)
+	require.NoError(t, err)
+	require.Equal(t, task.StatusID, fetched.StatusID)
+
+	_, err = testStore.TransitionTask(t.Context(), TransitionTaskParams{ID: blocker.ID, ToStatusID: done.ID})
+	require.NoError(t, err)
+
+	moved, err := testStore.TransitionTask(t.Context(), TransitionTaskParams{ID: task.ID, ToStatusID: done.ID})
+	require.NoError(t, err)
+	require.Equal(t, done.ID, moved.StatusID)
+}
@@ -0,0 +1,224 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TaskSortField is a column tasks can be ordered by.
+type TaskSortField string
+
+const (
+	TaskSortCreatedAt TaskSortField = "created_at"
+	TaskSortDueDate   TaskSortField = "due_date"
+	TaskSortTitle     TaskSortField = "title"
+)
+
+// NoDueDate stands in for a missing due date when ordering by due date, so
+// tasks without one sort after every task that has one.
+var NoDueDate = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+var taskSortExpressions = map[TaskSortField]string{
+	TaskSortCreatedAt: "t.created_at",
+	TaskSortDueDate:   "coalesce(t.due_date, '9999-12-31 00:00:00+00'::timestamptz)",
+	TaskSortTitle:     "t.title",
+}
+
+type TaskSort struct {
+	Field TaskSortField `json:"field"`
+	Desc  bool          `json:"desc"`
+}
+
+// TaskCursor holds the sort key of the last task on a page. The next page
+// starts right after it.
+type TaskCursor struct {
+	CreatedAt time.Time `json:"createdAt"`
+	DueDate   time.Time `json:"dueDate"`
+	Title     string    `json:"title"`
+	ID        int32     `json:"id"`
+}
+
+// NewTaskCursor returns the cursor pointing just past task.
+func NewTaskCursor(task Task) TaskCursor {
+	dueDate := NoDueDate
+	if task.DueDate != nil {
+		dueDate = *task.DueDate
+	}
+
+	return TaskCursor{
+		CreatedAt: task.CreatedAt,
+		DueDate:   dueDate,
+		Title:     task.Title,
+		ID:        task.ID,
+	}
+}
+
+func (c TaskCursor) value(field TaskSortField) any {
+	switch field {
+	case TaskSortDueDate:
+		return c.DueDate
+	case TaskSortTitle:
+		return c.Title
+	default:
+		return c.CreatedAt
+	}
+}
+
+type ListTasksParams struct {
+	StatusID       *int32     `json:"statusId"`
+	StatusCategory *string    `json:"statusCategory"`
+	AssigneeID     *int32     `json:"assigneeId"`
+	CreatedBy      *int32     `json:"createdBy"`
+	TeamID         *int32     `json:"teamId"`
+	DueAfter       *time.Time `json:"dueAfter"`
+	DueBefore      *time.Time `json:"dueBefore"`
+	// OverdueAt keeps only tasks that were due before this time and are not
+	// in a done status yet.
+	OverdueAt *time.Time `json:"overdueAt"`
+	// Unless ViewAll is set, only the tasks UserID can see are listed: those
+	// of their teams and the personal tasks they created or are assigned.
+	ViewAll bool        `json:"viewAll"`
+	UserID  int32       `json:"userId"`
+	Sort    []TaskSort  `json:"sort"`
+	After   *TaskCursor `json:"after"`
+	Limit   int32       `json:"limit"`
+}
+
+// ListTasks returns the tasks matching the filters in the requested order,
+// using keyset pagination: After is the cursor of the last task of the
+// previous page. The task ID always breaks ties so the order is total.
+func (q *Queries) ListTasks(ctx context.Context, arg ListTasksParams) ([]Task, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+
+	param := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if !arg.ViewAll {
+		userID := param(arg.UserID)
+		conditions = append(conditions, "(exists (select 1 from team_members tm where tm.team_id = t.team_id and tm.user_id = "+userID+")"+
+			" or (t.team_id is null and (t.created_by = "+userID+
+			" or exists (select 1 from user_tasks ut where ut.task_id = t.id and ut.user_id = "+userID+"))))")
+	}
+	if arg.StatusID != nil {
+		conditions = append(conditions, "t.status_id = "+param(*arg.StatusID))
+	}
+	if arg.StatusCategory != nil {
+		conditions = append(conditions, "s.category = "+param(*arg.StatusCategory))
+	}
+	if arg.AssigneeID != nil {
+		conditions = append(conditions, "exists (select 1 from user_tasks ut where ut.task_id = t.id and ut.user_id = "+param(*arg.AssigneeID)+")")
+	}
+	if arg.CreatedBy != nil {
+		conditions = append(conditions, "t.created_by = "+param(*arg.CreatedBy))
+	}
+	if arg.TeamID != nil {
+		conditions = append(conditions, "t.team_id = "+param(*arg.TeamID))
+	}
+	if arg.DueAfter != nil {
+		conditions = append(conditions, "t.due_date >= "+param(*arg.DueAfter))
+	}
+	if arg.DueBefore != nil {
+		conditions = append(conditions, "t.due_date < "+param(*arg.DueBefore))
+	}
+	if arg.OverdueAt != nil {
+		conditions = append(conditions, "t.due_date < "+param(*arg.OverdueAt)+" and s.category <> 'done'")
+	}
+
+	keys := make([]string, 0, len(arg.Sort)+1)
+	desc := make([]bool, 0, len(arg.Sort)+1)
+	for _, sort := range arg.Sort {
+		expr, ok := taskSortExpressions[sort.Field]
+		if !ok {
+			return nil, fmt.Errorf("cannot sort tasks by %q", sort.Field)
+		}
+		keys = append(keys, expr)
+		desc = append(desc, sort.Desc)
+	}
+
+	// The ID follows the direction of the last sort key so a single index
+	// on (key, id) can serve the query in either direction.
+	idDesc := len(desc) > 0 && desc[len(desc)-1]
+	keys = append(keys, "t.id")
+	desc = append(desc, idDesc)
+
+	if arg.After != nil {
+		values := make([]string, len(keys))
+		for i, sort := range arg.Sort {
+			values[i] = param(arg.After.value(sort.Field))
+		}
+		values[len(keys)-1] = param(arg.After.ID)
+
+		// (k1 > v1) or (k1 = v1 and k2 > v2) or ... with < for descending keys.
+		var alternatives []string
+		for i := range keys {
+			var terms []string
+			for j := 0; j < i; j++ {
+				terms = append(terms, keys[j]+" = "+values[j])
+			}
+			op := " > "
+			if desc[i] {
+				op = " < "
+			}
+			terms = append(terms, keys[i]+op+values[i])
+			alternatives = append(alternatives, "("+strings.Join(terms, " and ")+")")
+		}
+		conditions = append(conditions, "("+strings.Join(alternatives, " or ")+")")
+	}
+
+	orderBy := make([]string, len(keys))
+	for i, key := range keys {
+		if desc[i] {
+			orderBy[i] = key + " desc"
+		} else {
+			orderBy[i] = key
+		}
+	}
+
+	var query strings.Builder
+	query.WriteString("select t.id, t.title, t.description, t.created_by, t.created_at, t.due_date, t.team_id, t.status_id, t.search_vector, t.parent_id, t.template_id from tasks t\n")
+	if arg.StatusCategory != nil || arg.OverdueAt != nil {
+		query.WriteString("join task_statuses s on s.id = t.status_id\n")
+	}
+	if len(conditions) > 0 {
+		query.WriteString("where " + strings.Join(conditions, "\n\tand ") + "\n")
+	}
+	query.WriteString("order by " + strings.Join(orderBy, ", ") + "\n")
+	query.WriteString("limit " + param(arg.Limit))
+
+	rows, err := q.db.Query(ctx, query.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.DueDate,
+			&i.TeamID,
+			&i.StatusID,
+			&i.SearchVector,
+			&i.ParentID,
+			&i.TemplateID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
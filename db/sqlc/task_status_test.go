@@ -0,0 +1,152 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/bolusarz/task-manager/util"
+	"github.com/stretchr/testify/require"
+)
+
+func initialTaskStatus(t *testing.T, teamID *int32) TaskStatus {
+	status, err := testQueries.GetInitialTaskStatus(t.Context(), teamID)
+	require.NoError(t, err)
+	return status
+}
+
+func createRandomTaskStatus(t *testing.T, teamID *int32) TaskStatus {
+	arg := CreateTaskStatusParams{
+		TeamID:   teamID,
+		Name:     util.RandomString(12),
+		Category: "in_progress",
+		Position: int32(util.RandomInt(10, 100)),
+		Color:    "#f59e0b",
+	}
+
+	status, err := testQueries.CreateTaskStatus(t.Context(), arg)
+
+	require.NoError(t, err)
+	require.Equal(t, arg.TeamID, status.TeamID)
+	require.Equal(t, arg.Name, status.Name)
+	require.Equal(t, arg.Category, status.Category)
+	require.Equal(t, arg.Position, status.Position)
+	require.Equal(t, arg.Color, status.Color)
+	require.NotZero(t, status.ID)
+
+	return status
+}
+
+func TestDefaultTaskStatuses(t *testing.T) {
+	statuses, err := testQueries.ListTaskStatuses(t.Context(), nil)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(statuses), 3)
+
+	initial := initialTaskStatus(t, nil)
+	require.Equal(t, statuses[0], initial)
+	require.Equal(t, "todo", initial.Category)
+}
+
+func TestTaskStatusNameIsUniquePerTeam(t *testing.T) {
+	team := createRandomTeam(t, createRandomUser(t))
+	status := createRandomTaskStatus(t, &team.ID)
+
+	_, err := testQueries.CreateTaskStatus(t.Context(), CreateTaskStatusParams{
+		TeamID:   &team.ID,
+		Name:     status.Name,
+		Category: "todo",
+		Color:    status.Color,
+	})
+	require.Error(t, err)
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+
+	otherTeam := createRandomTeam(t, createRandomUser(t))
+	_, err = testQueries.CreateTaskStatus(t.Context(), CreateTaskStatusParams{
+		TeamID:   &otherTeam.ID,
+		Name:     status.Name,
+		Category: "todo",
+		Color:    status.Color,
+	})
+	require.NoError(t, err)
+}
+
+func TestUpdateTaskStatus(t *testing.T) {
+	team := createRandomTeam(t, createRandomUser(t))
+	status := createRandomTaskStatus(t, &team.ID)
+
+	arg := UpdateTaskStatusParams{
+		ID:       status.ID,
+		Name:     "Review",
+		Category: "in_progress",
+		Position: status.Position + 1,
+		Color:    "#a855f7",
+	}
+
+	updated, err := testQueries.UpdateTaskStatus(t.Context(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Name, updated.Name)
+	require.Equal(t, arg.Position, updated.Position)
+	require.Equal(t, arg.Color, updated.Color)
+	require.Equal(t, status.TeamID, updated.TeamID)
+}
+
+func TestDeleteTaskStatusInUse(t *testing.T) {
+	team := createRandomTeam(t, createRandomUser(t))
+	status := createRandomTaskStatus(t, &team.ID)
+
+	task, err := testQueries.CreateTask(t.Context(), CreateTaskParams{
+		Title:    util.RandomString(12),
+		TeamID:   &team.ID,
+		StatusID: status.ID,
+	})
+	require.NoError(t, err)
+
+	err = testQueries.DeleteTaskStatus(t.Context(), status.ID)
+	require.Error(t, err)
+	require.Equal(t, ForeignKeyViolation, ErrorCode(err))
+
+	err = testQueries.DeleteTask(t.Context(), task.ID)
+	require.NoError(t, err)
+
+	err = testQueries.DeleteTaskStatus(t.Context(), status.ID)
+	require.NoError(t, err)
+
+	_, err = testQueries.GetTaskStatus(t.Context(), status.ID)
+	require.EqualError(t, err, ErrRecordNotFound.Error())
+}
+
+func TestTransitionTask(t *testing.T) {
+	team := createRandomTeam(t, createRandomUser(t))
+	open := createRandomTaskStatus(t, &team.ID)
+	review := createRandomTaskStatus(t, &team.ID)
+	blocked := createRandomTaskStatus(t, &team.ID)
+
+	_, err := testQueries.CreateTaskStatusTransition(t.Context(), CreateTaskStatusTransitionParams{
+		FromStatusID: open.ID,
+		ToStatusID:   review.ID,
+	})
+	require.NoError(t, err)
+
+	transitions, err := testQueries.ListTaskStatusTransitions(t.Context(), &team.ID)
+	require.NoError(t, err)
+	require.Equal(t, []TaskStatusTransition{{FromStatusID: open.ID, ToStatusID: review.ID}}, transitions)
+
+	task, err := testQueries.CreateTask(t.Context(), CreateTaskParams{
+		Title:    util.RandomString(12),
+		TeamID:   &team.ID,
+		StatusID: open.ID,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.TransitionTask(t.Context(), TransitionTaskParams{ID: task.ID, ToStatusID: blocked.ID})
+	require.EqualError(t, err, ErrRecordNotFound.Error())
+
+	moved, err := testQueries.TransitionTask(t.Context(), TransitionTaskParams{ID: task.ID, ToStatusID: review.ID})
+	require.NoError(t, err)
+	require.Equal(t, review.ID, moved.StatusID)
+
+	deleted, err := testQueries.DeleteTaskStatusTransition(t.Context(), DeleteTaskStatusTransitionParams{
+		FromStatusID: open.ID,
+		ToStatusID:   review.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+}
@@ -0,0 +1,162 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bolusarz/task-manager/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomTaskTemplate(t *testing.T, user User, team *Team) TaskOccurrenceResult {
+	first := time.Now().Add(time.Hour).UTC().Truncate(time.Microsecond)
+	following := first.AddDate(0, 0, 1)
+
+	arg := CreateTaskTemplateWithOccurrenceParams{
+		CreateTaskTemplateParams: CreateTaskTemplateParams{
+			Title:       util.RandomString(12),
+			Description: pgtype.Text{String: util.RandomString(30), Valid: true},
+			CreatedBy:   &user.ID,
+			Rrule:       "FREQ=DAILY",
+			Timezone:    "Europe/London",
+			StartsAt:    first,
+			NextDueAt:   &first,
+		},
+		FollowingDueAt: &following,
+	}
+	if team != nil {
+		arg.TeamID = &team.ID
+	}
+
+	result, err := testStore.CreateTaskTemplateWithOccurrence(t.Context(), arg)
+	require.NoError(t, err)
+
+	require.NotZero(t, result.Template.ID)
+	require.Equal(t, arg.Title, result.Template.Title)
+	require.Equal(t, arg.Rrule, result.Template.Rrule)
+	require.Equal(t, arg.Timezone, result.Template.Timezone)
+	require.Equal(t, arg.TeamID, result.Template.TeamID)
+	require.WithinDuration(t, first, result.Template.StartsAt, 0)
+	require.WithinDuration(t, following, *result.Template.NextDueAt, 0)
+
+	require.Equal(t, arg.Title, result.Task.Title)
+	require.Equal(t, arg.Description, result.Task.Description)
+	require.Equal(t, arg.TeamID, result.Task.TeamID)
+	require.Equal(t, result.Template.ID, *result.Task.TemplateID)
+	require.Equal(t, initialTaskStatus(t, arg.TeamID).ID, result.Task.StatusID)
+	require.WithinDuration(t, first, *result.Task.DueDate, 0)
+
+	return result
+}
+
+// createRandomTeamWithWorkflow creates a team with its copy of the default
+// workflow, which team occurrences start in.
+func createRandomTeamWithWorkflow(t *testing.T, owner User) Team {
+	team, err := testStore.CreateTeamWithOwner(t.Context(), CreateTeamWithOwnerParams{
+		Name:    util.RandomString(10),
+		OwnerID: owner.ID,
+	})
+	require.NoError(t, err)
+	return team
+}
+
+func TestCreateTaskTemplateWithOccurrence(t *testing.T) {
+	user := createRandomUser(t)
+	team := createRandomTeamWithWorkflow(t, user)
+
+	createRandomTaskTemplate(t, user, nil)
+	createRandomTaskTemplate(t, user, &team)
+}
+
+func TestMaterializeTaskOccurrence(t *testing.T) {
+	created := createRandomTaskTemplate(t, createRandomUser(t), nil)
+	template := created.Template
+	following := template.NextDueAt.AddDate(0, 0, 1)
+
+	result, err := testStore.MaterializeTaskOccurrence(t.Context(), MaterializeTaskOccurrenceParams{
+		Template:  template,
+		NextDueAt: &following,
+	})
+	require.NoError(t, err)
+	require.WithinDuration(t, *template.NextDueAt, *result.Task.DueDate, 0)
+	require.WithinDuration(t, following, *result.Template.NextDueAt, 0)
+
+	// The stale copy of the template points at the occurrence that was just
+	// materialized.
+	_, err = testStore.MaterializeTaskOccurrence(t.Context(), MaterializeTaskOccurrenceParams{
+		Template:  template,
+		NextDueAt: &following,
+	})
+	require.ErrorIs(t, err, ErrOccurrenceExists)
+
+	// The last occurrence ends the series.
+	result, err = testStore.MaterializeTaskOccurrence(t.Context(), MaterializeTaskOccurrenceParams{
+		Template: result.Template,
+	})
+	require.NoError(t, err)
+	require.Nil(t, result.Template.NextDueAt)
+
+	open, err := testQueries.CountOpenTemplateTasks(t.Context(), &template.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), open)
+
+	due, err := testQueries.ListDueTaskTemplates(t.Context(), ListDueTaskTemplatesParams{
+		Now:   following.AddDate(1, 0, 0),
+		Limit: 1000,
+	})
+	require.NoError(t, err)
+	for _, dueTemplate := range due {
+		require.NotEqual(t, template.ID, dueTemplate.ID)
+	}
+}
+
+func TestListDueTaskTemplates(t *testing.T) {
+	created := createRandomTaskTemplate(t, createRandomUser(t), nil)
+
+	due, err := testQueries.ListDueTaskTemplates(t.Context(), ListDueTaskTemplatesParams{
+		Now:   created.Template.NextDueAt.Add(-time.Second),
+		Limit: 1000,
+	})
+	require.NoError(t, err)
+	for _, template := range due {
+		require.NotEqual(t, created.Template.ID, template.ID)
+	}
+
+	due, err = testQueries.ListDueTaskTemplates(t.Context(), ListDueTaskTemplatesParams{
+		Now:   *created.Template.NextDueAt,
+		Limit: 1000,
+	})
+	require.NoError(t, err)
+	require.Contains(t, due, created.Template)
+}
+
+func TestListTaskTemplates(t *testing.T) {
+	user := createRandomUser(t)
+	team := createRandomTeamWithWorkflow(t, user)
+
+	personal := createRandomTaskTemplate(t, user, nil).Template
+	teamTemplate := createRandomTaskTemplate(t, user, &team).Template
+	createRandomTaskTemplate(t, createRandomUser(t), nil)
+
+	templates, err := testQueries.ListTaskTemplates(t.Context(), ListTaskTemplatesParams{UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, []TaskTemplate{personal}, templates)
+
+	templates, err = testQueries.ListTaskTemplates(t.Context(), ListTaskTemplatesParams{TeamID: &team.ID, UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, []TaskTemplate{teamTemplate}, templates)
+}
+
+func TestDeleteTaskTemplateKeepsTasks(t *testing.T) {
+	created := createRandomTaskTemplate(t, createRandomUser(t), nil)
+
+	require.NoError(t, testQueries.DeleteTaskTemplate(t.Context(), created.Template.ID))
+
+	_, err := testQueries.GetTaskTemplate(t.Context(), created.Template.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	task, err := testQueries.GetTask(t.Context(), created.Task.ID)
+	require.NoError(t, err)
+	require.Nil(t, task.TemplateID)
+}
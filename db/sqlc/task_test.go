@@ -0,0 +1,268 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bolusarz/task-manager/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomTask(t *testing.T) Task {
+	dueDate := time.Now().Add(48 * time.Hour).Truncate(time.Microsecond)
+
+	arg := CreateTaskParams{
+		Title:       util.RandomString(12),
+		Description: pgtype.Text{String: util.RandomString(40), Valid: true},
+		DueDate:     &dueDate,
+		StatusID:    initialTaskStatus(t, nil).ID,
+	}
+
+	task, err := testQueries.CreateTask(t.Context(), arg)
+
+	require.NoError(t, err)
+	require.Equal(t, arg.Title, task.Title)
+	require.Equal(t, arg.Description, task.Description)
+	require.WithinDuration(t, *arg.DueDate, *task.DueDate, time.Second)
+	require.Equal(t, arg.StatusID, task.StatusID)
+	require.NotZero(t, task.ID)
+	require.NotZero(t, task.CreatedAt)
+
+	assignees, err := testQueries.ListTaskAssignees(t.Context(), task.ID)
+	require.NoError(t, err)
+	require.Empty(t, assignees)
+
+	return task
+}
+
+func TestCreateTask(t *testing.T) {
+	createRandomTask(t)
+}
+
+func TestGetTask(t *testing.T) {
+	task := createRandomTask(t)
+
+	fetchedTask, err := testQueries.GetTask(t.Context(), task.ID)
+	require.NoError(t, err)
+
+	require.Equal(t, task, fetchedTask)
+
+	_, err = testQueries.GetTask(t.Context(), -1)
+	require.Error(t, err)
+	require.EqualError(t, err, ErrRecordNotFound.Error())
+}
+
+func TestListTasks(t *testing.T) {
+	for range 5 {
+		createRandomTask(t)
+	}
+
+	tasks, err := testQueries.ListTasks(t.Context(), ListTasksParams{
+		ViewAll: true,
+		Limit:   5,
+	})
+	require.NoError(t, err)
+	require.Len(t, tasks, 5)
+
+	for _, task := range tasks {
+		require.NotZero(t, task.ID)
+	}
+}
+
+func TestListTasksKeysetPagination(t *testing.T) {
+	team := createRandomTeam(t, createRandomUser(t))
+	status := initialTaskStatus(t, nil)
+
+	// Two tasks share a due date and one has none, to exercise the
+	// tie-breaker and the missing due date placeholder.
+	base := time.Now().Add(72 * time.Hour).Truncate(time.Microsecond)
+	dueDates := []*time.Time{&base, nil, &base, ptr(base.Add(time.Hour)), ptr(base.Add(-time.Hour))}
+
+	for _, dueDate := range dueDates {
+		_, err := testQueries.CreateTask(t.Context(), CreateTaskParams{
+			Title:    util.RandomString(12),
+			TeamID:   &team.ID,
+			DueDate:  dueDate,
+			StatusID: status.ID,
+		})
+		require.NoError(t, err)
+	}
+
+	arg := ListTasksParams{
+		TeamID:  &team.ID,
+		ViewAll: true,
+		Sort:    []TaskSort{{Field: TaskSortDueDate, Desc: true}},
+		Limit:   100,
+	}
+
+	all, err := testQueries.ListTasks(t.Context(), arg)
+	require.NoError(t, err)
+	require.Len(t, all, len(dueDates))
+	require.Nil(t, all[0].DueDate)
+
+	var paged []Task
+	arg.Limit = 2
+	for {
+		page, err := testQueries.ListTasks(t.Context(), arg)
+		require.NoError(t, err)
+		paged = append(paged, page...)
+
+		if len(page) < int(arg.Limit) {
+			break
+		}
+		cursor := NewTaskCursor(page[len(page)-1])
+		arg.After = &cursor
+	}
+
+	require.Equal(t, all, paged)
+}
+
+func TestListTasksFilters(t *testing.T) {
+	user := createRandomUser(t)
+	team := createRandomTeam(t, user)
+
+	statuses, err := testQueries.ListTaskStatuses(t.Context(), nil)
+	require.NoError(t, err)
+
+	var todo, done TaskStatus
+	for _, status := range statuses {
+		switch status.Category {
+		case "todo":
+			todo = status
+		case "done":
+			done = status
+		}
+	}
+
+	yesterday := time.Now().Add(-24 * time.Hour).Truncate(time.Microsecond)
+
+	overdue, err := testQueries.CreateTask(t.Context(), CreateTaskParams{
+		Title: util.RandomString(12), TeamID: &team.ID, DueDate: &yesterday, StatusID: todo.ID,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.CreateTask(t.Context(), CreateTaskParams{
+		Title: util.RandomString(12), TeamID: &team.ID, DueDate: &yesterday, StatusID: done.ID,
+	})
+	require.NoError(t, err)
+
+	assigned, err := testQueries.CreateTask(t.Context(), CreateTaskParams{
+		Title: util.RandomString(12), TeamID: &team.ID, CreatedBy: &user.ID, StatusID: todo.ID,
+	})
+	require.NoError(t, err)
+
+	err = testQueries.AddTaskAssignee(t.Context(), AddTaskAssigneeParams{UserID: user.ID, TaskID: assigned.ID})
+	require.NoError(t, err)
+
+	now := time.Now()
+	tasks, err := testQueries.ListTasks(t.Context(), ListTasksParams{TeamID: &team.ID, ViewAll: true, OverdueAt: &now, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []Task{overdue}, tasks)
+
+	tasks, err = testQueries.ListTasks(t.Context(), ListTasksParams{TeamID: &team.ID, ViewAll: true, AssigneeID: &user.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []Task{assigned}, tasks)
+
+	tasks, err = testQueries.ListTasks(t.Context(), ListTasksParams{TeamID: &team.ID, ViewAll: true, CreatedBy: &user.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []Task{assigned}, tasks)
+
+	category := "done"
+	tasks, err = testQueries.ListTasks(t.Context(), ListTasksParams{TeamID: &team.ID, ViewAll: true, StatusCategory: &category, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	require.Equal(t, done.ID, tasks[0].StatusID)
+}
+
+func TestListTasksVisibility(t *testing.T) {
+	user := createRandomUser(t)
+	other := createRandomUser(t)
+	status := initialTaskStatus(t, nil)
+
+	team := createRandomTeam(t, user)
+	_, err := testQueries.AddTeamMember(t.Context(), AddTeamMemberParams{TeamID: team.ID, UserID: user.ID})
+	require.NoError(t, err)
+	otherTeam := createRandomTeam(t, other)
+
+	createTask := func(teamID, createdBy *int32) Task {
+		task, err := testQueries.CreateTask(t.Context(), CreateTaskParams{
+			Title: util.RandomString(12), TeamID: teamID, CreatedBy: createdBy, StatusID: status.ID,
+		})
+		require.NoError(t, err)
+		return task
+	}
+
+	teamTask := createTask(&team.ID, &other.ID)
+	otherTeamTask := createTask(&otherTeam.ID, &user.ID)
+	personal := createTask(nil, &user.ID)
+	assigned := createTask(nil, &other.ID)
+	hidden := createTask(nil, &other.ID)
+
+	err = testQueries.AddTaskAssignee(t.Context(), AddTaskAssigneeParams{UserID: user.ID, TaskID: assigned.ID})
+	require.NoError(t, err)
+
+	list := func(arg ListTasksParams) []int32 {
+		// The newest tasks come first, so the ones above are on the page
+		// even when the table holds many more.
+		arg.Sort = []TaskSort{{Field: TaskSortCreatedAt, Desc: true}}
+		arg.Limit = 100
+		tasks, err := testQueries.ListTasks(t.Context(), arg)
+		require.NoError(t, err)
+
+		var ids []int32
+		for _, task := range tasks {
+			switch task.ID {
+			case teamTask.ID, otherTeamTask.ID, personal.ID, assigned.ID, hidden.ID:
+				ids = append(ids, task.ID)
+			}
+		}
+		return ids
+	}
+
+	// Creating a task in another team does not make it visible.
+	require.ElementsMatch(t, []int32{teamTask.ID, personal.ID, assigned.ID}, list(ListTasksParams{UserID: user.ID}))
+	require.Empty(t, list(ListTasksParams{UserID: user.ID, TeamID: &otherTeam.ID}))
+	require.ElementsMatch(t,
+		[]int32{teamTask.ID, otherTeamTask.ID, personal.ID, assigned.ID, hidden.ID},
+		list(ListTasksParams{UserID: user.ID, ViewAll: true}),
+	)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestUpdateTask(t *testing.T) {
+	task := createRandomTask(t)
+
+	arg := UpdateTaskParams{
+		ID:          task.ID,
+		Title:       util.RandomString(14),
+		Description: task.Description,
+		DueDate:     nil,
+	}
+
+	updatedTask, err := testQueries.UpdateTask(t.Context(), arg)
+	require.NoError(t, err)
+
+	require.Equal(t, arg.Title, updatedTask.Title)
+	require.Equal(t, task.StatusID, updatedTask.StatusID)
+	require.Nil(t, updatedTask.DueDate)
+
+	arg.ID = -1
+	_, err = testQueries.UpdateTask(t.Context(), arg)
+	require.Error(t, err)
+	require.EqualError(t, err, ErrRecordNotFound.Error())
+}
+
+func TestDeleteTask(t *testing.T) {
+	task := createRandomTask(t)
+
+	err := testQueries.DeleteTask(t.Context(), task.ID)
+	require.NoError(t, err)
+
+	_, err = testQueries.GetTask(t.Context(), task.ID)
+	require.Error(t, err)
+	require.EqualError(t, err, ErrRecordNotFound.Error())
+}
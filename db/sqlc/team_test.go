@@ -0,0 +1,155 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/bolusarz/task-manager/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomTeam(t *testing.T, owner User) Team {
+	arg := CreateTeamParams{
+		Name:        util.RandomString(10),
+		Description: pgtype.Text{String: util.RandomString(30), Valid: true},
+		CreatedBy:   &owner.ID,
+	}
+
+	team, err := testQueries.CreateTeam(t.Context(), arg)
+
+	require.NoError(t, err)
+	require.Equal(t, arg.Name, team.Name)
+	require.Equal(t, arg.Description, team.Description)
+	require.Equal(t, arg.CreatedBy, team.CreatedBy)
+	require.NotZero(t, team.ID)
+	require.NotZero(t, team.CreatedAt)
+
+	return team
+}
+
+func TestCreateTeam(t *testing.T) {
+	createRandomTeam(t, createRandomUser(t))
+}
+
+func TestGetTeam(t *testing.T) {
+	team := createRandomTeam(t, createRandomUser(t))
+
+	fetchedTeam, err := testQueries.GetTeam(t.Context(), team.ID)
+	require.NoError(t, err)
+	require.Equal(t, team, fetchedTeam)
+}
+
+func TestUpdateTeam(t *testing.T) {
+	team := createRandomTeam(t, createRandomUser(t))
+
+	arg := UpdateTeamParams{
+		ID:          team.ID,
+		Name:        util.RandomString(10),
+		Description: team.Description,
+	}
+
+	updatedTeam, err := testQueries.UpdateTeam(t.Context(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Name, updatedTeam.Name)
+	require.Equal(t, team.CreatedBy, updatedTeam.CreatedBy)
+}
+
+func TestDeleteTeam(t *testing.T) {
+	team := createRandomTeam(t, createRandomUser(t))
+
+	err := testQueries.DeleteTeam(t.Context(), team.ID)
+	require.NoError(t, err)
+
+	_, err = testQueries.GetTeam(t.Context(), team.ID)
+	require.EqualError(t, err, ErrRecordNotFound.Error())
+}
+
+func TestTeamMembers(t *testing.T) {
+	owner := createRandomUser(t)
+	member := createRandomUser(t)
+	team := createRandomTeam(t, owner)
+
+	_, err := testQueries.AddTeamMember(t.Context(), AddTeamMemberParams{TeamID: team.ID, UserID: member.ID})
+	require.NoError(t, err)
+
+	_, err = testQueries.AddTeamMember(t.Context(), AddTeamMemberParams{TeamID: team.ID, UserID: member.ID})
+	require.Error(t, err)
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+
+	members, err := testQueries.ListTeamMembers(t.Context(), team.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	require.Equal(t, member.ID, members[0].UserID)
+	require.Equal(t, member.Email, members[0].Email)
+
+	teams, err := testQueries.ListTeamsByUser(t.Context(), member.ID)
+	require.NoError(t, err)
+	require.Contains(t, teams, team)
+
+	err = testQueries.RemoveTeamMember(t.Context(), RemoveTeamMemberParams{TeamID: team.ID, UserID: member.ID})
+	require.NoError(t, err)
+
+	_, err = testQueries.GetTeamMember(t.Context(), GetTeamMemberParams{TeamID: team.ID, UserID: member.ID})
+	require.EqualError(t, err, ErrRecordNotFound.Error())
+}
+
+func TestRemoveTeamMemberDropsAssignments(t *testing.T) {
+	owner := createRandomUser(t)
+	member := createRandomUser(t)
+	team := createRandomTeam(t, owner)
+
+	_, err := testQueries.AddTeamMember(t.Context(), AddTeamMemberParams{TeamID: team.ID, UserID: member.ID})
+	require.NoError(t, err)
+
+	teamTask, err := testQueries.CreateTask(t.Context(), CreateTaskParams{
+		Title:    util.RandomString(12),
+		TeamID:   &team.ID,
+		StatusID: initialTaskStatus(t, nil).ID,
+	})
+	require.NoError(t, err)
+	otherTask := createRandomTask(t)
+
+	for _, task := range []Task{teamTask, otherTask} {
+		err = testQueries.AddTaskAssignee(t.Context(), AddTaskAssigneeParams{UserID: member.ID, TaskID: task.ID})
+		require.NoError(t, err)
+		err = testQueries.AddTaskWatcher(t.Context(), AddTaskWatcherParams{TaskID: task.ID, UserID: member.ID})
+		require.NoError(t, err)
+	}
+
+	err = testQueries.RemoveTeamMember(t.Context(), RemoveTeamMemberParams{TeamID: team.ID, UserID: member.ID})
+	require.NoError(t, err)
+
+	assignees, err := testQueries.ListTaskAssignees(t.Context(), teamTask.ID)
+	require.NoError(t, err)
+	require.Empty(t, assignees)
+
+	watchers, err := testQueries.ListTaskWatchers(t.Context(), teamTask.ID)
+	require.NoError(t, err)
+	require.Empty(t, watchers)
+
+	// Assignments and watches outside the team are left alone.
+	assignees, err = testQueries.ListTaskAssignees(t.Context(), otherTask.ID)
+	require.NoError(t, err)
+	require.Len(t, assignees, 1)
+
+	watchers, err = testQueries.ListTaskWatchers(t.Context(), otherTask.ID)
+	require.NoError(t, err)
+	require.Len(t, watchers, 1)
+}
+
+func TestFilterTeamMembers(t *testing.T) {
+	owner := createRandomUser(t)
+	member := createRandomUser(t)
+	outsider := createRandomUser(t)
+	team := createRandomTeam(t, owner)
+
+	_, err := testQueries.AddTeamMember(t.Context(), AddTeamMemberParams{TeamID: team.ID, UserID: member.ID})
+	require.NoError(t, err)
+
+	members, err := testQueries.FilterTeamMembers(t.Context(), FilterTeamMembersParams{
+		TeamID:  team.ID,
+		UserIds: []int32{member.ID, outsider.ID},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int32{member.ID}, members)
+}
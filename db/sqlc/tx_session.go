@@ -0,0 +1,39 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrSessionRotated is returned by ReplaceSession when the session was already
+// rotated or revoked, i.e. its refresh token is being reused.
+var ErrSessionRotated = errors.New("session has already been rotated")
+
+type ReplaceSessionParams struct {
+	ID   uuid.UUID           `json:"id"`
+	Next CreateSessionParams `json:"next"`
+}
+
+// ReplaceSession marks the session as rotated and creates its successor, so a
+// refresh either fully succeeds or leaves the old session usable.
+func (store *SQLStore) ReplaceSession(ctx context.Context, arg ReplaceSessionParams) (Session, error) {
+	var session Session
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		rotated, err := q.RotateSession(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if rotated == 0 {
+			return ErrSessionRotated
+		}
+
+		session, err = q.CreateSession(ctx, arg.Next)
+		return err
+	})
+
+	return session, err
+}
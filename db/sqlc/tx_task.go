@@ -0,0 +1,253 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+type CreateTaskWithAssigneesParams struct {
+	CreateTaskParams
+	AssigneeIDs []int32 `json:"assigneeIds"`
+}
+
+type CreateTaskWithAssigneesResult struct {
+	Task        Task    `json:"task"`
+	AssigneeIDs []int32 `json:"assigneeIds"`
+}
+
+// CreateTaskWithAssignees creates a task and assigns it to every user in
+// AssigneeIDs in a single transaction.
+func (store *SQLStore) CreateTaskWithAssignees(ctx context.Context, arg CreateTaskWithAssigneesParams) (CreateTaskWithAssigneesResult, error) {
+	var result CreateTaskWithAssigneesResult
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Task, err = q.CreateTask(ctx, arg.CreateTaskParams)
+		if err != nil {
+			return err
+		}
+
+		if err = recordEvent(ctx, q, TaskCreated{Task: result.Task}); err != nil {
+			return err
+		}
+
+		result.AssigneeIDs = []int32{}
+		for _, userID := range arg.AssigneeIDs {
+			err = q.AddTaskAssignee(ctx, AddTaskAssigneeParams{
+				UserID: userID,
+				TaskID: result.Task.ID,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = recordEvent(ctx, q, TaskAssigned{TaskID: result.Task.ID, TeamID: result.Task.TeamID, UserID: userID})
+			if err != nil {
+				return err
+			}
+			result.AssigneeIDs = append(result.AssigneeIDs, userID)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+type UpdateTaskAssigneesParams struct {
+	TaskID int32   `json:"taskId"`
+	Add    []int32 `json:"add"`
+	Remove []int32 `json:"remove"`
+}
+
+// UpdateTaskAssignees adds and removes assignees of a task in a single
+// transaction and returns who is assigned afterwards. Users that are already
+// assigned keep their original assigned_at. Only actual changes are recorded
+// as events, so adding someone twice or removing someone who was not assigned
+// records nothing.
+func (store *SQLStore) UpdateTaskAssignees(ctx context.Context, arg UpdateTaskAssigneesParams) ([]ListTaskAssigneesRow, error) {
+	var assignees []ListTaskAssigneesRow
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		task, err := q.GetTask(ctx, arg.TaskID)
+		if err != nil {
+			return err
+		}
+
+		before, err := q.ListTaskAssignees(ctx, arg.TaskID)
+		if err != nil {
+			return err
+		}
+
+		for _, userID := range arg.Add {
+			err := q.AddTaskAssignee(ctx, AddTaskAssigneeParams{
+				UserID: userID,
+				TaskID: arg.TaskID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, userID := range arg.Remove {
+			err := q.RemoveTaskAssignee(ctx, RemoveTaskAssigneeParams{
+				UserID: userID,
+				TaskID: arg.TaskID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		assignees, err = q.ListTaskAssignees(ctx, arg.TaskID)
+		if err != nil {
+			return err
+		}
+
+		wasAssigned := make(map[int32]bool, len(before))
+		for _, assignee := range before {
+			wasAssigned[assignee.UserID] = true
+		}
+
+		for _, assignee := range assignees {
+			if wasAssigned[assignee.UserID] {
+				delete(wasAssigned, assignee.UserID)
+				continue
+			}
+
+			err = recordEvent(ctx, q, TaskAssigned{TaskID: task.ID, TeamID: task.TeamID, UserID: assignee.UserID})
+			if err != nil {
+				return err
+			}
+		}
+
+		// Whoever is left was assigned before and is not any more, in the
+		// order they were assigned.
+		for _, assignee := range before {
+			if !wasAssigned[assignee.UserID] {
+				continue
+			}
+
+			err = recordEvent(ctx, q, TaskUnassigned{TaskID: task.ID, TeamID: task.TeamID, UserID: assignee.UserID})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return assignees, err
+}
+
+// CreateTask creates a task and records TaskCreated.
+func (store *SQLStore) CreateTask(ctx context.Context, arg CreateTaskParams) (Task, error) {
+	var task Task
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		var err error
+
+		task, err = q.CreateTask(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(ctx, q, TaskCreated{Task: task})
+	})
+
+	return task, err
+}
+
+// UpdateTask updates a task and records TaskUpdated.
+func (store *SQLStore) UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, error) {
+	var task Task
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		var err error
+
+		task, err = q.UpdateTask(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(ctx, q, TaskUpdated{Task: task})
+	})
+
+	return task, err
+}
+
+// DeleteTask deletes a task and records TaskDeleted. Deleting a task that
+// does not exist is not an error and records nothing.
+func (store *SQLStore) DeleteTask(ctx context.Context, id int32) error {
+	return store.ExecTx(ctx, func(q *Queries) error {
+		task, err := q.GetTask(ctx, id)
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		if err = q.DeleteTask(ctx, id); err != nil {
+			return err
+		}
+
+		return recordEvent(ctx, q, TaskDeleted{TaskID: task.ID, TeamID: task.TeamID})
+	})
+}
+
+// TaskBlockedError is returned when a task would move to a done status while
+// tasks blocking it are still open.
+type TaskBlockedError struct {
+	Blockers []Task
+}
+
+func (e *TaskBlockedError) Error() string {
+	return "task is blocked by open tasks"
+}
+
+// TransitionTask moves a task to another status of its workflow and records
+// TaskStatusChanged. ErrRecordNotFound is returned when the task does not
+// exist or the workflow does not allow the move, and a *TaskBlockedError when
+// the move is to a done status and some of the task's blockers are open.
+// The blockers are checked in the same transaction as the move, so one
+// reopened or added meanwhile cannot slip through.
+func (store *SQLStore) TransitionTask(ctx context.Context, arg TransitionTaskParams) (Task, error) {
+	var task Task
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		current, err := q.GetTask(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		task, err = q.TransitionTask(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		if task.StatusID == current.StatusID {
+			return nil
+		}
+
+		status, err := q.GetTaskStatus(ctx, task.StatusID)
+		if err != nil {
+			return err
+		}
+
+		if status.Category == "done" {
+			blockers, err := q.ListOpenBlockers(ctx, task.ID)
+			if err != nil {
+				return err
+			}
+			if len(blockers) > 0 {
+				return &TaskBlockedError{Blockers: blockers}
+			}
+		}
+
+		return recordEvent(ctx, q, TaskStatusChanged{Task: task, FromStatusID: current.StatusID})
+	})
+
+	return task, err
+}
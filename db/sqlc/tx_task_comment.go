@@ -0,0 +1,133 @@
+package db
+
+import (
+	"context"
+	"slices"
+)
+
+type CreateTaskCommentWithMentionsParams struct {
+	CreateTaskCommentParams
+	MentionIDs []int32 `json:"mentionIds"`
+}
+
+// CreateTaskCommentWithMentions creates a comment and records the users it
+// mentions in a single transaction, along with UsersMentioned.
+func (store *SQLStore) CreateTaskCommentWithMentions(ctx context.Context, arg CreateTaskCommentWithMentionsParams) (TaskComment, error) {
+	var comment TaskComment
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		var err error
+
+		comment, err = q.CreateTaskComment(ctx, arg.CreateTaskCommentParams)
+		if err != nil {
+			return err
+		}
+
+		if len(arg.MentionIDs) == 0 {
+			return nil
+		}
+
+		err = q.AddTaskCommentMentions(ctx, AddTaskCommentMentionsParams{
+			CommentID: comment.ID,
+			UserIds:   arg.MentionIDs,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.recordMentions(ctx, comment, arg.MentionIDs)
+	})
+
+	return comment, err
+}
+
+type EditTaskCommentParams struct {
+	ID         int32   `json:"id"`
+	Body       string  `json:"body"`
+	BodyHtml   string  `json:"bodyHtml"`
+	EditedBy   *int32  `json:"editedBy"`
+	MentionIDs []int32 `json:"mentionIds"`
+}
+
+// EditTaskComment replaces the body of a comment, keeping the previous body
+// as a revision, and swaps its mentions for MentionIDs. UsersMentioned is
+// recorded for the users the comment did not mention before.
+func (store *SQLStore) EditTaskComment(ctx context.Context, arg EditTaskCommentParams) (TaskComment, error) {
+	var comment TaskComment
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		current, err := q.GetTaskCommentForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateTaskCommentRevision(ctx, CreateTaskCommentRevisionParams{
+			CommentID: current.ID,
+			Body:      current.Body,
+			EditedBy:  arg.EditedBy,
+		})
+		if err != nil {
+			return err
+		}
+
+		comment, err = q.UpdateTaskComment(ctx, UpdateTaskCommentParams{
+			ID:       current.ID,
+			Body:     arg.Body,
+			BodyHtml: arg.BodyHtml,
+		})
+		if err != nil {
+			return err
+		}
+
+		previous, err := q.ListTaskCommentMentions(ctx, []int32{current.ID})
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteTaskCommentMentions(ctx, current.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(arg.MentionIDs) == 0 {
+			return nil
+		}
+
+		err = q.AddTaskCommentMentions(ctx, AddTaskCommentMentionsParams{
+			CommentID: current.ID,
+			UserIds:   arg.MentionIDs,
+		})
+		if err != nil {
+			return err
+		}
+
+		added := slices.DeleteFunc(slices.Clone(arg.MentionIDs), func(userID int32) bool {
+			return slices.ContainsFunc(previous, func(mention ListTaskCommentMentionsRow) bool {
+				return mention.UserID == userID
+			})
+		})
+
+		return q.recordMentions(ctx, comment, added)
+	})
+
+	return comment, err
+}
+
+// recordMentions records UsersMentioned for userIDs, unless there are none.
+func (q *Queries) recordMentions(ctx context.Context, comment TaskComment, userIDs []int32) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	task, err := q.GetTask(ctx, comment.TaskID)
+	if err != nil {
+		return err
+	}
+
+	return recordEvent(ctx, q, UsersMentioned{
+		TaskID:    task.ID,
+		TeamID:    task.TeamID,
+		CommentID: comment.ID,
+		UserIDs:   userIDs,
+	})
+}
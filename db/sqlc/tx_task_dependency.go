@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrDependencyCycle is returned when a parent or blocker link would make a
+// task depend on itself.
+var ErrDependencyCycle = errors.New("dependency cycle")
+
+type SetTaskParentParams struct {
+	ID       int32  `json:"id"`
+	ParentID *int32 `json:"parentId"`
+}
+
+// SetTaskParent moves a task under ParentID, or to the top level when it is
+// nil. It fails with ErrDependencyCycle when the new parent is the task itself
+// or one of its subtasks. The check runs in the same serializable transaction
+// as the update, so two concurrent moves cannot close a loop between them.
+func (store *SQLStore) SetTaskParent(ctx context.Context, arg SetTaskParentParams) (Task, error) {
+	var task Task
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		if arg.ParentID != nil {
+			cycle, err := q.IsTaskAncestor(ctx, IsTaskAncestorParams{
+				TaskID:     *arg.ParentID,
+				AncestorID: arg.ID,
+			})
+			if err != nil {
+				return err
+			}
+			if cycle {
+				return ErrDependencyCycle
+			}
+		}
+
+		var err error
+		task, err = q.UpdateTaskParent(ctx, UpdateTaskParentParams{
+			ParentID: arg.ParentID,
+			ID:       arg.ID,
+		})
+		return err
+	})
+
+	return task, err
+}
+
+// AddTaskDependency records that BlockerID blocks BlockedID. It fails with
+// ErrDependencyCycle when BlockedID already blocks BlockerID, directly or
+// through other tasks, and like SetTaskParent checks and inserts in one
+// serializable transaction.
+func (store *SQLStore) AddTaskDependency(ctx context.Context, arg CreateTaskDependencyParams) error {
+	if arg.BlockerID == arg.BlockedID {
+		return ErrDependencyCycle
+	}
+
+	return store.ExecTx(ctx, func(q *Queries) error {
+		cycle, err := q.HasTaskDependencyPath(ctx, HasTaskDependencyPathParams{
+			FromID: arg.BlockedID,
+			ToID:   arg.BlockerID,
+		})
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return ErrDependencyCycle
+		}
+
+		return q.CreateTaskDependency(ctx, arg)
+	})
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrOccurrenceExists is returned when the occurrence being materialized
+// already has its task, because the template moved on since it was read.
+var ErrOccurrenceExists = errors.New("occurrence already materialized")
+
+type TaskOccurrenceResult struct {
+	Template TaskTemplate `json:"template"`
+	Task     Task         `json:"task"`
+}
+
+type MaterializeTaskOccurrenceParams struct {
+	Template TaskTemplate `json:"template"`
+	// NextDueAt is when the occurrence after this one is due, nil when
+	// this one is the last.
+	NextDueAt *time.Time `json:"nextDueAt"`
+}
+
+// MaterializeTaskOccurrence creates the task for the template's next
+// occurrence and moves next_due_at on to NextDueAt. next_due_at is compared
+// against the template that was read, so when several callers race for the
+// same occurrence one creates the task and the rest get ErrOccurrenceExists.
+func (store *SQLStore) MaterializeTaskOccurrence(ctx context.Context, arg MaterializeTaskOccurrenceParams) (TaskOccurrenceResult, error) {
+	var result TaskOccurrenceResult
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = materializeTaskOccurrence(ctx, q, arg.Template, arg.NextDueAt)
+		return err
+	})
+
+	return result, err
+}
+
+type CreateTaskTemplateWithOccurrenceParams struct {
+	CreateTaskTemplateParams
+	// FollowingDueAt is when the occurrence after the first one is due, nil
+	// when the first is also the last.
+	FollowingDueAt *time.Time `json:"followingDueAt"`
+}
+
+// CreateTaskTemplateWithOccurrence creates a template together with the task
+// for its first occurrence, which is due at NextDueAt and must be set.
+func (store *SQLStore) CreateTaskTemplateWithOccurrence(ctx context.Context, arg CreateTaskTemplateWithOccurrenceParams) (TaskOccurrenceResult, error) {
+	var result TaskOccurrenceResult
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		template, err := q.CreateTaskTemplate(ctx, arg.CreateTaskTemplateParams)
+		if err != nil {
+			return err
+		}
+
+		result, err = materializeTaskOccurrence(ctx, q, template, arg.FollowingDueAt)
+		return err
+	})
+
+	return result, err
+}
+
+func materializeTaskOccurrence(ctx context.Context, q *Queries, template TaskTemplate, nextDueAt *time.Time) (TaskOccurrenceResult, error) {
+	var result TaskOccurrenceResult
+
+	if template.NextDueAt == nil {
+		return result, ErrOccurrenceExists
+	}
+
+	advanced, err := q.AdvanceTaskTemplate(ctx, AdvanceTaskTemplateParams{
+		NextDueAt: nextDueAt,
+		ID:        template.ID,
+		DueAt:     *template.NextDueAt,
+	})
+	if err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			return result, ErrOccurrenceExists
+		}
+		return result, err
+	}
+
+	status, err := q.GetInitialTaskStatus(ctx, template.TeamID)
+	if err != nil {
+		return result, err
+	}
+
+	task, err := q.CreateTemplateTask(ctx, CreateTemplateTaskParams{
+		Title:       template.Title,
+		Description: template.Description,
+		CreatedBy:   template.CreatedBy,
+		TeamID:      template.TeamID,
+		DueDate:     template.NextDueAt,
+		StatusID:    status.ID,
+		TemplateID:  &template.ID,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if err = recordEvent(ctx, q, TaskCreated{Task: task}); err != nil {
+		return result, err
+	}
+
+	return TaskOccurrenceResult{Template: advanced, Task: task}, nil
+}
@@ -0,0 +1,208 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrTeamOwner = errors.New("team owner cannot be removed")
+
+type CreateTeamWithOwnerParams struct {
+	Name        string      `json:"name"`
+	Description pgtype.Text `json:"description"`
+	OwnerID     int32       `json:"ownerId"`
+}
+
+type TransferTeamOwnershipParams struct {
+	TeamID     int32 `json:"teamId"`
+	NewOwnerID int32 `json:"newOwnerId"`
+}
+
+// CreateTeamWithOwner creates a team owned by OwnerID, adds the owner as its
+// first member and gives the team its own copy of the default workflow.
+func (store *SQLStore) CreateTeamWithOwner(ctx context.Context, arg CreateTeamWithOwnerParams) (Team, error) {
+	var team Team
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		var err error
+
+		team, err = q.CreateTeam(ctx, CreateTeamParams{
+			Name:        arg.Name,
+			Description: arg.Description,
+			CreatedBy:   &arg.OwnerID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err = recordEvent(ctx, q, TeamCreated{Team: team}); err != nil {
+			return err
+		}
+
+		_, err = q.AddTeamMember(ctx, AddTeamMemberParams{
+			TeamID: team.ID,
+			UserID: arg.OwnerID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err = recordEvent(ctx, q, MemberJoinedTeam{TeamID: team.ID, UserID: arg.OwnerID}); err != nil {
+			return err
+		}
+
+		if err = q.CopyDefaultTaskStatuses(ctx, team.ID); err != nil {
+			return err
+		}
+
+		return q.CopyDefaultTaskStatusTransitions(ctx, team.ID)
+	})
+
+	return team, err
+}
+
+// UpdateTeam updates a team and records TeamUpdated.
+func (store *SQLStore) UpdateTeam(ctx context.Context, arg UpdateTeamParams) (Team, error) {
+	var team Team
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		var err error
+
+		team, err = q.UpdateTeam(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(ctx, q, TeamUpdated{Team: team})
+	})
+
+	return team, err
+}
+
+// DeleteTeam deletes a team along with its tasks, recording TaskDeleted for
+// each of them and then TeamDeleted. Deleting a team that does not exist is
+// not an error and records nothing.
+func (store *SQLStore) DeleteTeam(ctx context.Context, id int32) error {
+	return store.ExecTx(ctx, func(q *Queries) error {
+		_, err := q.GetTeam(ctx, id)
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		taskIDs, err := q.ListTeamTaskIDs(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err = q.DeleteTeam(ctx, id); err != nil {
+			return err
+		}
+
+		for _, taskID := range taskIDs {
+			if err = recordEvent(ctx, q, TaskDeleted{TaskID: taskID, TeamID: &id}); err != nil {
+				return err
+			}
+		}
+
+		return recordEvent(ctx, q, TeamDeleted{TeamID: id})
+	})
+}
+
+// TransferTeamOwnership hands the team over to another user and records
+// TeamOwnerChanged. The new owner must already be a member;
+// ErrRecordNotFound is returned otherwise.
+func (store *SQLStore) TransferTeamOwnership(ctx context.Context, arg TransferTeamOwnershipParams) (Team, error) {
+	var team Team
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		previous, err := q.GetTeam(ctx, arg.TeamID)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.GetTeamMember(ctx, GetTeamMemberParams{
+			TeamID: arg.TeamID,
+			UserID: arg.NewOwnerID,
+		})
+		if err != nil {
+			return err
+		}
+
+		team, err = q.UpdateTeamOwner(ctx, UpdateTeamOwnerParams{
+			ID:        arg.TeamID,
+			CreatedBy: &arg.NewOwnerID,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(ctx, q, TeamOwnerChanged{
+			TeamID:      arg.TeamID,
+			FromOwnerID: previous.CreatedBy,
+			ToOwnerID:   arg.NewOwnerID,
+		})
+	})
+
+	return team, err
+}
+
+// AddTeamMember adds a user to a team and records MemberJoinedTeam.
+func (store *SQLStore) AddTeamMember(ctx context.Context, arg AddTeamMemberParams) (TeamMember, error) {
+	var member TeamMember
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		var err error
+
+		member, err = q.AddTeamMember(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(ctx, q, MemberJoinedTeam{TeamID: arg.TeamID, UserID: arg.UserID})
+	})
+
+	return member, err
+}
+
+// RemoveTeamMember removes a user from a team, along with their assignments
+// to its tasks and the watches they put on them, and records MemberLeftTeam.
+// Removing someone who is not a member is not an error and records nothing.
+// The owner cannot be removed, ErrTeamOwner is returned, until they have
+// transferred the team to someone else.
+func (store *SQLStore) RemoveTeamMember(ctx context.Context, arg RemoveTeamMemberParams) error {
+	return store.ExecTx(ctx, func(q *Queries) error {
+		team, err := q.GetTeam(ctx, arg.TeamID)
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		if team.CreatedBy != nil && *team.CreatedBy == arg.UserID {
+			return ErrTeamOwner
+		}
+
+		_, err = q.GetTeamMember(ctx, GetTeamMemberParams{
+			TeamID: arg.TeamID,
+			UserID: arg.UserID,
+		})
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		if err = q.RemoveTeamMember(ctx, arg); err != nil {
+			return err
+		}
+
+		return recordEvent(ctx, q, MemberLeftTeam{TeamID: arg.TeamID, UserID: arg.UserID})
+	})
+}
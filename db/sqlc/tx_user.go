@@ -0,0 +1,101 @@
+package db
+
+import "context"
+
+type CreateUserWithRolesParams struct {
+	CreateUserParams
+	// AfterCreate grants the new user its roles. It runs in the same
+	// transaction as the insert, so a failure leaves no user behind.
+	AfterCreate func(q Querier, user User) error
+}
+
+// CreateUserWithRoles creates a user and runs AfterCreate atomically.
+func (store *SQLStore) CreateUserWithRoles(ctx context.Context, arg CreateUserWithRolesParams) (User, error) {
+	var user User
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		var err error
+
+		user, err = q.CreateUser(ctx, arg.CreateUserParams)
+		if err != nil {
+			return err
+		}
+
+		return arg.AfterCreate(q, user)
+	})
+
+	return user, err
+}
+
+// ChangeUserPassword stores a new password hash and revokes every session of
+// the user atomically.
+func (store *SQLStore) ChangeUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	return store.ExecTx(ctx, func(q *Queries) error {
+		return ChangePassword(ctx, q, arg)
+	})
+}
+
+// ChangePassword stores a new password hash and revokes every session of the
+// user using q. It is meant to run inside a transaction, e.g. from
+// ConsumeUserToken.
+func ChangePassword(ctx context.Context, q Querier, arg UpdateUserPasswordParams) error {
+	if err := q.UpdateUserPassword(ctx, arg); err != nil {
+		return err
+	}
+
+	return revokeAllSessions(ctx, q, arg.ID)
+}
+
+// revokeAllSessions revokes every session of the user and records it, so
+// the streams the user holds are ended too.
+func revokeAllSessions(ctx context.Context, q Querier, userID int32) error {
+	if err := q.RevokeUserSessions(ctx, userID); err != nil {
+		return err
+	}
+
+	return recordEvent(ctx, q, SessionsRevoked{UserID: userID})
+}
+
+type UpdateUserProfileParams struct {
+	UpdateUserParams
+	// RevokeSessions signs the user out everywhere, e.g. after their email
+	// address, which their tokens are bound to, changed.
+	RevokeSessions bool `json:"revokeSessions"`
+	// InvalidateTokens lists the purposes of the user's outstanding one-time
+	// tokens to invalidate, e.g. email verification after the address they
+	// were sent to was replaced.
+	InvalidateTokens []string `json:"invalidateTokens"`
+}
+
+// UpdateUserProfile updates the user and, when asked to, invalidates their
+// one-time tokens and revokes all of their sessions in the same transaction.
+func (store *SQLStore) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	var user User
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		var err error
+
+		user, err = q.UpdateUser(ctx, arg.UpdateUserParams)
+		if err != nil {
+			return err
+		}
+
+		for _, purpose := range arg.InvalidateTokens {
+			err := q.InvalidateUserTokens(ctx, InvalidateUserTokensParams{
+				UserID:  user.ID,
+				Purpose: purpose,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if !arg.RevokeSessions {
+			return nil
+		}
+
+		return revokeAllSessions(ctx, q, user.ID)
+	})
+
+	return user, err
+}
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUserTokenUsed is returned when a one-time token has already been
+// consumed.
+var ErrUserTokenUsed = errors.New("token has already been used")
+
+type ConsumeUserTokenParams struct {
+	TokenID int32 `json:"tokenId"`
+	// Apply performs the action the token authorizes. It runs in the same
+	// transaction that marks the token used, so the token is only spent if
+	// the action succeeds.
+	Apply func(q Querier) error
+}
+
+// ConsumeUserToken marks a one-time token as used and applies its effect
+// atomically. ErrUserTokenUsed is returned if the token was already used.
+func (store *SQLStore) ConsumeUserToken(ctx context.Context, arg ConsumeUserTokenParams) error {
+	return store.ExecTx(ctx, func(q *Queries) error {
+		used, err := q.UseUserToken(ctx, arg.TokenID)
+		if err != nil {
+			return err
+		}
+
+		if used == 0 {
+			return ErrUserTokenUsed
+		}
+
+		return arg.Apply(q)
+	})
+}
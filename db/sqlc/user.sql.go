@@ -7,10 +7,11 @@ package db
 
 import (
 	"context"
+	"time"
 )
 
 const createUser = `-- name: CreateUser :one
-insert into users (first_name, last_name, email, password_hash, profile_picture_url) values ($1, $2, $3, $4, $5) returning id, first_name, last_name, email, is_email_verified, password_hash, profile_picture_url, created_at
+insert into users (first_name, last_name, email, password_hash, profile_picture_url, timezone) values ($1, $2, $3, $4, $5, $6) returning id, first_name, last_name, email, is_email_verified, password_hash, profile_picture_url, created_at, password_changed_at, timezone
 `
 
 type CreateUserParams struct {
@@ -19,6 +20,7 @@ type CreateUserParams struct {
 	Email             string `json:"email"`
 	PasswordHash      string `json:"passwordHash"`
 	ProfilePictureUrl string `json:"profilePictureUrl"`
+	Timezone          string `json:"timezone"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -28,6 +30,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.Email,
 		arg.PasswordHash,
 		arg.ProfilePictureUrl,
+		arg.Timezone,
 	)
 	var i User
 	err := row.Scan(
@@ -39,6 +42,8 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.PasswordHash,
 		&i.ProfilePictureUrl,
 		&i.CreatedAt,
+		&i.PasswordChangedAt,
+		&i.Timezone,
 	)
 	return i, err
 }
@@ -53,7 +58,7 @@ func (q *Queries) DeleteUser(ctx context.Context, id int32) error {
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-select id, first_name, last_name, email, is_email_verified, password_hash, profile_picture_url, created_at from users where email = $1
+select id, first_name, last_name, email, is_email_verified, password_hash, profile_picture_url, created_at, password_changed_at, timezone from users where email = $1
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
@@ -68,12 +73,14 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.PasswordHash,
 		&i.ProfilePictureUrl,
 		&i.CreatedAt,
+		&i.PasswordChangedAt,
+		&i.Timezone,
 	)
 	return i, err
 }
 
 const getUserById = `-- name: GetUserById :one
-select id, first_name, last_name, email, is_email_verified, password_hash, profile_picture_url, created_at from  users where id = $1
+select id, first_name, last_name, email, is_email_verified, password_hash, profile_picture_url, created_at, password_changed_at, timezone from  users where id = $1
 `
 
 func (q *Queries) GetUserById(ctx context.Context, id int32) (User, error) {
@@ -88,19 +95,79 @@ func (q *Queries) GetUserById(ctx context.Context, id int32) (User, error) {
 		&i.PasswordHash,
 		&i.ProfilePictureUrl,
 		&i.CreatedAt,
+		&i.PasswordChangedAt,
+		&i.Timezone,
 	)
 	return i, err
 }
 
+const listUsersByEmails = `-- name: ListUsersByEmails :many
+select id, first_name, last_name, email from users
+where lower(email) = any($1::text[])
+order by id
+`
+
+type ListUsersByEmailsRow struct {
+	ID        int32  `json:"id"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Email     string `json:"email"`
+}
+
+func (q *Queries) ListUsersByEmails(ctx context.Context, emails []string) ([]ListUsersByEmailsRow, error) {
+	rows, err := q.db.Query(ctx, listUsersByEmails, emails)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUsersByEmailsRow{}
+	for rows.Next() {
+		var i ListUsersByEmailsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FirstName,
+			&i.LastName,
+			&i.Email,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markUserEmailVerified = `-- name: MarkUserEmailVerified :execrows
+update users
+	set is_email_verified = true
+where id = $1 and email = $2
+`
+
+type MarkUserEmailVerifiedParams struct {
+	ID    int32  `json:"id"`
+	Email string `json:"email"`
+}
+
+func (q *Queries) MarkUserEmailVerified(ctx context.Context, arg MarkUserEmailVerifiedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markUserEmailVerified, arg.ID, arg.Email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateUser = `-- name: UpdateUser :one
 update users
 	set first_name = $2,
 		last_name = $3,
 		email = $4,
 		profile_picture_url = $5,
-        is_email_verified = $6
+        is_email_verified = $6,
+        timezone = $7
 where id = $1
-returning id, first_name, last_name, email, is_email_verified, password_hash, profile_picture_url, created_at
+returning id, first_name, last_name, email, is_email_verified, password_hash, profile_picture_url, created_at, password_changed_at, timezone
 `
 
 type UpdateUserParams struct {
@@ -110,6 +177,7 @@ type UpdateUserParams struct {
 	Email             string `json:"email"`
 	ProfilePictureUrl string `json:"profilePictureUrl"`
 	IsEmailVerified   bool   `json:"isEmailVerified"`
+	Timezone          string `json:"timezone"`
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
@@ -120,6 +188,7 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		arg.Email,
 		arg.ProfilePictureUrl,
 		arg.IsEmailVerified,
+		arg.Timezone,
 	)
 	var i User
 	err := row.Scan(
@@ -131,22 +200,26 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.PasswordHash,
 		&i.ProfilePictureUrl,
 		&i.CreatedAt,
+		&i.PasswordChangedAt,
+		&i.Timezone,
 	)
 	return i, err
 }
 
 const updateUserPassword = `-- name: UpdateUserPassword :exec
 update users 
-	set password_hash = $2
+	set password_hash = $2,
+		password_changed_at = $3
 where id = $1
 `
 
 type UpdateUserPasswordParams struct {
-	ID           int32  `json:"id"`
-	PasswordHash string `json:"passwordHash"`
+	ID                int32      `json:"id"`
+	PasswordHash      string     `json:"passwordHash"`
+	PasswordChangedAt *time.Time `json:"passwordChangedAt"`
 }
 
 func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
-	_, err := q.db.Exec(ctx, updateUserPassword, arg.ID, arg.PasswordHash)
+	_, err := q.db.Exec(ctx, updateUserPassword, arg.ID, arg.PasswordHash, arg.PasswordChangedAt)
 	return err
 }
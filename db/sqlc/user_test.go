@@ -13,6 +13,7 @@ func createRandomUser(t *testing.T) User {
 		LastName:     util.RandomString(10),
 		Email:        util.RandomEmail(),
 		PasswordHash: util.RandomString(12),
+		Timezone:     "UTC",
 	}
 
 	user, err := testQueries.CreateUser(t.Context(), arg)
@@ -22,6 +23,7 @@ func createRandomUser(t *testing.T) User {
 	require.Equal(t, user.LastName, arg.LastName)
 	require.Equal(t, user.Email, arg.Email)
 	require.Equal(t, user.PasswordHash, arg.PasswordHash)
+	require.Equal(t, user.Timezone, arg.Timezone)
 	require.NotZero(t, user.ID)
 	require.NotZero(t, user.CreatedAt)
 
@@ -68,6 +70,7 @@ func TestUpdateUser(t *testing.T) {
 		Email:             user.Email,
 		ProfilePictureUrl: user.ProfilePictureUrl,
 		IsEmailVerified:   user.IsEmailVerified,
+		Timezone:          "Africa/Lagos",
 	}
 
 	updatedUser, err := testQueries.UpdateUser(t.Context(), arg)
@@ -76,6 +79,7 @@ func TestUpdateUser(t *testing.T) {
 	require.Equal(t, updatedUser.FirstName, arg.FirstName)
 	require.Equal(t, updatedUser.LastName, arg.LastName)
 	require.Equal(t, updatedUser.Email, arg.Email)
+	require.Equal(t, updatedUser.Timezone, arg.Timezone)
 	require.Equal(t, updatedUser.PasswordHash, user.PasswordHash)
 
 	arg = UpdateUserParams{
@@ -119,3 +123,27 @@ func TestDeleteUserPassword(t *testing.T) {
 	require.Error(t, err)
 	require.EqualError(t, err, ErrRecordNotFound.Error())
 }
+
+func TestDeleteUserKeepsCreatedRecords(t *testing.T) {
+	user := createRandomUser(t)
+
+	task, err := testQueries.CreateTask(t.Context(), CreateTaskParams{
+		Title:     util.RandomString(12),
+		CreatedBy: &user.ID,
+		StatusID:  initialTaskStatus(t, nil).ID,
+	})
+	require.NoError(t, err)
+
+	team := createRandomTeam(t, user)
+
+	err = testQueries.DeleteUser(t.Context(), user.ID)
+	require.NoError(t, err)
+
+	fetchedTask, err := testQueries.GetTask(t.Context(), task.ID)
+	require.NoError(t, err)
+	require.Nil(t, fetchedTask.CreatedBy)
+
+	fetchedTeam, err := testQueries.GetTeam(t.Context(), team.ID)
+	require.NoError(t, err)
+	require.Nil(t, fetchedTeam.CreatedBy)
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bolusarz/task-manager/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomUserToken(t *testing.T, user User, purpose string) UserToken {
+	arg := CreateUserTokenParams{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: util.HashToken(util.RandomString(32)),
+		ExpiresAt: time.Now().UTC().Add(time.Hour).Truncate(time.Second),
+		Email:     user.Email,
+	}
+
+	userToken, err := testQueries.CreateUserToken(t.Context(), arg)
+
+	require.NoError(t, err)
+	require.Equal(t, arg.UserID, userToken.UserID)
+	require.Equal(t, arg.Purpose, userToken.Purpose)
+	require.Equal(t, arg.TokenHash, userToken.TokenHash)
+	require.Equal(t, arg.Email, userToken.Email)
+	require.Nil(t, userToken.UsedAt)
+
+	return userToken
+}
+
+func TestGetUserTokenByHash(t *testing.T) {
+	userToken := createRandomUserToken(t, createRandomUser(t), "email_verification")
+
+	fetched, err := testQueries.GetUserTokenByHash(t.Context(), GetUserTokenByHashParams{
+		TokenHash: userToken.TokenHash,
+		Purpose:   userToken.Purpose,
+	})
+	require.NoError(t, err)
+	require.Equal(t, userToken.ID, fetched.ID)
+
+	// A token is only valid for the purpose it was issued for.
+	_, err = testQueries.GetUserTokenByHash(t.Context(), GetUserTokenByHashParams{
+		TokenHash: userToken.TokenHash,
+		Purpose:   "password_reset",
+	})
+	require.EqualError(t, err, ErrRecordNotFound.Error())
+}
+
+func TestConsumeUserToken(t *testing.T) {
+	user := createRandomUser(t)
+	userToken := createRandomUserToken(t, user, "email_verification")
+
+	apply := func(q Querier) error {
+		_, err := q.MarkUserEmailVerified(t.Context(), MarkUserEmailVerifiedParams{ID: user.ID, Email: userToken.Email})
+		return err
+	}
+
+	err := testStore.ConsumeUserToken(t.Context(), ConsumeUserTokenParams{TokenID: userToken.ID, Apply: apply})
+	require.NoError(t, err)
+
+	fetchedUser, err := testQueries.GetUserById(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.True(t, fetchedUser.IsEmailVerified)
+
+	err = testStore.ConsumeUserToken(t.Context(), ConsumeUserTokenParams{TokenID: userToken.ID, Apply: apply})
+	require.ErrorIs(t, err, ErrUserTokenUsed)
+}
+
+func TestMarkUserEmailVerifiedStaleEmail(t *testing.T) {
+	user := createRandomUser(t)
+
+	// A token sent to an address the user no longer has verifies nothing.
+	rows, err := testQueries.MarkUserEmailVerified(t.Context(), MarkUserEmailVerifiedParams{ID: user.ID, Email: util.RandomEmail()})
+	require.NoError(t, err)
+	require.Zero(t, rows)
+
+	fetchedUser, err := testQueries.GetUserById(t.Context(), user.ID)
+	require.NoError(t, err)
+	require.False(t, fetchedUser.IsEmailVerified)
+}
+
+func TestInvalidateUserTokens(t *testing.T) {
+	user := createRandomUser(t)
+	first := createRandomUserToken(t, user, "email_verification")
+	second := createRandomUserToken(t, user, "email_verification")
+
+	latest, err := testQueries.GetLatestUserToken(t.Context(), GetLatestUserTokenParams{UserID: user.ID, Purpose: "email_verification"})
+	require.NoError(t, err)
+	require.Equal(t, second.ID, latest.ID)
+
+	err = testQueries.InvalidateUserTokens(t.Context(), InvalidateUserTokensParams{UserID: user.ID, Purpose: "email_verification"})
+	require.NoError(t, err)
+
+	used, err := testQueries.UseUserToken(t.Context(), first.ID)
+	require.NoError(t, err)
+	require.Zero(t, used)
+}
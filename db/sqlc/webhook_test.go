@@ -0,0 +1,188 @@
+package db
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/bolusarz/task-manager/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomWebhook(t *testing.T, team Team, eventTypes ...string) Webhook {
+	if len(eventTypes) == 0 {
+		eventTypes = []string{string(EventTaskCreated)}
+	}
+
+	arg := CreateWebhookParams{
+		TeamID:     team.ID,
+		Url:        "https://example.com/" + util.RandomString(8),
+		EventTypes: eventTypes,
+		Secret:     util.RandomString(32),
+		CreatedBy:  team.CreatedBy,
+	}
+
+	webhook, err := testQueries.CreateWebhook(t.Context(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, webhook.ID)
+	require.Equal(t, arg.TeamID, webhook.TeamID)
+	require.Equal(t, arg.Url, webhook.Url)
+	require.Equal(t, arg.EventTypes, webhook.EventTypes)
+	require.Equal(t, arg.Secret, webhook.Secret)
+	require.True(t, webhook.Active)
+	require.Zero(t, webhook.ConsecutiveFailures)
+	require.Nil(t, webhook.DisabledAt)
+
+	return webhook
+}
+
+func createRandomWebhookDelivery(t *testing.T, webhook Webhook, eventID int64) WebhookDelivery {
+	err := testQueries.CreateWebhookDelivery(t.Context(), CreateWebhookDeliveryParams{
+		WebhookID: webhook.ID,
+		EventID:   eventID,
+		EventType: string(EventTaskCreated),
+		Payload:   json.RawMessage(`{"id":1}`),
+	})
+	require.NoError(t, err)
+
+	deliveries, err := testQueries.ListWebhookDeliveries(t.Context(), ListWebhookDeliveriesParams{
+		WebhookID: webhook.ID,
+		Limit:     1,
+	})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	return deliveries[0]
+}
+
+func TestListWebhooksForEvent(t *testing.T) {
+	owner := createRandomUser(t)
+	team := createRandomTeam(t, owner)
+
+	created := createRandomWebhook(t, team, string(EventTaskCreated), string(EventTaskDeleted))
+	createRandomWebhook(t, team, string(EventTaskUpdated))
+
+	disabled := createRandomWebhook(t, team, string(EventTaskCreated))
+	_, err := testQueries.UpdateWebhook(t.Context(), UpdateWebhookParams{
+		Url:        disabled.Url,
+		EventTypes: disabled.EventTypes,
+		Active:     false,
+		ID:         disabled.ID,
+	})
+	require.NoError(t, err)
+
+	webhooks, err := testQueries.ListWebhooksForEvent(t.Context(), ListWebhooksForEventParams{
+		TeamID:    team.ID,
+		EventType: string(EventTaskCreated),
+	})
+	require.NoError(t, err)
+	require.Len(t, webhooks, 1)
+	require.Equal(t, created.ID, webhooks[0].ID)
+
+	all, err := testQueries.ListTeamWebhooks(t.Context(), team.ID)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+}
+
+func TestCreateWebhookDeliveryIsIdempotent(t *testing.T) {
+	owner := createRandomUser(t)
+	webhook := createRandomWebhook(t, createRandomTeam(t, owner))
+
+	delivery := createRandomWebhookDelivery(t, webhook, 42)
+	require.Equal(t, "pending", delivery.Status)
+	require.Zero(t, delivery.Attempts)
+	require.Nil(t, delivery.RedeliveryOf)
+
+	again := createRandomWebhookDelivery(t, webhook, 42)
+	require.Equal(t, delivery.ID, again.ID)
+
+	redelivery, err := testQueries.CreateWebhookRedelivery(t.Context(), delivery.ID)
+	require.NoError(t, err)
+	require.NotEqual(t, delivery.ID, redelivery.ID)
+	require.Equal(t, delivery.EventID, redelivery.EventID)
+	require.JSONEq(t, string(delivery.Payload), string(redelivery.Payload))
+	require.Equal(t, &delivery.ID, redelivery.RedeliveryOf)
+	require.Equal(t, "pending", redelivery.Status)
+
+	deliveries, err := testQueries.ListWebhookDeliveries(t.Context(), ListWebhookDeliveriesParams{
+		WebhookID: webhook.ID,
+		BeforeID:  &redelivery.ID,
+		Limit:     10,
+	})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, delivery.ID, deliveries[0].ID)
+}
+
+func TestClaimWebhookDeliveriesSkipsDisabledWebhooks(t *testing.T) {
+	owner := createRandomUser(t)
+	team := createRandomTeam(t, owner)
+
+	active := createRandomWebhook(t, team)
+	disabled := createRandomWebhook(t, team)
+
+	activeDelivery := createRandomWebhookDelivery(t, active, 1)
+	disabledDelivery := createRandomWebhookDelivery(t, disabled, 1)
+
+	_, err := testQueries.UpdateWebhook(t.Context(), UpdateWebhookParams{
+		Url:        disabled.Url,
+		EventTypes: disabled.EventTypes,
+		Active:     false,
+		ID:         disabled.ID,
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	claimed := make(map[int64]bool)
+	for {
+		deliveries, err := testQueries.ClaimWebhookDeliveries(t.Context(), ClaimWebhookDeliveriesParams{
+			LeaseUntil: now.Add(time.Minute),
+			Now:        now,
+			Limit:      100,
+		})
+		require.NoError(t, err)
+
+		if len(deliveries) == 0 {
+			break
+		}
+		for _, delivery := range deliveries {
+			claimed[delivery.ID] = true
+		}
+	}
+
+	require.True(t, claimed[activeDelivery.ID])
+	require.False(t, claimed[disabledDelivery.ID])
+}
+
+func TestRecordWebhookFailure(t *testing.T) {
+	owner := createRandomUser(t)
+	webhook := createRandomWebhook(t, createRandomTeam(t, owner))
+
+	for i := int32(1); i < 3; i++ {
+		updated, err := testQueries.RecordWebhookFailure(t.Context(), RecordWebhookFailureParams{MaxFailures: 3, ID: webhook.ID})
+		require.NoError(t, err)
+		require.Equal(t, i, updated.ConsecutiveFailures)
+		require.True(t, updated.Active)
+	}
+
+	err := testQueries.ResetWebhookFailures(t.Context(), webhook.ID)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		webhook, err = testQueries.RecordWebhookFailure(t.Context(), RecordWebhookFailureParams{MaxFailures: 3, ID: webhook.ID})
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(3), webhook.ConsecutiveFailures)
+	require.False(t, webhook.Active)
+	require.NotNil(t, webhook.DisabledAt)
+
+	webhook, err = testQueries.UpdateWebhook(t.Context(), UpdateWebhookParams{
+		Url:        webhook.Url,
+		EventTypes: webhook.EventTypes,
+		Active:     true,
+		ID:         webhook.ID,
+	})
+	require.NoError(t, err)
+	require.True(t, webhook.Active)
+	require.Zero(t, webhook.ConsecutiveFailures)
+	require.Nil(t, webhook.DisabledAt)
+}
@@ -0,0 +1,37 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// LogMailer writes messages to w instead of delivering them.
+type LogMailer struct {
+	mu     sync.Mutex
+	w      io.Writer
+	sender string
+}
+
+func NewLogMailer(w io.Writer, sender string) *LogMailer {
+	return &LogMailer{
+		w:      w,
+		sender: sender,
+	}
+}
+
+func (m *LogMailer) Send(ctx context.Context, message Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, err := fmt.Fprintf(
+		m.w,
+		"--- email ---\nFrom: %s\nTo: %s\nSubject: %s\n\n%s\n--- end ---\n",
+		m.sender,
+		message.To,
+		message.Subject,
+		message.Body,
+	)
+	return err
+}
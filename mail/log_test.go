@@ -0,0 +1,45 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/bolusarz/task-manager/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogMailer(t *testing.T) {
+	var buf bytes.Buffer
+
+	sender := util.RandomEmail()
+	mailer := NewLogMailer(&buf, sender)
+
+	message := Message{
+		To:      util.RandomEmail(),
+		Subject: util.RandomString(10),
+		Body:    util.RandomString(40),
+	}
+
+	err := mailer.Send(context.Background(), message)
+	require.NoError(t, err)
+
+	out := buf.String()
+	require.Contains(t, out, "From: "+sender)
+	require.Contains(t, out, "To: "+message.To)
+	require.Contains(t, out, "Subject: "+message.Subject)
+	require.Contains(t, out, message.Body)
+}
+
+func TestNewMailer(t *testing.T) {
+	mailer, err := NewMailer(util.Config{})
+	require.NoError(t, err)
+	require.IsType(t, &LogMailer{}, mailer)
+
+	mailer, err = NewMailer(util.Config{Mailer: "smtp", SMTPHost: "localhost", SMTPPort: 25})
+	require.NoError(t, err)
+	require.IsType(t, &SMTPMailer{}, mailer)
+
+	_, err = NewMailer(util.Config{Mailer: util.RandomString(6)})
+	require.Error(t, err)
+}
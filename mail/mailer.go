@@ -0,0 +1,41 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/bolusarz/task-manager/util"
+)
+
+// Message is a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers email to users.
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// NewMailer returns the Mailer selected by config.Mailer: "smtp" delivers
+// through the configured SMTP server, while "log" (the default) writes every
+// message to stdout, which is what local development and tests want.
+func NewMailer(config util.Config) (Mailer, error) {
+	switch config.Mailer {
+	case "smtp":
+		return NewSMTPMailer(
+			config.SMTPHost,
+			config.SMTPPort,
+			config.SMTPUsername,
+			config.SMTPPassword,
+			config.EmailSenderAddress,
+		), nil
+	case "", "log":
+		return NewLogMailer(os.Stdout, config.EmailSenderAddress), nil
+	default:
+		return nil, fmt.Errorf("unknown mailer %q", config.Mailer)
+	}
+}
@@ -0,0 +1,58 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+type SMTPMailer struct {
+	addr   string
+	host   string
+	auth   smtp.Auth
+	sender string
+}
+
+func NewSMTPMailer(host string, port int, username, password, sender string) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTPMailer{
+		addr:   net.JoinHostPort(host, strconv.Itoa(port)),
+		host:   host,
+		auth:   auth,
+		sender: sender,
+	}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, message Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := smtp.SendMail(m.addr, m.auth, m.sender, []string{message.To}, m.format(message))
+	if err != nil {
+		return fmt.Errorf("unable to send email to %s: %w", message.To, err)
+	}
+
+	return nil
+}
+
+func (m *SMTPMailer) format(message Message) []byte {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "From: %s\r\n", m.sender)
+	fmt.Fprintf(&sb, "To: %s\r\n", message.To)
+	fmt.Fprintf(&sb, "Subject: %s\r\n", message.Subject)
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	sb.WriteString("\r\n")
+	sb.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+
+	return []byte(sb.String())
+}
@@ -4,21 +4,34 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/bolusarz/task-manager/api"
 	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/mail"
+	"github.com/bolusarz/task-manager/notify"
+	"github.com/bolusarz/task-manager/outbox"
+	"github.com/bolusarz/task-manager/recurrence"
+	"github.com/bolusarz/task-manager/stream"
 	"github.com/bolusarz/task-manager/util"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/bolusarz/task-manager/webhook"
 )
 
 func main() {
+	// Everything winds down on an interrupt or SIGTERM; the server finishes
+	// the requests it is serving first.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	config, err := util.LoadConfig(".")
 
 	if err != nil {
 		log.Fatal(fmt.Errorf("unable to load config %v", err))
 	}
 
-	conn, err := pgxpool.New(context.Background(), config.DBSource)
+	conn, err := db.NewPool(ctx, config.DBSource)
 
 	if err != nil {
 		log.Fatal(err)
@@ -26,11 +39,34 @@ func main() {
 
 	store := db.NewStore(conn)
 
-	server, err := api.NewServer(store, config)
+	recurrences := recurrence.NewGenerator(store, config.RecurrenceInterval)
+	go recurrences.Run(ctx)
+
+	dispatcher := outbox.NewDispatcher(store, config.OutboxInterval)
+
+	webhooks := webhook.NewSender(store, config.WebhookInterval)
+	webhooks.Subscribe(dispatcher)
+
+	notify.NewNotifier(store).Subscribe(dispatcher)
+
+	mailer, err := mail.NewMailer(config)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	go dispatcher.Run(ctx)
+	go webhooks.Run(ctx)
+	go notify.NewDigester(store, mailer, config.DigestInterval).Run(ctx)
+
+	hub := stream.NewHub()
+	go stream.NewListener(conn, store, hub).Run(ctx)
+
+	server, err := api.NewServer(store, config, mailer, recurrences, hub)
 
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	server.StartServer(config.HTTPServerAddress)
+	server.StartServer(ctx, config.HTTPServerAddress)
 }
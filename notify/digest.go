@@ -0,0 +1,158 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/mail"
+)
+
+const (
+	defaultDigestInterval = time.Minute
+	digestBatchSize       = 100
+
+	// DigestHour is the hour of the day, in each user's own time zone, from
+	// which their daily digest is sent.
+	DigestHour = 8
+
+	// maxDigestItems is how many notifications a digest lists; the rest are
+	// left for the app.
+	maxDigestItems = 50
+)
+
+// Digester emails a daily digest of unread notifications to the users who
+// asked for one.
+type Digester struct {
+	store    db.Store
+	mailer   mail.Mailer
+	interval time.Duration
+	now      func() time.Time
+}
+
+// NewDigester returns a digester that looks for digests to send every
+// interval, or every minute when interval is not positive.
+func NewDigester(store db.Store, mailer mail.Mailer, interval time.Duration) *Digester {
+	if interval <= 0 {
+		interval = defaultDigestInterval
+	}
+
+	return &Digester{
+		store:    store,
+		mailer:   mailer,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// Run sends digests until ctx is done.
+func (d *Digester) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.SendDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("unable to send notification digests: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDue sends today's digest to every user who is due one: they asked for
+// it, it is past DigestHour where they are and they have not had one yet
+// today. A user with nothing unread since their last digest gets no email,
+// but counts as having had it.
+func (d *Digester) SendDue(ctx context.Context) error {
+	for {
+		now := d.now().UTC()
+
+		recipients, err := d.store.ListDigestRecipients(ctx, db.ListDigestRecipientsParams{
+			Now:        now,
+			DigestHour: DigestHour,
+			Limit:      digestBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(recipients) == 0 {
+			return nil
+		}
+
+		for _, recipient := range recipients {
+			if err := d.send(ctx, recipient, now); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (d *Digester) send(ctx context.Context, recipient db.ListDigestRecipientsRow, now time.Time) error {
+	since := now.Add(-24 * time.Hour)
+	if recipient.LastDigestAt != nil {
+		since = *recipient.LastDigestAt
+	}
+
+	notifications, err := d.store.ListUnreadNotificationsSince(ctx, db.ListUnreadNotificationsSinceParams{
+		UserID: recipient.UserID,
+		Since:  since,
+		Limit:  maxDigestItems + 1,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(notifications) > 0 {
+		err = d.mailer.Send(ctx, mail.Message{
+			To:      recipient.Email,
+			Subject: "Your daily digest",
+			Body:    digestBody(recipient.FirstName, notifications),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return d.store.MarkDigestSent(ctx, db.MarkDigestSentParams{
+		SentAt: now,
+		UserID: recipient.UserID,
+	})
+}
+
+func digestBody(firstName string, notifications []db.Notification) string {
+	var body strings.Builder
+
+	fmt.Fprintf(&body, "Hi %s,\n\nHere is what happened since your last digest:\n\n", firstName)
+
+	for i, notification := range notifications {
+		if i == maxDigestItems {
+			body.WriteString("- and more\n")
+			break
+		}
+		fmt.Fprintf(&body, "- %s\n", describe(notification.Type, notification.TaskTitle))
+	}
+
+	body.WriteString("\nYou can see everything in your notifications, and turn this digest off in your notification preferences.\n")
+	return body.String()
+}
+
+func describe(notificationType, taskTitle string) string {
+	switch notificationType {
+	case TypeTaskAssigned:
+		return fmt.Sprintf("You were assigned to %q", taskTitle)
+	case TypeMentioned:
+		return fmt.Sprintf("You were mentioned on %q", taskTitle)
+	case TypeWatchedTaskChanged:
+		return fmt.Sprintf("%q changed", taskTitle)
+	default:
+		return fmt.Sprintf("Something happened on %q", taskTitle)
+	}
+}
@@ -0,0 +1,128 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/mail"
+	mockmail "github.com/bolusarz/task-manager/mail/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newTestDigester(store db.Store, mailer mail.Mailer, now time.Time) *Digester {
+	digester := NewDigester(store, mailer, time.Second)
+	digester.now = func() time.Time { return now }
+	return digester
+}
+
+// expectRecipients has the store hand out recipients once, then nothing.
+func expectRecipients(store *mockdb.MockStore, now time.Time, recipients ...db.ListDigestRecipientsRow) {
+	arg := db.ListDigestRecipientsParams{
+		Now:        now,
+		DigestHour: DigestHour,
+		Limit:      digestBatchSize,
+	}
+
+	gomock.InOrder(
+		store.EXPECT().ListDigestRecipients(gomock.Any(), gomock.Eq(arg)).Times(1).Return(recipients, nil),
+		store.EXPECT().ListDigestRecipients(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]db.ListDigestRecipientsRow{}, nil),
+	)
+}
+
+func TestSendDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	lastDigestAt := now.Add(-20 * time.Hour)
+
+	busy := db.ListDigestRecipientsRow{UserID: 1, FirstName: "Ada", Email: "ada@example.com", Timezone: "UTC", LastDigestAt: &lastDigestAt}
+	quiet := db.ListDigestRecipientsRow{UserID: 2, FirstName: "Bo", Email: "bo@example.com", Timezone: "UTC"}
+
+	store := mockdb.NewMockStore(ctrl)
+	mailer := mockmail.NewMockMailer(ctrl)
+	expectRecipients(store, now, busy, quiet)
+
+	store.EXPECT().
+		ListUnreadNotificationsSince(gomock.Any(), gomock.Eq(db.ListUnreadNotificationsSinceParams{
+			UserID: busy.UserID,
+			Since:  lastDigestAt,
+			Limit:  maxDigestItems + 1,
+		})).
+		Times(1).
+		Return([]db.Notification{
+			{ID: 1, Type: TypeTaskAssigned, TaskTitle: "Write report"},
+			{ID: 2, Type: TypeMentioned, TaskTitle: "Fix login"},
+			{ID: 3, Type: TypeWatchedTaskChanged, TaskTitle: "Ship it"},
+		}, nil)
+	store.EXPECT().
+		ListUnreadNotificationsSince(gomock.Any(), gomock.Eq(db.ListUnreadNotificationsSinceParams{
+			UserID: quiet.UserID,
+			Since:  now.Add(-24 * time.Hour),
+			Limit:  maxDigestItems + 1,
+		})).
+		Times(1).
+		Return([]db.Notification{}, nil)
+
+	mailer.EXPECT().
+		Send(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, message mail.Message) error {
+			require.Equal(t, busy.Email, message.To)
+			require.Contains(t, message.Body, "Hi Ada,")
+			require.Contains(t, message.Body, `- You were assigned to "Write report"`)
+			require.Contains(t, message.Body, `- You were mentioned on "Fix login"`)
+			require.Contains(t, message.Body, `- "Ship it" changed`)
+			return nil
+		})
+
+	// Both count as having had today's digest, even though only one had
+	// anything to read.
+	store.EXPECT().MarkDigestSent(gomock.Any(), gomock.Eq(db.MarkDigestSentParams{SentAt: now, UserID: busy.UserID})).Times(1).Return(nil)
+	store.EXPECT().MarkDigestSent(gomock.Any(), gomock.Eq(db.MarkDigestSentParams{SentAt: now, UserID: quiet.UserID})).Times(1).Return(nil)
+
+	require.NoError(t, newTestDigester(store, mailer, now).SendDue(t.Context()))
+}
+
+func TestSendDueMailerError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	recipient := db.ListDigestRecipientsRow{UserID: 1, FirstName: "Ada", Email: "ada@example.com", Timezone: "UTC"}
+	errMailer := errors.New("smtp unavailable")
+
+	store := mockdb.NewMockStore(ctrl)
+	mailer := mockmail.NewMockMailer(ctrl)
+
+	store.EXPECT().ListDigestRecipients(gomock.Any(), gomock.Any()).Times(1).Return([]db.ListDigestRecipientsRow{recipient}, nil)
+	store.EXPECT().
+		ListUnreadNotificationsSince(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return([]db.Notification{{ID: 1, Type: TypeTaskAssigned, TaskTitle: "Write report"}}, nil)
+	mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(1).Return(errMailer)
+
+	// The digest is not marked as sent, so it is tried again on the next run.
+	store.EXPECT().MarkDigestSent(gomock.Any(), gomock.Any()).Times(0)
+
+	err := newTestDigester(store, mailer, now).SendDue(t.Context())
+	require.ErrorIs(t, err, errMailer)
+}
+
+func TestDigestBodyIsCapped(t *testing.T) {
+	notifications := make([]db.Notification, maxDigestItems+1)
+	for i := range notifications {
+		notifications[i] = db.Notification{Type: TypeTaskAssigned, TaskTitle: fmt.Sprintf("Task %d", i)}
+	}
+
+	body := digestBody("Ada", notifications)
+	require.Contains(t, body, fmt.Sprintf(`"Task %d"`, maxDigestItems-1))
+	require.NotContains(t, body, fmt.Sprintf(`"Task %d"`, maxDigestItems))
+	require.Contains(t, body, "- and more\n")
+}
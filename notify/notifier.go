@@ -0,0 +1,90 @@
+package notify
+
+import (
+	"context"
+	"slices"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/outbox"
+)
+
+// Types of notification. Users can turn each of them off in their
+// preferences.
+const (
+	TypeTaskAssigned       = "task_assigned"
+	TypeMentioned          = "mentioned"
+	TypeWatchedTaskChanged = "watched_task_changed"
+)
+
+// Notifier turns domain events into notifications for the users they
+// concern: the assignee of a task, the users mentioned in a comment and the
+// watchers of a task that changed. Nobody is notified of what they did
+// themselves.
+type Notifier struct {
+	store db.Store
+}
+
+func NewNotifier(store db.Store) *Notifier {
+	return &Notifier{store: store}
+}
+
+// Subscribe has the events of dispatcher that users are notified of passed
+// to Notify.
+func (n *Notifier) Subscribe(dispatcher *outbox.Dispatcher) {
+	dispatcher.Subscribe(db.EventTaskAssigned, n.Notify)
+	dispatcher.Subscribe(db.EventUsersMentioned, n.Notify)
+	dispatcher.Subscribe(db.EventTaskUpdated, n.Notify)
+	dispatcher.Subscribe(db.EventTaskStatusChanged, n.Notify)
+}
+
+// Notify creates the notifications for event, leaving out users who turned
+// its type of notification off. Notifying of the same event again does
+// nothing.
+func (n *Notifier) Notify(ctx context.Context, event outbox.Event) error {
+	arg := db.CreateNotificationsParams{
+		ActorID: event.ActorID,
+		EventID: event.ID,
+	}
+
+	switch e := event.Data.(type) {
+	case db.TaskAssigned:
+		arg.Type = TypeTaskAssigned
+		arg.TaskID = e.TaskID
+		arg.UserIds = []int32{e.UserID}
+	case db.UsersMentioned:
+		arg.Type = TypeMentioned
+		arg.TaskID = e.TaskID
+		arg.CommentID = &e.CommentID
+		arg.UserIds = e.UserIDs
+	case db.TaskUpdated:
+		arg.Type = TypeWatchedTaskChanged
+		arg.TaskID = e.Task.ID
+	case db.TaskStatusChanged:
+		arg.Type = TypeWatchedTaskChanged
+		arg.TaskID = e.Task.ID
+	default:
+		return nil
+	}
+
+	if arg.Type == TypeWatchedTaskChanged {
+		watchers, err := n.store.ListTaskWatchers(ctx, arg.TaskID)
+		if err != nil {
+			return err
+		}
+		for _, watcher := range watchers {
+			arg.UserIds = append(arg.UserIds, watcher.UserID)
+		}
+	}
+
+	if event.ActorID != nil {
+		arg.UserIds = slices.DeleteFunc(slices.Clone(arg.UserIds), func(userID int32) bool {
+			return userID == *event.ActorID
+		})
+	}
+
+	if len(arg.UserIds) == 0 {
+		return nil
+	}
+
+	return n.store.CreateNotifications(ctx, arg)
+}
@@ -0,0 +1,128 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/outbox"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNotify(t *testing.T) {
+	actorID := int32(3)
+	commentID := int32(21)
+	task := db.Task{ID: 4, Title: "Write report"}
+	watchers := []db.ListTaskWatchersRow{{UserID: 3}, {UserID: 8}, {UserID: 9}}
+
+	testCases := []struct {
+		name       string
+		data       db.DomainEvent
+		buildStubs func(store *mockdb.MockStore)
+	}{
+		{
+			name: "Task Assigned",
+			data: db.TaskAssigned{TaskID: task.ID, UserID: 9},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateNotifications(gomock.Any(), gomock.Eq(db.CreateNotificationsParams{
+						Type:    TypeTaskAssigned,
+						ActorID: &actorID,
+						EventID: 7,
+						TaskID:  task.ID,
+						UserIds: []int32{9},
+					})).
+					Times(1).
+					Return(nil)
+			},
+		},
+		{
+			name: "Assigned Themselves",
+			data: db.TaskAssigned{TaskID: task.ID, UserID: actorID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateNotifications(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "Users Mentioned",
+			data: db.UsersMentioned{TaskID: task.ID, CommentID: commentID, UserIDs: []int32{actorID, 8}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateNotifications(gomock.Any(), gomock.Eq(db.CreateNotificationsParams{
+						Type:      TypeMentioned,
+						CommentID: &commentID,
+						ActorID:   &actorID,
+						EventID:   7,
+						TaskID:    task.ID,
+						UserIds:   []int32{8},
+					})).
+					Times(1).
+					Return(nil)
+			},
+		},
+		{
+			name: "Watched Task Updated",
+			data: db.TaskUpdated{Task: task},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListTaskWatchers(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(watchers, nil)
+				store.EXPECT().
+					CreateNotifications(gomock.Any(), gomock.Eq(db.CreateNotificationsParams{
+						Type:    TypeWatchedTaskChanged,
+						ActorID: &actorID,
+						EventID: 7,
+						TaskID:  task.ID,
+						UserIds: []int32{8, 9},
+					})).
+					Times(1).
+					Return(nil)
+			},
+		},
+		{
+			name: "Watched Task Status Changed",
+			data: db.TaskStatusChanged{Task: task, FromStatusID: 1},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListTaskWatchers(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return(watchers, nil)
+				store.EXPECT().
+					CreateNotifications(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+		},
+		{
+			name: "Unwatched Task",
+			data: db.TaskUpdated{Task: task},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListTaskWatchers(gomock.Any(), gomock.Eq(task.ID)).Times(1).Return([]db.ListTaskWatchersRow{}, nil)
+				store.EXPECT().CreateNotifications(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "Other Events",
+			data: db.MemberJoinedTeam{TeamID: 2, UserID: 9},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateNotifications(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tt.buildStubs(store)
+
+			err := NewNotifier(store).Notify(t.Context(), outbox.Event{
+				ID:         7,
+				OccurredAt: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+				Attempt:    1,
+				ActorID:    &actorID,
+				Data:       tt.data,
+			})
+			require.NoError(t, err)
+		})
+	}
+}
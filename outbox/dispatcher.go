@@ -0,0 +1,193 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+)
+
+const (
+	defaultInterval = time.Second
+	batchSize       = 100
+
+	// leaseDuration is how long a claimed event is left to its dispatcher.
+	// An event still undelivered after that, because its dispatcher died
+	// or is stuck, is claimed again.
+	leaseDuration = 5 * time.Minute
+
+	// maxAttempts is how many times delivery is tried before the event is
+	// given up on. With the backoff below that spans about half an hour.
+	maxAttempts = 12
+	minBackoff  = time.Second
+	maxBackoff  = time.Hour
+)
+
+// errUndecodable marks events that cannot be delivered however often they
+// are retried, such as ones recorded by a newer version of the code.
+var errUndecodable = errors.New("undecodable event")
+
+// Event is a domain event as handlers receive it.
+type Event struct {
+	ID         int64     `json:"id"`
+	OccurredAt time.Time `json:"occurredAt"`
+	// Attempt counts deliveries of this event, starting at 1.
+	Attempt int32 `json:"attempt"`
+	// ActorID is the user who caused the event, if it was caused by one.
+	ActorID *int32         `json:"actorId"`
+	Data    db.DomainEvent `json:"data"`
+}
+
+// Handler reacts to an event. An event can be delivered more than once, so
+// handlers must be idempotent; an error has the event delivered again later.
+type Handler func(ctx context.Context, event Event) error
+
+// Dispatcher delivers the events in the outbox to the handlers subscribed to
+// them. Every event is delivered at least once and the events of one
+// aggregate in the order they were recorded: a failing event is retried with
+// backoff and holds back the ones after it until it is delivered or given up
+// on.
+type Dispatcher struct {
+	store    db.Store
+	interval time.Duration
+	now      func() time.Time
+	handlers map[db.EventType][]Handler
+}
+
+// NewDispatcher returns a dispatcher that looks for undelivered events every
+// interval, or every second when interval is not positive.
+func NewDispatcher(store db.Store, interval time.Duration) *Dispatcher {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	return &Dispatcher{
+		store:    store,
+		interval: interval,
+		now:      time.Now,
+		handlers: make(map[db.EventType][]Handler),
+	}
+}
+
+// Subscribe has handler called for every event of eventType. Handlers must
+// be subscribed before Run is called.
+func (d *Dispatcher) Subscribe(eventType db.EventType, handler Handler) {
+	d.handlers[eventType] = append(d.handlers[eventType], handler)
+}
+
+// Run delivers events until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.DispatchPending(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("unable to dispatch outbox events: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchPending delivers every event that is due. An event is only claimed
+// once the events before it of the same aggregate are out of the way, so it
+// keeps claiming until nothing is left to deliver right now.
+func (d *Dispatcher) DispatchPending(ctx context.Context) error {
+	for {
+		now := d.now().UTC()
+
+		events, err := d.store.ClaimOutboxEvents(ctx, db.ClaimOutboxEventsParams{
+			LeaseUntil: now.Add(leaseDuration),
+			Now:        now,
+			Limit:      batchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(events) == 0 {
+			return nil
+		}
+
+		sort.Slice(events, func(i, j int) bool {
+			return events[i].ID < events[j].ID
+		})
+
+		for _, event := range events {
+			if err := d.dispatch(ctx, event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// dispatch delivers one event and records the outcome. Only failing to
+// record it is returned; a failed delivery is retried later.
+func (d *Dispatcher) dispatch(ctx context.Context, event db.OutboxEvent) error {
+	err := d.deliver(ctx, event)
+	if err == nil {
+		return d.store.MarkOutboxEventDispatched(ctx, event.ID)
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	attempt := event.Attempts + 1
+	if attempt >= maxAttempts || errors.Is(err, errUndecodable) {
+		log.Printf("giving up on outbox event %d (%s) after %d attempts: %v", event.ID, event.EventType, attempt, err)
+		return d.store.FailOutboxEvent(ctx, db.FailOutboxEventParams{
+			LastError: err.Error(),
+			ID:        event.ID,
+		})
+	}
+
+	return d.store.RetryOutboxEvent(ctx, db.RetryOutboxEventParams{
+		LastError:     err.Error(),
+		NextAttemptAt: d.now().UTC().Add(backoff(attempt)),
+		ID:            event.ID,
+	})
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, event db.OutboxEvent) error {
+	data, err := db.DecodeDomainEvent(event)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUndecodable, err)
+	}
+
+	delivery := Event{
+		ID:         event.ID,
+		OccurredAt: event.CreatedAt,
+		Attempt:    event.Attempts + 1,
+		ActorID:    event.ActorID,
+		Data:       data,
+	}
+
+	// Every handler runs on every attempt, including those that succeeded
+	// the last time, which is why they must be idempotent.
+	for _, handler := range d.handlers[data.EventType()] {
+		if err := handler(ctx, delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// backoff is how long to wait before the next attempt after the given number
+// of failed ones: it doubles from a second up to an hour.
+func backoff(attempts int32) time.Duration {
+	delay := minBackoff
+	for i := int32(1); i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
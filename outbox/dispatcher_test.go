@@ -0,0 +1,220 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newTestDispatcher(store db.Store, now time.Time) *Dispatcher {
+	dispatcher := NewDispatcher(store, time.Second)
+	dispatcher.now = func() time.Time { return now }
+	return dispatcher
+}
+
+func outboxEvent(t *testing.T, id int64, attempts int32, event db.DomainEvent) db.OutboxEvent {
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	return db.OutboxEvent{
+		ID:            id,
+		AggregateType: event.AggregateType(),
+		AggregateID:   event.AggregateID(),
+		EventType:     string(event.EventType()),
+		Payload:       payload,
+		Attempts:      attempts,
+		CreatedAt:     time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+// expectClaims has the store hand out batches in turn, then nothing.
+func expectClaims(store *mockdb.MockStore, now time.Time, batches ...[]db.OutboxEvent) {
+	arg := db.ClaimOutboxEventsParams{
+		LeaseUntil: now.Add(leaseDuration),
+		Now:        now,
+		Limit:      batchSize,
+	}
+
+	calls := make([]any, 0, len(batches)+1)
+	for _, batch := range append(batches, []db.OutboxEvent{}) {
+		calls = append(calls, store.EXPECT().
+			ClaimOutboxEvents(gomock.Any(), gomock.Eq(arg)).
+			Times(1).
+			Return(batch, nil))
+	}
+	gomock.InOrder(calls...)
+}
+
+func TestDispatchPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	assigned := db.TaskAssigned{TaskID: 4, UserID: 9}
+	joined := db.MemberJoinedTeam{TeamID: 2, UserID: 9}
+
+	actorID := int32(3)
+	assignedEvent := outboxEvent(t, 10, 0, assigned)
+	assignedEvent.ActorID = &actorID
+
+	store := mockdb.NewMockStore(ctrl)
+	// Claimed events are delivered in ID order whatever order they come in.
+	expectClaims(store, now,
+		[]db.OutboxEvent{outboxEvent(t, 11, 0, joined), assignedEvent},
+		[]db.OutboxEvent{outboxEvent(t, 12, 0, db.TaskUnassigned{TaskID: 4, UserID: 9})},
+	)
+	gomock.InOrder(
+		store.EXPECT().MarkOutboxEventDispatched(gomock.Any(), gomock.Eq(int64(10))).Times(1).Return(nil),
+		store.EXPECT().MarkOutboxEventDispatched(gomock.Any(), gomock.Eq(int64(11))).Times(1).Return(nil),
+		store.EXPECT().MarkOutboxEventDispatched(gomock.Any(), gomock.Eq(int64(12))).Times(1).Return(nil),
+	)
+
+	var delivered []Event
+	record := func(_ context.Context, event Event) error {
+		delivered = append(delivered, event)
+		return nil
+	}
+
+	dispatcher := newTestDispatcher(store, now)
+	dispatcher.Subscribe(db.EventTaskAssigned, record)
+	dispatcher.Subscribe(db.EventMemberJoinedTeam, record)
+
+	require.NoError(t, dispatcher.DispatchPending(t.Context()))
+
+	// Events nobody subscribed to are marked as dispatched all the same.
+	require.Len(t, delivered, 2)
+	require.Equal(t, int64(10), delivered[0].ID)
+	require.Equal(t, int32(1), delivered[0].Attempt)
+	require.Equal(t, time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC), delivered[0].OccurredAt)
+	require.Equal(t, &actorID, delivered[0].ActorID)
+	require.Equal(t, assigned, delivered[0].Data)
+	require.Nil(t, delivered[1].ActorID)
+	require.Equal(t, joined, delivered[1].Data)
+}
+
+func TestDispatchPendingHandlerError(t *testing.T) {
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	event := db.TaskCreated{Task: db.Task{ID: 4, Title: "Write report"}}
+	errHandler := errors.New("handler failed")
+
+	testCases := []struct {
+		name       string
+		attempts   int32
+		buildStubs func(store *mockdb.MockStore)
+	}{
+		{
+			name:     "Retry",
+			attempts: 0,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RetryOutboxEvent(gomock.Any(), gomock.Eq(db.RetryOutboxEventParams{
+						LastError:     errHandler.Error(),
+						NextAttemptAt: now.Add(time.Second),
+						ID:            1,
+					})).
+					Times(1).
+					Return(nil)
+				store.EXPECT().FailOutboxEvent(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:     "Retry With Backoff",
+			attempts: 3,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RetryOutboxEvent(gomock.Any(), gomock.Eq(db.RetryOutboxEventParams{
+						LastError:     errHandler.Error(),
+						NextAttemptAt: now.Add(8 * time.Second),
+						ID:            1,
+					})).
+					Times(1).
+					Return(nil)
+				store.EXPECT().FailOutboxEvent(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:     "Give Up",
+			attempts: maxAttempts - 1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().RetryOutboxEvent(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					FailOutboxEvent(gomock.Any(), gomock.Eq(db.FailOutboxEventParams{
+						LastError: errHandler.Error(),
+						ID:        1,
+					})).
+					Times(1).
+					Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			expectClaims(store, now, []db.OutboxEvent{outboxEvent(t, 1, tt.attempts, event)})
+			store.EXPECT().MarkOutboxEventDispatched(gomock.Any(), gomock.Any()).Times(0)
+			tt.buildStubs(store)
+
+			dispatcher := newTestDispatcher(store, now)
+			dispatcher.Subscribe(db.EventTaskCreated, func(_ context.Context, delivered Event) error {
+				require.Equal(t, tt.attempts+1, delivered.Attempt)
+				return errHandler
+			})
+
+			require.NoError(t, dispatcher.DispatchPending(t.Context()))
+		})
+	}
+}
+
+func TestDispatchPendingUndecodableEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	event := db.OutboxEvent{ID: 1, AggregateType: db.AggregateTask, AggregateID: 4, EventType: "task.archived", Payload: json.RawMessage(`{}`)}
+
+	store := mockdb.NewMockStore(ctrl)
+	expectClaims(store, now, []db.OutboxEvent{event})
+	store.EXPECT().RetryOutboxEvent(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().
+		FailOutboxEvent(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.FailOutboxEventParams) error {
+			require.Equal(t, int64(1), arg.ID)
+			require.Contains(t, arg.LastError, "task.archived")
+			return nil
+		})
+
+	require.NoError(t, newTestDispatcher(store, now).DispatchPending(t.Context()))
+}
+
+func TestDispatchPendingStoreError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	errStore := errors.New("connection lost")
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ClaimOutboxEvents(gomock.Any(), gomock.Any()).Times(1).Return(nil, errStore)
+
+	err := newTestDispatcher(store, time.Now()).DispatchPending(t.Context())
+	require.ErrorIs(t, err, errStore)
+}
+
+func TestBackoff(t *testing.T) {
+	require.Equal(t, time.Second, backoff(1))
+	require.Equal(t, 2*time.Second, backoff(2))
+	require.Equal(t, 1024*time.Second, backoff(11))
+	require.Equal(t, time.Hour, backoff(13))
+	require.Equal(t, time.Hour, backoff(100))
+}
@@ -0,0 +1,152 @@
+package recurrence
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+)
+
+const (
+	defaultInterval = time.Minute
+	batchSize       = 100
+)
+
+// Generator materializes the tasks of recurring task templates. An
+// occurrence gets its task when its due date arrives, or earlier, as soon as
+// every earlier occurrence of the template is done.
+type Generator struct {
+	store    db.Store
+	interval time.Duration
+	now      func() time.Time
+}
+
+// NewGenerator returns a generator that looks for due occurrences every
+// interval, or every minute when interval is not positive.
+func NewGenerator(store db.Store, interval time.Duration) *Generator {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	return &Generator{
+		store:    store,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// Run materializes due occurrences until ctx is done.
+func (g *Generator) Run(ctx context.Context) {
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := g.GenerateDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("unable to generate recurring tasks: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GenerateDue materializes every occurrence whose due date has arrived.
+func (g *Generator) GenerateDue(ctx context.Context) error {
+	for {
+		templates, err := g.store.ListDueTaskTemplates(ctx, db.ListDueTaskTemplatesParams{
+			Now:   g.now().UTC(),
+			Limit: batchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		// A template that cannot be materialized is logged and left for the
+		// next run so it does not hold up the others.
+		materialized := 0
+		for _, template := range templates {
+			_, err := g.Materialize(ctx, template)
+			switch {
+			case err == nil:
+				materialized++
+			case errors.Is(err, db.ErrOccurrenceExists):
+			case ctx.Err() != nil:
+				return ctx.Err()
+			default:
+				log.Printf("unable to materialize task template %d: %v", template.ID, err)
+			}
+		}
+
+		if len(templates) < batchSize || materialized == 0 {
+			return nil
+		}
+	}
+}
+
+// TaskTransitioned materializes the next occurrence of the task's template
+// early once none of the template's tasks is open any more, which is usually
+// right after the latest one was done. It can be called after any
+// transition; tasks without a template are ignored.
+func (g *Generator) TaskTransitioned(ctx context.Context, task db.Task) error {
+	if task.TemplateID == nil {
+		return nil
+	}
+
+	open, err := g.store.CountOpenTemplateTasks(ctx, task.TemplateID)
+	if err != nil || open > 0 {
+		return err
+	}
+
+	template, err := g.store.GetTaskTemplate(ctx, *task.TemplateID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if template.NextDueAt == nil {
+		return nil
+	}
+
+	_, err = g.Materialize(ctx, template)
+	if errors.Is(err, db.ErrOccurrenceExists) {
+		return nil
+	}
+	return err
+}
+
+// Materialize creates the task for the template's next occurrence. When
+// occurrences were missed, for instance while nothing was running, the
+// template skips ahead to the first one still in the future, so it catches
+// up with one task instead of a backlog.
+func (g *Generator) Materialize(ctx context.Context, template db.TaskTemplate) (db.TaskOccurrenceResult, error) {
+	if template.NextDueAt == nil {
+		return db.TaskOccurrenceResult{}, db.ErrOccurrenceExists
+	}
+
+	schedule, err := NewSchedule(template.Rrule, template.StartsAt, template.Timezone)
+	if err != nil {
+		return db.TaskOccurrenceResult{}, err
+	}
+
+	after := *template.NextDueAt
+	if now := g.now(); now.After(after) {
+		after = now
+	}
+
+	var nextDueAt *time.Time
+	if next, ok := schedule.Next(after); ok {
+		nextDueAt = &next
+	}
+
+	return g.store.MaterializeTaskOccurrence(ctx, db.MaterializeTaskOccurrenceParams{
+		Template:  template,
+		NextDueAt: nextDueAt,
+	})
+}
@@ -0,0 +1,187 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newTestGenerator(store db.Store, now time.Time) *Generator {
+	generator := NewGenerator(store, time.Minute)
+	generator.now = func() time.Time { return now }
+	return generator
+}
+
+func dailyTemplate(nextDueAt time.Time) db.TaskTemplate {
+	return db.TaskTemplate{
+		ID:        7,
+		Title:     "Stand-up notes",
+		Rrule:     "FREQ=DAILY",
+		Timezone:  "America/New_York",
+		StartsAt:  time.Date(2025, 3, 1, 14, 0, 0, 0, time.UTC),
+		NextDueAt: &nextDueAt,
+	}
+}
+
+func TestMaterialize(t *testing.T) {
+	// 09:00 in New York is 14:00 UTC before DST starts on March 9th and
+	// 13:00 UTC after.
+	due := time.Date(2025, 3, 8, 14, 0, 0, 0, time.UTC)
+	next := time.Date(2025, 3, 9, 13, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		now      time.Time
+		expected time.Time
+	}{
+		{name: "Early", now: due.Add(-12 * time.Hour), expected: next},
+		{name: "Due", now: due, expected: next},
+		{name: "Missed Occurrences", now: due.AddDate(0, 0, 3), expected: time.Date(2025, 3, 12, 13, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			template := dailyTemplate(due)
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				MaterializeTaskOccurrence(gomock.Any(), gomock.Eq(db.MaterializeTaskOccurrenceParams{
+					Template:  template,
+					NextDueAt: &tt.expected,
+				})).
+				Times(1).
+				Return(db.TaskOccurrenceResult{}, nil)
+
+			_, err := newTestGenerator(store, tt.now).Materialize(t.Context(), template)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestMaterializeLastOccurrence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	due := time.Date(2025, 3, 2, 14, 0, 0, 0, time.UTC)
+	template := dailyTemplate(due)
+	template.Rrule = "FREQ=DAILY;COUNT=2"
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		MaterializeTaskOccurrence(gomock.Any(), gomock.Eq(db.MaterializeTaskOccurrenceParams{Template: template})).
+		Times(1).
+		Return(db.TaskOccurrenceResult{}, nil)
+
+	_, err := newTestGenerator(store, due).Materialize(t.Context(), template)
+	require.NoError(t, err)
+}
+
+func TestGenerateDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2025, 3, 8, 15, 0, 0, 0, time.UTC)
+	first := dailyTemplate(time.Date(2025, 3, 8, 14, 0, 0, 0, time.UTC))
+	second := dailyTemplate(time.Date(2025, 3, 8, 14, 0, 0, 0, time.UTC))
+	second.ID = 8
+	broken := dailyTemplate(time.Date(2025, 3, 8, 14, 0, 0, 0, time.UTC))
+	broken.ID = 9
+	broken.Timezone = "Nowhere/Special"
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListDueTaskTemplates(gomock.Any(), gomock.Eq(db.ListDueTaskTemplatesParams{Now: now, Limit: batchSize})).
+		Times(1).
+		Return([]db.TaskTemplate{first, broken, second}, nil)
+	store.EXPECT().
+		MaterializeTaskOccurrence(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.TaskOccurrenceResult{}, nil)
+	store.EXPECT().
+		MaterializeTaskOccurrence(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.TaskOccurrenceResult{}, db.ErrOccurrenceExists)
+
+	err := newTestGenerator(store, now).GenerateDue(t.Context())
+	require.NoError(t, err)
+}
+
+func TestTaskTransitioned(t *testing.T) {
+	templateID := int32(7)
+	due := time.Date(2025, 3, 10, 13, 0, 0, 0, time.UTC)
+	now := due.AddDate(0, 0, -1)
+
+	testCases := []struct {
+		name       string
+		task       db.Task
+		buildStubs func(store *mockdb.MockStore)
+	}{
+		{
+			name: "No Template",
+			task: db.Task{ID: 1},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CountOpenTemplateTasks(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "Occurrence Still Open",
+			task: db.Task{ID: 1, TemplateID: &templateID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CountOpenTemplateTasks(gomock.Any(), gomock.Eq(&templateID)).Times(1).Return(int64(1), nil)
+				store.EXPECT().GetTaskTemplate(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "All Done",
+			task: db.Task{ID: 1, TemplateID: &templateID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CountOpenTemplateTasks(gomock.Any(), gomock.Eq(&templateID)).Times(1).Return(int64(0), nil)
+				store.EXPECT().GetTaskTemplate(gomock.Any(), gomock.Eq(templateID)).Times(1).Return(dailyTemplate(due), nil)
+				store.EXPECT().
+					MaterializeTaskOccurrence(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TaskOccurrenceResult{}, db.ErrOccurrenceExists)
+			},
+		},
+		{
+			name: "Series Ended",
+			task: db.Task{ID: 1, TemplateID: &templateID},
+			buildStubs: func(store *mockdb.MockStore) {
+				template := dailyTemplate(due)
+				template.NextDueAt = nil
+
+				store.EXPECT().CountOpenTemplateTasks(gomock.Any(), gomock.Eq(&templateID)).Times(1).Return(int64(0), nil)
+				store.EXPECT().GetTaskTemplate(gomock.Any(), gomock.Eq(templateID)).Times(1).Return(template, nil)
+				store.EXPECT().MaterializeTaskOccurrence(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "Template Deleted",
+			task: db.Task{ID: 1, TemplateID: &templateID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CountOpenTemplateTasks(gomock.Any(), gomock.Eq(&templateID)).Times(1).Return(int64(0), nil)
+				store.EXPECT().GetTaskTemplate(gomock.Any(), gomock.Eq(templateID)).Times(1).Return(db.TaskTemplate{}, db.ErrRecordNotFound)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tt.buildStubs(store)
+
+			err := newTestGenerator(store, now).TaskTransitioned(t.Context(), tt.task)
+			require.NoError(t, err)
+		})
+	}
+}
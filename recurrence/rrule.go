@@ -0,0 +1,246 @@
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+// maxInterval keeps a rule from skipping so far ahead that it never comes
+// round again in practice.
+const maxInterval = 1000
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// WeekdayNum is one BYDAY entry. N picks the Nth such weekday of the month,
+// counting from the end when negative; 0 means every one of them.
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+// Rule is the subset of an RFC 5545 RRULE that tasks can repeat on:
+// FREQ=DAILY, WEEKLY or MONTHLY with INTERVAL, BYDAY, BYMONTHDAY, COUNT and
+// UNTIL. Weeks start on Monday.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	Count      int
+	Until      *time.Time
+}
+
+// ParseRule reads an RRULE value such as "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10".
+// A leading "RRULE:" is accepted.
+func ParseRule(value string) (Rule, error) {
+	rule := Rule{Interval: 1}
+
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return Rule{}, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+
+	seen := map[string]bool{}
+
+	for _, part := range strings.Split(value, ";") {
+		name, val, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(name)
+		if !ok || val == "" {
+			return Rule{}, fmt.Errorf("%w: %q is not NAME=VALUE", ErrInvalidRule, part)
+		}
+		if seen[name] {
+			return Rule{}, fmt.Errorf("%w: %s is given twice", ErrInvalidRule, name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			rule.Freq = Frequency(strings.ToUpper(val))
+			if rule.Freq != Daily && rule.Freq != Weekly && rule.Freq != Monthly {
+				err = errors.New("FREQ must be DAILY, WEEKLY or MONTHLY")
+			}
+		case "INTERVAL":
+			rule.Interval, err = parseBounded(val, 1, maxInterval)
+		case "COUNT":
+			rule.Count, err = parseBounded(val, 1, 10_000)
+		case "UNTIL":
+			var until time.Time
+			until, err = parseUntil(val)
+			rule.Until = &until
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(val)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseByMonthDay(val)
+		case "WKST":
+			if strings.ToUpper(val) != "MO" {
+				err = errors.New("only WKST=MO is supported")
+			}
+		default:
+			err = fmt.Errorf("%s is not supported", name)
+		}
+		if err != nil {
+			return Rule{}, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+		}
+	}
+
+	if rule.Freq == "" {
+		return Rule{}, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+
+	if rule.Count > 0 && rule.Until != nil {
+		return Rule{}, fmt.Errorf("%w: COUNT and UNTIL cannot be used together", ErrInvalidRule)
+	}
+
+	if rule.Freq != Monthly {
+		for _, day := range rule.ByDay {
+			if day.N != 0 {
+				return Rule{}, fmt.Errorf("%w: numbered BYDAY values need FREQ=MONTHLY", ErrInvalidRule)
+			}
+		}
+	}
+
+	if rule.Freq == Weekly && len(rule.ByMonthDay) > 0 {
+		return Rule{}, fmt.Errorf("%w: BYMONTHDAY cannot be used with FREQ=WEEKLY", ErrInvalidRule)
+	}
+
+	return rule, nil
+}
+
+// String formats the rule in canonical RRULE form.
+func (rule Rule) String() string {
+	parts := []string{"FREQ=" + string(rule.Freq)}
+
+	if rule.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(rule.Interval))
+	}
+
+	if len(rule.ByDay) > 0 {
+		days := make([]string, len(rule.ByDay))
+		for i, day := range rule.ByDay {
+			days[i] = weekdayCode(day.Weekday)
+			if day.N != 0 {
+				days[i] = strconv.Itoa(day.N) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if len(rule.ByMonthDay) > 0 {
+		days := make([]string, len(rule.ByMonthDay))
+		for i, day := range rule.ByMonthDay {
+			days[i] = strconv.Itoa(day)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+
+	if rule.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(rule.Count))
+	}
+
+	if rule.Until != nil {
+		parts = append(parts, "UNTIL="+rule.Until.UTC().Format("20060102T150405Z"))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+func parseBounded(value string, min, max int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("%q must be a number from %d to %d", value, min, max)
+	}
+	return n, nil
+}
+
+// parseUntil accepts the UTC date-time and date forms of UNTIL. A plain date
+// includes the whole day.
+func parseUntil(value string) (time.Time, error) {
+	if until, err := time.Parse("20060102T150405Z", value); err == nil {
+		return until, nil
+	}
+
+	if until, err := time.Parse("20060102", value); err == nil {
+		return until.Add(24*time.Hour - time.Second), nil
+	}
+
+	return time.Time{}, fmt.Errorf("UNTIL %q must look like 20060102T150405Z or 20060102", value)
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.ToUpper(item)
+		if len(item) < 2 {
+			return nil, fmt.Errorf("BYDAY value %q is not a weekday", item)
+		}
+
+		weekday, ok := weekdayCodes[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("BYDAY value %q is not a weekday", item)
+		}
+
+		day := WeekdayNum{Weekday: weekday}
+		if prefix := item[:len(item)-2]; prefix != "" {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, fmt.Errorf("BYDAY value %q must be numbered from 1 to 5 or -1 to -5", item)
+			}
+			day.N = n
+		}
+
+		if !slices.Contains(days, day) {
+			days = append(days, day)
+		}
+	}
+
+	return days, nil
+}
+
+func parseByMonthDay(value string) ([]int, error) {
+	var days []int
+
+	for _, item := range strings.Split(value, ",") {
+		day, err := strconv.Atoi(item)
+		if err != nil || day == 0 || day < -31 || day > 31 {
+			return nil, fmt.Errorf("BYMONTHDAY value %q must be from 1 to 31 or -1 to -31", item)
+		}
+		if !slices.Contains(days, day) {
+			days = append(days, day)
+		}
+	}
+
+	return days, nil
+}
+
+func weekdayCode(weekday time.Weekday) string {
+	for code, day := range weekdayCodes {
+		if day == weekday {
+			return code
+		}
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRule(t *testing.T) {
+	until := time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC)
+
+	testCases := []struct {
+		value     string
+		rule      Rule
+		canonical string
+	}{
+		{
+			value:     "FREQ=DAILY",
+			rule:      Rule{Freq: Daily, Interval: 1},
+			canonical: "FREQ=DAILY",
+		},
+		{
+			value: "RRULE:freq=weekly;interval=2;byday=MO,we,MO;COUNT=10",
+			rule: Rule{
+				Freq:     Weekly,
+				Interval: 2,
+				ByDay:    []WeekdayNum{{Weekday: time.Monday}, {Weekday: time.Wednesday}},
+				Count:    10,
+			},
+			canonical: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10",
+		},
+		{
+			value: "FREQ=MONTHLY;BYDAY=-1FR,2TU;UNTIL=20251231",
+			rule: Rule{
+				Freq:     Monthly,
+				Interval: 1,
+				ByDay:    []WeekdayNum{{Weekday: time.Friday, N: -1}, {Weekday: time.Tuesday, N: 2}},
+				Until:    &until,
+			},
+			canonical: "FREQ=MONTHLY;BYDAY=-1FR,2TU;UNTIL=20251231T235959Z",
+		},
+		{
+			value:     "FREQ=MONTHLY;BYMONTHDAY=1,-1;WKST=MO",
+			rule:      Rule{Freq: Monthly, Interval: 1, ByMonthDay: []int{1, -1}},
+			canonical: "FREQ=MONTHLY;BYMONTHDAY=1,-1",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.value, func(t *testing.T) {
+			rule, err := ParseRule(tt.value)
+			require.NoError(t, err)
+			require.Equal(t, tt.rule, rule)
+			require.Equal(t, tt.canonical, rule.String())
+
+			reparsed, err := ParseRule(rule.String())
+			require.NoError(t, err)
+			require.Equal(t, rule, reparsed)
+		})
+	}
+}
+
+func TestParseRuleInvalid(t *testing.T) {
+	testCases := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=-1",
+		"FREQ=DAILY;COUNT=3;UNTIL=20250101",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=DAILY;BYHOUR=9",
+		"FREQ=DAILY;WKST=SU",
+		"FREQ",
+	}
+
+	for _, value := range testCases {
+		_, err := ParseRule(value)
+		require.ErrorIs(t, err, ErrInvalidRule, value)
+	}
+}
@@ -0,0 +1,195 @@
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"time"
+
+	"github.com/bolusarz/task-manager/util"
+)
+
+// maxPeriods bounds how many days, weeks or months a schedule is expanded
+// while looking for an occurrence, so a rule that can never match again
+// does not loop forever.
+const maxPeriods = 50_000
+
+var ErrInvalidTimezone = errors.New("invalid timezone")
+
+// Schedule is a rule anchored at its first occurrence. The rule is expanded
+// on the wall clock of Location, so a task due at 09:00 stays at 09:00 local
+// time when daylight saving time starts or ends.
+type Schedule struct {
+	Rule     Rule
+	Start    time.Time
+	Location *time.Location
+}
+
+// NewSchedule parses rrule and timezone, an IANA name such as
+// "Europe/London", into a schedule that starts at start.
+func NewSchedule(rrule string, start time.Time, timezone string) (Schedule, error) {
+	rule, err := ParseRule(rrule)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	location, err := util.LoadTimezone(timezone)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("%w: %q", ErrInvalidTimezone, timezone)
+	}
+
+	return Schedule{Rule: rule, Start: start, Location: location}, nil
+}
+
+// First returns the first occurrence, which is Start itself when it matches
+// the rule.
+func (s Schedule) First() (time.Time, bool) {
+	return s.Next(s.Start.Add(-time.Nanosecond))
+}
+
+// Upcoming returns the first occurrence at or after now. It is where a new
+// template starts when its DTSTART lies in the past.
+func (s Schedule) Upcoming(now time.Time) (time.Time, bool) {
+	if now.Before(s.Start) {
+		return s.First()
+	}
+	return s.Next(now.Add(-time.Nanosecond))
+}
+
+// Next returns the first occurrence after t, in UTC. It reports false once
+// COUNT or UNTIL has been reached.
+func (s Schedule) Next(t time.Time) (time.Time, bool) {
+	for occurrence := range s.Occurrences() {
+		if occurrence.After(t) {
+			return occurrence, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Occurrences yields every occurrence in order, in UTC.
+func (s Schedule) Occurrences() iter.Seq[time.Time] {
+	return func(yield func(time.Time) bool) {
+		start := s.Start.In(s.Location)
+		hour, minute, second := start.Clock()
+		first := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+
+		count := 0
+		for period := range maxPeriods {
+			for _, day := range s.periodDays(first, period) {
+				occurrence := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, 0, s.Location)
+				if occurrence.Before(s.Start) {
+					continue
+				}
+				if s.Rule.Until != nil && occurrence.After(*s.Rule.Until) {
+					return
+				}
+
+				if !yield(occurrence.UTC()) {
+					return
+				}
+
+				count++
+				if s.Rule.Count > 0 && count >= s.Rule.Count {
+					return
+				}
+			}
+		}
+	}
+}
+
+// periodDays returns, in order, the days of the given period that match the
+// rule. Days are midnight UTC and only carry the calendar date.
+func (s Schedule) periodDays(first time.Time, period int) []time.Time {
+	step := period * s.Rule.Interval
+
+	switch s.Rule.Freq {
+	case Daily:
+		day := first.AddDate(0, 0, step)
+		if s.matchesWeekday(day) && s.matchesMonthDay(day) {
+			return []time.Time{day}
+		}
+		return nil
+
+	case Weekly:
+		monday := first.AddDate(0, 0, -int((first.Weekday()+6)%7)+7*step)
+		if len(s.Rule.ByDay) == 0 {
+			return []time.Time{monday.AddDate(0, 0, int((first.Weekday()+6)%7))}
+		}
+
+		var days []time.Time
+		for offset := range 7 {
+			day := monday.AddDate(0, 0, offset)
+			if s.matchesWeekday(day) {
+				days = append(days, day)
+			}
+		}
+		return days
+
+	default:
+		month := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, step, 0)
+		return s.monthDays(month, first.Day())
+	}
+}
+
+// monthDays returns the matching days of the month starting at month. Without
+// BYDAY or BYMONTHDAY that is the start's day of the month, and months too
+// short for it are skipped.
+func (s Schedule) monthDays(month time.Time, startDay int) []time.Time {
+	length := month.AddDate(0, 1, -1).Day()
+
+	var days []time.Time
+	for n := range length {
+		day := month.AddDate(0, 0, n)
+
+		switch {
+		case len(s.Rule.ByDay) == 0 && len(s.Rule.ByMonthDay) == 0:
+			if day.Day() != startDay {
+				continue
+			}
+		case len(s.Rule.ByDay) == 0:
+			if !s.matchesMonthDay(day) {
+				continue
+			}
+		default:
+			if !s.matchesMonthWeekday(day, length) || !s.matchesMonthDay(day) {
+				continue
+			}
+		}
+
+		days = append(days, day)
+	}
+
+	return days
+}
+
+func (s Schedule) matchesWeekday(day time.Time) bool {
+	if len(s.Rule.ByDay) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(s.Rule.ByDay, func(w WeekdayNum) bool {
+		return w.Weekday == day.Weekday()
+	})
+}
+
+// matchesMonthWeekday applies numbered BYDAY values such as 2TU or -1FR.
+func (s Schedule) matchesMonthWeekday(day time.Time, length int) bool {
+	fromStart := (day.Day()-1)/7 + 1
+	fromEnd := -((length-day.Day())/7 + 1)
+
+	return slices.ContainsFunc(s.Rule.ByDay, func(w WeekdayNum) bool {
+		return w.Weekday == day.Weekday() && (w.N == 0 || w.N == fromStart || w.N == fromEnd)
+	})
+}
+
+func (s Schedule) matchesMonthDay(day time.Time) bool {
+	if len(s.Rule.ByMonthDay) == 0 {
+		return true
+	}
+
+	length := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	return slices.ContainsFunc(s.Rule.ByMonthDay, func(n int) bool {
+		return n == day.Day() || n == day.Day()-length-1
+	})
+}
@@ -0,0 +1,204 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// occurrences returns the first n occurrences of the schedule formatted in
+// its own timezone.
+func occurrences(t *testing.T, schedule Schedule, n int) []string {
+	var result []string
+	for occurrence := range schedule.Occurrences() {
+		require.Equal(t, time.UTC, occurrence.Location())
+		result = append(result, occurrence.In(schedule.Location).Format("Mon 2006-01-02 15:04 MST"))
+		if len(result) == n {
+			break
+		}
+	}
+	return result
+}
+
+func TestScheduleOccurrences(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rrule    string
+		start    string
+		timezone string
+		expected []string
+	}{
+		{
+			name:     "Daily Across DST Start",
+			rrule:    "FREQ=DAILY",
+			start:    "2025-03-07T09:00:00-05:00",
+			timezone: "America/New_York",
+			expected: []string{
+				"Fri 2025-03-07 09:00 EST",
+				"Sat 2025-03-08 09:00 EST",
+				"Sun 2025-03-09 09:00 EDT",
+				"Mon 2025-03-10 09:00 EDT",
+			},
+		},
+		{
+			name:     "Weekly Across DST End",
+			rrule:    "FREQ=WEEKLY;BYDAY=MO,TH",
+			start:    "2025-10-20T08:30:00+01:00",
+			timezone: "Europe/London",
+			expected: []string{
+				"Mon 2025-10-20 08:30 BST",
+				"Thu 2025-10-23 08:30 BST",
+				"Mon 2025-10-27 08:30 GMT",
+				"Thu 2025-10-30 08:30 GMT",
+			},
+		},
+		{
+			name:     "Weekly Starting Mid Week",
+			rrule:    "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR",
+			start:    "2025-09-03T10:00:00Z",
+			timezone: "UTC",
+			expected: []string{
+				"Fri 2025-09-05 10:00 UTC",
+				"Mon 2025-09-15 10:00 UTC",
+				"Fri 2025-09-19 10:00 UTC",
+				"Mon 2025-09-29 10:00 UTC",
+			},
+		},
+		{
+			name:     "Weekly Without BYDAY",
+			rrule:    "FREQ=WEEKLY",
+			start:    "2025-09-03T10:00:00Z",
+			timezone: "UTC",
+			expected: []string{
+				"Wed 2025-09-03 10:00 UTC",
+				"Wed 2025-09-10 10:00 UTC",
+			},
+		},
+		{
+			name:     "Monthly Skips Short Months",
+			rrule:    "FREQ=MONTHLY",
+			start:    "2025-01-31T12:00:00Z",
+			timezone: "UTC",
+			expected: []string{
+				"Fri 2025-01-31 12:00 UTC",
+				"Mon 2025-03-31 12:00 UTC",
+				"Sat 2025-05-31 12:00 UTC",
+			},
+		},
+		{
+			name:     "Monthly Last Friday",
+			rrule:    "FREQ=MONTHLY;BYDAY=-1FR",
+			start:    "2025-01-01T17:00:00+01:00",
+			timezone: "Europe/Berlin",
+			expected: []string{
+				"Fri 2025-01-31 17:00 CET",
+				"Fri 2025-02-28 17:00 CET",
+				"Fri 2025-03-28 17:00 CET",
+				"Fri 2025-04-25 17:00 CEST",
+			},
+		},
+		{
+			name:     "Monthly Last Day",
+			rrule:    "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start:    "2024-01-15T09:00:00Z",
+			timezone: "UTC",
+			expected: []string{
+				"Wed 2024-01-31 09:00 UTC",
+				"Thu 2024-02-29 09:00 UTC",
+				"Sun 2024-03-31 09:00 UTC",
+			},
+		},
+		{
+			name:     "Monthly Friday The 13th",
+			rrule:    "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13",
+			start:    "2025-01-01T00:00:00Z",
+			timezone: "UTC",
+			expected: []string{
+				"Fri 2025-06-13 00:00 UTC",
+				"Fri 2026-02-13 00:00 UTC",
+			},
+		},
+		{
+			name:     "Count",
+			rrule:    "FREQ=DAILY;INTERVAL=3;COUNT=2",
+			start:    "2025-09-01T09:00:00Z",
+			timezone: "UTC",
+			expected: []string{
+				"Mon 2025-09-01 09:00 UTC",
+				"Thu 2025-09-04 09:00 UTC",
+			},
+		},
+		{
+			name:     "Until",
+			rrule:    "FREQ=DAILY;BYDAY=SA,SU;UNTIL=20250914T090000Z",
+			start:    "2025-09-01T09:00:00Z",
+			timezone: "UTC",
+			expected: []string{
+				"Sat 2025-09-06 09:00 UTC",
+				"Sun 2025-09-07 09:00 UTC",
+				"Sat 2025-09-13 09:00 UTC",
+				"Sun 2025-09-14 09:00 UTC",
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			start, err := time.Parse(time.RFC3339, tt.start)
+			require.NoError(t, err)
+
+			schedule, err := NewSchedule(tt.rrule, start, tt.timezone)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expected, occurrences(t, schedule, len(tt.expected)+1)[:len(tt.expected)])
+		})
+	}
+}
+
+func TestScheduleRunsOut(t *testing.T) {
+	start := time.Date(2025, 9, 1, 9, 0, 0, 0, time.UTC)
+
+	schedule, err := NewSchedule("FREQ=WEEKLY;COUNT=2", start, "UTC")
+	require.NoError(t, err)
+	require.Len(t, occurrences(t, schedule, 10), 2)
+
+	last := start.AddDate(0, 0, 7)
+	next, ok := schedule.Next(start)
+	require.True(t, ok)
+	require.Equal(t, last, next)
+
+	_, ok = schedule.Next(last)
+	require.False(t, ok)
+}
+
+func TestScheduleUpcoming(t *testing.T) {
+	start := time.Date(2025, 9, 1, 9, 0, 0, 0, time.UTC)
+
+	schedule, err := NewSchedule("FREQ=DAILY", start, "UTC")
+	require.NoError(t, err)
+
+	upcoming, ok := schedule.Upcoming(start.AddDate(0, 0, -3))
+	require.True(t, ok)
+	require.Equal(t, start, upcoming)
+
+	upcoming, ok = schedule.Upcoming(start.AddDate(0, 0, 5))
+	require.True(t, ok)
+	require.Equal(t, start.AddDate(0, 0, 5), upcoming)
+
+	upcoming, ok = schedule.Upcoming(start.AddDate(0, 0, 5).Add(time.Minute))
+	require.True(t, ok)
+	require.Equal(t, start.AddDate(0, 0, 6), upcoming)
+}
+
+func TestNewScheduleInvalidTimezone(t *testing.T) {
+	start := time.Date(2025, 9, 1, 9, 0, 0, 0, time.UTC)
+
+	for _, timezone := range []string{"", "Local", "Mars/Olympus_Mons"} {
+		_, err := NewSchedule("FREQ=DAILY", start, timezone)
+		require.ErrorIs(t, err, ErrInvalidTimezone, timezone)
+	}
+
+	_, err := NewSchedule("FREQ=HOURLY", start, "UTC")
+	require.ErrorIs(t, err, ErrInvalidRule)
+}
@@ -0,0 +1,181 @@
+package stream
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+)
+
+// subscriptionBuffer is how many events a subscription holds for a client
+// that is slow to take them before it is dropped.
+const subscriptionBuffer = 64
+
+var errNoTeam = errors.New("event has no team")
+
+// Event is a domain event as it is streamed to clients.
+type Event struct {
+	// ID is the outbox ID of the event, from which a client resumes a
+	// stream. Events are streamed in the order they were committed, which
+	// is not always the order of their IDs.
+	ID         int64          `json:"id"`
+	Type       db.EventType   `json:"type"`
+	OccurredAt time.Time      `json:"occurredAt"`
+	TeamID     int32          `json:"teamId"`
+	Data       db.DomainEvent `json:"data"`
+}
+
+// NewEvent turns an outbox row into the event streamed for it. Only events
+// that happened in a team are streamed, and those of a user, which only
+// reach the streams of that user.
+func NewEvent(row db.OutboxEvent) (Event, error) {
+	data, err := db.DecodeDomainEvent(row)
+	if err != nil {
+		return Event{}, err
+	}
+
+	event := Event{
+		ID:         row.ID,
+		Type:       data.EventType(),
+		OccurredAt: row.CreatedAt,
+		Data:       data,
+	}
+
+	switch {
+	case row.TeamID != nil:
+		event.TeamID = *row.TeamID
+	case data.AggregateType() != db.AggregateUser:
+		return Event{}, errNoTeam
+	}
+
+	return event, nil
+}
+
+// Hub fans events out to the subscriptions of the teams they happened in.
+// Publishing never blocks: a subscription that falls behind is closed, and
+// its client is expected to reconnect and resume from the last event it got.
+type Hub struct {
+	mu            sync.Mutex
+	subscriptions map[*Subscription]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subscriptions: make(map[*Subscription]struct{})}
+}
+
+// Subscription receives the events of the teams a user belongs to, and those
+// of the user. The teams follow the user's membership as it changes: the
+// user's joining a team is the first event they get of it, and leaving it or
+// its deletion the last.
+type Subscription struct {
+	hub    *Hub
+	userID int32
+	// teams and closed are guarded by hub.mu.
+	teams  map[int32]bool
+	closed bool
+	events chan Event
+}
+
+// Subscribe starts a subscription of userID to the events of teamIDs. It
+// must be closed once it is no longer read.
+func (h *Hub) Subscribe(userID int32, teamIDs []int32) *Subscription {
+	sub := &Subscription{
+		hub:    h,
+		userID: userID,
+		teams:  make(map[int32]bool, len(teamIDs)),
+		events: make(chan Event, subscriptionBuffer),
+	}
+	for _, teamID := range teamIDs {
+		sub.teams[teamID] = true
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.subscriptions[sub] = struct{}{}
+	return sub
+}
+
+// Publish hands event to every subscription of its team.
+func (h *Hub) Publish(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscriptions {
+		if !sub.follow(event) {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+			h.remove(sub)
+		}
+	}
+}
+
+// remove closes sub. h.mu must be held.
+func (h *Hub) remove(sub *Subscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	delete(h.subscriptions, sub)
+	close(sub.events)
+}
+
+// follow reports whether event is one for the subscription, and keeps its
+// teams in step with the memberships the event changes. hub.mu must be held.
+func (s *Subscription) follow(event Event) bool {
+	member := s.teams[event.TeamID]
+
+	switch e := event.Data.(type) {
+	case db.TeamCreated:
+		if e.Team.CreatedBy != nil && *e.Team.CreatedBy == s.userID {
+			s.teams[event.TeamID] = true
+			return true
+		}
+	case db.MemberJoinedTeam:
+		if e.UserID == s.userID {
+			s.teams[event.TeamID] = true
+			return true
+		}
+	case db.MemberLeftTeam:
+		if e.UserID == s.userID {
+			delete(s.teams, event.TeamID)
+		}
+	case db.TeamDeleted:
+		delete(s.teams, event.TeamID)
+	case db.SessionsRevoked:
+		return e.UserID == s.userID
+	}
+
+	return member
+}
+
+// Events delivers the events of the subscription. It is closed when the
+// subscription is, including when the hub drops it for falling behind.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Teams returns the teams the subscription currently receives the events of.
+func (s *Subscription) Teams() []int32 {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	teamIDs := make([]int32, 0, len(s.teams))
+	for teamID := range s.teams {
+		teamIDs = append(teamIDs, teamID)
+	}
+	return teamIDs
+}
+
+// Close ends the subscription. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	s.hub.remove(s)
+}
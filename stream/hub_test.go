@@ -0,0 +1,140 @@
+package stream
+
+import (
+	"encoding/json"
+	"testing"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func teamEvent(id int64, teamID int32, data db.DomainEvent) Event {
+	return Event{ID: id, Type: data.EventType(), TeamID: teamID, Data: data}
+}
+
+// received drains the events the subscription has been handed so far.
+func received(sub *Subscription) []int64 {
+	var ids []int64
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return ids
+			}
+			ids = append(ids, event.ID)
+		default:
+			return ids
+		}
+	}
+}
+
+func TestHubPublishesToTeams(t *testing.T) {
+	hub := NewHub()
+
+	alice := hub.Subscribe(1, []int32{10, 20})
+	defer alice.Close()
+	bob := hub.Subscribe(2, []int32{20})
+	defer bob.Close()
+
+	hub.Publish(teamEvent(1, 10, db.TaskDeleted{TaskID: 5}))
+	hub.Publish(teamEvent(2, 20, db.TaskDeleted{TaskID: 6}))
+	hub.Publish(teamEvent(3, 30, db.TaskDeleted{TaskID: 7}))
+
+	require.Equal(t, []int64{1, 2}, received(alice))
+	require.Equal(t, []int64{2}, received(bob))
+}
+
+func TestHubFollowsMembership(t *testing.T) {
+	hub := NewHub()
+	creatorID := int32(1)
+
+	sub := hub.Subscribe(creatorID, nil)
+	defer sub.Close()
+	other := hub.Subscribe(2, []int32{10})
+	defer other.Close()
+
+	hub.Publish(teamEvent(1, 10, db.MemberJoinedTeam{TeamID: 10, UserID: 1}))
+	hub.Publish(teamEvent(2, 10, db.TaskDeleted{TaskID: 5}))
+	hub.Publish(teamEvent(3, 10, db.MemberLeftTeam{TeamID: 10, UserID: 1}))
+	hub.Publish(teamEvent(4, 10, db.TaskDeleted{TaskID: 6}))
+	hub.Publish(teamEvent(5, 20, db.TeamCreated{Team: db.Team{ID: 20, CreatedBy: &creatorID}}))
+	hub.Publish(teamEvent(6, 20, db.TaskDeleted{TaskID: 7}))
+	hub.Publish(teamEvent(7, 10, db.MemberJoinedTeam{TeamID: 10, UserID: 3}))
+	hub.Publish(teamEvent(8, 20, db.TeamDeleted{TeamID: 20}))
+	hub.Publish(teamEvent(9, 20, db.TaskDeleted{TaskID: 7}))
+
+	require.Equal(t, []int64{1, 2, 3, 5, 6, 8}, received(sub))
+	require.Equal(t, []int64{1, 2, 3, 4, 7}, received(other))
+}
+
+func TestHubPublishesToUser(t *testing.T) {
+	hub := NewHub()
+
+	alice := hub.Subscribe(1, []int32{10})
+	defer alice.Close()
+	bob := hub.Subscribe(2, []int32{10})
+	defer bob.Close()
+
+	hub.Publish(Event{ID: 1, Type: db.EventSessionsRevoked, Data: db.SessionsRevoked{UserID: 1}})
+	hub.Publish(teamEvent(2, 10, db.MemberLeftTeam{TeamID: 10, UserID: 2}))
+
+	require.Equal(t, []int64{1, 2}, received(alice))
+	require.Equal(t, []int64{2}, received(bob))
+	require.Equal(t, []int32{10}, alice.Teams())
+	require.Empty(t, bob.Teams())
+}
+
+func TestHubDropsSlowSubscription(t *testing.T) {
+	hub := NewHub()
+
+	slow := hub.Subscribe(1, []int32{10})
+	defer slow.Close()
+
+	for i := range subscriptionBuffer + 1 {
+		hub.Publish(teamEvent(int64(i+1), 10, db.TaskDeleted{TaskID: 5}))
+	}
+
+	require.Len(t, received(slow), subscriptionBuffer)
+	_, ok := <-slow.Events()
+	require.False(t, ok)
+
+	// Closing it again after the hub did is harmless.
+	slow.Close()
+	require.Empty(t, hub.subscriptions)
+}
+
+func TestNewEvent(t *testing.T) {
+	teamID := int32(10)
+	payload, err := json.Marshal(db.TaskDeleted{TaskID: 5, TeamID: &teamID})
+	require.NoError(t, err)
+
+	row := db.OutboxEvent{
+		ID:        3,
+		EventType: string(db.EventTaskDeleted),
+		Payload:   payload,
+		TeamID:    &teamID,
+	}
+
+	event, err := NewEvent(row)
+	require.NoError(t, err)
+	require.Equal(t, teamEvent(3, teamID, db.TaskDeleted{TaskID: 5, TeamID: &teamID}), event)
+
+	row.TeamID = nil
+	_, err = NewEvent(row)
+	require.ErrorIs(t, err, errNoTeam)
+
+	// Events of a user belong to no team, but are streamed all the same.
+	payload, err = json.Marshal(db.SessionsRevoked{UserID: 7})
+	require.NoError(t, err)
+
+	row = db.OutboxEvent{
+		ID:            4,
+		AggregateType: db.AggregateUser,
+		EventType:     string(db.EventSessionsRevoked),
+		Payload:       payload,
+	}
+
+	event, err = NewEvent(row)
+	require.NoError(t, err)
+	require.Equal(t, Event{ID: 4, Type: db.EventSessionsRevoked, Data: db.SessionsRevoked{UserID: 7}}, event)
+}
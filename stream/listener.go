@@ -0,0 +1,144 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// Channel is the Postgres channel a trigger on the outbox announces every
+	// team and user event on.
+	Channel = "outbox_events"
+
+	reconnectDelay = 5 * time.Second
+	// pollInterval bounds how long an event waits to be published when it
+	// could not be read as its notification arrived, because a transaction
+	// older than its own was still running.
+	pollInterval = time.Second
+	batchSize    = 100
+)
+
+// Listener publishes the team and user events recorded by any server instance
+// to the hub of this one. Events are read in the order of the transactions
+// that recorded them, and only once no older transaction can still commit
+// one, so none is passed over; notifications only wake it up to read them.
+type Listener struct {
+	pool  *pgxpool.Pool
+	store db.Store
+	hub   *Hub
+	// txID and lastID are the position of the newest event published, from
+	// which the events recorded since are read.
+	txID   int64
+	lastID int64
+	// started reports whether the position was set, which the first
+	// connection does, since there is no client yet to have missed anything.
+	started bool
+}
+
+func NewListener(pool *pgxpool.Pool, store db.Store, hub *Hub) *Listener {
+	return &Listener{pool: pool, store: store, hub: hub}
+}
+
+// Run publishes events until ctx is done, reconnecting whenever the
+// connection it listens on is lost.
+func (l *Listener) Run(ctx context.Context) {
+	for {
+		err := l.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("stream listener lost its connection, reconnecting: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// listen holds a connection of its own for as long as it listens, since a
+// pooled one would go on receiving notifications after it was released.
+func (l *Listener) listen(ctx context.Context) error {
+	conn, err := pgx.ConnectConfig(ctx, l.pool.Config().ConnConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "listen "+Channel); err != nil {
+		return err
+	}
+
+	if !l.started {
+		if err := l.Start(ctx); err != nil {
+			return err
+		}
+	}
+
+	for {
+		if err := l.CatchUp(ctx); err != nil {
+			return err
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx, pollInterval)
+		_, err := conn.WaitForNotification(waitCtx)
+		cancel()
+		if err != nil && (ctx.Err() != nil || !errors.Is(err, context.DeadlineExceeded)) {
+			return err
+		}
+	}
+}
+
+// Start sets the position to the newest event recorded, so that only the
+// events recorded from now on are published.
+func (l *Listener) Start(ctx context.Context) error {
+	row, err := l.store.GetLastStreamOutboxEvent(ctx)
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		return err
+	}
+
+	l.txID, l.lastID = row.TxID, row.ID
+	l.started = true
+	return nil
+}
+
+// CatchUp publishes the events recorded since the newest one published.
+func (l *Listener) CatchUp(ctx context.Context) error {
+	for {
+		rows, err := l.store.ListOutboxEventsAfter(ctx, db.ListOutboxEventsAfterParams{
+			AfterTxID: l.txID,
+			AfterID:   l.lastID,
+			Limit:     batchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			l.publish(row)
+		}
+
+		if len(rows) < batchSize {
+			return nil
+		}
+	}
+}
+
+func (l *Listener) publish(row db.OutboxEvent) {
+	l.txID, l.lastID = row.TxID, row.ID
+
+	event, err := NewEvent(row)
+	if err != nil {
+		log.Printf("not streaming outbox event %d (%s): %v", row.ID, row.EventType, err)
+		return
+	}
+
+	l.hub.Publish(event)
+}
@@ -0,0 +1,89 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func outboxRow(t *testing.T, id int64, teamID int32) db.OutboxEvent {
+	payload, err := json.Marshal(db.TaskDeleted{TaskID: 5, TeamID: &teamID})
+	require.NoError(t, err)
+
+	return db.OutboxEvent{
+		ID:        id,
+		EventType: string(db.EventTaskDeleted),
+		Payload:   payload,
+		TeamID:    &teamID,
+		TxID:      id,
+	}
+}
+
+func TestListenerStart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	listener := NewListener(nil, store, NewHub())
+
+	head := outboxRow(t, 4, 10)
+	head.TxID = 9
+	store.EXPECT().GetLastStreamOutboxEvent(gomock.Any()).Times(1).Return(head, nil)
+
+	require.NoError(t, listener.Start(context.Background()))
+	require.True(t, listener.started)
+	require.Equal(t, int64(9), listener.txID)
+	require.Equal(t, int64(4), listener.lastID)
+
+	// Nothing was recorded yet, so every event is new.
+	listener = NewListener(nil, store, NewHub())
+	store.EXPECT().GetLastStreamOutboxEvent(gomock.Any()).Times(1).Return(db.OutboxEvent{}, db.ErrRecordNotFound)
+
+	require.NoError(t, listener.Start(context.Background()))
+	require.True(t, listener.started)
+	require.Zero(t, listener.txID)
+	require.Zero(t, listener.lastID)
+}
+
+func TestListenerCatchUp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	hub := NewHub()
+	sub := hub.Subscribe(1, []int32{10})
+	defer sub.Close()
+
+	listener := NewListener(nil, store, hub)
+	listener.txID, listener.lastID = 3, 3
+
+	// The first page is full, so another is asked for.
+	page := make([]db.OutboxEvent, batchSize)
+	for i := range page {
+		page[i] = outboxRow(t, int64(i+4), 20)
+	}
+	last := page[batchSize-1]
+
+	// An event given a lower ID than the last one, but committed after it.
+	late := outboxRow(t, 2, 10)
+	late.TxID = last.TxID + 1
+
+	gomock.InOrder(
+		store.EXPECT().
+			ListOutboxEventsAfter(gomock.Any(), gomock.Eq(db.ListOutboxEventsAfterParams{AfterTxID: 3, AfterID: 3, Limit: batchSize})).
+			Times(1).
+			Return(page, nil),
+		store.EXPECT().
+			ListOutboxEventsAfter(gomock.Any(), gomock.Eq(db.ListOutboxEventsAfterParams{AfterTxID: last.TxID, AfterID: last.ID, Limit: batchSize})).
+			Times(1).
+			Return([]db.OutboxEvent{late}, nil),
+	)
+
+	require.NoError(t, listener.CatchUp(context.Background()))
+	require.Equal(t, []int64{late.ID}, received(sub))
+	require.Equal(t, late.TxID, listener.txID)
+	require.Equal(t, late.ID, listener.lastID)
+}
@@ -3,7 +3,13 @@ package token
 import "time"
 
 type TokenMaker interface {
-	CreateToken(email string, duration time.Duration) (string, *Payload, error)
+	CreateToken(userID int32, email string, tokenType TokenType, duration time.Duration) (string, *Payload, error)
 
-	ValidateToken(token string) (*Payload, error)
+	ValidateToken(token string, tokenType TokenType) (*Payload, error)
+
+	CreateResourceToken(resource string, duration time.Duration) (string, *Payload, error)
+
+	// ValidateResourceToken returns ErrInvalidToken unless token was issued
+	// for exactly this resource.
+	ValidateResourceToken(token string, resource string) (*Payload, error)
 }
@@ -18,17 +18,31 @@ func NewPasetoMaker(symmetricKey string) (TokenMaker, error) {
 		return nil, fmt.Errorf("invalid key size: must be exactly %d characters", chacha20poly1305.KeySize)
 	}
 
+	key, err := paseto.V4SymmetricKeyFromBytes([]byte(symmetricKey))
+	if err != nil {
+		return nil, err
+	}
+
 	maker := PasetoMaker{
-		maker:        paseto.NewV4SymmetricKey(),
+		maker:        key,
 		symmetricKey: []byte(symmetricKey),
 	}
 
 	return &maker, nil
 }
 
-func (m *PasetoMaker) CreateToken(email string, duration time.Duration) (string, *Payload, error) {
-	payload := NewPayload(email, duration)
+func (m *PasetoMaker) CreateToken(userID int32, email string, tokenType TokenType, duration time.Duration) (string, *Payload, error) {
+	return m.encrypt(NewPayload(userID, email, tokenType, duration))
+}
+
+func (m *PasetoMaker) CreateResourceToken(resource string, duration time.Duration) (string, *Payload, error) {
+	payload := NewPayload(0, "", TokenTypeResource, duration)
+	payload.Resource = resource
 
+	return m.encrypt(payload)
+}
+
+func (m *PasetoMaker) encrypt(payload *Payload) (string, *Payload, error) {
 	token := paseto.NewToken()
 
 	token.SetExpiration(payload.ExpiresAt)
@@ -44,12 +58,14 @@ func (m *PasetoMaker) CreateToken(email string, duration time.Duration) (string,
 	return token.V4Encrypt(m.maker, m.symmetricKey), payload, nil
 }
 
-func (m *PasetoMaker) ValidateToken(token string) (*Payload, error) {
-	parser := paseto.NewParser()
+func (m *PasetoMaker) ValidateToken(token string, tokenType TokenType) (*Payload, error) {
+	// Expiry is checked against the payload below so that an expired token can
+	// be told apart from one that was tampered with or never issued by us.
+	parser := paseto.NewParserWithoutExpiryCheck()
 	parsedToken, err := parser.ParseV4Local(m.maker, token, m.symmetricKey)
 
 	if err != nil {
-		return nil, err
+		return nil, ErrInvalidToken
 	}
 
 	payload := &Payload{}
@@ -59,5 +75,26 @@ func (m *PasetoMaker) ValidateToken(token string) (*Payload, error) {
 		return nil, ErrInvalidToken
 	}
 
+	if payload.Type != tokenType {
+		return nil, ErrInvalidToken
+	}
+
+	if err := payload.Valid(); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+func (m *PasetoMaker) ValidateResourceToken(token string, resource string) (*Payload, error) {
+	payload, err := m.ValidateToken(token, TokenTypeResource)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload.Resource != resource {
+		return nil, ErrInvalidToken
+	}
+
 	return payload, nil
 }
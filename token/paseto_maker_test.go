@@ -12,23 +12,26 @@ func TestPasetoMaker(t *testing.T) {
 	maker, err := NewPasetoMaker(util.RandomString(32))
 	require.NoError(t, err)
 
+	userID := int32(util.RandomInt(1, 1000))
 	email := util.RandomEmail()
 	duration := time.Minute
 
 	issuedAt := time.Now()
 	expiredAt := time.Now().Add(duration)
 
-	token, payload, err := maker.CreateToken(email, duration)
+	token, payload, err := maker.CreateToken(userID, email, TokenTypeAccessToken, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
 
-	payload, err = maker.ValidateToken(token)
+	payload, err = maker.ValidateToken(token, TokenTypeAccessToken)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
 	require.NotZero(t, payload.ID)
+	require.Equal(t, userID, payload.UserID)
 	require.Equal(t, email, payload.Email)
+	require.Equal(t, TokenTypeAccessToken, payload.Type)
 	require.WithinDuration(t, issuedAt, payload.IssuedAt, duration)
 	require.WithinDuration(t, expiredAt, payload.ExpiresAt, duration)
 }
@@ -37,13 +40,97 @@ func TestExpiredPasetoToken(t *testing.T) {
 	maker, err := NewPasetoMaker(util.RandomString(32))
 	require.NoError(t, err)
 
-	token, payload, err := maker.CreateToken(util.RandomEmail(), -time.Minute)
+	token, payload, err := maker.CreateToken(int32(util.RandomInt(1, 1000)), util.RandomEmail(), TokenTypeAccessToken, -time.Minute)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
 
-	payload, err = maker.ValidateToken(token)
+	payload, err = maker.ValidateToken(token, TokenTypeAccessToken)
 	require.Error(t, err)
 	require.EqualError(t, err, ErrExpiredToken.Error())
 	require.Nil(t, payload)
 }
+
+func TestInvalidPasetoToken(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(int32(util.RandomInt(1, 1000)), util.RandomEmail(), TokenTypeAccessToken, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.ValidateToken(token+"x", TokenTypeAccessToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+
+	otherMaker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	payload, err = otherMaker.ValidateToken(token, TokenTypeAccessToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestPasetoTokenSharedKey(t *testing.T) {
+	key := util.RandomString(32)
+
+	maker, err := NewPasetoMaker(key)
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(int32(util.RandomInt(1, 1000)), util.RandomEmail(), TokenTypeAccessToken, time.Minute)
+	require.NoError(t, err)
+
+	// A second maker built from the same key, e.g. after a restart or on another
+	// instance, must accept tokens issued by the first.
+	otherMaker, err := NewPasetoMaker(key)
+	require.NoError(t, err)
+
+	payload, err := otherMaker.ValidateToken(token, TokenTypeAccessToken)
+	require.NoError(t, err)
+	require.NotNil(t, payload)
+}
+
+func TestPasetoTokenTypeMismatch(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(int32(util.RandomInt(1, 1000)), util.RandomEmail(), TokenTypeRefreshToken, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.ValidateToken(token, TokenTypeAccessToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+
+	payload, err = maker.ValidateToken(token, TokenTypeRefreshToken)
+	require.NoError(t, err)
+	require.Equal(t, TokenTypeRefreshToken, payload.Type)
+}
+
+func TestPasetoResourceToken(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, payload, err := maker.CreateResourceToken("attachment:7", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, "attachment:7", payload.Resource)
+
+	payload, err = maker.ValidateResourceToken(token, "attachment:7")
+	require.NoError(t, err)
+	require.Equal(t, TokenTypeResource, payload.Type)
+
+	_, err = maker.ValidateResourceToken(token, "attachment:8")
+	require.EqualError(t, err, ErrInvalidToken.Error())
+
+	// Resource tokens are not access tokens and vice versa.
+	_, err = maker.ValidateToken(token, TokenTypeAccessToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+
+	accessToken, _, err := maker.CreateToken(int32(util.RandomInt(1, 1000)), util.RandomEmail(), TokenTypeAccessToken, time.Minute)
+	require.NoError(t, err)
+	_, err = maker.ValidateResourceToken(accessToken, "")
+	require.EqualError(t, err, ErrInvalidToken.Error())
+
+	expired, _, err := maker.CreateResourceToken("attachment:7", -time.Minute)
+	require.NoError(t, err)
+	_, err = maker.ValidateResourceToken(expired, "attachment:7")
+	require.EqualError(t, err, ErrExpiredToken.Error())
+}
@@ -7,9 +7,26 @@ import (
 	"github.com/google/uuid"
 )
 
+// TokenType tells access tokens apart from refresh tokens so that one can't
+// be presented in place of the other.
+type TokenType byte
+
+const (
+	TokenTypeAccessToken  TokenType = 1
+	TokenTypeRefreshToken TokenType = 2
+	// TokenTypeResource tokens grant access to a single resource, such as a
+	// file behind a signed download URL, rather than identifying a user.
+	TokenTypeResource TokenType = 3
+)
+
 type Payload struct {
-	ID        uuid.UUID
+	ID   uuid.UUID
+	Type TokenType
+	// UserID is the user the token was issued to, and Email their address
+	// at the time. Resource tokens have neither.
+	UserID    int32 `json:",omitempty"`
 	Email     string
+	Resource  string `json:",omitempty"`
 	IssuedAt  time.Time
 	ExpiresAt time.Time
 }
@@ -19,13 +36,23 @@ var (
 	ErrExpiredToken = errors.New("this token has expired")
 )
 
-func NewPayload(email string, duration time.Duration) *Payload {
+func NewPayload(userID int32, email string, tokenType TokenType, duration time.Duration) *Payload {
 	issuedAt := time.Now()
 
 	return &Payload{
 		ID:        uuid.New(),
+		Type:      tokenType,
+		UserID:    userID,
 		Email:     email,
 		IssuedAt:  issuedAt,
 		ExpiresAt: issuedAt.Add(duration),
 	}
 }
+
+func (payload *Payload) Valid() error {
+	if time.Now().After(payload.ExpiresAt) {
+		return ErrExpiredToken
+	}
+
+	return nil
+}
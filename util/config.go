@@ -13,6 +13,38 @@ type Config struct {
 	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
 	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
 	TokenSymmetricKey    string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	AdminEmail           string        `mapstructure:"ADMIN_EMAIL"`
+
+	AppBaseURL                     string        `mapstructure:"APP_BASE_URL"`
+	Mailer                         string        `mapstructure:"MAILER"`
+	SMTPHost                       string        `mapstructure:"SMTP_HOST"`
+	SMTPPort                       int           `mapstructure:"SMTP_PORT"`
+	SMTPUsername                   string        `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword                   string        `mapstructure:"SMTP_PASSWORD"`
+	EmailSenderAddress             string        `mapstructure:"EMAIL_SENDER_ADDRESS"`
+	EmailVerificationTokenDuration time.Duration `mapstructure:"EMAIL_VERIFICATION_TOKEN_DURATION"`
+	EmailVerificationResendDelay   time.Duration `mapstructure:"EMAIL_VERIFICATION_RESEND_DELAY"`
+	PasswordResetTokenDuration     time.Duration `mapstructure:"PASSWORD_RESET_TOKEN_DURATION"`
+	RequireVerifiedEmail           bool          `mapstructure:"REQUIRE_VERIFIED_EMAIL"`
+
+	BlobStore              string        `mapstructure:"BLOB_STORE"`
+	BlobLocalDir           string        `mapstructure:"BLOB_LOCAL_DIR"`
+	S3Endpoint             string        `mapstructure:"S3_ENDPOINT"`
+	S3Region               string        `mapstructure:"S3_REGION"`
+	S3Bucket               string        `mapstructure:"S3_BUCKET"`
+	S3AccessKeyID          string        `mapstructure:"S3_ACCESS_KEY_ID"`
+	S3SecretAccessKey      string        `mapstructure:"S3_SECRET_ACCESS_KEY"`
+	AttachmentMaxSize      int64         `mapstructure:"ATTACHMENT_MAX_SIZE"`
+	AttachmentAllowedTypes []string      `mapstructure:"ATTACHMENT_ALLOWED_TYPES"`
+	AttachmentURLDuration  time.Duration `mapstructure:"ATTACHMENT_URL_DURATION"`
+	AvatarMaxSize          int64         `mapstructure:"AVATAR_MAX_SIZE"`
+
+	RecurrenceInterval time.Duration `mapstructure:"RECURRENCE_INTERVAL"`
+	OutboxInterval     time.Duration `mapstructure:"OUTBOX_INTERVAL"`
+	WebhookInterval    time.Duration `mapstructure:"WEBHOOK_INTERVAL"`
+	DigestInterval     time.Duration `mapstructure:"DIGEST_INTERVAL"`
+
+	StreamHeartbeatInterval time.Duration `mapstructure:"STREAM_HEARTBEAT_INTERVAL"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -0,0 +1,113 @@
+package util
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// jpegWithOrientation encodes img as a JPEG carrying an EXIF block with the
+// given orientation.
+func jpegWithOrientation(t *testing.T, img image.Image, orientation uint16, order binary.ByteOrder) []byte {
+	var encoded bytes.Buffer
+	require.NoError(t, jpeg.Encode(&encoded, img, nil))
+
+	tiff := make([]byte, 26)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], exifOrientationTag)
+	order.PutUint16(tiff[12:], 3)
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], orientation)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	data := encoded.Bytes()
+	return append(append(append([]byte{}, data[:2]...), app1...), data[2:]...)
+}
+
+func TestImageOrientation(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+
+	for orientation := uint16(1); orientation <= 8; orientation++ {
+		require.Equal(t, int(orientation), ImageOrientation(jpegWithOrientation(t, img, orientation, binary.BigEndian)))
+		require.Equal(t, int(orientation), ImageOrientation(jpegWithOrientation(t, img, orientation, binary.LittleEndian)))
+	}
+
+	var plain bytes.Buffer
+	require.NoError(t, jpeg.Encode(&plain, img, nil))
+
+	require.Equal(t, 1, ImageOrientation(plain.Bytes()))
+	require.Equal(t, 1, ImageOrientation(jpegWithOrientation(t, img, 42, binary.BigEndian)))
+	require.Equal(t, 1, ImageOrientation([]byte("\x89PNG\r\n\x1a\n")))
+	require.Equal(t, 1, ImageOrientation([]byte{0xFF, 0xD8, 0xFF, 0xE1, 0xFF, 0xFF}))
+}
+
+func TestSquareThumbnail(t *testing.T) {
+	red := color.NRGBA{R: 255, A: 255}
+	blue := color.NRGBA{B: 255, A: 255}
+
+	// A wide image with a red middle third: the crop keeps only the middle.
+	img := image.NewNRGBA(image.Rect(0, 0, 300, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 300; x++ {
+			if x >= 100 && x < 200 {
+				img.SetNRGBA(x, y, red)
+			} else {
+				img.SetNRGBA(x, y, blue)
+			}
+		}
+	}
+
+	thumb := SquareThumbnail(img, 32)
+	require.Equal(t, image.Rect(0, 0, 32, 32), thumb.Bounds())
+
+	for _, p := range []image.Point{{0, 0}, {31, 0}, {16, 16}, {0, 31}, {31, 31}} {
+		require.Equal(t, red, thumb.NRGBAAt(p.X, p.Y), p)
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// A 2×1 image: red on the left, blue on the right.
+	red := color.NRGBA{R: 255, A: 255}
+	blue := color.NRGBA{B: 255, A: 255}
+
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	img.SetNRGBA(0, 0, red)
+	img.SetNRGBA(1, 0, blue)
+
+	testCases := []struct {
+		orientation int
+		bounds      image.Rectangle
+		redAt       image.Point
+	}{
+		{orientation: 1, bounds: image.Rect(0, 0, 2, 1), redAt: image.Pt(0, 0)},
+		{orientation: 2, bounds: image.Rect(0, 0, 2, 1), redAt: image.Pt(1, 0)},
+		{orientation: 3, bounds: image.Rect(0, 0, 2, 1), redAt: image.Pt(1, 0)},
+		{orientation: 4, bounds: image.Rect(0, 0, 2, 1), redAt: image.Pt(0, 0)},
+		{orientation: 5, bounds: image.Rect(0, 0, 1, 2), redAt: image.Pt(0, 0)},
+		{orientation: 6, bounds: image.Rect(0, 0, 1, 2), redAt: image.Pt(0, 0)},
+		{orientation: 7, bounds: image.Rect(0, 0, 1, 2), redAt: image.Pt(0, 1)},
+		{orientation: 8, bounds: image.Rect(0, 0, 1, 2), redAt: image.Pt(0, 1)},
+	}
+
+	for _, tt := range testCases {
+		oriented := Orient(img, tt.orientation)
+		require.Equal(t, tt.bounds, oriented.Bounds(), tt.orientation)
+		require.Equal(t, red, oriented.NRGBAAt(tt.redAt.X, tt.redAt.Y), tt.orientation)
+	}
+}
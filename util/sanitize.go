@@ -1,22 +1,44 @@
 package util
 
 import (
+	"bytes"
 	"strings"
 	"unicode"
 
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
 	"golang.org/x/text/unicode/norm"
 )
 
+// markdownPolicy allows the formatting user generated markdown produces and
+// drops everything that could run script, such as event handlers and
+// javascript: links.
+var markdownPolicy = bluemonday.UGCPolicy().RequireNoFollowOnLinks(true)
+
 func SanitizeInput(s string) string {
+	return sanitize(s, false)
+}
+
+// SanitizeMultilineInput is SanitizeInput for free text such as comments:
+// line breaks and tabs are kept, with \r\n folded into \n.
+func SanitizeMultilineInput(s string) string {
+	return sanitize(strings.ReplaceAll(s, "\r\n", "\n"), true)
+}
+
+func sanitize(s string, keepLineBreaks bool) string {
 	// Trim spaces
 	s = strings.TrimSpace(s)
 
 	// Normalize Unicode (e.g. é -> single code point)
 	s = norm.NFC.String(s)
 
-	// Remove control chars except newline/tab
+	// Remove control chars, keeping newline/tab for multiline input
 	var b strings.Builder
 	for _, r := range s {
+		if keepLineBreaks && (r == '\n' || r == '\t') {
+			b.WriteRune(r)
+			continue
+		}
 		if unicode.IsControl(r) {
 			continue
 		}
@@ -25,3 +47,14 @@ func SanitizeInput(s string) string {
 
 	return b.String()
 }
+
+// RenderMarkdown converts markdown to HTML that is safe to embed in a page.
+// Raw HTML in the source is never passed through.
+func RenderMarkdown(src string) (string, error) {
+	var buf bytes.Buffer
+	if err := goldmark.Convert([]byte(src), &buf); err != nil {
+		return "", err
+	}
+
+	return markdownPolicy.Sanitize(buf.String()), nil
+}
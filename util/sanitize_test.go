@@ -56,3 +56,53 @@ func TestSanitizeInput(t *testing.T) {
 		})
 	}
 }
+
+func TestSanitizeMultilineInput(t *testing.T) {
+	require.Equal(t, "line one\nline two\n\tindented", SanitizeMultilineInput("  line one\r\nline two\n\tindented\x00 \n"))
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "renders formatting",
+			input:    "**bold** and `code`",
+			contains: []string{"<strong>bold</strong>", "<code>code</code>"},
+		},
+		{
+			name:     "drops raw html",
+			input:    "hello <script>alert(1)</script><img src=x onerror=alert(1)>",
+			contains: []string{"hello"},
+			excludes: []string{"<script", "onerror"},
+		},
+		{
+			name:     "drops script links",
+			input:    "[click](javascript:alert(1))",
+			contains: []string{"click"},
+			excludes: []string{"javascript:"},
+		},
+		{
+			name:     "keeps safe links",
+			input:    "[docs](https://example.com)",
+			contains: []string{`href="https://example.com"`, `rel="nofollow"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, err := RenderMarkdown(tt.input)
+			require.NoError(t, err)
+
+			for _, s := range tt.contains {
+				require.Contains(t, html, s)
+			}
+			for _, s := range tt.excludes {
+				require.NotContains(t, html, s)
+			}
+		})
+	}
+}
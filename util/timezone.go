@@ -0,0 +1,35 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	// Users pick their zone from the IANA database, which the host may not
+	// ship.
+	_ "time/tzdata"
+)
+
+var ErrInvalidTimezone = errors.New("invalid timezone")
+
+// LoadTimezone resolves an IANA zone name such as "Europe/London". Unlike
+// time.LoadLocation it rejects "" and "Local", whose meaning depends on the
+// server the code happens to run on.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTimezone, name)
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTimezone, name)
+	}
+
+	return location, nil
+}
+
+// EndOfDay returns the last second of the given calendar day on the wall
+// clock of location, in UTC.
+func EndOfDay(year int, month time.Month, day int, location *time.Location) time.Time {
+	return time.Date(year, month, day, 23, 59, 59, 0, location).UTC()
+}
@@ -0,0 +1,43 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTimezone(t *testing.T) {
+	location, err := LoadTimezone("America/New_York")
+	require.NoError(t, err)
+	require.Equal(t, "America/New_York", location.String())
+
+	for _, name := range []string{"", "Local", "Mars/Olympus_Mons"} {
+		_, err := LoadTimezone(name)
+		require.ErrorIs(t, err, ErrInvalidTimezone, name)
+	}
+}
+
+func TestEndOfDay(t *testing.T) {
+	newYork, err := LoadTimezone("America/New_York")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		day      int
+		location *time.Location
+		expected time.Time
+	}{
+		{name: "UTC", day: 8, location: time.UTC, expected: time.Date(2025, 3, 8, 23, 59, 59, 0, time.UTC)},
+		{name: "Standard Time", day: 8, location: newYork, expected: time.Date(2025, 3, 9, 4, 59, 59, 0, time.UTC)},
+		{name: "Daylight Saving Time", day: 9, location: newYork, expected: time.Date(2025, 3, 10, 3, 59, 59, 0, time.UTC)},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			endOfDay := EndOfDay(2025, time.March, tt.day, tt.location)
+			require.Equal(t, tt.expected, endOfDay)
+			require.Equal(t, time.UTC, endOfDay.Location())
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecureToken(t *testing.T) {
+	token, err := SecureToken(32)
+	require.NoError(t, err)
+	require.Len(t, token, 43)
+
+	other, err := SecureToken(32)
+	require.NoError(t, err)
+	require.NotEqual(t, token, other)
+}
+
+func TestHashToken(t *testing.T) {
+	token, err := SecureToken(32)
+	require.NoError(t, err)
+
+	hash := HashToken(token)
+	require.Len(t, hash, 64)
+	require.Equal(t, hash, HashToken(token))
+	require.NotEqual(t, hash, HashToken(token+"x"))
+}
@@ -0,0 +1,326 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/outbox"
+)
+
+const (
+	defaultInterval = 5 * time.Second
+	batchSize       = 50
+
+	// requestTimeout bounds a single delivery. A claimed batch is sent all
+	// at once, so leaseDuration only has to stay well above the timeout for
+	// a delivery in flight not to be claimed a second time.
+	requestTimeout = 10 * time.Second
+	leaseDuration  = time.Minute
+
+	// maxAttempts is how many times a delivery is tried before it is given
+	// up on. With the backoff below that spans about an hour.
+	maxAttempts = 8
+	minBackoff  = 30 * time.Second
+	maxBackoff  = time.Hour
+
+	// MaxConsecutiveFailures is how many deliveries in a row can be given
+	// up on before the webhook is disabled.
+	MaxConsecutiveFailures = 5
+
+	// maxErrorLength is how much of a failed attempt's error is kept in the
+	// delivery log. Nothing the receiver responds with is kept beyond its
+	// status code.
+	maxErrorLength = 256
+
+	// drainLimit is how much of a response is read, and thrown away, to
+	// reuse the connection.
+	drainLimit = 4096
+)
+
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Payload is the body of every delivery.
+type Payload struct {
+	// ID identifies the event; redeliveries and retries repeat it, so
+	// receivers can use it to drop duplicates.
+	ID         int64          `json:"id"`
+	Type       db.EventType   `json:"type"`
+	OccurredAt time.Time      `json:"occurredAt"`
+	TeamID     int32          `json:"teamId"`
+	Data       db.DomainEvent `json:"data"`
+}
+
+// Sender posts domain events to the webhooks of the team they happened in.
+// Events are first queued as deliveries, one per subscribed webhook, and
+// sent from there with retries, so a slow or failing receiver holds up
+// neither the outbox nor the other webhooks.
+type Sender struct {
+	store    db.Store
+	client   *http.Client
+	interval time.Duration
+	now      func() time.Time
+}
+
+// NewSender returns a sender that looks for due deliveries every interval,
+// or every five seconds when interval is not positive.
+func NewSender(store db.Store, interval time.Duration) *Sender {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	return &Sender{
+		store: store,
+		client: &http.Client{
+			Timeout:   requestTimeout,
+			Transport: newTransport(),
+			// A redirect counts as a failed delivery rather than sending the
+			// signed payload somewhere the team did not configure.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// Subscribe has every event of dispatcher queued for delivery.
+func (s *Sender) Subscribe(dispatcher *outbox.Dispatcher) {
+	for _, eventType := range db.EventTypes {
+		dispatcher.Subscribe(eventType, s.Enqueue)
+	}
+}
+
+// Enqueue queues event for every active webhook of its team that subscribed
+// to its type. Queueing the same event again does nothing, so it is safe for
+// the outbox to deliver it more than once.
+func (s *Sender) Enqueue(ctx context.Context, event outbox.Event) error {
+	// Events of personal tasks are not sent anywhere.
+	teamID := db.EventTeamID(event.Data)
+	if teamID == nil {
+		return nil
+	}
+
+	webhooks, err := s.store.ListWebhooksForEvent(ctx, db.ListWebhooksForEventParams{
+		TeamID:    *teamID,
+		EventType: string(event.Data.EventType()),
+	})
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+
+	payload, err := json.Marshal(Payload{
+		ID:         event.ID,
+		Type:       event.Data.EventType(),
+		OccurredAt: event.OccurredAt,
+		TeamID:     *teamID,
+		Data:       event.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		err := s.store.CreateWebhookDelivery(ctx, db.CreateWebhookDeliveryParams{
+			WebhookID: webhook.ID,
+			EventID:   event.ID,
+			EventType: string(event.Data.EventType()),
+			Payload:   payload,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Run sends due deliveries until ctx is done.
+func (s *Sender) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.DeliverPending(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("unable to send webhook deliveries: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverPending sends every delivery that is due, to webhooks that are
+// active. Each claimed batch is sent concurrently.
+func (s *Sender) DeliverPending(ctx context.Context) error {
+	for {
+		now := s.now().UTC()
+
+		deliveries, err := s.store.ClaimWebhookDeliveries(ctx, db.ClaimWebhookDeliveriesParams{
+			LeaseUntil: now.Add(leaseDuration),
+			Now:        now,
+			Limit:      batchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		// Sending the batch one delivery after the other could outlast the
+		// lease of the last ones, which would then be claimed again.
+		var (
+			wg   sync.WaitGroup
+			errs = make([]error, len(deliveries))
+		)
+		for i, delivery := range deliveries {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = s.Deliver(ctx, delivery)
+			}()
+		}
+		wg.Wait()
+
+		if err := errors.Join(errs...); err != nil {
+			return err
+		}
+	}
+}
+
+// Deliver makes one attempt at sending delivery and records the outcome.
+// Only failing to record it is returned; a failed attempt is retried later.
+func (s *Sender) Deliver(ctx context.Context, delivery db.WebhookDelivery) error {
+	webhook, err := s.store.GetWebhook(ctx, delivery.WebhookID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	responseStatus, err := s.send(ctx, webhook, delivery)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	arg := db.RecordWebhookDeliveryAttemptParams{
+		Status:         StatusSucceeded,
+		ResponseStatus: responseStatus,
+		NextAttemptAt:  s.now().UTC(),
+		ID:             delivery.ID,
+	}
+
+	if err == nil {
+		if _, err := s.store.RecordWebhookDeliveryAttempt(ctx, arg); err != nil {
+			return err
+		}
+		return s.store.ResetWebhookFailures(ctx, webhook.ID)
+	}
+
+	arg.LastError = truncateError(err)
+
+	attempt := delivery.Attempts + 1
+	if attempt < maxAttempts {
+		arg.Status = StatusPending
+		arg.NextAttemptAt = arg.NextAttemptAt.Add(backoff(attempt))
+
+		_, err = s.store.RecordWebhookDeliveryAttempt(ctx, arg)
+		return err
+	}
+
+	arg.Status = StatusFailed
+	if _, err := s.store.RecordWebhookDeliveryAttempt(ctx, arg); err != nil {
+		return err
+	}
+
+	updated, err := s.store.RecordWebhookFailure(ctx, db.RecordWebhookFailureParams{
+		MaxFailures: MaxConsecutiveFailures,
+		ID:          webhook.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if webhook.Active && !updated.Active {
+		log.Printf("disabled webhook %d of team %d after %d failed deliveries", webhook.ID, webhook.TeamID, updated.ConsecutiveFailures)
+	}
+	return nil
+}
+
+// send posts the delivery, signed with the webhook's secret, and returns
+// the status the receiver responded with. Anything but a 2xx response is an
+// error.
+func (s *Sender) send(ctx context.Context, webhook db.Webhook, delivery db.WebhookDelivery) (*int32, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "task-manager-webhooks/1")
+	request.Header.Set(EventHeader, delivery.EventType)
+	request.Header.Set(DeliveryHeader, fmt.Sprint(delivery.ID))
+	request.Header.Set(SignatureHeader, Sign(webhook.Secret, s.now(), delivery.Payload))
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	// The body is drained, not read, so the connection can be reused
+	// without anything the receiver sent ending up in the log.
+	io.Copy(io.Discard, io.LimitReader(response.Body, drainLimit))
+
+	status := int32(response.StatusCode)
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return &status, fmt.Errorf("receiver responded with status %d", response.StatusCode)
+	}
+	return &status, nil
+}
+
+// truncateError cuts the text of err down to maxErrorLength bytes, keeping it
+// valid UTF-8 without NUL bytes, as the log stores text.
+func truncateError(err error) string {
+	message := strings.ReplaceAll(strings.ToValidUTF8(err.Error(), "\uFFFD"), "\x00", "")
+	if len(message) <= maxErrorLength {
+		return message
+	}
+
+	cut := maxErrorLength
+	for cut > 0 && !utf8.RuneStart(message[cut]) {
+		cut--
+	}
+	return message[:cut]
+}
+
+// backoff is how long to wait before the next attempt after the given number
+// of failed ones: it doubles from 30 seconds up to an hour.
+func backoff(attempts int32) time.Duration {
+	delay := minBackoff
+	for i := int32(1); i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
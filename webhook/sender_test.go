@@ -0,0 +1,382 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	mockdb "github.com/bolusarz/task-manager/db/mock"
+	db "github.com/bolusarz/task-manager/db/sqlc"
+	"github.com/bolusarz/task-manager/outbox"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const testSecret = "a-very-secret-value"
+
+func newTestSender(store db.Store, now time.Time) *Sender {
+	sender := NewSender(store, time.Second)
+	sender.now = func() time.Time { return now }
+	// The test receivers listen on loopback, which deliveries are otherwise
+	// not allowed to reach.
+	sender.client.Transport = http.DefaultTransport
+	return sender
+}
+
+func testWebhook(url string) db.Webhook {
+	return db.Webhook{
+		ID:         3,
+		TeamID:     2,
+		Url:        url,
+		EventTypes: []string{string(db.EventTaskCreated)},
+		Secret:     testSecret,
+		Active:     true,
+	}
+}
+
+func testDelivery(attempts int32) db.WebhookDelivery {
+	return db.WebhookDelivery{
+		ID:        40,
+		WebhookID: 3,
+		EventID:   7,
+		EventType: string(db.EventTaskCreated),
+		Payload:   json.RawMessage(`{"id":7,"type":"task.created"}`),
+		Status:    StatusPending,
+		Attempts:  attempts,
+	}
+}
+
+func TestEnqueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	teamID := int32(2)
+	occurredAt := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	event := outbox.Event{
+		ID:         7,
+		OccurredAt: occurredAt,
+		Attempt:    1,
+		Data:       db.TaskCreated{Task: db.Task{ID: 4, Title: "Write report", TeamID: &teamID}},
+	}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListWebhooksForEvent(gomock.Any(), gomock.Eq(db.ListWebhooksForEventParams{TeamID: teamID, EventType: "task.created"})).
+		Times(1).
+		Return([]db.Webhook{{ID: 3}, {ID: 5}}, nil)
+
+	for _, webhookID := range []int32{3, 5} {
+		store.EXPECT().
+			CreateWebhookDelivery(gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(func(_ context.Context, arg db.CreateWebhookDeliveryParams) error {
+				require.Equal(t, webhookID, arg.WebhookID)
+				require.Equal(t, int64(7), arg.EventID)
+				require.Equal(t, "task.created", arg.EventType)
+
+				var payload struct {
+					ID         int64          `json:"id"`
+					Type       string         `json:"type"`
+					OccurredAt time.Time      `json:"occurredAt"`
+					TeamID     int32          `json:"teamId"`
+					Data       db.TaskCreated `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(arg.Payload, &payload))
+				require.Equal(t, int64(7), payload.ID)
+				require.Equal(t, "task.created", payload.Type)
+				require.True(t, occurredAt.Equal(payload.OccurredAt))
+				require.Equal(t, teamID, payload.TeamID)
+				require.Equal(t, "Write report", payload.Data.Task.Title)
+				return nil
+			})
+	}
+
+	require.NoError(t, newTestSender(store, time.Now()).Enqueue(t.Context(), event))
+}
+
+func TestEnqueuePersonalTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListWebhooksForEvent(gomock.Any(), gomock.Any()).Times(0)
+
+	event := outbox.Event{ID: 7, Data: db.TaskAssigned{TaskID: 4, UserID: 9}}
+	require.NoError(t, newTestSender(store, time.Now()).Enqueue(t.Context(), event))
+}
+
+func TestDeliver(t *testing.T) {
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name       string
+		attempts   int32
+		respond    func(w http.ResponseWriter, r *http.Request)
+		buildStubs func(store *mockdb.MockStore)
+	}{
+		{
+			name: "OK",
+			respond: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("thanks"))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				status := int32(http.StatusOK)
+				store.EXPECT().
+					RecordWebhookDeliveryAttempt(gomock.Any(), gomock.Eq(db.RecordWebhookDeliveryAttemptParams{
+						Status:         StatusSucceeded,
+						ResponseStatus: &status,
+						NextAttemptAt:  now,
+						ID:             40,
+					})).
+					Times(1).
+					Return(db.WebhookDelivery{}, nil)
+				store.EXPECT().ResetWebhookFailures(gomock.Any(), gomock.Eq(int32(3))).Times(1).Return(nil)
+				store.EXPECT().RecordWebhookFailure(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name: "Retry",
+			respond: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "try later", http.StatusServiceUnavailable)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				status := int32(http.StatusServiceUnavailable)
+				store.EXPECT().
+					RecordWebhookDeliveryAttempt(gomock.Any(), gomock.Eq(db.RecordWebhookDeliveryAttemptParams{
+						Status:         StatusPending,
+						ResponseStatus: &status,
+						LastError:      "receiver responded with status 503",
+						NextAttemptAt:  now.Add(30 * time.Second),
+						ID:             40,
+					})).
+					Times(1).
+					Return(db.WebhookDelivery{}, nil)
+				store.EXPECT().ResetWebhookFailures(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().RecordWebhookFailure(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:     "Retry With Backoff",
+			attempts: 3,
+			respond: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RecordWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RecordWebhookDeliveryAttemptParams) (db.WebhookDelivery, error) {
+						require.Equal(t, StatusPending, arg.Status)
+						require.Equal(t, now.Add(4*time.Minute), arg.NextAttemptAt)
+						return db.WebhookDelivery{}, nil
+					})
+			},
+		},
+		{
+			name: "Redirect Not Followed",
+			respond: func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, "https://example.com/elsewhere", http.StatusFound)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RecordWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RecordWebhookDeliveryAttemptParams) (db.WebhookDelivery, error) {
+						require.Equal(t, StatusPending, arg.Status)
+						require.Equal(t, int32(http.StatusFound), *arg.ResponseStatus)
+						return db.WebhookDelivery{}, nil
+					})
+			},
+		},
+		{
+			name:     "Give Up And Disable",
+			attempts: maxAttempts - 1,
+			respond: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusGone)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RecordWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.RecordWebhookDeliveryAttemptParams) (db.WebhookDelivery, error) {
+						require.Equal(t, StatusFailed, arg.Status)
+						require.Equal(t, "receiver responded with status 410", arg.LastError)
+						return db.WebhookDelivery{}, nil
+					})
+				store.EXPECT().
+					RecordWebhookFailure(gomock.Any(), gomock.Eq(db.RecordWebhookFailureParams{
+						MaxFailures: MaxConsecutiveFailures,
+						ID:          3,
+					})).
+					Times(1).
+					Return(db.Webhook{ID: 3, ConsecutiveFailures: MaxConsecutiveFailures}, nil)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			delivery := testDelivery(tt.attempts)
+
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+
+				require.Equal(t, http.MethodPost, r.Method)
+				require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				require.Equal(t, "task.created", r.Header.Get(EventHeader))
+				require.Equal(t, fmt.Sprint(delivery.ID), r.Header.Get(DeliveryHeader))
+				require.JSONEq(t, string(delivery.Payload), string(body))
+				require.NoError(t, Verify(testSecret, r.Header.Get(SignatureHeader), body, now, DefaultTolerance))
+
+				tt.respond(w, r)
+			}))
+			defer receiver.Close()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetWebhook(gomock.Any(), gomock.Eq(int32(3))).Times(1).Return(testWebhook(receiver.URL), nil)
+			tt.buildStubs(store)
+
+			require.NoError(t, newTestSender(store, now).Deliver(t.Context(), delivery))
+		})
+	}
+}
+
+func TestDeliverUnreachable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	receiver := httptest.NewServer(http.NotFoundHandler())
+	receiver.Close()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetWebhook(gomock.Any(), gomock.Any()).Times(1).Return(testWebhook(receiver.URL), nil)
+	store.EXPECT().
+		RecordWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.RecordWebhookDeliveryAttemptParams) (db.WebhookDelivery, error) {
+			require.Equal(t, StatusPending, arg.Status)
+			require.Nil(t, arg.ResponseStatus)
+			require.NotEmpty(t, arg.LastError)
+			return db.WebhookDelivery{}, nil
+		})
+
+	require.NoError(t, newTestSender(store, time.Now()).Deliver(t.Context(), testDelivery(0)))
+}
+
+func TestDeliverInternalTarget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	received := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = true
+	}))
+	defer receiver.Close()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetWebhook(gomock.Any(), gomock.Any()).Times(1).Return(testWebhook(receiver.URL), nil)
+	store.EXPECT().
+		RecordWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.RecordWebhookDeliveryAttemptParams) (db.WebhookDelivery, error) {
+			require.Equal(t, StatusPending, arg.Status)
+			require.Nil(t, arg.ResponseStatus)
+			require.Contains(t, arg.LastError, ErrForbiddenTarget.Error())
+			require.LessOrEqual(t, len(arg.LastError), maxErrorLength)
+			return db.WebhookDelivery{}, nil
+		})
+
+	sender := NewSender(store, time.Second)
+	require.NoError(t, sender.Deliver(t.Context(), testDelivery(0)))
+	require.False(t, received)
+}
+
+func TestDeliverPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	received := 0
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+	}))
+	defer receiver.Close()
+
+	arg := db.ClaimWebhookDeliveriesParams{
+		LeaseUntil: now.Add(leaseDuration),
+		Now:        now,
+		Limit:      batchSize,
+	}
+
+	store := mockdb.NewMockStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]db.WebhookDelivery{testDelivery(0)}, nil),
+		store.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]db.WebhookDelivery{}, nil),
+	)
+	store.EXPECT().GetWebhook(gomock.Any(), gomock.Any()).Times(1).Return(testWebhook(receiver.URL), nil)
+	store.EXPECT().RecordWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).Times(1).Return(db.WebhookDelivery{}, nil)
+	store.EXPECT().ResetWebhookFailures(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+
+	require.NoError(t, newTestSender(store, now).DeliverPending(t.Context()))
+	require.Equal(t, 1, received)
+}
+
+func TestDeliverPendingSendsBatchAtOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	// The receiver holds every request until the whole batch has arrived,
+	// which only happens if they are sent at the same time.
+	const size = 3
+	var arrived sync.WaitGroup
+	arrived.Add(size)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived.Done()
+		arrived.Wait()
+	}))
+	defer receiver.Close()
+
+	batch := make([]db.WebhookDelivery, size)
+	for i := range batch {
+		batch[i] = testDelivery(0)
+		batch[i].ID += int64(i)
+	}
+
+	store := mockdb.NewMockStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Any()).Times(1).Return(batch, nil),
+		store.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Any()).Times(1).Return([]db.WebhookDelivery{}, nil),
+	)
+	store.EXPECT().GetWebhook(gomock.Any(), gomock.Any()).Times(size).Return(testWebhook(receiver.URL), nil)
+	store.EXPECT().
+		RecordWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).
+		Times(size).
+		DoAndReturn(func(_ context.Context, arg db.RecordWebhookDeliveryAttemptParams) (db.WebhookDelivery, error) {
+			require.Equal(t, StatusSucceeded, arg.Status)
+			return db.WebhookDelivery{}, nil
+		})
+	store.EXPECT().ResetWebhookFailures(gomock.Any(), gomock.Any()).Times(size).Return(nil)
+
+	require.NoError(t, newTestSender(store, now).DeliverPending(t.Context()))
+}
+
+func TestBackoff(t *testing.T) {
+	require.Equal(t, 30*time.Second, backoff(1))
+	require.Equal(t, time.Minute, backoff(2))
+	require.Equal(t, 32*time.Minute, backoff(7))
+	require.Equal(t, time.Hour, backoff(8))
+	require.Equal(t, time.Hour, backoff(100))
+}
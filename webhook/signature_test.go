@@ -0,0 +1,46 @@
+package webhook
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignature(t *testing.T) {
+	secret := "a-very-secret-value"
+	body := []byte(`{"id":1,"type":"task.created"}`)
+	sentAt := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	header := Sign(secret, sentAt, body)
+	require.True(t, strings.HasPrefix(header, "t=1740819600,v1="))
+
+	testCases := []struct {
+		name   string
+		secret string
+		header string
+		body   []byte
+		now    time.Time
+		err    error
+	}{
+		{name: "OK", secret: secret, header: header, body: body, now: sentAt.Add(time.Minute)},
+		{name: "OK: Clock Skew", secret: secret, header: header, body: body, now: sentAt.Add(-time.Minute)},
+		{name: "Wrong Secret", secret: "another-secret", header: header, body: body, now: sentAt, err: ErrInvalidSignature},
+		{name: "Tampered Body", secret: secret, header: header, body: []byte(`{"id":2}`), now: sentAt, err: ErrInvalidSignature},
+		{name: "Replayed", secret: secret, header: header, body: body, now: sentAt.Add(DefaultTolerance + time.Second), err: ErrSignatureExpired},
+		{name: "Timestamp Changed", secret: secret, header: strings.Replace(header, "t=1740819600", "t=1740819660", 1), body: body, now: sentAt, err: ErrInvalidSignature},
+		{name: "Missing Timestamp", secret: secret, header: strings.TrimPrefix(header, "t=1740819600,"), body: body, now: sentAt, err: ErrInvalidSignature},
+		{name: "Malformed", secret: secret, header: "t=abc,v1=xyz", body: body, now: sentAt, err: ErrInvalidSignature},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.header, tt.body, tt.now, DefaultTolerance)
+			if tt.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// lookupTimeout bounds the DNS lookup CheckURL makes.
+const lookupTimeout = 3 * time.Second
+
+var (
+	ErrInvalidURL      = errors.New("webhook URL must be an absolute http or https URL")
+	ErrForbiddenTarget = errors.New("webhook URL must not point at a loopback, private, link-local or unspecified address")
+)
+
+// CheckURL reports whether deliveries may be sent to raw: it must be an http
+// or https URL whose host does not resolve to an address allowedAddr refuses.
+// A host that cannot be resolved is let through; the sender checks every
+// address again when it connects.
+func CheckURL(ctx context.Context, raw string) error {
+	target, err := url.Parse(raw)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		return ErrInvalidURL
+	}
+
+	host := target.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !allowedAddr(addr) {
+			return ErrForbiddenTarget
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil
+	}
+
+	for _, addr := range addrs {
+		if !allowedAddr(addr) {
+			return ErrForbiddenTarget
+		}
+	}
+	return nil
+}
+
+// allowedAddr reports whether a delivery may connect to addr. Everything that
+// reaches the server itself or the network it runs in is refused, which
+// includes cloud metadata endpoints on link-local addresses.
+func allowedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified()
+}
+
+// controlDial refuses connections to addresses allowedAddr refuses. It runs
+// on the address actually dialled, after DNS resolution, so a host that
+// resolved to a public address when the webhook was saved cannot be pointed
+// somewhere internal later.
+func controlDial(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !allowedAddr(addrPort.Addr()) {
+		return ErrForbiddenTarget
+	}
+	return nil
+}
+
+// newTransport returns the transport deliveries are sent with. It never goes
+// through a proxy, which would otherwise be the only address checked.
+func newTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout: requestTimeout,
+		Control: controlDial,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
@@ -0,0 +1,58 @@
+package webhook
+
+import (
+	"errors"
+	"net/netip"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckURL(t *testing.T) {
+	testCases := []struct {
+		url string
+		err error
+	}{
+		{url: "https://203.0.113.10/hooks"},
+		{url: "http://[2001:db8::1]:8080/hooks"},
+		{url: "ftp://203.0.113.10/hooks", err: ErrInvalidURL},
+		{url: "https:///hooks", err: ErrInvalidURL},
+		{url: "http://127.0.0.1/hooks", err: ErrForbiddenTarget},
+		{url: "http://[::1]/hooks", err: ErrForbiddenTarget},
+		{url: "http://10.1.2.3/hooks", err: ErrForbiddenTarget},
+		{url: "http://192.168.0.10/hooks", err: ErrForbiddenTarget},
+		{url: "http://169.254.169.254/latest/meta-data", err: ErrForbiddenTarget},
+		{url: "http://[fe80::1]/hooks", err: ErrForbiddenTarget},
+		{url: "http://0.0.0.0:8080/hooks", err: ErrForbiddenTarget},
+		{url: "http://[::ffff:127.0.0.1]/hooks", err: ErrForbiddenTarget},
+		{url: "http://localhost:8080/hooks", err: ErrForbiddenTarget},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.url, func(t *testing.T) {
+			err := CheckURL(t.Context(), tt.url)
+			if tt.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestControlDial(t *testing.T) {
+	require.NoError(t, controlDial("tcp4", "203.0.113.10:443", nil))
+	require.ErrorIs(t, controlDial("tcp4", "127.0.0.1:443", nil), ErrForbiddenTarget)
+	require.ErrorIs(t, controlDial("tcp6", "[fd00::1]:443", nil), ErrForbiddenTarget)
+	require.True(t, allowedAddr(netip.MustParseAddr("2001:db8::1")))
+}
+
+func TestTruncateError(t *testing.T) {
+	require.Equal(t, "refused", truncateError(errors.New("refused")))
+
+	message := truncateError(errors.New(strings.Repeat("é", maxErrorLength)))
+	require.LessOrEqual(t, len(message), maxErrorLength)
+	require.True(t, utf8.ValidString(message))
+}